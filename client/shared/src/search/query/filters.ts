@@ -17,6 +17,7 @@ export enum FilterType {
     content = 'content',
     context = 'context',
     count = 'count',
+    cursor = 'cursor',
     file = 'file',
     fork = 'fork',
     lang = 'lang',
@@ -27,6 +28,7 @@ export enum FilterType {
     repohasfile = 'repohasfile',
     // eslint-disable-next-line unicorn/prevent-abbreviations
     rev = 'rev',
+    scope = 'scope',
     select = 'select',
     timeout = 'timeout',
     type = 'type',
@@ -231,6 +233,11 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         placeholder: 'number',
         singular: true,
     },
+    [FilterType.cursor]: {
+        description: 'Resume the search from the cursor of a previous search',
+        placeholder: 'cursor',
+        singular: true,
+    },
     [FilterType.file]: {
         alias: 'f',
         negatable: true,
@@ -289,6 +296,11 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         placeholder: 'branch/commit/tag',
         singular: true,
     },
+    [FilterType.scope]: {
+        discreteValues: () => ['code', 'comment', 'string'].map(value => ({ label: value })),
+        description: 'Only match the search pattern in code, comments or string literals.',
+        singular: true,
+    },
     [FilterType.select]: {
         discreteValues: value => selectorCompletion(value).map(value => ({ label: value })),
         description: 'Selects the kind of result to display.',
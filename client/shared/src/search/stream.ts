@@ -169,6 +169,13 @@ export interface Progress {
 
     // The URL of the trace for this query, if it exists.
     trace?: string
+
+    /**
+     * An opaque position from which the search can be resumed by adding
+     * cursor:<value> to the query. Unset once all repositories have been
+     * searched.
+     */
+    cursor?: string
 }
 
 export interface Skipped {
@@ -83,6 +83,17 @@ type UpsertEmptyBatchChangeArgs struct {
 	Name      string
 }
 
+type CreateBatchChangeFromComputeQueryArgs struct {
+	Namespace     graphql.ID
+	Name          string
+	Description   string
+	Query         string
+	Branch        string
+	Title         string
+	Body          string
+	CommitMessage *string
+}
+
 type CreateBatchSpecFromRawArgs struct {
 	BatchSpec        string
 	AllowIgnored     bool
@@ -251,6 +262,7 @@ type BatchChangesResolver interface {
 	CreateBatchSpec(ctx context.Context, args *CreateBatchSpecArgs) (BatchSpecResolver, error)
 	CreateEmptyBatchChange(ctx context.Context, args *CreateEmptyBatchChangeArgs) (BatchChangeResolver, error)
 	UpsertEmptyBatchChange(ctx context.Context, args *UpsertEmptyBatchChangeArgs) (BatchChangeResolver, error)
+	CreateBatchChangeFromComputeQuery(ctx context.Context, args *CreateBatchChangeFromComputeQueryArgs) (BatchChangeResolver, error)
 	CreateBatchSpecFromRaw(ctx context.Context, args *CreateBatchSpecFromRawArgs) (BatchSpecResolver, error)
 	ReplaceBatchSpecInput(ctx context.Context, args *ReplaceBatchSpecInputArgs) (BatchSpecResolver, error)
 	UpsertBatchSpecInput(ctx context.Context, args *UpsertBatchSpecInputArgs) (BatchSpecResolver, error)
@@ -0,0 +1,27 @@
+package graphqlbackend
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// CaptureGroupMatchResolver resolves a distinct value of a capture group of
+// the search pattern, as returned by queries with select:content.group.
+type CaptureGroupMatchResolver struct {
+	capture result.CaptureGroupMatch
+}
+
+func (r *CaptureGroupMatchResolver) Value() string     { return r.capture.Value }
+func (r *CaptureGroupMatchResolver) FileCount() int32  { return int32(r.capture.FileCount) }
+func (r *CaptureGroupMatchResolver) MatchCount() int32 { return int32(r.capture.MatchCount) }
+
+func (r *CaptureGroupMatchResolver) ToRepository() (*RepositoryResolver, bool) { return nil, false }
+func (r *CaptureGroupMatchResolver) ToFileMatch() (*FileMatchResolver, bool)   { return nil, false }
+func (r *CaptureGroupMatchResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *CaptureGroupMatchResolver) ToCodeOwnerMatch() (*CodeOwnerMatchResolver, bool) {
+	return nil, false
+}
+func (r *CaptureGroupMatchResolver) ToCaptureGroupMatch() (*CaptureGroupMatchResolver, bool) {
+	return r, true
+}
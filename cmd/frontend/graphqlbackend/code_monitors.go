@@ -80,6 +80,7 @@ type MonitorAction interface {
 	ToMonitorEmail() (MonitorEmailResolver, bool)
 	ToMonitorWebhook() (MonitorWebhookResolver, bool)
 	ToMonitorSlackWebhook() (MonitorSlackWebhookResolver, bool)
+	ToMonitorIssueTracker() (MonitorIssueTrackerResolver, bool)
 }
 
 type MonitorEmailResolver interface {
@@ -88,6 +89,8 @@ type MonitorEmailResolver interface {
 	IncludeResults() bool
 	Priority() string
 	Header() string
+	DeliveryPolicy() string
+	MaxNotificationsPerHour() *int32
 	Recipients(ctx context.Context, args *ListRecipientsArgs) (MonitorActionEmailRecipientsConnectionResolver, error)
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
 }
@@ -97,6 +100,8 @@ type MonitorWebhookResolver interface {
 	Enabled() bool
 	IncludeResults() bool
 	URL() string
+	DeliveryPolicy() string
+	MaxNotificationsPerHour() *int32
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
 }
 
@@ -105,9 +110,36 @@ type MonitorSlackWebhookResolver interface {
 	Enabled() bool
 	IncludeResults() bool
 	URL() string
+	DeliveryPolicy() string
+	MaxNotificationsPerHour() *int32
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
 }
 
+type MonitorIssueTrackerResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	IncludeResults() bool
+	Kind() string
+	URL() string
+	Repository() string
+	Labels() []string
+	Assignees() []string
+	AssignCodeOwners() bool
+	IssuePerCommit() bool
+	RESTTemplate() MonitorRESTIssueTemplateResolver
+	DeliveryPolicy() string
+	MaxNotificationsPerHour() *int32
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
+type MonitorRESTIssueTemplateResolver interface {
+	IssueBody() string
+	KeyField() string
+	URLField() *string
+	CommentURL() string
+	CommentBody() string
+}
+
 type MonitorEmailRecipient interface {
 	ToUser() (*UserResolver, bool)
 }
@@ -165,6 +197,7 @@ type CreateActionArgs struct {
 	Email        *CreateActionEmailArgs
 	Webhook      *CreateActionWebhookArgs
 	SlackWebhook *CreateActionSlackWebhookArgs
+	IssueTracker *CreateActionIssueTrackerArgs
 }
 
 type CreateActionEmailArgs struct {
@@ -173,18 +206,52 @@ type CreateActionEmailArgs struct {
 	Priority       string
 	Recipients     []graphql.ID
 	Header         string
+
+	DeliveryPolicy          *string
+	MaxNotificationsPerHour *int32
 }
 
 type CreateActionWebhookArgs struct {
 	Enabled        bool
 	IncludeResults bool
 	URL            string
+
+	DeliveryPolicy          *string
+	MaxNotificationsPerHour *int32
 }
 
 type CreateActionSlackWebhookArgs struct {
 	Enabled        bool
 	IncludeResults bool
 	URL            string
+
+	DeliveryPolicy          *string
+	MaxNotificationsPerHour *int32
+}
+
+type CreateActionIssueTrackerArgs struct {
+	Enabled          bool
+	IncludeResults   bool
+	Kind             string
+	URL              string
+	Repository       *string
+	Labels           *[]string
+	Assignees        *[]string
+	AssignCodeOwners *bool
+	IssuePerCommit   *bool
+	Token            *string
+	RESTTemplate     *RESTIssueTemplateArgs
+
+	DeliveryPolicy          *string
+	MaxNotificationsPerHour *int32
+}
+
+type RESTIssueTemplateArgs struct {
+	IssueBody   string
+	KeyField    string
+	URLField    *string
+	CommentURL  string
+	CommentBody string
 }
 
 type ToggleCodeMonitorArgs struct {
@@ -239,10 +306,16 @@ type EditActionSlackWebhookArgs struct {
 	Update *CreateActionSlackWebhookArgs
 }
 
+type EditActionIssueTrackerArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionIssueTrackerArgs
+}
+
 type EditActionArgs struct {
 	Email        *EditActionEmailArgs
 	Webhook      *EditActionWebhookArgs
 	SlackWebhook *EditActionSlackWebhookArgs
+	IssueTracker *EditActionIssueTrackerArgs
 }
 
 type EditTriggerArgs struct {
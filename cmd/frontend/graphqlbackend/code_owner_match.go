@@ -0,0 +1,26 @@
+package graphqlbackend
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// CodeOwnerMatchResolver resolves a code owner of matched files, as returned
+// by queries with select:file.owners.
+type CodeOwnerMatchResolver struct {
+	owner result.OwnerMatch
+}
+
+func (r *CodeOwnerMatchResolver) Handle() string    { return r.owner.Handle }
+func (r *CodeOwnerMatchResolver) Type() string      { return r.owner.Type }
+func (r *CodeOwnerMatchResolver) FileCount() int32  { return int32(r.owner.FileCount) }
+func (r *CodeOwnerMatchResolver) MatchCount() int32 { return int32(r.owner.MatchCount) }
+
+func (r *CodeOwnerMatchResolver) ToRepository() (*RepositoryResolver, bool) { return nil, false }
+func (r *CodeOwnerMatchResolver) ToFileMatch() (*FileMatchResolver, bool)   { return nil, false }
+func (r *CodeOwnerMatchResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *CodeOwnerMatchResolver) ToCodeOwnerMatch() (*CodeOwnerMatchResolver, bool) { return r, true }
+func (r *CodeOwnerMatchResolver) ToCaptureGroupMatch() (*CaptureGroupMatchResolver, bool) {
+	return nil, false
+}
@@ -97,3 +97,9 @@ func (r *CommitSearchResultResolver) ToFileMatch() (*FileMatchResolver, bool)
 func (r *CommitSearchResultResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
 	return r, true
 }
+func (r *CommitSearchResultResolver) ToCodeOwnerMatch() (*CodeOwnerMatchResolver, bool) {
+	return nil, false
+}
+func (r *CommitSearchResultResolver) ToCaptureGroupMatch() (*CaptureGroupMatchResolver, bool) {
+	return nil, false
+}
@@ -81,6 +81,10 @@ func (fm *FileMatchResolver) ToFileMatch() (*FileMatchResolver, bool)   { return
 func (fm *FileMatchResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
 	return nil, false
 }
+func (fm *FileMatchResolver) ToCodeOwnerMatch() (*CodeOwnerMatchResolver, bool) { return nil, false }
+func (fm *FileMatchResolver) ToCaptureGroupMatch() (*CaptureGroupMatchResolver, bool) {
+	return nil, false
+}
 
 type lineMatchResolver struct {
 	*result.LineMatch
@@ -0,0 +1,65 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// QuarantinedRepositories resolves the repositories whose corrupt clones are
+// quarantined on gitserver.
+func (r *schemaResolver) QuarantinedRepositories(ctx context.Context) ([]*quarantinedRepositoryResolver, error) {
+	// 🚨 SECURITY: Only site admins may view quarantined repositories
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repos, err := r.db.GitserverRepos().ListQuarantined(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*quarantinedRepositoryResolver, 0, len(repos))
+	for _, repo := range repos {
+		resolvers = append(resolvers, &quarantinedRepositoryResolver{db: r.db, repo: repo})
+	}
+
+	return resolvers, nil
+}
+
+type quarantinedRepositoryResolver struct {
+	db   database.DB
+	repo *types.QuarantinedRepo
+}
+
+func (r *quarantinedRepositoryResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := r.db.Repos().Get(ctx, r.repo.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, repo), nil
+}
+
+func (r *quarantinedRepositoryResolver) Shard() string { return r.repo.ShardID }
+func (r *quarantinedRepositoryResolver) Path() string  { return r.repo.QuarantinePath }
+func (r *quarantinedRepositoryResolver) QuarantinedAt() DateTime {
+	return DateTime{r.repo.QuarantinedAt}
+}
+
+func (r *quarantinedRepositoryResolver) CorruptionLogs() []*repositoryCorruptionLogResolver {
+	resolvers := make([]*repositoryCorruptionLogResolver, 0, len(r.repo.CorruptionLogs))
+	for _, log := range r.repo.CorruptionLogs {
+		resolvers = append(resolvers, &repositoryCorruptionLogResolver{log})
+	}
+	return resolvers
+}
+
+type repositoryCorruptionLogResolver struct {
+	log types.RepoCorruptionLog
+}
+
+func (r *repositoryCorruptionLogResolver) Timestamp() DateTime { return DateTime{r.log.Timestamp} }
+func (r *repositoryCorruptionLogResolver) Step() string        { return r.log.Step }
+func (r *repositoryCorruptionLogResolver) Reason() string      { return r.log.Reason }
@@ -310,6 +310,10 @@ func (r *RepositoryResolver) ToFileMatch() (*FileMatchResolver, bool)   { return
 func (r *RepositoryResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
 	return nil, false
 }
+func (r *RepositoryResolver) ToCodeOwnerMatch() (*CodeOwnerMatchResolver, bool) { return nil, false }
+func (r *RepositoryResolver) ToCaptureGroupMatch() (*CaptureGroupMatchResolver, bool) {
+	return nil, false
+}
 
 func (r *RepositoryResolver) Type(ctx context.Context) (*types.Repo, error) {
 	return r.repo(ctx)
@@ -121,6 +121,14 @@ func (c *SearchResultsResolver) Timedout(ctx context.Context) ([]*RepositoryReso
 	return c.repositoryResolvers(ctx, c.repoIDsByStatus(search.RepoStatusTimedout))
 }
 
+func (c *SearchResultsResolver) Cursor() *string {
+	if c.Stats.Cursor.Done() {
+		return nil
+	}
+	cursor := c.Stats.Cursor.Encode()
+	return &cursor
+}
+
 func (c *SearchResultsResolver) IndexUnavailable() bool {
 	// This used to return c.Stats.IsIndexUnavailable, but it was never set,
 	// so would always return false
@@ -165,6 +173,10 @@ func matchesToResolvers(db database.DB, matches []result.Match) []SearchResultRe
 				db:          db,
 				CommitMatch: *v,
 			})
+		case *result.OwnerMatch:
+			resolvers = append(resolvers, &CodeOwnerMatchResolver{owner: *v})
+		case *result.CaptureGroupMatch:
+			resolvers = append(resolvers, &CaptureGroupMatchResolver{capture: *v})
 		}
 	}
 	return resolvers
@@ -662,10 +674,14 @@ func (r *searchResolver) Stats(ctx context.Context) (stats *searchResultsStats,
 //   - *RepositoryResolver         // repo name match
 //   - *fileMatchResolver          // text match
 //   - *commitSearchResultResolver // diff or commit match
+//   - *CodeOwnerMatchResolver     // select:file.owners
+//   - *CaptureGroupMatchResolver  // select:content.group
 //
 // Note: Any new result types added here also need to be handled properly in search_results.go:301 (sparklines)
 type SearchResultResolver interface {
 	ToRepository() (*RepositoryResolver, bool)
 	ToFileMatch() (*FileMatchResolver, bool)
 	ToCommitSearchResult() (*CommitSearchResultResolver, bool)
+	ToCodeOwnerMatch() (*CodeOwnerMatchResolver, bool)
+	ToCaptureGroupMatch() (*CaptureGroupMatchResolver, bool)
 }
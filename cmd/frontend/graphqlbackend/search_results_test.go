@@ -699,3 +699,30 @@ func TestSubRepoFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesToResolvers_OwnerMatch(t *testing.T) {
+	resolvers := matchesToResolvers(database.NewMockDB(), []result.Match{
+		&result.OwnerMatch{Handle: "@alice", Type: "username", FileCount: 2, MatchCount: 3},
+	})
+	require.Len(t, resolvers, 1)
+
+	owner, ok := resolvers[0].ToCodeOwnerMatch()
+	require.True(t, ok)
+	require.Equal(t, "@alice", owner.Handle())
+	require.Equal(t, "username", owner.Type())
+	require.Equal(t, int32(2), owner.FileCount())
+	require.Equal(t, int32(3), owner.MatchCount())
+}
+
+func TestMatchesToResolvers_CaptureGroupMatch(t *testing.T) {
+	resolvers := matchesToResolvers(database.NewMockDB(), []result.Match{
+		&result.CaptureGroupMatch{Value: "fmt", FileCount: 2, MatchCount: 3},
+	})
+	require.Len(t, resolvers, 1)
+
+	capture, ok := resolvers[0].ToCaptureGroupMatch()
+	require.True(t, ok)
+	require.Equal(t, "fmt", capture.Value())
+	require.Equal(t, int32(2), capture.FileCount())
+	require.Equal(t, int32(3), capture.MatchCount())
+}
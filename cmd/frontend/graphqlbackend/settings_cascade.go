@@ -141,6 +141,7 @@ var settingsFieldMergeDepths = map[string]int{
 	"SearchScopes":           1,
 	"SearchSavedQueries":     1,
 	"SearchRepositoryGroups": 1,
+	"SearchMacros":           1,
 	"InsightsDashboards":     1,
 	"InsightsAllRepos":       1,
 	"Quicklinks":             1,
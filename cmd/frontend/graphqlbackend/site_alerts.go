@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/versions"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	srcprometheus "github.com/sourcegraph/sourcegraph/internal/src-prometheus"
 	"github.com/sourcegraph/sourcegraph/internal/version"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -179,6 +180,22 @@ func init() {
 
 	// Warn if customer is using GitLab on a version < 12.0.
 	AlertFuncs = append(AlertFuncs, gitlabVersionAlert)
+
+	// Warn about search macros that cannot be expanded.
+	AlertFuncs = append(AlertFuncs, invalidSearchMacrosAlert)
+}
+
+func invalidSearchMacrosAlert(args AlertFuncArgs) []*Alert {
+	if args.ViewerFinalSettings == nil || len(args.ViewerFinalSettings.SearchMacros) == 0 {
+		return nil
+	}
+	if err := query.ValidateMacros(query.Macros(args.ViewerFinalSettings.SearchMacros)); err != nil {
+		return []*Alert{{
+			TypeValue:    AlertTypeWarning,
+			MessageValue: "Update the `search.macros` setting to resolve problems: " + err.Error(),
+		}}
+	}
+	return nil
 }
 
 func storageLimitReachedAlert(args AlertFuncArgs) []*Alert {
@@ -178,3 +178,23 @@ func TestObservabilityActiveAlertsAlert(t *testing.T) {
 		})
 	}
 }
+
+func TestInvalidSearchMacrosAlert(t *testing.T) {
+	valid := AlertFuncArgs{ViewerFinalSettings: &schema.Settings{
+		SearchMacros: map[string]string{"go": "lang:go"},
+	}}
+	if got := invalidSearchMacrosAlert(valid); got != nil {
+		t.Fatalf("unexpected alerts: %+v", got)
+	}
+
+	invalid := AlertFuncArgs{ViewerFinalSettings: &schema.Settings{
+		SearchMacros: map[string]string{"loop": "@loop"},
+	}}
+	want := []*Alert{{
+		TypeValue:    AlertTypeWarning,
+		MessageValue: "Update the `search.macros` setting to resolve problems: macro @loop references itself: @loop -> @loop. Remove one of the references and try again",
+	}}
+	if diff := cmp.Diff(want, invalidSearchMacrosAlert(invalid)); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,572 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// exportCursorHeader is the response trailer holding the cursor to pass
+	// to the next export request. It is only set if rows remain.
+	exportCursorHeader = "X-Sourcegraph-Export-Cursor"
+
+	defaultExportPageSize = 10000
+	maxExportPageSize     = 100000
+
+	// exportFlushRows is the number of rows we write between flushes.
+	exportFlushRows = 1000
+)
+
+// ExportHandler is an http handler which runs a search and writes its
+// results as CSV or JSON Lines, one row per line match.
+//
+// Rows are written as results arrive. The search pages through
+// repositories, so that an export can be resumed: once a page holds enough
+// rows and all repositories with rows in it have been searched completely,
+// the search stops and the cursor to request the next page is returned in
+// the X-Sourcegraph-Export-Cursor trailer.
+func ExportHandler(db database.DB) http.Handler {
+	logger := log.Scoped("searchExportHandler", "")
+	return &exportHandler{
+		logger:       logger,
+		db:           db,
+		searchClient: client.NewSearchClient(logger, db, search.Indexed(), search.SearcherURLs()),
+	}
+}
+
+type exportHandler struct {
+	logger       log.Logger
+	db           database.DB
+	searchClient client.SearchClient
+}
+
+func (h *exportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tr, ctx := trace.New(r.Context(), "search.ServeExport", "")
+	defer tr.Finish()
+
+	args, err := parseExportURLQuery(r.URL.Query())
+	if err != nil {
+		tr.SetError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tr.TagFields(
+		otlog.String("query", args.Query),
+		otlog.String("format", args.Format),
+		otlog.Bool("resumed", args.Cursor.Search != ""),
+	)
+
+	settings, err := graphqlbackend.DecodedViewerFinalSettings(ctx, h.db)
+	if err != nil {
+		tr.SetError(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	inputs, err := h.searchClient.Plan(ctx, args.Version, strPtr(args.PatternType), args.searchQuery(), search.Streaming, settings, envvar.SourcegraphDotComMode())
+	if err != nil {
+		tr.SetError(err)
+		var queryErr *client.QueryError
+		if errors.As(err, &queryErr) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream := &exportStream{
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    h.logger,
+		db:        h.db,
+		w:         newExportWriter(w, args.Format),
+		pageSize:  args.PageSize,
+		remaining: inputs.MaxResults(),
+		window:    make(map[api.RepoID]struct{}),
+		pages:     make(map[string]types.MultiCursor),
+	}
+	_, err = h.searchClient.Execute(ctx, stream, inputs)
+
+	next, err := stream.finish(err)
+	if err != nil {
+		tr.SetError(err)
+		if !stream.w.started {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Headers are already written, so all we can do is log.
+		h.logger.Warn("failed to write search export", log.Error(err))
+		return
+	}
+	if next != nil {
+		w.Header().Set(exportCursorHeader, exportCursor{Query: args.Cursor.Query, Search: next.Encode()}.Encode())
+	}
+}
+
+// exportStream writes the rows of the results it receives, and stops the
+// search once a page is complete or the result limit of the query is reached.
+//
+// The rows of file results are written as they arrive. The search can only
+// be stopped at a position which covers every row written so far: all
+// repository pagers but one must be done, and the remaining pager must have
+// searched all repositories with rows in its current page. Repository and
+// commit results are not searched by repository pagers, so their rows are
+// only written once the whole search is done.
+type exportStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger log.Logger
+	db     database.DB
+
+	mu       sync.Mutex
+	w        *exportWriter
+	pageSize int
+	err      error
+
+	// remaining is the number of results left until the result limit of the
+	// query is reached. Once it is 0, the search is stopped and the export
+	// is complete.
+	remaining int
+
+	// cursor is the latest position of the search.
+	cursor search.Cursor
+	// next is the position to resume from once the search was stopped.
+	next *search.Cursor
+	// window are the repositories with rows written since the current page
+	// of the last active pager started.
+	window map[api.RepoID]struct{}
+	// pages are the current pages of each pager.
+	pages map[string]types.MultiCursor
+	// deferred are the rows written once the search is done.
+	deferred []exportRow
+}
+
+func (s *exportStream) Send(event streaming.SearchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next != nil || s.err != nil || s.remaining <= 0 {
+		return
+	}
+
+	// Drops the results beyond the limit from the event.
+	s.remaining = event.Results.Limit(s.remaining)
+
+	repoMetadata, err := getEventRepoMetadata(s.ctx, s.db, event)
+	if err != nil {
+		s.logger.Error("failed to get repo metadata", log.Error(err))
+		return
+	}
+
+	for _, match := range event.Results {
+		// Like the stream handler, don't export matches which we cannot map
+		// to a repo the actor has access to.
+		// Aggregate matches span repos, which were resolved with the
+		// actor's permissions.
+		repo := match.RepoName()
+		if md, ok := repoMetadata[repo.ID]; !isAggregateMatch(match) && (!ok || md.Name != repo.Name) {
+			continue
+		}
+		if _, ok := match.(*result.FileMatch); !ok {
+			s.deferred = append(s.deferred, exportRows(match)...)
+			continue
+		}
+		for _, row := range exportRows(match) {
+			if s.err = s.w.Write(row); s.err != nil {
+				s.cancel()
+				return
+			}
+			s.window[repo.ID] = struct{}{}
+		}
+	}
+
+	if s.remaining <= 0 {
+		// The export is complete, so there is no page to resume from.
+		s.cancel()
+		return
+	}
+
+	if len(event.Stats.Cursor.Pagers) > 0 {
+		s.cursor.Update(&event.Stats.Cursor)
+		s.checkpoint()
+	}
+}
+
+// checkpoint stops the search if the page is full and the current position
+// covers all rows written so far. s.mu must be held.
+func (s *exportStream) checkpoint() {
+	var active []*search.PagerPosition
+	newPage := false
+	for key, pos := range s.cursor.Pagers {
+		if pos.Done {
+			continue
+		}
+		active = append(active, pos)
+		if page, ok := s.pages[key]; !ok || !reflect.DeepEqual(page, pos.Page) {
+			s.pages[key] = pos.Page
+			newPage = true
+		}
+	}
+	if len(active) != 1 {
+		// While several pagers run, we cannot tell which pager the rows
+		// written so far belong to.
+		return
+	}
+	if newPage {
+		// Pages are searched one after the other and all other pagers are
+		// done, so all rows written so far belong to repositories which
+		// were searched completely.
+		s.window = make(map[api.RepoID]struct{})
+	}
+
+	if s.w.rows < s.pageSize {
+		return
+	}
+	searched := make(map[api.RepoID]struct{}, len(active[0].Searched))
+	for _, id := range active[0].Searched {
+		searched[id] = struct{}{}
+	}
+	for id := range s.window {
+		if _, ok := searched[id]; !ok {
+			return
+		}
+	}
+
+	next := search.Cursor{}
+	next.Update(&s.cursor)
+	s.next = &next
+	s.cancel()
+}
+
+// finish writes the remaining rows after the search returned err. It
+// returns the cursor to resume from, or nil if the search is done.
+func (s *exportStream) finish(err error) (*search.Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.next != nil {
+		// We stopped the search, so ignore the cancellation error.
+		return s.next, s.w.Close()
+	}
+	if err != nil && s.remaining > 0 {
+		// If the limit was reached, we stopped the search and ignore the
+		// cancellation error.
+		return nil, err
+	}
+	for _, row := range s.deferred {
+		if err := s.w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	return nil, s.w.Close()
+}
+
+// exportRow is a row of a search export. Line and column are 1-based, and 0
+// if the row is not a line match.
+type exportRow struct {
+	Repository string `json:"repository"`
+	Revision   string `json:"revision"`
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Preview    string `json:"preview"`
+}
+
+var exportColumns = []string{"repository", "revision", "path", "line", "column", "preview"}
+
+func (r exportRow) record() []string {
+	record := []string{r.Repository, r.Revision, r.Path, "", "", r.Preview}
+	if r.Line > 0 {
+		record[3] = strconv.Itoa(r.Line)
+		record[4] = strconv.Itoa(r.Column)
+	}
+	return record
+}
+
+// exportRows returns the rows for a match: one per line match of a file
+// with content matches, and one for every other match. Capture group values
+// only have a preview.
+func exportRows(match result.Match) []exportRow {
+	switch v := match.(type) {
+	case *result.FileMatch:
+		file := exportRow{
+			Repository: string(v.Repo.Name),
+			Revision:   string(v.CommitID),
+			Path:       v.Path,
+		}
+		if len(v.Symbols) > 0 {
+			rows := make([]exportRow, 0, len(v.Symbols))
+			for _, sym := range v.Symbols {
+				row := file
+				row.Line = sym.Symbol.Line
+				row.Column = sym.Symbol.Character + 1
+				row.Preview = sym.Symbol.Name
+				rows = append(rows, row)
+			}
+			return rows
+		}
+		if v.ChunkMatches.MatchCount() == 0 {
+			return []exportRow{file}
+		}
+		lineMatches := v.ChunkMatches.AsLineMatches()
+		rows := make([]exportRow, 0, len(lineMatches))
+		for _, lm := range lineMatches {
+			if len(lm.OffsetAndLengths) == 0 {
+				// A context line of a multiline chunk.
+				continue
+			}
+			row := file
+			row.Line = int(lm.LineNumber) + 1
+			row.Column = int(lm.OffsetAndLengths[0][0]) + 1
+			row.Preview = lm.Preview
+			rows = append(rows, row)
+		}
+		return rows
+	case *result.RepoMatch:
+		return []exportRow{{
+			Repository: string(v.Name),
+			Revision:   v.Rev,
+		}}
+	case *result.CommitMatch:
+		return []exportRow{{
+			Repository: string(v.Repo.Name),
+			Revision:   string(v.Commit.ID),
+			Preview:    v.Commit.Message.Subject(),
+		}}
+	case *result.CaptureGroupMatch:
+		return []exportRow{{
+			Preview: v.Value,
+		}}
+	default:
+		return nil
+	}
+}
+
+// exportWriter writes rows to an http.ResponseWriter in an export format,
+// flushing periodically.
+type exportWriter struct {
+	w      http.ResponseWriter
+	format string
+	flush  func()
+
+	csv  *csv.Writer
+	json *json.Encoder
+
+	// started is true once the headers are written.
+	started bool
+	rows    int
+}
+
+func newExportWriter(w http.ResponseWriter, format string) *exportWriter {
+	flush := func() {}
+	if f, ok := w.(http.Flusher); ok {
+		flush = f.Flush
+	}
+	return &exportWriter{w: w, format: format, flush: flush}
+}
+
+// start writes the headers and, for CSV, the header row.
+func (e *exportWriter) start() error {
+	e.started = true
+	// The cursor is only known once the search stopped.
+	e.w.Header().Set("Trailer", exportCursorHeader)
+
+	switch e.format {
+	case "csv":
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		e.w.Header().Set("Content-Disposition", `attachment; filename="search-results.csv"`)
+		e.csv = csv.NewWriter(e.w)
+		return e.csv.Write(exportColumns)
+	case "jsonl":
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.w.Header().Set("Content-Disposition", `attachment; filename="search-results.jsonl"`)
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	return errors.Errorf("unsupported export format %q", e.format)
+}
+
+func (e *exportWriter) Write(row exportRow) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if e.csv != nil {
+		err = e.csv.Write(row.record())
+	} else {
+		err = e.json.Encode(row)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush writes buffered rows to the client.
+func (e *exportWriter) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	e.flush()
+	return nil
+}
+
+// Close writes the headers if no rows were written and flushes.
+func (e *exportWriter) Close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+// exportCursor identifies the position of the next page of an export.
+type exportCursor struct {
+	// Query is a fingerprint of the search the cursor belongs to.
+	Query string `json:"q"`
+	// Search is the encoded position of the search, see search.Cursor.
+	Search string `json:"s,omitempty"`
+}
+
+func (c exportCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeExportCursor(s string) (exportCursor, error) {
+	var c exportCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	if _, err := search.DecodeCursor(c.Search); c.Search == "" || err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	return c, nil
+}
+
+// exportFingerprint identifies a search, so that cursors cannot be used to
+// resume a different search.
+func exportFingerprint(version, patternType, query string) string {
+	h := sha256.New()
+	for _, s := range []string{version, patternType, query} {
+		_, _ = io.WriteString(h, s)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+type exportArgs struct {
+	Query       string
+	Version     string
+	PatternType string
+	Format      string
+	PageSize    int
+	Cursor      exportCursor
+}
+
+// searchQuery returns the query to run for the page at args.Cursor. It
+// always contains a cursor, so that the search pages through repositories
+// and reports its position.
+func (a *exportArgs) searchQuery() string {
+	cursor := a.Cursor.Search
+	if cursor == "" {
+		cursor = (&search.Cursor{}).Encode()
+	}
+	return fmt.Sprintf("(%s) cursor:%s", a.Query, cursor)
+}
+
+func parseExportURLQuery(q url.Values) (*exportArgs, error) {
+	get := func(k, def string) string {
+		v := q.Get(k)
+		if v == "" {
+			return def
+		}
+		return v
+	}
+
+	a := exportArgs{
+		Query:       get("q", ""),
+		Version:     get("v", "V3"),
+		PatternType: get("t", ""),
+		Format:      get("format", "csv"),
+	}
+
+	if a.Query == "" {
+		return nil, errors.New("no query found")
+	}
+
+	if a.Format != "csv" && a.Format != "jsonl" {
+		return nil, errors.Errorf("format must be csv or jsonl, got %q", a.Format)
+	}
+
+	pageSize := get("rows", strconv.Itoa(defaultExportPageSize))
+	var err error
+	if a.PageSize, err = strconv.Atoi(pageSize); err != nil {
+		return nil, errors.Errorf("rows must be an integer, got %q: %w", pageSize, err)
+	}
+	if a.PageSize <= 0 || a.PageSize > maxExportPageSize {
+		return nil, errors.Errorf("rows must be between 1 and %d, got %d", maxExportPageSize, a.PageSize)
+	}
+
+	fingerprint := exportFingerprint(a.Version, a.PatternType, a.Query)
+	a.Cursor = exportCursor{Query: fingerprint}
+	if cursor := q.Get("cursor"); cursor != "" {
+		if a.Cursor, err = decodeExportCursor(cursor); err != nil {
+			return nil, err
+		}
+		if a.Cursor.Query != fingerprint {
+			return nil, errors.New("cursor belongs to a different search")
+		}
+	}
+
+	return &a, nil
+}
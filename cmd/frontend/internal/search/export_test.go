@@ -0,0 +1,243 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestServeExport(t *testing.T) {
+	graphqlbackend.MockDecodedViewerFinalSettings = &schema.Settings{}
+	t.Cleanup(func() { graphqlbackend.MockDecodedViewerFinalSettings = nil })
+
+	repo := func(id api2.RepoID) types.MinimalRepo {
+		return types.MinimalRepo{ID: id, Name: api2.RepoName(fmt.Sprintf("repo%d", id))}
+	}
+
+	fileMatch := func(id api2.RepoID, path string, lines ...string) *result.FileMatch {
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo:     repo(id),
+				CommitID: "deadbeef",
+				Path:     path,
+			},
+		}
+		for i, line := range lines {
+			fm.ChunkMatches = append(fm.ChunkMatches, result.ChunkMatch{
+				Content:      line,
+				ContentStart: result.Location{Line: i},
+				Ranges: result.Ranges{{
+					Start: result.Location{Line: i, Column: 4},
+					End:   result.Location{Line: i, Column: 7},
+				}},
+			})
+		}
+		return fm
+	}
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultHook(func(_ context.Context, _ string, _ *string, queryString string, _ search.Protocol, _ *schema.Settings, _ bool) (*search.Inputs, error) {
+		plan, err := query.Pipeline(query.Init(queryString, query.SearchTypeLiteral))
+		require.NoError(t, err)
+		return &search.Inputs{Query: plan.ToQ(), OriginalQuery: queryString}, nil
+	})
+
+	// The search is a repository pager with two pages, which searches one
+	// repository after the other, and a commit search which is not paged.
+	const pagerKey = "pager"
+	pages := [][]api2.RepoID{{1, 2}, {3}}
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, inputs *search.Inputs) (*search.Alert, error) {
+		_, v, _ := strings.Cut(inputs.OriginalQuery, "cursor:")
+		cursor, err := search.DecodeCursor(v)
+		require.NoError(t, err)
+
+		sendPosition := func(pos search.PagerPosition) {
+			pos.Searched = append([]api2.RepoID(nil), pos.Searched...)
+			s.Send(streaming.SearchEvent{Stats: streaming.Stats{
+				Cursor: search.Cursor{Pagers: map[string]*search.PagerPosition{pagerKey: &pos}},
+			}})
+		}
+
+		s.Send(streaming.SearchEvent{Results: result.Matches{&result.CommitMatch{
+			Repo:   repo(1),
+			Commit: gitdomain.Commit{ID: "cafe", Message: "Fix foo\n\nDetails"},
+		}}})
+
+		start := cursor.Position(pagerKey)
+		if start != nil && start.Done {
+			return nil, nil
+		}
+		first := 0
+		skip := map[api2.RepoID]bool{}
+		if start != nil {
+			first, _ = strconv.Atoi(start.Page[0].Value)
+			for _, id := range start.Searched {
+				skip[id] = true
+			}
+		}
+		for i := first; i < len(pages); i++ {
+			pos := search.PagerPosition{Page: types.MultiCursor{{Column: "page", Value: strconv.Itoa(i)}}}
+			if i == first && start != nil {
+				pos.Searched = start.Searched
+			}
+			sendPosition(pos)
+			for _, id := range pages[i] {
+				if skip[id] {
+					continue
+				}
+				s.Send(streaming.SearchEvent{Results: result.Matches{fileMatch(id, "a.go", "var foo = 1", "foo()")}})
+				pos.Searched = append(pos.Searched, id)
+				sendPosition(pos)
+			}
+		}
+		sendPosition(search.PagerPosition{Done: true})
+		return nil, nil
+	})
+
+	mockRepos := database.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultHook(func(_ context.Context, ids ...api2.RepoID) ([]*types.SearchedRepo, error) {
+		out := make([]*types.SearchedRepo, 0, len(ids))
+		for _, id := range ids {
+			out = append(out, &types.SearchedRepo{ID: id, Name: repo(id).Name})
+		}
+		return out, nil
+	})
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(&exportHandler{
+		logger:       logtest.Scoped(t),
+		db:           db,
+		searchClient: mock,
+	})
+	defer ts.Close()
+
+	get := func(params url.Values) (*http.Response, string) {
+		res, err := http.Get(ts.URL + "?" + params.Encode())
+		require.NoError(t, err)
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, string(b)
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		res, body := get(url.Values{"q": {"foo count:all"}})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, res.Trailer.Get(exportCursorHeader))
+		require.Equal(t, `repository,revision,path,line,column,preview
+repo1,deadbeef,a.go,1,5,var foo = 1
+repo1,deadbeef,a.go,2,5,foo()
+repo2,deadbeef,a.go,1,5,var foo = 1
+repo2,deadbeef,a.go,2,5,foo()
+repo3,deadbeef,a.go,1,5,var foo = 1
+repo3,deadbeef,a.go,2,5,foo()
+repo1,cafe,,,,Fix foo
+`, body)
+	})
+
+	t.Run("jsonl pages", func(t *testing.T) {
+		// The page ends once repo2 was searched completely.
+		res, body := get(url.Values{"q": {"foo count:all"}, "format": {"jsonl"}, "rows": {"3"}})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, `{"repository":"repo1","revision":"deadbeef","path":"a.go","line":1,"column":5,"preview":"var foo = 1"}
+{"repository":"repo1","revision":"deadbeef","path":"a.go","line":2,"column":5,"preview":"foo()"}
+{"repository":"repo2","revision":"deadbeef","path":"a.go","line":1,"column":5,"preview":"var foo = 1"}
+{"repository":"repo2","revision":"deadbeef","path":"a.go","line":2,"column":5,"preview":"foo()"}
+`, body)
+
+		cursor := res.Trailer.Get(exportCursorHeader)
+		require.NotEmpty(t, cursor)
+		res, body = get(url.Values{"q": {"foo count:all"}, "format": {"jsonl"}, "rows": {"3"}, "cursor": {cursor}})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, res.Trailer.Get(exportCursorHeader))
+		require.Equal(t, `{"repository":"repo3","revision":"deadbeef","path":"a.go","line":1,"column":5,"preview":"var foo = 1"}
+{"repository":"repo3","revision":"deadbeef","path":"a.go","line":2,"column":5,"preview":"foo()"}
+{"repository":"repo1","revision":"cafe","path":"","line":0,"column":0,"preview":"Fix foo"}
+`, body)
+
+		res, _ = get(url.Values{"q": {"bar count:all"}, "cursor": {cursor}})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("result limit", func(t *testing.T) {
+		// The commit and the first file match reach the limit, which
+		// completes the export.
+		res, body := get(url.Values{"q": {"foo count:3"}})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Empty(t, res.Trailer.Get(exportCursorHeader))
+		require.Equal(t, `repository,revision,path,line,column,preview
+repo1,deadbeef,a.go,1,5,var foo = 1
+repo1,deadbeef,a.go,2,5,foo()
+repo1,cafe,,,,Fix foo
+`, body)
+
+		// Results beyond the limit are dropped from file matches.
+		res, body = get(url.Values{"q": {"foo count:2"}})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, `repository,revision,path,line,column,preview
+repo1,deadbeef,a.go,1,5,var foo = 1
+repo1,cafe,,,,Fix foo
+`, body)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		res, _ := get(url.Values{"q": {"foo"}, "format": {"xml"}})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestExportStream_CaptureGroups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Capture group values don't belong to a repository.
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(database.NewMockRepoStore())
+
+	rec := httptest.NewRecorder()
+	s := &exportStream{
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    logtest.Scoped(t),
+		db:        db,
+		w:         newExportWriter(rec, "csv"),
+		pageSize:  10,
+		remaining: 10,
+		window:    make(map[api2.RepoID]struct{}),
+		pages:     make(map[string]types.MultiCursor),
+	}
+	s.Send(streaming.SearchEvent{Results: result.Matches{
+		&result.CaptureGroupMatch{Value: "fmt", FileCount: 2, MatchCount: 3},
+		&result.CaptureGroupMatch{Value: "errors", FileCount: 1, MatchCount: 1},
+	}})
+	next, err := s.finish(nil)
+	require.NoError(t, err)
+	require.Nil(t, next)
+
+	require.Equal(t, `repository,revision,path,line,column,preview
+,,,,,fmt
+,,,,,errors
+`, rec.Body.String())
+}
@@ -294,6 +294,10 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo,
 		return fromRepository(v, repoCache)
 	case *result.CommitMatch:
 		return fromCommit(v, repoCache)
+	case *result.OwnerMatch:
+		return fromOwner(v)
+	case *result.CaptureGroupMatch:
+		return fromCaptureGroup(v)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -513,6 +517,36 @@ func fromCommit(commit *result.CommitMatch, repoCache map[api.RepoID]*types.Sear
 	return commitEvent
 }
 
+// isAggregateMatch returns true if match aggregates matches across
+// repositories, like the results of select:file.owners.
+func isAggregateMatch(match result.Match) bool {
+	switch match.(type) {
+	case *result.OwnerMatch, *result.CaptureGroupMatch:
+		return true
+	default:
+		return false
+	}
+}
+
+func fromOwner(owner *result.OwnerMatch) *streamhttp.EventOwnerMatch {
+	return &streamhttp.EventOwnerMatch{
+		Type:       streamhttp.OwnerMatchType,
+		Handle:     owner.Handle,
+		OwnerType:  owner.Type,
+		FileCount:  owner.FileCount,
+		MatchCount: owner.MatchCount,
+	}
+}
+
+func fromCaptureGroup(capture *result.CaptureGroupMatch) *streamhttp.EventCaptureGroupMatch {
+	return &streamhttp.EventCaptureGroupMatch{
+		Type:       streamhttp.CaptureGroupMatchType,
+		Value:      capture.Value,
+		FileCount:  capture.FileCount,
+		MatchCount: capture.MatchCount,
+	}
+}
+
 // eventStreamOTHook returns a StatHook which logs to log.
 func eventStreamOTHook(log func(...otlog.Field)) func(streamhttp.WriterStat) {
 	return func(stat streamhttp.WriterStat) {
@@ -671,8 +705,9 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 
 		// Don't send matches which we cannot map to a repo the actor has access to. This
 		// check is expected to always pass. Missing metadata is a sign that we have
-		// searched repos that user shouldn't have access to.
-		if md, ok := repoMetadata[repo.ID]; !ok || md.Name != repo.Name {
+		// searched repos that user shouldn't have access to. Aggregate matches
+		// span repos, which were resolved with the actor's permissions.
+		if md, ok := repoMetadata[repo.ID]; !isAggregateMatch(match) && (!ok || md.Name != repo.Name) {
 			continue
 		}
 
@@ -0,0 +1,546 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/internal/accesslog"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Blames are cached per commit and path in the blame cache directory of each
+// repository, so that they are removed together with the repository. Blaming
+// a file in a commit reuses the cached blame of the file in its parent commit:
+// the lines that didn't change keep their attribution, and only the lines that
+// changed are blamed with git. The janitor evicts the cached blames that
+// weren't read in a while. The cache doesn't count towards the size of the
+// repository, see gitDirSize.
+
+const blameCacheDirName = "sg_blame_cache"
+
+var (
+	blameCacheMaxAge     = env.MustGetDuration("SRC_BLAME_CACHE_MAX_AGE", 7*24*time.Hour, "the duration after which cached blames that weren't read are evicted")
+	blameCacheMaxSize, _ = strconv.Atoi(env.Get("SRC_BLAME_CACHE_MAX_SIZE_MB", "100", "the maximum size of the blame cache of a repository in MB. Set to 0 to disable the blame cache"))
+)
+
+// blameCacheEntry is the blame of a file in a commit.
+type blameCacheEntry struct {
+	Commits []blameCommit
+	Lines   []blameLine
+}
+
+// blameCommit is a commit that lines of a file are attributed to.
+type blameCommit struct {
+	ID       api.CommitID
+	Author   gitdomain.Signature
+	Message  string
+	Filename string
+}
+
+// blameLine is a blamed line of a file: the index of its commit in Commits,
+// its line number in that commit, and its length in bytes. It is an array to
+// keep cache entries small.
+type blameLine [3]int
+
+func (s *Server) handleBlame(w http.ResponseWriter, r *http.Request) {
+	var req protocol.BlameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "decoding body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Log which actor is accessing the repo.
+	accesslog.Record(r.Context(), string(req.Repo), map[string]string{
+		"commit": string(req.Commit),
+		"path":   req.Path,
+	})
+
+	if !isAbsoluteRevision(string(req.Commit)) {
+		http.Error(w, "commit must be a full commit ID", http.StatusBadRequest)
+		return
+	}
+
+	repo := protocol.NormalizeRepo(req.Repo)
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		s.repoNotCloned(r.Context(), w, repo, dir)
+		return
+	}
+	s.ensureRevision(r.Context(), repo, string(req.Commit), dir)
+
+	entry, err := s.blame(r.Context(), repo, dir, req.Commit, req.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hunks, err := entry.hunks(req.Path, req.StartLine, req.EndLine)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(protocol.BlameResponse{Hunks: hunks}); err != nil {
+		s.Logger.Error("sending blame response", log.Error(err))
+	}
+}
+
+// blame returns the blame of the file at path in commit.
+func (s *Server) blame(ctx context.Context, repo api.RepoName, dir GitDir, commit api.CommitID, path string) (_ *blameCacheEntry, err error) {
+	fetchEnv, redactor := s.lazyFetchEnv(ctx, repo, dir)
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		dir.Set(cmd)
+		withLazyFetchEnv(cmd, fetchEnv)
+		out, err := cmd.Output()
+		return out, redactError(redactor, wrapCmdError(cmd, err))
+	}
+
+	if blameCacheMaxSize <= 0 {
+		return blameRanges(run, commit, path, nil)
+	}
+
+	result := "miss"
+	start := time.Now()
+	defer func() {
+		if err == nil {
+			blameCacheRequests.WithLabelValues(result).Inc()
+			blameDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+		}
+	}()
+
+	if entry, ok := readBlameCache(dir, commit, path); ok {
+		result = "hit"
+		return entry, nil
+	}
+
+	var entry *blameCacheEntry
+	if parent, err := singleParent(run, commit); err != nil {
+		return nil, err
+	} else if parent != "" {
+		if parentEntry, ok := readBlameCache(dir, parent, path); ok {
+			if entry, err = blameIncremental(run, parentEntry, parent, commit, path); err != nil {
+				return nil, err
+			}
+			if entry != nil {
+				result = "incremental"
+			}
+		}
+	}
+	if entry == nil {
+		if entry, err = blameRanges(run, commit, path, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeBlameCache(dir, commit, path, entry); err != nil {
+		s.Logger.Warn("failed to cache blame", log.String("repo", string(repo)), log.String("commit", string(commit)), log.Error(err))
+	}
+	return entry, nil
+}
+
+// singleParent returns the parent of commit, or an empty string if commit is
+// a root or merge commit.
+func singleParent(run func(...string) ([]byte, error), commit api.CommitID) (api.CommitID, error) {
+	out, err := run("rev-list", "--parents", "-n", "1", string(commit), "--")
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return "", nil
+	}
+	return api.CommitID(fields[1]), nil
+}
+
+var diffHunkHeader = lazyregexp.New(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// blameIncremental blames the file at path in commit from the blame of the
+// file in its parent commit. It returns nil if the blame can't be derived
+// from the blame in the parent commit, for example because the file was
+// deleted.
+//
+// The lines that didn't change keep their attribution. The diff we compute may
+// align ambiguous lines, such as lone closing braces, differently than git
+// blame does. Such lines may then be attributed to an older commit that added
+// an identical line.
+func blameIncremental(run func(...string) ([]byte, error), parent *blameCacheEntry, parentCommit, commit api.CommitID, path string) (*blameCacheEntry, error) {
+	out, err := run("diff", "--no-ext-diff", "--no-color", "--no-renames", "--text", "-U0", string(parentCommit), string(commit), "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &blameCacheEntry{Commits: parent.Commits}
+	var changed [][2]int // ranges of lines to blame with git
+	var files int
+	next := 0 // index of the next line of the parent to copy
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files++
+		}
+		if strings.HasPrefix(line, "deleted file mode ") || files > 1 {
+			// The file was deleted, replaced by a file of another type, or
+			// path is a directory.
+			return nil, nil
+		}
+
+		m := diffHunkHeader.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		oldStart, oldLines := atoiDefault(m[1], 1), atoiDefault(m[2], 1)
+		newStart, newLines := atoiDefault(m[3], 1), atoiDefault(m[4], 1)
+
+		// Without context, oldStart is the line before the added lines if no
+		// lines were removed.
+		unchanged := oldStart - 1
+		if oldLines == 0 {
+			unchanged = oldStart
+		}
+		if unchanged < next || unchanged+oldLines > len(parent.Lines) {
+			return nil, errors.Errorf("unexpected diff hunk %q of %s", line, path)
+		}
+		entry.Lines = append(entry.Lines, parent.Lines[next:unchanged]...)
+		next = unchanged + oldLines
+
+		if newLines > 0 {
+			changed = append(changed, [2]int{newStart, newStart + newLines - 1})
+			for i := 0; i < newLines; i++ {
+				entry.Lines = append(entry.Lines, blameLine{-1})
+			}
+		}
+	}
+	entry.Lines = append(entry.Lines, parent.Lines[next:]...)
+
+	if len(changed) > 0 {
+		blamed, err := blameRanges(run, commit, path, changed)
+		if err != nil {
+			return nil, err
+		}
+		entry.merge(blamed)
+	}
+	for i, line := range entry.Lines {
+		if line[0] < 0 {
+			return nil, errors.Errorf("line %d of %s was not blamed", i+1, path)
+		}
+	}
+	entry.compact()
+	return entry, nil
+}
+
+// blameRanges blames the given ranges of lines of the file at path in commit
+// with git, or the whole file if ranges is empty. The lines outside of ranges
+// are left unblamed, with a commit index of -1.
+func blameRanges(run func(...string) ([]byte, error), commit api.CommitID, path string, ranges [][2]int) (*blameCacheEntry, error) {
+	args := []string{"blame", "-w", "--porcelain"}
+	for _, r := range ranges {
+		args = append(args, "-L"+strconv.Itoa(r[0])+","+strconv.Itoa(r[1]))
+	}
+	out, err := run(append(args, string(commit), "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &blameCacheEntry{}
+	index := map[api.CommitID]int{}
+	var cur *blameLine
+	var final int
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "\t"):
+			// The content of the line, preceded by a tab instead of followed
+			// by a newline.
+			if cur == nil {
+				return nil, errors.Errorf("unexpected line in git blame output: %q", line)
+			}
+			cur[2] = len(line)
+			for len(entry.Lines) < final {
+				entry.Lines = append(entry.Lines, blameLine{-1})
+			}
+			entry.Lines[final-1] = *cur
+			cur = nil
+
+		case cur == nil:
+			// <commit> SP <original line> SP <final line> [SP <lines in group>]
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, errors.Errorf("unexpected line in git blame output: %q", line)
+			}
+			id := api.CommitID(fields[0])
+			i, ok := index[id]
+			if !ok {
+				i = len(entry.Commits)
+				index[id] = i
+				entry.Commits = append(entry.Commits, blameCommit{ID: id})
+			}
+			orig, _ := strconv.Atoi(fields[1])
+			final, _ = strconv.Atoi(fields[2])
+			if final < 1 {
+				return nil, errors.Errorf("unexpected line in git blame output: %q", line)
+			}
+			cur = &blameLine{i, orig}
+
+		default:
+			// Commit information is only output for the first line of a commit.
+			c := &entry.Commits[cur[0]]
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "author":
+				c.Author.Name = value
+			case "author-mail":
+				c.Author.Email = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+			case "author-time":
+				t, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("failed to parse author-time %q", value)
+				}
+				c.Author.Date = time.Unix(t, 0).UTC()
+			case "summary":
+				c.Message = value
+			case "filename":
+				if c.Filename == "" {
+					c.Filename = value
+				}
+			}
+		}
+	}
+	return entry, nil
+}
+
+// merge sets the lines of e that are blamed in other.
+func (e *blameCacheEntry) merge(other *blameCacheEntry) {
+	index := make(map[api.CommitID]int, len(e.Commits))
+	for i, c := range e.Commits {
+		index[c.ID] = i
+	}
+	for i, line := range other.Lines {
+		if line[0] < 0 || i >= len(e.Lines) {
+			continue
+		}
+		c := other.Commits[line[0]]
+		j, ok := index[c.ID]
+		if !ok {
+			j = len(e.Commits)
+			index[c.ID] = j
+			e.Commits = append(e.Commits, c)
+		}
+		e.Lines[i] = blameLine{j, line[1], line[2]}
+	}
+}
+
+// compact removes the commits no line is attributed to.
+func (e *blameCacheEntry) compact() {
+	index := make(map[int]int, len(e.Commits))
+	var commits []blameCommit
+	for i, line := range e.Lines {
+		j, ok := index[line[0]]
+		if !ok {
+			j = len(commits)
+			index[line[0]] = j
+			commits = append(commits, e.Commits[line[0]])
+		}
+		e.Lines[i][0] = j
+	}
+	e.Commits = commits
+}
+
+// hunks returns the hunks of lines startLine to endLine of the file, or of the
+// whole file if both are 0. Like git blame, it reports an error if the range is
+// invalid.
+func (e *blameCacheEntry) hunks(path string, startLine, endLine int) ([]*protocol.BlameHunk, error) {
+	if startLine == 0 && endLine == 0 {
+		startLine, endLine = 1, len(e.Lines)
+	} else {
+		if startLine < 1 || endLine < 1 {
+			return nil, errors.Errorf("invalid line range %d,%d", startLine, endLine)
+		}
+		if startLine > endLine {
+			startLine, endLine = endLine, startLine
+		}
+		if startLine > len(e.Lines) {
+			return nil, errors.Errorf("file %s has only %d lines", path, len(e.Lines))
+		}
+		if endLine > len(e.Lines) {
+			endLine = len(e.Lines)
+		}
+	}
+
+	// Like git blame, consecutive lines are grouped into a hunk if they were
+	// consecutive in the commit they are attributed to.
+	var hunks []*protocol.BlameHunk
+	var hunk *protocol.BlameHunk
+	var prev blameLine
+	byteOffset := 0
+	for n := startLine; n <= endLine; n++ {
+		line := e.Lines[n-1]
+		if hunk != nil && line[0] == prev[0] && line[1] == prev[1]+1 {
+			hunk.EndLine++
+		} else {
+			c := e.Commits[line[0]]
+			hunk = &protocol.BlameHunk{
+				StartLine: n,
+				EndLine:   n + 1,
+				StartByte: byteOffset,
+				CommitID:  c.ID,
+				Author:    c.Author,
+				Message:   c.Message,
+				Filename:  c.Filename,
+			}
+			hunks = append(hunks, hunk)
+		}
+		byteOffset += line[2]
+		hunk.EndByte = byteOffset
+		prev = line
+	}
+	return hunks, nil
+}
+
+// valid reports whether every line of e is attributed to one of its commits.
+func (e *blameCacheEntry) valid() bool {
+	for _, line := range e.Lines {
+		if line[0] < 0 || line[0] >= len(e.Commits) {
+			return false
+		}
+	}
+	return true
+}
+
+func blameCachePath(dir GitDir, commit api.CommitID, path string) string {
+	key := sha256.Sum256([]byte(string(commit) + "\x00" + path))
+	name := hex.EncodeToString(key[:])
+	return dir.Path(blameCacheDirName, name[:2], name[2:])
+}
+
+// readBlameCache returns the cached blame of the file at path in commit.
+func readBlameCache(dir GitDir, commit api.CommitID, path string) (*blameCacheEntry, bool) {
+	p := blameCachePath(dir, commit, path)
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	var entry blameCacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil || !entry.valid() {
+		return nil, false
+	}
+
+	// The janitor evicts the cached blames that weren't read in a while.
+	now := time.Now()
+	_ = os.Chtimes(p, now, now)
+	return &entry, true
+}
+
+// writeBlameCache caches the blame of the file at path in commit.
+func writeBlameCache(dir GitDir, commit api.CommitID, path string, entry *blameCacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	p := blameCachePath(dir, commit, path)
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	// Concurrent readers only see complete entries, since renames are atomic.
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// evictBlameCache removes the cached blames of the repo at dir that weren't
+// read within blameCacheMaxAge, as well as the least recently read ones while
+// the cache is larger than blameCacheMaxSize. It returns the size of the
+// remaining cache.
+func evictBlameCache(dir GitDir) (int64, error) {
+	root := dir.Path(blameCacheDirName)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0, nil
+	}
+	if blameCacheMaxSize <= 0 {
+		return 0, os.RemoveAll(root)
+	}
+
+	var entries []fs.FileInfo
+	var paths []string
+	var multi error
+	err := bestEffortWalk(root, func(path string, fi fs.FileInfo) error {
+		if fi.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(fi.Name(), ".tmp-") {
+			// Left behind by an interrupted write.
+			if _, err := removeFileOlderThan(path, time.Hour); err != nil {
+				multi = errors.Append(multi, err)
+			}
+			return nil
+		}
+		entries = append(entries, fi)
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Keep the most recently read entries.
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return entries[order[a]].ModTime().After(entries[order[b]].ModTime())
+	})
+
+	maxBytes := int64(blameCacheMaxSize) * 1024 * 1024
+	var size int64
+	for _, i := range order {
+		fi := entries[i]
+		if time.Since(fi.ModTime()) <= blameCacheMaxAge && size+fi.Size() <= maxBytes {
+			size += fi.Size()
+			continue
+		}
+		if err := os.Remove(paths[i]); err != nil && !os.IsNotExist(err) {
+			multi = errors.Append(multi, err)
+			continue
+		}
+		blameCacheEvicted.Inc()
+	}
+	return size, multi
+}
+
+// atoiDefault parses s as an integer, or returns def if s is empty.
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
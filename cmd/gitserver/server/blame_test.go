@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestBlameCache(t *testing.T) {
+	ctx := context.Background()
+	remote := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", ".")
+	commit := func(script string) api.CommitID {
+		t.Helper()
+		cmd("sh", "-c", script)
+		cmd("git", "add", "-A")
+		cmd("git", "commit", "-m", script)
+		return api.CommitID(strings.TrimSpace(cmd("git", "rev-parse", "HEAD")))
+	}
+	commits := []api.CommitID{
+		commit(`printf 'a\nb\nc\n' > f`),
+		// Change a line and add one.
+		commit(`printf 'a\nb2\nc\nd\n' > f`),
+		// Add a line before all others and remove one.
+		commit(`printf 'x\na\nb2\nd\n' > f`),
+		// Leave f unchanged.
+		commit(`echo other > g`),
+		// Change whitespace only, which doesn't change the attribution, and
+		// remove the trailing newline.
+		commit(`printf 'x\n  a\nb2\nd' > f`),
+		commit(`printf '' > f`),
+		commit(`printf 'y\nz\n' > f`),
+	}
+
+	dir := GitDir(filepath.Join(remote, ".git"))
+	s := &Server{Logger: logtest.Scoped(t)}
+	run := func(args ...string) ([]byte, error) {
+		c := exec.Command("git", args...)
+		dir.Set(c)
+		return c.Output()
+	}
+
+	incremental := testutil.ToFloat64(blameCacheRequests.WithLabelValues("incremental"))
+	for _, c := range commits {
+		got, err := s.blame(ctx, "repo", dir, c, "f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := blameRanges(run, c, "f", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotHunks, _ := got.hunks("f", 0, 0)
+		wantHunks, _ := want.hunks("f", 0, 0)
+		if diff := cmp.Diff(wantHunks, gotHunks); diff != "" {
+			t.Errorf("unexpected hunks of %s (-want +got):\n%s", c, diff)
+		}
+	}
+	if n := testutil.ToFloat64(blameCacheRequests.WithLabelValues("incremental")) - incremental; n != float64(len(commits)-1) {
+		t.Errorf("got %v incremental blames, want %d", n, len(commits)-1)
+	}
+
+	hits := testutil.ToFloat64(blameCacheRequests.WithLabelValues("hit"))
+	entry, err := s.blame(ctx, "repo", dir, commits[2], "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if testutil.ToFloat64(blameCacheRequests.WithLabelValues("hit")) != hits+1 {
+		t.Error("expected cached blame to be read")
+	}
+
+	// Byte offsets start at the first line of the range. Lines of the same
+	// commit are in separate hunks if they weren't consecutive in the commit.
+	hunks, err := entry.hunks("f", 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type hunk struct {
+		commit             api.CommitID
+		startLine, endLine int
+		startByte, endByte int
+	}
+	var gotHunks []hunk
+	for _, h := range hunks {
+		gotHunks = append(gotHunks, hunk{h.CommitID, h.StartLine, h.EndLine, h.StartByte, h.EndByte})
+	}
+	wantHunks := []hunk{
+		{commits[0], 2, 3, 0, 2},
+		{commits[1], 3, 4, 2, 5},
+		{commits[1], 4, 5, 5, 7},
+	}
+	if diff := cmp.Diff(wantHunks, gotHunks, cmp.AllowUnexported(hunk{})); diff != "" {
+		t.Errorf("unexpected hunks (-want +got):\n%s", diff)
+	}
+	for _, r := range [][2]int{{0, 2}, {5, 6}} {
+		if _, err := entry.hunks("f", r[0], r[1]); err == nil {
+			t.Errorf("expected error for range %v", r)
+		}
+	}
+
+	if _, err := s.blame(ctx, "repo", dir, commits[len(commits)-1], "missing"); err == nil {
+		t.Error("expected error blaming missing file")
+	}
+}
+
+func TestEvictBlameCache(t *testing.T) {
+	defer func(size int) { blameCacheMaxSize = size }(blameCacheMaxSize)
+	blameCacheMaxSize = 1
+
+	dir := GitDir(t.TempDir())
+	// Entries of about 600KB, so that only one fits in the cache.
+	entry := &blameCacheEntry{Commits: []blameCommit{{ID: "a"}}}
+	for i := 0; i < 50000; i++ {
+		entry.Lines = append(entry.Lines, blameLine{0, 100000 + i, 100})
+	}
+	now := time.Now()
+	for commit, age := range map[api.CommitID]time.Duration{
+		"recent": time.Hour,
+		"older":  2 * time.Hour,
+		"old":    blameCacheMaxAge + time.Hour,
+	} {
+		if err := writeBlameCache(dir, commit, "f", entry); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(-age)
+		if err := os.Chtimes(blameCachePath(dir, commit, "f"), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size, err := evictBlameCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(blameCachePath(dir, "recent", "f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != fi.Size() {
+		t.Errorf("got size %d, want %d", size, fi.Size())
+	}
+	for _, commit := range []api.CommitID{"older", "old"} {
+		if _, ok := readBlameCache(dir, commit, "f"); ok {
+			t.Errorf("expected %s to be evicted", commit)
+		}
+	}
+
+	blameCacheMaxSize = 0
+	if _, err := evictBlameCache(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir.Path(blameCacheDirName)); !os.IsNotExist(err) {
+		t.Error("expected blame cache to be removed when it is disabled")
+	}
+}
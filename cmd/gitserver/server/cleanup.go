@@ -167,11 +167,13 @@ const reposStatsName = "repos-stats.json"
 // 5. Ensure gc.auto=0 or unset depending on gitGCMode
 // 6. Scrub remote URLs
 // 7. Perform garbage collection
-// 8. Re-clone repos after a while. (simulate git gc)
-// 9. Remove repos based on disk pressure.
-// 10. Perform sg-maintenance
-// 11. Git prune
-// 12. Only during first run: Set sizes of repos which don't have it in a database.
+// 8. Repair repos flagged as corrupt, quarantining and re-cloning them if needed.
+// 9. Re-clone repos after a while. (simulate git gc)
+// 10. Remove expired quarantined repos.
+// 11. Remove quarantined repos, then repos, based on disk pressure.
+// 12. Perform sg-maintenance
+// 13. Git prune
+// 14. Only during first run: Set sizes of repos which don't have it in a database.
 func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 	janitorRunning.Set(1)
 	janitorStart := time.Now()
@@ -217,19 +219,24 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 	}()
 
 	collectSizeAndMaybeDeleteWrongShardRepos := func(dir GitDir) (done bool, err error) {
-		size := dirSize(dir.Path("."))
+		size := gitDirSize(dir)
 		stats.GitDirBytes += size
+		if isPartialClone(dir) {
+			stats.PartialClones++
+			stats.PartialCloneGitDirBytes += size
+		}
 		name := s.name(dir)
 		repoToSize[name] = size
 
 		// Record the number and disk usage used of repos that should
 		// not belong on this instance and remove up to SRC_WRONG_SHARD_DELETE_LIMIT in a single Janitor run.
 		addr, err := s.addrForRepo(bCtx, name, gitServerAddrs)
-		if !s.hostnameMatch(addr) {
+		if _, secondary := s.secondaryOf(bCtx, name, gitServerAddrs); !s.hostnameMatch(addr) && !secondary {
 			wrongShardRepoCount++
 			wrongShardRepoSize += size
 
-			if knownGitServerShard && wrongShardReposDeleteLimit > 0 && wrongShardReposDeleted < int64(wrongShardReposDeleteLimit) {
+			// The rebalancer deletes them once they are moved to their shard.
+			if knownGitServerShard && !s.rebalancingEnabled() && wrongShardReposDeleteLimit > 0 && wrongShardReposDeleted < int64(wrongShardReposDeleteLimit) {
 				s.Logger.Info(
 					"removing repo cloned on the wrong shard",
 					log.String("dir", string(dir)),
@@ -307,6 +314,10 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 
 	scrubRemoteURL := func(dir GitDir) (done bool, err error) {
 		cmd := exec.Command("git", "remote", "remove", "origin")
+		if isPartialClone(dir) {
+			// origin is the promisor remote of partial clones, which we keep.
+			cmd = exec.Command("git", "config", "--unset", "remote.origin.url")
+		}
 		dir.Set(cmd)
 		// ignore error since we fail if the remote has already been scrubbed.
 		_ = cmd.Run()
@@ -326,12 +337,6 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 
 		// Add a jitter to spread out re-cloning of repos cloned at the same time.
 		var reason string
-		const maybeCorrupt = "maybeCorrupt"
-		if maybeCorrupt, _ := gitConfigGet(dir, gitConfigMaybeCorrupt); maybeCorrupt != "" {
-			reason = maybeCorrupt
-			// unset flag to stop constantly re-cloning if it fails.
-			_ = gitConfigUnset(dir, gitConfigMaybeCorrupt)
-		}
 		if time.Since(recloneTime) > repoTTL+jitterDuration(string(dir), repoTTL/4) {
 			reason = "old"
 		}
@@ -347,10 +352,11 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 			}
 		}
 
-		// We believe converting a Perforce depot to a Git repository is generally a
-		// very expensive operation, therefore we do not try to re-clone/redo the
-		// conversion only because it is old or slow to do "git gc".
-		if repoType == "perforce" && reason != maybeCorrupt {
+		// We believe converting a Perforce depot or Subversion repository to a Git
+		// repository is generally a very expensive operation, therefore we do not
+		// try to re-clone/redo the conversion only because it is old or slow to do
+		// "git gc".
+		if repoType == "perforce" || repoType == "subversion" {
 			reason = ""
 		}
 
@@ -385,6 +391,20 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		return true, nil
 	}
 
+	maybeRepairCorrupt := func(dir GitDir) (done bool, err error) {
+		reason, _ := gitConfigGet(dir, gitConfigMaybeCorrupt)
+		if reason == "" {
+			return false, nil
+		}
+		// unset flag to stop constantly repairing if it fails.
+		_ = gitConfigUnset(dir, gitConfigMaybeCorrupt)
+
+		ctx, cancel := context.WithTimeout(bCtx, conf.GitLongCommandTimeout())
+		defer cancel()
+
+		return s.repairCorruptRepo(ctx, s.name(dir), dir, reason)
+	}
+
 	removeStaleLocks := func(gitDir GitDir) (done bool, err error) {
 		// if removing a lock fails, we still want to try the other locks.
 		var multi error
@@ -437,6 +457,17 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		return false, multi
 	}
 
+	var blameCacheBytes int64
+	defer func() {
+		// We want to set the gauge only at the end when we know the total
+		blameCacheSize.Set(float64(blameCacheBytes))
+	}()
+	evictBlameCaches := func(dir GitDir) (done bool, err error) {
+		size, err := evictBlameCache(dir)
+		blameCacheBytes += size
+		return false, err
+	}
+
 	performGC := func(dir GitDir) (done bool, err error) {
 		return false, gitGC(dir)
 	}
@@ -473,6 +504,9 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		// happen if several git-gc operations are running at the same time.
 		// We only disable if sg is managing gc.
 		{"auto gc config", ensureAutoGC},
+		// Remove cached blames that weren't read in a while and keep the blame
+		// cache below its maximum size.
+		{"evict blame cache", evictBlameCaches},
 	}
 
 	if gitGCMode == gitGCModeJanitorAutoGC {
@@ -498,6 +532,12 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		// slow and resource intensive. It is cheaper and faster to just re-clone the
 		// repository. We don't do this if DisableAutoGitUpdates is set as it could
 		// potentially kick off a clone operation.
+		//
+		// Repos flagged as corrupt are repaired first, which may re-clone them.
+		cleanups = append(cleanups, cleanupFn{
+			Name: "maybe repair corrupt",
+			Do:   maybeRepairCorrupt,
+		})
 		cleanups = append(cleanups, cleanupFn{
 			Name: "maybe re-clone",
 			Do:   maybeReclone,
@@ -548,6 +588,10 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		cleanupLogger.Error("setting repo sizes", log.Error(err))
 	}
 
+	if err := s.cleanupQuarantine(bCtx); err != nil {
+		cleanupLogger.Error("error removing expired quarantined repos", log.Error(err))
+	}
+
 	if s.DiskSizer == nil {
 		s.DiskSizer = &StatDiskSizer{}
 	}
@@ -555,7 +599,11 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 	if err != nil {
 		cleanupLogger.Error("ensuring free disk space", log.Error(err))
 	}
-	if err := s.freeUpSpace(b); err != nil {
+	freed, err := s.freeUpQuarantine(bCtx, b)
+	if err != nil {
+		cleanupLogger.Error("error removing quarantined repos", log.Error(err))
+	}
+	if err := s.freeUpSpace(b - freed); err != nil {
 		cleanupLogger.Error("error freeing up space", log.Error(err))
 	}
 }
@@ -797,6 +845,25 @@ func dirSize(d string) int64 {
 	return size
 }
 
+// gitDirSize returns the total size in bytes of all the files of the repo at
+// dir. The blame cache isn't part of the repo: its size is reported and
+// limited on its own.
+func gitDirSize(dir GitDir) int64 {
+	blameCache := dir.Path(blameCacheDirName)
+	var size int64
+	_ = bestEffortWalk(dir.Path("."), func(path string, fi fs.FileInfo) error {
+		if fi.IsDir() {
+			if path == blameCache {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		size += fi.Size()
+		return nil
+	})
+	return size
+}
+
 // removeRepoDirectory atomically removes a directory from s.ReposDir.
 //
 // It first moves the directory to a temporary location to avoid leaving
@@ -1023,11 +1090,13 @@ func checkMaybeCorruptRepo(repo api.RepoName, dir GitDir, stderr string) {
 
 	logger := log.Scoped("checkMaybeCorruptRepo", "check if repo is corrupt").With(log.String("repo", string(repo)))
 
-	logger.Warn("marking repo for re-cloning due to stderr output indicating repo corruption", log.String("stderr", stderr))
+	logger.Warn("marking repo for repair due to stderr output indicating repo corruption", log.String("stderr", stderr))
 
 	// We set a flag in the config for the cleanup janitor job to fix. The janitor
-	// runs every minute.
-	err := gitConfigSet(dir, gitConfigMaybeCorrupt, strconv.FormatInt(time.Now().Unix(), 10))
+	// runs every minute. The flag holds the first line of stderr, which is
+	// recorded as the reason in the corruption logs of the repo.
+	reason, _, _ := strings.Cut(strings.TrimSpace(stderr), "\n")
+	err := gitConfigSet(dir, gitConfigMaybeCorrupt, reason)
 	if err != nil {
 		logger.Error("failed to set maybeCorruptRepo config", log.Error(err))
 	}
@@ -1180,6 +1249,16 @@ func sgMaintenance(logger log.Logger, dir GitDir) (err error) {
 		logger.Debug("sg maintenance", log.String("dir", string(dir)), log.String("out", string(b)))
 		return errors.Wrapf(wrapCmdError(cmd, err), "failed to run sg maintenance")
 	}
+
+	// We write the commit-graph instead of the script, so that it is written
+	// with the same options as the layers added after fetches.
+	if err := writeCommitGraph(context.Background(), dir, true); err != nil {
+		if err := writeSGMLog(dir, []byte(err.Error())); err != nil {
+			logger.Debug("sg maintenance failed to write log file", log.String("file", dir.Path(sgmLog)), log.Error(err))
+		}
+		return errors.Wrap(err, "failed to run sg maintenance")
+	}
+
 	// Remove the log file after a successful run.
 	_ = os.Remove(dir.Path(sgmLog))
 	return nil
@@ -1250,13 +1329,16 @@ func pruneIfNeeded(dir GitDir, limit int) (err error) {
 
 func needsMaintenance(dir GitDir) (bool, string, error) {
 	// Bitmaps store reachability information about the set of objects in a
-	// packfile which speeds up clone and fetch operations.
-	hasBm, err := hasBitmap(dir)
-	if err != nil {
-		return false, "", err
-	}
-	if !hasBm {
-		return true, "bitmap", nil
+	// packfile which speeds up clone and fetch operations. git doesn't write
+	// bitmaps for partial clones, because objects are missing from their packs.
+	if !isPartialClone(dir) {
+		hasBm, err := hasBitmap(dir)
+		if err != nil {
+			return false, "", err
+		}
+		if !hasBm {
+			return true, "bitmap", nil
+		}
 	}
 
 	// The commit-graph file is a supplemental data structure that accelerates
@@ -1289,18 +1371,28 @@ func needsMaintenance(dir GitDir) (bool, string, error) {
 
 var reHexadecimal = lazyregexp.New("^[0-9a-f]+$")
 
-// tooManyLooseObjects follows Git's approach of estimating the number of
+// tooManyLooseObjects reports whether the estimated number of loose objects of
+// the repo at dir exceeds limit.
+func tooManyLooseObjects(dir GitDir, limit int) (bool, error) {
+	count, err := estimateLooseObjects(dir)
+	if err != nil {
+		return false, err
+	}
+	return count > limit, nil
+}
+
+// estimateLooseObjects follows Git's approach of estimating the number of
 // loose objects by counting the objects in a sentinel folder and extrapolating
 // based on the assumption that loose objects are randomly distributed in the
 // 256 possible folders.
-func tooManyLooseObjects(dir GitDir, limit int) (bool, error) {
+func estimateLooseObjects(dir GitDir) (int, error) {
 	// We use the same folder git uses to estimate the number of loose objects.
 	objs, err := os.ReadDir(filepath.Join(dir.Path(), "objects", "17"))
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			return false, nil
+			return 0, nil
 		}
-		return false, errors.Wrap(err, "tooManyLooseObjects")
+		return 0, errors.Wrap(err, "estimateLooseObjects")
 	}
 
 	count := 0
@@ -1317,7 +1409,7 @@ func tooManyLooseObjects(dir GitDir, limit int) (bool, error) {
 		}
 		count++
 	}
-	return count*256 > limit, nil
+	return count * 256, nil
 }
 
 func hasBitmap(dir GitDir) (bool, error) {
@@ -1329,13 +1421,18 @@ func hasBitmap(dir GitDir) (bool, error) {
 }
 
 func hasCommitGraph(dir GitDir) (bool, error) {
-	if _, err := os.Stat(dir.Path("objects", "info", "commit-graph")); err == nil {
-		return true, nil
-	} else if errors.Is(err, fs.ErrNotExist) {
-		return false, nil
-	} else {
-		return false, err
+	// The commit-graph is either a single file or a chain of layers.
+	for _, p := range []string{
+		dir.Path("objects", "info", "commit-graph"),
+		dir.Path("objects", "info", "commit-graphs", "commit-graph-chain"),
+	} {
+		if _, err := os.Stat(p); err == nil {
+			return true, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
 	}
+	return false, nil
 }
 
 // tooManyPackfiles counts the packfiles in objects/pack. Packfiles with an
@@ -369,7 +369,7 @@ func TestCleanupExpired(t *testing.T) {
 		repoOld:           2 * repoTTL,
 		repoGCOld:         2 * repoTTLGC,
 		repoBoom:          2 * repoTTL,
-		repoCorrupt:       repoTTLGC / 2, // should only trigger corrupt, not old
+		repoCorrupt:       repoTTLGC / 2, // should only trigger repair, not old
 		repoPerforce:      2 * repoTTL,
 		repoPerforceGCOld: 2 * repoTTLGC,
 	} {
@@ -381,7 +381,7 @@ func TestCleanupExpired(t *testing.T) {
 			t.Fatal(err)
 		}
 	}
-	if err := gitConfigSet(GitDir(repoCorrupt), gitConfigMaybeCorrupt, "1"); err != nil {
+	if err := gitConfigSet(GitDir(repoCorrupt), gitConfigMaybeCorrupt, "error: packfile"); err != nil {
 		t.Fatal(err)
 	}
 	if err := setRepositoryType(GitDir(repoPerforce), "perforce"); err != nil {
@@ -399,7 +399,7 @@ func TestCleanupExpired(t *testing.T) {
 	repoOldTime := modTime(repoOld)
 	repoGCNewTime := modTime(repoGCNew)
 	repoGCOldTime := modTime(repoGCOld)
-	repoCorruptTime := modTime(repoBoom)
+	repoCorruptTime := modTime(repoCorrupt)
 	repoPerforceTime := modTime(repoPerforce)
 	repoPerforceGCOldTime := modTime(repoPerforceGCOld)
 	repoBoomTime := modTime(repoBoom)
@@ -434,6 +434,13 @@ func TestCleanupExpired(t *testing.T) {
 	if repoPerforceGCOldTime.Before(modTime(repoPerforceGCOld)) {
 		t.Error("expected repoPerforceGCOld to not be modified")
 	}
+	// git fsck finds no corruption in repoCorrupt, so it is only unflagged.
+	if repoCorruptTime.Before(modTime(repoCorrupt)) {
+		t.Error("expected repoCorrupt to not be modified")
+	}
+	if v, _ := gitConfigGet(GitDir(repoCorrupt), gitConfigMaybeCorrupt); v != "" {
+		t.Errorf("expected repoCorrupt to be unflagged, got %q", v)
+	}
 
 	// repos that should be recloned
 	if !repoOldTime.Before(modTime(repoOld)) {
@@ -442,9 +449,6 @@ func TestCleanupExpired(t *testing.T) {
 	if !repoGCOldTime.Before(modTime(repoGCOld)) {
 		t.Error("expected repoGCOld to be recloned during clean up")
 	}
-	if !repoCorruptTime.Before(modTime(repoCorrupt)) {
-		t.Error("expected repoCorrupt to be recloned during clean up")
-	}
 
 	// repos that fail to clone need to have recloneTime updated
 	if repoBoomTime.Before(modTime(repoBoom)) {
@@ -1307,6 +1311,21 @@ func TestTooManyLooseObjects(t *testing.T) {
 	}
 }
 
+func TestGitDirSize(t *testing.T) {
+	gitDir := prepareEmptyGitRepo(t, t.TempDir())
+	want := dirSize(gitDir.Path("."))
+
+	if err := writeBlameCache(gitDir, "commit", "f", &blameCacheEntry{Commits: []blameCommit{{ID: "a"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if dirSize(gitDir.Path(".")) == want {
+		t.Fatal("expected the blame cache to take up space")
+	}
+	if got := gitDirSize(gitDir); got != want {
+		t.Errorf("got size %d, want %d without the blame cache", got, want)
+	}
+}
+
 func TestTooManyLooseObjectsMissingSentinelDir(t *testing.T) {
 	dir := t.TempDir()
 	gitDir := prepareEmptyGitRepo(t, dir)
@@ -3,6 +3,8 @@ package server
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/sourcegraph/log"
 
@@ -43,3 +45,63 @@ func handleGetObject(getObject gitdomain.GetObjectFunc) func(w http.ResponseWrit
 		}
 	}
 }
+
+// commandRequests are the constructors of the requests of the typed command
+// API, whose commands are served at /commands/<name>.
+var commandRequests = []func() protocol.CommandRequest{
+	func() protocol.CommandRequest { return &protocol.LogRequest{} },
+	func() protocol.CommandRequest { return &protocol.DiffRequest{} },
+	func() protocol.CommandRequest { return &protocol.ShowRequest{} },
+	func() protocol.CommandRequest { return &protocol.LsTreeRequest{} },
+	func() protocol.CommandRequest { return &protocol.RevParseRequest{} },
+}
+
+// commandLimits are the limits of the commands of the typed command API.
+// Commands walking the history of a repository take longer and write more than
+// those reading single objects.
+var commandLimits = map[string]execLimits{
+	"log":       {timeout: 2 * time.Minute, maxOutputSize: 512 * 1024 * 1024},
+	"diff":      {timeout: 2 * time.Minute, maxOutputSize: 512 * 1024 * 1024},
+	"show":      {timeout: time.Minute, maxOutputSize: 256 * 1024 * 1024},
+	"ls-tree":   {timeout: time.Minute, maxOutputSize: 256 * 1024 * 1024},
+	"rev-parse": {timeout: 10 * time.Second, maxOutputSize: 1024 * 1024},
+}
+
+// handleCommand serves a command of the typed command API. The response is
+// streamed like the response of /exec, and the command is stopped when the
+// client cancels the request.
+func (s *Server) handleCommand(newReq func() protocol.CommandRequest) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🚨 SECURITY: Only allow POST requests.
+		if r.Method != http.MethodPost {
+			http.Error(w, "", http.StatusMethodNotAllowed)
+			return
+		}
+
+		req := newReq()
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: The arguments are built from the fields of the
+		// request, so only the options it supports can be passed to git.
+		args, err := req.Args()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		opts := req.Options()
+		accesslog.Record(r.Context(), string(opts.Repo), map[string]string{
+			"cmd":  args[0],
+			"args": strings.Join(args[1:], " "),
+		})
+
+		s.runExec(w, r, &protocol.ExecRequest{
+			Repo:           opts.Repo,
+			EnsureRevision: opts.EnsureRevision,
+			Args:           args,
+		}, commandLimits[req.Command()])
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The commit-graph speeds up walks of the commit graph, and its changed-path
+// Bloom filters speed up walks limited to paths, like git log -- <path>. We
+// write a split commit-graph: after each fetch, the new commits are written to
+// a new layer, which git merges with the layers below once they are smaller.
+// sg maintenance replaces all layers with a single one.
+
+var commitGraphAfterFetch, _ = strconv.ParseBool(env.Get("SRC_COMMIT_GRAPH_AFTER_FETCH", "true", "add the fetched commits to the commit-graph of a repository after each fetch"))
+
+var (
+	commitGraphWrites = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_commit_graph_writes_total",
+		Help: "number of commit-graph writes, either incremental after a fetch or replacing all layers during maintenance",
+	}, []string{"mode", "success"})
+	commitGraphWriteDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_commit_graph_write_duration_seconds",
+		Help:    "time taken to write a commit-graph",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600},
+	}, []string{"mode"})
+)
+
+// writeCommitGraph writes the commits of the repo at dir that aren't in its
+// commit-graph yet to a new layer. If replace is true, all layers are replaced
+// by a single one instead.
+func writeCommitGraph(ctx context.Context, dir GitDir, replace bool) (err error) {
+	mode, split := "incremental", "--split"
+	if replace {
+		mode, split = "replace", "--split=replace"
+	}
+	start := time.Now()
+	defer func() {
+		commitGraphWrites.WithLabelValues(mode, strconv.FormatBool(err == nil)).Inc()
+		commitGraphWriteDuration.WithLabelValues(mode).Observe(time.Since(start).Seconds())
+	}()
+
+	args := []string{"commit-graph", "write", "--reachable", split, "--no-progress"}
+	// Computing changed paths reads the trees of all new commits, which
+	// partial clones that omit trees would fetch from the code host.
+	if filter, _ := gitConfigGet(dir, "remote.origin.partialclonefilter"); !strings.HasPrefix(filter, "tree:") {
+		args = append(args, "--changed-paths")
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	if _, err := cmd.Output(); err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "failed to write commit-graph")
+	}
+	return nil
+}
+
+// updateCommitGraph adds the commits fetched into the repo at dir to its
+// commit-graph. The first commit-graph of a repo is written by sg maintenance,
+// since writing it can take a while for large repos.
+func (s *Server) updateCommitGraph(ctx context.Context, repo api.RepoName, dir GitDir) {
+	if hasCg, err := hasCommitGraph(dir); err != nil || !hasCg {
+		return
+	}
+
+	// Skip the update while the repo is maintained, which rewrites the
+	// commit-graph anyway.
+	err, unlock := lockRepoForGC(dir)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	if err := writeCommitGraph(ctx, dir, false); err != nil {
+		s.Logger.Warn("failed to update commit-graph", log.String("repo", string(repo)), log.Error(err))
+	}
+}
+
+// commitGraphLayers returns the paths of the commit-graph files of the repo
+// at dir, from the base layer to the top layer.
+func commitGraphLayers(dir GitDir) ([]string, error) {
+	f, err := os.Open(dir.Path("objects", "info", "commit-graphs", "commit-graph-chain"))
+	if os.IsNotExist(err) {
+		if hasCg, err := hasCommitGraph(dir); err != nil || !hasCg {
+			return nil, err
+		}
+		return []string{dir.Path("objects", "info", "commit-graph")}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var layers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if hash := strings.TrimSpace(scanner.Text()); hash != "" {
+			layers = append(layers, dir.Path("objects", "info", "commit-graphs", "graph-"+hash+".graph"))
+		}
+	}
+	return layers, scanner.Err()
+}
+
+// commitGraphChunks returns the IDs of the chunks of the commit-graph file at
+// path, for example "BDAT" for changed-path Bloom filters.
+func commitGraphChunks(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The header is the signature "CGPH", the version, the hash version, the
+	// number of chunks and the number of base commit-graphs. It is followed by
+	// the chunk table, which has an ID and an offset of 12 bytes in total for
+	// each chunk.
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "CGPH" {
+		return nil, errors.Errorf("%s is not a commit-graph file", path)
+	}
+	table := make([]byte, 12*int(header[6]))
+	if _, err := io.ReadFull(f, table); err != nil {
+		return nil, err
+	}
+
+	chunks := make([]string, 0, header[6])
+	for i := 0; i < len(table); i += 12 {
+		chunks = append(chunks, string(table[i:i+4]))
+	}
+	return chunks, nil
+}
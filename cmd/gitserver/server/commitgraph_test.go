@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+)
+
+func TestWriteCommitGraph(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote")
+	if err := os.MkdirAll(remote, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", ".")
+	commit := func(name string) {
+		t.Helper()
+		cmd("sh", "-c", "echo "+name+" > "+name)
+		cmd("git", "add", "-A")
+		cmd("git", "commit", "-m", name)
+	}
+	commit("a")
+
+	reposDir := filepath.Join(root, "repos")
+	runCmd(t, root, "git", "clone", "--mirror", remote, filepath.Join(reposDir, "repo", ".git"))
+	s := &Server{Logger: logtest.Scoped(t), ReposDir: reposDir}
+	dir := s.dir("repo")
+	fetch := func() {
+		t.Helper()
+		runCmd(t, string(dir), "git", "fetch", "origin", "+refs/heads/*:refs/heads/*")
+	}
+	layers := func() []string {
+		t.Helper()
+		layers, err := commitGraphLayers(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return layers
+	}
+
+	// Repos without a commit-graph get one during maintenance.
+	for _, name := range []string{"b", "c", "d", "e", "f", "g", "h", "i"} {
+		commit(name)
+	}
+	fetch()
+	s.updateCommitGraph(ctx, "repo", dir)
+	if got := layers(); len(got) != 0 {
+		t.Fatalf("expected no commit-graph, got %v", got)
+	}
+
+	if err := writeCommitGraph(ctx, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := layers(); len(got) != 1 {
+		t.Fatalf("got %d layers, want 1", len(got))
+	}
+
+	// Each fetch adds a layer on top. git merges a new layer into the one below
+	// unless that one has more than twice as many commits.
+	commit("j")
+	fetch()
+	s.updateCommitGraph(ctx, "repo", dir)
+	if got := layers(); len(got) != 2 {
+		t.Fatalf("got %d layers, want 2", len(got))
+	}
+	for _, layer := range layers() {
+		chunks, err := commitGraphChunks(layer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !containsString(chunks, "BDAT") {
+			t.Errorf("expected changed-path Bloom filters in %s, got chunks %v", layer, chunks)
+		}
+	}
+
+	status, err := repoMaintenanceStatus(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Cloned || !status.CommitGraph || status.CommitGraphLayers != 2 || !status.ChangedPaths {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if err := writeCommitGraph(ctx, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := layers(); len(got) != 1 {
+		t.Fatalf("got %d layers after replacing them, want 1", len(got))
+	}
+
+	if status, err := repoMaintenanceStatus(s.dir("missing")); err != nil || status.Cloned {
+		t.Errorf("unexpected status of missing repo %+v: %v", status, err)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,384 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (s *Server) handleCreateCommitFromEdits(w http.ResponseWriter, r *http.Request) {
+	var req protocol.CreateCommitFromEditsRequest
+	var resp protocol.CreateCommitFromEditsResponse
+	var status int
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.SetError("", "", "", errors.Wrap(err, "decoding CreateCommitFromEditsRequest"))
+		status = http.StatusBadRequest
+	} else {
+		status, resp = s.createCommitFromEdits(r.Context(), req)
+	}
+
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// createCommitFromEdits creates a commit from a list of file edits. Unlike
+// createCommitFromPatch, it doesn't need a working copy: the tree of the commit
+// is built with git plumbing in a temporary index.
+func (s *Server) createCommitFromEdits(ctx context.Context, req protocol.CreateCommitFromEditsRequest) (int, protocol.CreateCommitFromEditsResponse) {
+	var resp protocol.CreateCommitFromEditsResponse
+
+	repo := protocol.NormalizeRepo(req.Repo)
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		resp.SetError(string(repo), "", "", errors.New("gitserver: repo does not exist"))
+		return http.StatusNotFound, resp
+	}
+
+	if err := validateFileEdits(req.Edits); err != nil {
+		resp.SetError(string(repo), "", "", err)
+		return http.StatusBadRequest, resp
+	}
+
+	ref := req.TargetRef
+	if !strings.HasPrefix(ref, "refs/") {
+		ref = ensureRefPrefix(ref)
+	}
+
+	var remoteURL *vcs.URL
+	if req.Push != nil || req.UniqueRef {
+		var err error
+		if req.Push != nil && req.Push.RemoteURL != "" {
+			remoteURL, err = vcs.ParseURL(req.Push.RemoteURL)
+		} else {
+			remoteURL, err = s.getRemoteURL(ctx, req.Repo)
+		}
+		if err != nil {
+			s.Logger.Error("Failed to get remote URL", log.String("ref", ref), log.Error(err))
+			resp.SetError(string(repo), "", "", errors.Wrap(err, "repoRemoteURL"))
+			return http.StatusInternalServerError, resp
+		}
+
+		redactor := newURLRedactor(remoteURL)
+		defer func() {
+			if resp.Error != nil {
+				resp.Error.Command = redactor.redact(resp.Error.Command)
+				resp.Error.CombinedOutput = redactor.redact(resp.Error.CombinedOutput)
+				if resp.Error.InternalError != "" {
+					resp.Error.InternalError = redactor.redact(resp.Error.InternalError)
+				}
+			}
+		}()
+	}
+
+	if req.UniqueRef {
+		var err error
+		if ref, err = uniqueRef(ctx, remoteURL, ref); err != nil {
+			s.Logger.Error("Failed to get remote refs", log.String("ref", ref), log.Error(err))
+			resp.SetError(string(repo), "", "", errors.Wrap(err, "repoRemoteRefs"))
+			return http.StatusInternalServerError, resp
+		}
+	}
+
+	tmpDir, err := s.tempDir("edits-")
+	if err != nil {
+		resp.SetError(string(repo), "", "", errors.Wrap(err, "gitserver: make tmp dir"))
+		return http.StatusInternalServerError, resp
+	}
+	defer cleanUpTmpRepo(tmpDir)
+	indexFile := filepath.Join(tmpDir, "index")
+	lazyFetchEnv, redactor := s.lazyFetchEnv(ctx, repo, dir)
+
+	git := func(stdin []byte, args ...string) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		dir.Set(cmd)
+		cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+indexFile, "GIT_LITERAL_PATHSPECS=1")
+		withLazyFetchEnv(cmd, lazyFetchEnv)
+		if stdin != nil {
+			cmd.Stdin = bytes.NewReader(stdin)
+		}
+		return cmd
+	}
+	// run returns the trimmed stdout of cmd.
+	run := func(cmd *exec.Cmd, reason string) (string, error) {
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			resp.SetError(string(repo), strings.Join(cmd.Args, " "), redactor.redact(string(out)+stderr.String()), errors.Wrap(err, "gitserver: "+reason))
+			return "", err
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	base := string(req.BaseCommit)
+	s.ensureRevision(ctx, repo, base, dir)
+	if _, err := run(git(nil, "rev-parse", "--verify", "--quiet", base+"^{commit}"), "resolving base commit"); err != nil {
+		return http.StatusBadRequest, resp
+	}
+
+	// If the target ref moved since the base commit, we apply the edits on top
+	// of it, unless they touch files that changed in the meantime.
+	parent := base
+	current, _ := git(nil, "rev-parse", "--verify", "--quiet", ref).Output()
+	local := strings.TrimSpace(string(current))
+	target := local
+	if req.Push != nil {
+		// Our clone may be behind the code host, which is where the ref is
+		// updated, so that's where we look it up.
+		refs, err := repoRemoteRefs(ctx, remoteURL, ref)
+		if err != nil {
+			s.Logger.Error("Failed to get remote refs", log.String("ref", ref), log.Error(err))
+			resp.SetError(string(repo), "", "", errors.Wrap(err, "repoRemoteRefs"))
+			return http.StatusInternalServerError, resp
+		}
+		// repoRemoteRefs strips the refs/<type>/ prefix.
+		target = ""
+		if parts := strings.SplitN(ref, "/", 3); len(parts) == 3 {
+			target = refs[parts[2]]
+		}
+		if target != "" {
+			s.ensureRevision(ctx, repo, target, dir)
+		}
+	}
+	if target != "" && target != base {
+		changed, err := run(git(nil, "diff", "--name-only", "-z", "--no-renames", base, target), "listing changed files")
+		if err != nil {
+			return http.StatusInternalServerError, resp
+		}
+		if conflicts := conflictingPaths(req.Edits, strings.Split(changed, "\x00")); len(conflicts) > 0 {
+			resp.SetError(string(repo), "", "", errors.Errorf("edits conflict with changes to %s since %s", ref, base))
+			resp.Error.ConflictingPaths = conflicts
+			return http.StatusConflict, resp
+		}
+		parent = target
+	}
+
+	if _, err := run(git(nil, "read-tree", parent), "reading parent tree"); err != nil {
+		return http.StatusInternalServerError, resp
+	}
+
+	// entry returns the mode and object ID of the file at p in the index.
+	entry := func(p string) (mode, sha string, ok bool, err error) {
+		out, err := run(git(nil, "ls-files", "--stage", "-z", "--", p), "reading index")
+		if err != nil {
+			return "", "", false, err
+		}
+		for _, line := range strings.Split(out, "\x00") {
+			// <mode> SP <object> SP <stage> TAB <path>
+			info, file, found := strings.Cut(line, "\t")
+			fields := strings.Fields(info)
+			if found && file == p && len(fields) == 3 {
+				return fields[0], fields[1], true, nil
+			}
+		}
+		return "", "", false, nil
+	}
+	add := func(mode, sha, p string) error {
+		_, err := run(git(nil, "update-index", "--add", "--cacheinfo", mode+","+sha+","+p), "adding file to index")
+		return err
+	}
+	remove := func(p string) error {
+		// Entries with mode 0 are removed. Unlike --force-remove, this doesn't
+		// need a work tree.
+		info := []byte("0 " + strings.Repeat("0", 40) + "\t" + p + "\x00")
+		_, err := run(git(info, "update-index", "-z", "--index-info"), "removing file from index")
+		return err
+	}
+
+	for _, edit := range req.Edits {
+		mode, sha, exists, err := entry(edit.Path)
+		if err != nil {
+			return http.StatusInternalServerError, resp
+		}
+		if !exists && edit.Operation != protocol.FileEditWrite {
+			resp.SetError(string(repo), "", "", errors.Errorf("cannot %s %q: file does not exist", edit.Operation, edit.Path))
+			return http.StatusBadRequest, resp
+		}
+
+		switch edit.Operation {
+		case protocol.FileEditWrite:
+			if !exists {
+				mode = "100644"
+			}
+			if sha, err = run(git(edit.Content, "hash-object", "-w", "--stdin"), "writing file"); err != nil {
+				return http.StatusInternalServerError, resp
+			}
+			err = add(mode, sha, edit.Path)
+
+		case protocol.FileEditDelete:
+			err = remove(edit.Path)
+
+		case protocol.FileEditRename:
+			_, _, newExists, entryErr := entry(edit.NewPath)
+			if entryErr != nil {
+				return http.StatusInternalServerError, resp
+			}
+			if newExists {
+				resp.SetError(string(repo), "", "", errors.Errorf("cannot rename %q to %q: file exists", edit.Path, edit.NewPath))
+				return http.StatusBadRequest, resp
+			}
+			if err = remove(edit.Path); err == nil {
+				err = add(mode, sha, edit.NewPath)
+			}
+
+		case protocol.FileEditChmod:
+			if mode != "100644" && mode != "100755" {
+				resp.SetError(string(repo), "", "", errors.Errorf("cannot chmod %q: not a regular file", edit.Path))
+				return http.StatusBadRequest, resp
+			}
+			mode = "100644"
+			if edit.Executable {
+				mode = "100755"
+			}
+			err = add(mode, sha, edit.Path)
+		}
+		if err != nil {
+			// Invalid edits, such as writing a file below another file, are
+			// rejected by git.
+			return http.StatusBadRequest, resp
+		}
+	}
+
+	tree, err := run(git(nil, "write-tree"), "writing tree")
+	if err != nil {
+		return http.StatusInternalServerError, resp
+	}
+
+	message := req.CommitInfo.Message
+	if message == "" {
+		message = "<Sourcegraph> Creating commit from edits"
+	}
+	cmd := git(nil, "commit-tree", tree, "-p", parent, "-m", message)
+	cmd.Env = append(cmd.Env, commitEnv(req.CommitInfo)...)
+	commit, err := run(cmd, "committing edits")
+	if err != nil {
+		return http.StatusInternalServerError, resp
+	}
+
+	// Updating the ref fails if it moved while we created the commit. We do
+	// this before pushing, so that nothing reaches the code host for a request
+	// we report as failed.
+	if _, err := run(git(nil, "update-ref", "--", ref, commit, local), "creating ref"); err != nil {
+		s.Logger.Error("Failed to create ref for commit.", log.String("ref", ref), log.String("commit", commit))
+		return http.StatusConflict, resp
+	}
+
+	if req.Push != nil {
+		// The push is rejected if the ref on the code host isn't where we
+		// expect it to be, which is a conflict.
+		cmd, closeAgent, err := pushCommand(ctx, string(dir), remoteURL, req.Push, fmt.Sprintf("%s:%s", commit, ref), fmt.Sprintf("--force-with-lease=%s:%s", ref, target))
+		if err != nil {
+			resp.SetError(string(repo), "", "", err)
+			s.restoreRef(ctx, dir, ref, commit, local)
+			return http.StatusInternalServerError, resp
+		}
+		defer closeAgent()
+
+		if out, err := runWith(ctx, cmd, true, nil); err != nil {
+			resp.SetError(string(repo), strings.Join(cmd.Args, " "), string(out), errors.Wrap(err, "gitserver: pushing ref"))
+			s.Logger.Error("Failed to push", log.String("ref", ref), log.String("commit", commit), log.String("output", string(out)))
+			s.restoreRef(ctx, dir, ref, commit, local)
+			if bytes.Contains(out, []byte("stale info")) {
+				return http.StatusConflict, resp
+			}
+			return http.StatusInternalServerError, resp
+		}
+	}
+
+	resp.Rev = ref
+	resp.Commit = api.CommitID(commit)
+	return http.StatusOK, resp
+}
+
+// restoreRef points ref back at old after a failed push, or deletes it if it
+// didn't exist. It leaves ref alone if it no longer points at commit.
+func (s *Server) restoreRef(ctx context.Context, dir GitDir, ref, commit, old string) {
+	args := []string{"update-ref", "--", ref, old, commit}
+	if old == "" {
+		args = []string{"update-ref", "-d", "--", ref, commit}
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		s.Logger.Warn("Failed to restore ref after failed push", log.String("ref", ref), log.String("output", string(out)), log.Error(err))
+	}
+}
+
+// validateFileEdits returns an error if an edit has an unknown operation or an
+// invalid path.
+func validateFileEdits(edits []protocol.FileEdit) error {
+	if len(edits) == 0 {
+		return errors.New("no edits")
+	}
+	for _, edit := range edits {
+		switch edit.Operation {
+		case protocol.FileEditWrite, protocol.FileEditDelete, protocol.FileEditChmod:
+		case protocol.FileEditRename:
+			if !validEditPath(edit.NewPath) {
+				return errors.Errorf("invalid path %q", edit.NewPath)
+			}
+		default:
+			return errors.Errorf("unknown edit operation %q", edit.Operation)
+		}
+		if !validEditPath(edit.Path) {
+			return errors.Errorf("invalid path %q", edit.Path)
+		}
+	}
+	return nil
+}
+
+// validEditPath reports whether p is a clean relative path that doesn't point
+// into the .git directory.
+func validEditPath(p string) bool {
+	if p == "" || path.IsAbs(p) || path.Clean(p) != p || strings.ContainsRune(p, 0) {
+		return false
+	}
+	for _, elem := range strings.Split(p, "/") {
+		if elem == ".." || strings.EqualFold(elem, ".git") {
+			return false
+		}
+	}
+	return true
+}
+
+// conflictingPaths returns the paths touched by edits that are in changed.
+func conflictingPaths(edits []protocol.FileEdit, changed []string) []string {
+	changedSet := make(map[string]struct{}, len(changed))
+	for _, p := range changed {
+		if p != "" {
+			changedSet[p] = struct{}{}
+		}
+	}
+
+	var conflicts []string
+	seen := map[string]struct{}{}
+	for _, edit := range edits {
+		for _, p := range []string{edit.Path, edit.NewPath} {
+			if _, ok := changedSet[p]; !ok {
+				continue
+			}
+			if _, ok := seen[p]; !ok {
+				seen[p] = struct{}{}
+				conflicts = append(conflicts, p)
+			}
+		}
+	}
+	return conflicts
+}
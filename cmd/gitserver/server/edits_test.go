@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestCreateCommitFromEdits(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote")
+	if err := os.MkdirAll(remote, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", ".")
+	cmd("sh", "-c", "echo a > a.txt && echo b > b.txt && echo c > c.txt && echo run > run.sh")
+	cmd("git", "add", ".")
+	cmd("git", "commit", "-m", "files")
+	base := api.CommitID(strings.TrimSpace(cmd("git", "rev-parse", "HEAD")))
+
+	reposDir := filepath.Join(root, "repos")
+	repo := api.RepoName("example.com/repo")
+	runCmd(t, root, "git", "clone", "--mirror", remote, filepath.Join(reposDir, string(repo), ".git"))
+
+	s := &Server{
+		Logger:           logtest.Scoped(t),
+		ReposDir:         reposDir,
+		GetRemoteURLFunc: staticGetRemoteURL(remote),
+	}
+	dir := s.dir(repo)
+	git := func(arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, string(dir), "git", arg...))
+	}
+
+	createCommit := func(ref string, edits ...protocol.FileEdit) (int, protocol.CreateCommitFromEditsResponse) {
+		return s.createCommitFromEdits(ctx, protocol.CreateCommitFromEditsRequest{
+			Repo:       repo,
+			BaseCommit: base,
+			TargetRef:  ref,
+			Edits:      edits,
+			CommitInfo: protocol.PatchCommitInfo{Message: "edits", AuthorName: "a", AuthorEmail: "a@a.com"},
+		})
+	}
+
+	status, resp := createCommit("edits",
+		protocol.FileEdit{Operation: protocol.FileEditWrite, Path: "a.txt", Content: []byte("new a\n")},
+		protocol.FileEdit{Operation: protocol.FileEditWrite, Path: "dir/d.txt", Content: []byte("d\n")},
+		protocol.FileEdit{Operation: protocol.FileEditDelete, Path: "b.txt"},
+		protocol.FileEdit{Operation: protocol.FileEditRename, Path: "c.txt", NewPath: "dir/c.txt"},
+		protocol.FileEdit{Operation: protocol.FileEditChmod, Path: "run.sh", Executable: true},
+	)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %v", status, resp.Error)
+	}
+	if resp.Rev != "refs/heads/edits" {
+		t.Errorf("got rev %q, want refs/heads/edits", resp.Rev)
+	}
+	if got := git("rev-parse", "refs/heads/edits"); got != string(resp.Commit) {
+		t.Errorf("ref points at %s, want %s", got, resp.Commit)
+	}
+	if got := git("rev-parse", string(resp.Commit)+"^"); got != string(base) {
+		t.Errorf("got parent %s, want %s", got, base)
+	}
+	wantTree := strings.Join([]string{
+		"100644 a.txt",
+		"100644 dir/c.txt",
+		"100644 dir/d.txt",
+		"100755 run.sh",
+	}, "\n")
+	if diff := cmp.Diff(wantTree, git("ls-tree", "-r", "--format=%(objectmode) %(path)", string(resp.Commit))); diff != "" {
+		t.Errorf("unexpected tree (-want +got):\n%s", diff)
+	}
+	if got := git("show", string(resp.Commit)+":a.txt"); got != "new a" {
+		t.Errorf("got a.txt %q, want %q", got, "new a")
+	}
+
+	// The ref moved since the base commit, but the edits don't touch the
+	// files that changed.
+	first := resp.Commit
+	status, resp = createCommit("edits", protocol.FileEdit{Operation: protocol.FileEditWrite, Path: "e.txt", Content: []byte("e\n")})
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %v", status, resp.Error)
+	}
+	if got := git("rev-parse", string(resp.Commit)+"^"); got != string(first) {
+		t.Errorf("got parent %s, want %s", got, first)
+	}
+
+	// The edits touch files that changed since the base commit.
+	status, resp = createCommit("edits",
+		protocol.FileEdit{Operation: protocol.FileEditWrite, Path: "a.txt", Content: []byte("other a\n")},
+		protocol.FileEdit{Operation: protocol.FileEditRename, Path: "run.sh", NewPath: "e.txt"},
+	)
+	if status != http.StatusConflict {
+		t.Fatalf("got status %d, want %d", status, http.StatusConflict)
+	}
+	if diff := cmp.Diff([]string{"a.txt", "run.sh", "e.txt"}, resp.Error.ConflictingPaths); diff != "" {
+		t.Errorf("unexpected conflicting paths (-want +got):\n%s", diff)
+	}
+
+	for _, edit := range []protocol.FileEdit{
+		{Operation: protocol.FileEditWrite, Path: "../a.txt"},
+		{Operation: protocol.FileEditWrite, Path: ".git/config"},
+		{Operation: protocol.FileEditWrite, Path: "/a.txt"},
+		{Operation: protocol.FileEditRename, Path: "a.txt", NewPath: "dir/../a.txt"},
+		{Operation: protocol.FileEditDelete, Path: "missing.txt"},
+		{Operation: protocol.FileEditRename, Path: "a.txt", NewPath: "run.sh"},
+		{Operation: "copy", Path: "a.txt"},
+	} {
+		if status, resp := createCommit("invalid", edit); status != http.StatusBadRequest {
+			t.Errorf("%+v: got status %d, want %d: %v", edit, status, http.StatusBadRequest, resp.Error)
+		}
+	}
+}
+
+func TestCreateCommitFromEdits_Push(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote")
+	if err := os.MkdirAll(remote, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", "--bare", ".")
+
+	work := filepath.Join(root, "work")
+	if err := os.MkdirAll(work, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	workCmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, work, name, arg...)
+	}
+	base := strings.TrimSpace(makeSingleCommitRepo(workCmd))
+	workCmd("git", "push", remote, "HEAD:refs/heads/main", "HEAD:refs/heads/stale")
+	workCmd("sh", "-c", "echo other > other.txt")
+	workCmd("git", "add", "other.txt")
+	workCmd("git", "commit", "-m", "other")
+	other := strings.TrimSpace(workCmd("git", "rev-parse", "HEAD"))
+	workCmd("git", "push", remote, "HEAD:refs/heads/other")
+
+	reposDir := filepath.Join(root, "repos")
+	repo := api.RepoName("example.com/repo")
+	runCmd(t, root, "git", "clone", "--mirror", remote, filepath.Join(reposDir, string(repo), ".git"))
+
+	s := &Server{
+		Logger:           logtest.Scoped(t),
+		ReposDir:         reposDir,
+		GetRemoteURLFunc: staticGetRemoteURL(remote),
+	}
+	createCommit := func(ref string) (int, protocol.CreateCommitFromEditsResponse) {
+		return s.createCommitFromEdits(ctx, protocol.CreateCommitFromEditsRequest{
+			Repo:       repo,
+			BaseCommit: api.CommitID(base),
+			TargetRef:  ref,
+			Edits:      []protocol.FileEdit{{Operation: protocol.FileEditWrite, Path: "hello.txt", Content: []byte("hi\n")}},
+			CommitInfo: protocol.PatchCommitInfo{Message: "edits", AuthorName: "a", AuthorEmail: "a@a.com"},
+			Push:       &protocol.PushConfig{RemoteURL: "file://" + remote},
+		})
+	}
+
+	status, resp := createCommit("pushed")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %v", status, resp.Error)
+	}
+	if got := strings.TrimSpace(cmd("git", "rev-parse", "refs/heads/pushed")); got != string(resp.Commit) {
+		t.Errorf("pushed ref points at %s, want %s", got, resp.Commit)
+	}
+
+	// The ref moved on the code host, but not in our clone. The edits are
+	// applied on top of where the code host has it.
+	cmd("git", "update-ref", "refs/heads/stale", other)
+	status, resp = createCommit("stale")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %v", status, resp.Error)
+	}
+	if got := strings.TrimSpace(cmd("git", "rev-parse", "refs/heads/stale")); got != string(resp.Commit) {
+		t.Errorf("pushed ref points at %s, want %s", got, resp.Commit)
+	}
+	if got := strings.TrimSpace(cmd("git", "rev-parse", string(resp.Commit)+"^")); got != other {
+		t.Errorf("commit parent is %s, want %s", got, other)
+	}
+
+	// If the code host rejects the push, the ref isn't left behind in our
+	// clone.
+	hook := filepath.Join(remote, "hooks", "pre-receive")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	status, resp = createCommit("rejected")
+	if status != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want %d: %v", status, http.StatusInternalServerError, resp.Error)
+	}
+	if out, err := exec.Command("git", "-C", filepath.Join(reposDir, string(repo), ".git"), "rev-parse", "--verify", "--quiet", "refs/heads/rejected").Output(); err == nil {
+		t.Errorf("ref was left behind at %s", out)
+	}
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Partial clones omit the objects excluded by a filter, for example the blobs
+// larger than a limit. The code host is configured as the promisor remote
+// "origin" of the repository, without its URL, which contains credentials and
+// is never stored on disk. Commands reading from a partial clone are run with
+// the URL in their environment instead, see lazyFetchEnv, so that git fetches
+// the missing objects when they are read. Fetched objects are kept in promisor
+// packs, which serve as a cache of the objects that were read.
+
+var partialCloneFilters = conf.Cached(func() map[string]string {
+	exp := conf.ExperimentalFeatures()
+	return buildPartialCloneFilters(exp.GitPartialClones)
+})
+
+func buildPartialCloneFilters(c []*schema.GitPartialCloneMapping) map[string]string {
+	filters := map[string]string{}
+	for _, mapping := range c {
+		filters[strings.Trim(mapping.DomainPath, "/")] = mapping.Filter
+	}
+	return filters
+}
+
+// partialCloneFilter returns the filter of a partial clone of the repo at
+// remoteURL, or an empty string if the repo is fully cloned. The filter of the
+// longest matching domain/path prefix applies.
+func partialCloneFilter(remoteURL *vcs.URL) string {
+	filters := partialCloneFilters()
+	if len(filters) == 0 {
+		return ""
+	}
+
+	dp := strings.Trim(path.Join(remoteURL.Host, strings.TrimSuffix(remoteURL.Path, ".git")), "/")
+	for ; dp != "." && dp != ""; dp = path.Dir(dp) {
+		if filter, ok := filters[dp]; ok {
+			return filter
+		}
+	}
+	return ""
+}
+
+// partialCloneConfigured reports whether the repo is configured to be
+// partially cloned. Partial clones are always cloned from the code host,
+// since the objects missing from them can't be transferred between gitserver
+// instances.
+func (s *Server) partialCloneConfigured(ctx context.Context, repo api.RepoName) bool {
+	remoteURL, err := s.getRemoteURL(actor.WithInternalActor(ctx), repo)
+	if err != nil {
+		return false
+	}
+	return partialCloneFilter(remoteURL) != ""
+}
+
+// isPartialClone reports whether the repository at dir is a partial clone,
+// which is the case if it has promisor packs.
+func isPartialClone(dir GitDir) bool {
+	promisors, err := filepath.Glob(dir.Path("objects", "pack", "*.promisor"))
+	return err == nil && len(promisors) > 0
+}
+
+// setupPartialClone configures the empty repository at dir to be a partial
+// clone with filter, fetching missing objects from the promisor remote origin.
+func setupPartialClone(dir GitDir, filter string) error {
+	for _, kv := range [][2]string{
+		// Extensions are only understood by repositories of format version 1.
+		{"core.repositoryformatversion", "1"},
+		{"extensions.partialClone", "origin"},
+		{"remote.origin.promisor", "true"},
+		{"remote.origin.partialclonefilter", filter},
+	} {
+		if err := gitConfigSet(dir, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partialCloneFetchCmd returns the command to fetch refspecs into a partial
+// clone with filter. git only fetches with a filter from the promisor remote,
+// so we pass its URL on the command line.
+func partialCloneFetchCmd(ctx context.Context, remoteURL *vcs.URL, filter string, refspecs []string) *exec.Cmd {
+	args := []string{
+		"-c", "remote.origin.url=" + remoteURL.String(),
+		"fetch", "--no-auto-gc", "--progress", "--prune", "--filter=" + filter, "origin",
+	}
+	return exec.CommandContext(ctx, "git", append(args, refspecs...)...)
+}
+
+// partialCloneURLTTL is how long the remote URL of a partial clone is cached.
+// Commands reading from partial clones need it on every request, but it only
+// changes when the credentials of the code host connection do.
+const partialCloneURLTTL = time.Minute
+
+type partialCloneURL struct {
+	url     *vcs.URL
+	expires time.Time
+}
+
+// partialCloneRemoteURL returns the remote URL of the partially cloned repo,
+// which is cached for partialCloneURLTTL.
+func (s *Server) partialCloneRemoteURL(ctx context.Context, repo api.RepoName) (*vcs.URL, error) {
+	now := time.Now()
+	s.partialCloneURLsMu.Lock()
+	cached, ok := s.partialCloneURLs[repo]
+	s.partialCloneURLsMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.url, nil
+	}
+
+	remoteURL, err := s.getRemoteURL(actor.WithInternalActor(ctx), repo)
+	if err != nil {
+		return nil, err
+	}
+
+	s.partialCloneURLsMu.Lock()
+	defer s.partialCloneURLsMu.Unlock()
+	if s.partialCloneURLs == nil {
+		s.partialCloneURLs = make(map[api.RepoName]partialCloneURL)
+	}
+	// Drop expired entries, so that repos which are no longer read don't
+	// accumulate.
+	for name, u := range s.partialCloneURLs {
+		if !now.Before(u.expires) {
+			delete(s.partialCloneURLs, name)
+		}
+	}
+	s.partialCloneURLs[repo] = partialCloneURL{url: remoteURL, expires: now.Add(partialCloneURLTTL)}
+	return remoteURL, nil
+}
+
+// lazyFetchEnv returns the environment variables git commands reading from the
+// repo at dir need to fetch missing objects from the code host, and a redactor
+// for the credentials they contain. Output of the commands must be redacted
+// before it is returned to clients, since git prints the remote URL when a
+// fetch fails. It returns a nil environment if the repo is not a partial clone.
+func (s *Server) lazyFetchEnv(ctx context.Context, repo api.RepoName, dir GitDir) ([]string, *urlRedactor) {
+	if !isPartialClone(dir) {
+		return nil, &urlRedactor{}
+	}
+	remoteURL, err := s.partialCloneRemoteURL(ctx, repo)
+	if err != nil {
+		// Commands still succeed if they don't read missing objects.
+		s.Logger.Warn("failed to get remote URL of partial clone", log.String("repo", string(repo)), log.Error(err))
+		return nil, &urlRedactor{}
+	}
+	env := []string{
+		"GIT_CONFIG_COUNT=2",
+		"GIT_CONFIG_KEY_0=remote.origin.url",
+		"GIT_CONFIG_VALUE_0=" + remoteURL.String(),
+		// Unset credential helper because the command is non-interactive.
+		"GIT_CONFIG_KEY_1=credential.helper",
+		"GIT_CONFIG_VALUE_1=",
+	}
+	return append(env, remoteGitCommandEnv(tlsExternal())...), newURLRedactor(remoteURL)
+}
+
+// redactError returns err with the credentials known to r redacted from its
+// message.
+func redactError(r *urlRedactor, err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := r.redact(err.Error()); msg != err.Error() {
+		return errors.New(msg)
+	}
+	return err
+}
+
+// withLazyFetchEnv adds env to the environment of cmd.
+func withLazyFetchEnv(cmd *exec.Cmd, env []string) {
+	if len(env) == 0 {
+		return
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, env...)
+}
@@ -121,23 +121,12 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 	}
 
 	if req.UniqueRef {
-		refs, err := repoRemoteRefs(ctx, remoteURL, ref)
+		ref, err = uniqueRef(ctx, remoteURL, ref)
 		if err != nil {
 			s.Logger.Error("Failed to get remote refs", log.String("ref", ref), log.Error(err))
 			resp.SetError(repo, "", "", errors.Wrap(err, "repoRemoteRefs"))
 			return http.StatusInternalServerError, resp
 		}
-
-		retry := 1
-		tmp := ref
-		for {
-			if _, ok := refs[tmp]; !ok {
-				break
-			}
-			tmp = ref + "-" + strconv.Itoa(retry)
-			retry++
-		}
-		ref = tmp
 	}
 
 	if req.Push != nil {
@@ -187,35 +176,11 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 	if message == "" {
 		message = "<Sourcegraph> Creating commit from patch"
 	}
-	authorName := req.CommitInfo.AuthorName
-	if authorName == "" {
-		authorName = "Sourcegraph"
-	}
-	authorEmail := req.CommitInfo.AuthorEmail
-	if authorEmail == "" {
-		authorEmail = "support@sourcegraph.com"
-	}
-	committerName := req.CommitInfo.CommitterName
-	if committerName == "" {
-		committerName = authorName
-	}
-	committerEmail := req.CommitInfo.CommitterEmail
-	if committerEmail == "" {
-		committerEmail = authorEmail
-	}
 
 	cmd = exec.CommandContext(ctx, "git", "commit", "-m", message)
 	cmd.Dir = tmpRepoDir
-	cmd.Env = append(os.Environ(), []string{
-		tmpGitPathEnv,
-		altObjectsEnv,
-		fmt.Sprintf("GIT_COMMITTER_NAME=%s", committerName),
-		fmt.Sprintf("GIT_COMMITTER_EMAIL=%s", committerEmail),
-		fmt.Sprintf("GIT_AUTHOR_NAME=%s", authorName),
-		fmt.Sprintf("GIT_AUTHOR_EMAIL=%s", authorEmail),
-		fmt.Sprintf("GIT_COMMITTER_DATE=%v", req.CommitInfo.Date),
-		fmt.Sprintf("GIT_AUTHOR_DATE=%v", req.CommitInfo.Date),
-	}...)
+	cmd.Env = append(os.Environ(), tmpGitPathEnv, altObjectsEnv)
+	cmd.Env = append(cmd.Env, commitEnv(req.CommitInfo)...)
 
 	if out, err := run(cmd, "committing patch"); err != nil {
 		s.Logger.Error("Failed to commit patch.", log.String("ref", ref), log.String("output", string(out)))
@@ -263,33 +228,13 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 	}
 
 	if req.Push != nil {
-		cmd = exec.CommandContext(ctx, "git", "push", "--force", remoteURL.String(), fmt.Sprintf("%s:%s", cmtHash, ref))
-		cmd.Dir = repoGitDir
-
-		// If the protocol is SSH and a private key was given, we want to
-		// use it for communication with the code host.
-		if remoteURL.IsSSH() && req.Push.PrivateKey != "" && req.Push.Passphrase != "" {
-			// We set up an agent here, which sets up a socket that can be provided to
-			// SSH via the $SSH_AUTH_SOCK environment variable and the goroutine to drive
-			// it in the background.
-			// This is used to pass the private key to be used when pushing to the remote,
-			// without the need to store it on the disk.
-			agent, err := newSSHAgent([]byte(req.Push.PrivateKey), []byte(req.Push.Passphrase))
-			if err != nil {
-				resp.SetError(repo, "", "", errors.Wrap(err, "gitserver: error creating ssh-agent"))
-				return http.StatusInternalServerError, resp
-			}
-			go agent.Listen()
-			// Make sure we shut this down once we're done.
-			defer agent.Close()
-
-			cmd.Env = append(
-				os.Environ(),
-				[]string{
-					fmt.Sprintf("SSH_AUTH_SOCK=%s", agent.Socket()),
-				}...,
-			)
+		cmd, closeAgent, err := pushCommand(ctx, repoGitDir, remoteURL, req.Push, fmt.Sprintf("%s:%s", cmtHash, ref), "--force")
+		if err != nil {
+			resp.SetError(repo, "", "", err)
+			return http.StatusInternalServerError, resp
 		}
+		// Make sure we shut the SSH agent down once we're done.
+		defer closeAgent()
 
 		if out, err = run(cmd, "pushing ref"); err != nil {
 			s.Logger.Error("Failed to push", log.String("ref", ref), log.String("commit", cmtHash), log.String("output", string(out)))
@@ -310,6 +255,89 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 	return http.StatusOK, resp
 }
 
+// uniqueRef returns ref if it doesn't exist on the code host at remoteURL, or
+// else ref with the lowest numeric suffix (ie ref-{#}) that doesn't exist.
+func uniqueRef(ctx context.Context, remoteURL *vcs.URL, ref string) (string, error) {
+	refs, err := repoRemoteRefs(ctx, remoteURL, ref)
+	if err != nil {
+		return ref, err
+	}
+
+	retry := 1
+	tmp := ref
+	for {
+		if _, ok := refs[tmp]; !ok {
+			break
+		}
+		tmp = ref + "-" + strconv.Itoa(retry)
+		retry++
+	}
+	return tmp, nil
+}
+
+// commitEnv returns the environment variables of a git command creating a
+// commit with the given information.
+func commitEnv(info protocol.PatchCommitInfo) []string {
+	authorName := info.AuthorName
+	if authorName == "" {
+		authorName = "Sourcegraph"
+	}
+	authorEmail := info.AuthorEmail
+	if authorEmail == "" {
+		authorEmail = "support@sourcegraph.com"
+	}
+	committerName := info.CommitterName
+	if committerName == "" {
+		committerName = authorName
+	}
+	committerEmail := info.CommitterEmail
+	if committerEmail == "" {
+		committerEmail = authorEmail
+	}
+
+	return []string{
+		fmt.Sprintf("GIT_COMMITTER_NAME=%s", committerName),
+		fmt.Sprintf("GIT_COMMITTER_EMAIL=%s", committerEmail),
+		fmt.Sprintf("GIT_AUTHOR_NAME=%s", authorName),
+		fmt.Sprintf("GIT_AUTHOR_EMAIL=%s", authorEmail),
+		fmt.Sprintf("GIT_COMMITTER_DATE=%v", info.Date),
+		fmt.Sprintf("GIT_AUTHOR_DATE=%v", info.Date),
+	}
+}
+
+// pushCommand returns the command pushing refspec from the repository at dir
+// to remoteURL with the given flags. The returned function must be called once
+// the command has finished.
+func pushCommand(ctx context.Context, dir string, remoteURL *vcs.URL, push *protocol.PushConfig, refspec string, flags ...string) (*exec.Cmd, func(), error) {
+	args := append(append([]string{"push"}, flags...), remoteURL.String(), refspec)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	// If the protocol is SSH and a private key was given, we want to
+	// use it for communication with the code host.
+	if remoteURL.IsSSH() && push.PrivateKey != "" && push.Passphrase != "" {
+		// We set up an agent here, which sets up a socket that can be provided to
+		// SSH via the $SSH_AUTH_SOCK environment variable and the goroutine to drive
+		// it in the background.
+		// This is used to pass the private key to be used when pushing to the remote,
+		// without the need to store it on the disk.
+		agent, err := newSSHAgent([]byte(push.PrivateKey), []byte(push.Passphrase))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "gitserver: error creating ssh-agent")
+		}
+		go agent.Listen()
+
+		cmd.Env = append(
+			os.Environ(),
+			[]string{
+				fmt.Sprintf("SSH_AUTH_SOCK=%s", agent.Socket()),
+			}...,
+		)
+		return cmd, func() { agent.Close() }, nil
+	}
+	return cmd, func() {}, nil
+}
+
 func cleanUpTmpRepo(path string) {
 	err := os.RemoveAll(path)
 	logger := log.Scoped("cleanUpTmpRepo", "cleans up temp Repo")
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The number of repos moved to the shard they belong to at the same time - value <=0 disables
+// rebalancing. While rebalancing is enabled, the janitor doesn't delete repos cloned on the wrong
+// shard, since the rebalancer deletes them once they are moved.
+var rebalanceConcurrency, _ = strconv.Atoi(env.Get("SRC_REPOS_REBALANCE_CONCURRENCY", "4", "the maximum number of repos moved to the shard they belong to at the same time. Set to 0 to disable rebalancing"))
+
+var (
+	reposRebalanced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_rebalanced",
+		Help: "number of repos moved to the shard they belong to",
+	}, []string{"success"})
+	rebalanceRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_rebalance_running",
+		Help: "set to 1 when the gitserver rebalancer is running",
+	})
+)
+
+// rebalancingEnabled reports whether repos cloned on the wrong shard are moved
+// to the shard they belong to.
+func (s *Server) rebalancingEnabled() bool {
+	return s.Peers != nil && rebalanceConcurrency > 0
+}
+
+// RebalanceRepos moves repos that belong to another shard to it and is
+// expected to run in a background goroutine. When shards are added or
+// removed, the repos are transferred from this shard instead of being
+// recloned from their code host.
+func (s *Server) RebalanceRepos(interval time.Duration) {
+	if !s.rebalancingEnabled() {
+		return
+	}
+	for {
+		s.rebalanceRepos(currentGitserverAddresses())
+		time.Sleep(interval)
+	}
+}
+
+// rebalanceRepos moves every repo cloned on this shard that belongs to
+// another shard. A repo is only deleted here once the shard it belongs to has
+// cloned it.
+func (s *Server) rebalanceRepos(gitServerAddrs gitserver.GitServerAddresses) {
+	rebalanceRunning.Set(1)
+	defer rebalanceRunning.Set(0)
+	logger := s.Logger.Scoped("rebalance", "moves repos to the shard they belong to")
+
+	if len(gitServerAddrs.Addresses) == 0 {
+		return
+	}
+	self := s.selfAddr(gitServerAddrs.Addresses)
+
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, rebalanceConcurrency)
+
+	err := bestEffortWalk(s.ReposDir, func(dir string, fi fs.FileInfo) error {
+		if s.ignorePath(dir) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !fi.IsDir() || fi.Name() != ".git" {
+			return nil
+		}
+
+		gitDir := GitDir(dir)
+		name := s.name(gitDir)
+		addr, err := s.addrForRepo(ctx, name, gitServerAddrs)
+		if err != nil {
+			logger.Warn("failed to find shard of repo", log.String("repo", string(name)), log.Error(err))
+			return filepath.SkipDir
+		}
+		if s.hostnameMatch(addr) {
+			return filepath.SkipDir
+		}
+		if _, ok := s.secondaryOf(ctx, name, gitServerAddrs); ok {
+			return filepath.SkipDir
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := s.rebalanceRepo(ctx, gitDir, name, self, addr)
+			reposRebalanced.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+			if err != nil {
+				logger.Warn("failed to move repo", log.String("repo", string(name)), log.String("target-shard", addr), log.Error(err))
+			}
+		}()
+		return filepath.SkipDir
+	})
+	wg.Wait()
+	if err != nil {
+		logger.Error("error iterating over repositories", log.Error(err))
+	}
+}
+
+// rebalanceRepo moves the repo at dir from this shard, reachable at from, to
+// the shard at to.
+func (s *Server) rebalanceRepo(ctx context.Context, dir GitDir, repo api.RepoName, from, to string) error {
+	progress, err := s.peerCloneProgress(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if progress.CloneInProgress {
+		// The shard is already cloning the repo, we check again in the next run.
+		return nil
+	}
+
+	if !progress.Cloned {
+		// The shard clones repos of types that can't be transferred from the
+		// code host instead.
+		s.Logger.Info("moving repo to the shard it belongs to", log.String("repo", string(repo)), log.String("target-shard", to))
+		resp, err := s.Peers.RequestRepoMigrate(ctx, repo, from, to)
+		if err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+
+		if progress, err = s.peerCloneProgress(ctx, repo); err != nil {
+			return err
+		}
+		if !progress.Cloned {
+			return errors.Errorf("repo is not cloned on %s after moving it", to)
+		}
+	}
+
+	// We don't update the clone status, since it now tracks the clone of the
+	// shard the repo belongs to.
+	s.Logger.Info("removing repo moved to the shard it belongs to", log.String("repo", string(repo)), log.String("target-shard", to))
+	return s.removeRepoDirectory(dir, false)
+}
+
+// peerCloneProgress returns the clone progress of repo on the shard it belongs to.
+func (s *Server) peerCloneProgress(ctx context.Context, repo api.RepoName) (*protocol.RepoCloneProgress, error) {
+	resp, err := s.Peers.RepoCloneProgress(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	progress, ok := resp.Results[repo]
+	if !ok || progress == nil {
+		return nil, errors.Errorf("no clone progress returned for %s", repo)
+	}
+	return progress, nil
+}
+
+// selfAddr returns the address of this shard. A shard that was removed is no
+// longer in addrs, so we derive its address from the address of another
+// shard, assuming shards are addressed by their hostname followed by the same
+// domain and port.
+func (s *Server) selfAddr(addrs []string) string {
+	for _, addr := range addrs {
+		if s.hostnameMatch(addr) {
+			return addr
+		}
+	}
+	if len(addrs) == 0 {
+		return s.Hostname
+	}
+	if i := strings.IndexAny(addrs[0], ".:"); i >= 0 {
+		return s.Hostname + addrs[0][i:]
+	}
+	return s.Hostname
+}
+
+// previousShard returns the address of the shard that still has repo cloned
+// after it was moved to this shard, or an empty string if there is none.
+func (s *Server) previousShard(ctx context.Context, repo api.RepoName, addrs []string) string {
+	gr, err := s.DB.GitserverRepos().GetByName(ctx, repo)
+	if err != nil || gr.ShardID == "" || gr.ShardID == s.Hostname || gr.CloneStatus != types.CloneStatusCloned {
+		return ""
+	}
+	for _, addr := range addrs {
+		if hostnameMatch(gr.ShardID, addr) {
+			return addr
+		}
+	}
+	return ""
+}
+
+// transferableRepoType reports whether a repo of the given type can be
+// transferred between shards with git. Repos converted from Subversion and
+// Mercurial keep conversion state outside of git that isn't transferred, so
+// they are recloned from their code host.
+func transferableRepoType(typ string) bool {
+	return typ != "subversion" && typ != "mercurial"
+}
+
+// shardURL returns the URL of repo on the shard at addr. addr may include
+// the scheme.
+func shardURL(addr string, repo api.RepoName) (*vcs.URL, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	return vcs.ParseURL(strings.TrimSuffix(addr, "/") + "/git/" + string(repo))
+}
+
+// shardSyncer transfers a repository from another shard with git. It reports
+// the type of the syncer of the repository, so that it is kept by the clone.
+type shardSyncer struct {
+	GitRepoSyncer
+	typ   string
+	shard string
+}
+
+func (s *shardSyncer) Type() string {
+	return s.typ
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestRebalanceRepo(t *testing.T) {
+	const repo = api.RepoName("example.com/foo/bar")
+
+	setup := func(t *testing.T, progress ...protocol.RepoCloneProgress) (*Server, *gitserver.MockClient, GitDir) {
+		t.Helper()
+		root := t.TempDir()
+		dir := GitDir(filepath.Join(root, string(repo), ".git"))
+		if err := exec.Command("git", "init", "--bare", string(dir)).Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		peers := gitserver.NewMockClient()
+		for _, p := range progress {
+			p := p
+			peers.RepoCloneProgressFunc.PushReturn(&protocol.RepoCloneProgressResponse{
+				Results: map[api.RepoName]*protocol.RepoCloneProgress{repo: &p},
+			}, nil)
+		}
+		peers.RequestRepoMigrateFunc.SetDefaultReturn(&protocol.RepoUpdateResponse{}, nil)
+
+		s := &Server{
+			Logger:   logtest.Scoped(t),
+			ReposDir: root,
+			Hostname: "gitserver-0",
+			Peers:    peers,
+		}
+		return s, peers, dir
+	}
+
+	exists := func(dir GitDir) bool {
+		_, err := os.Stat(string(dir))
+		return err == nil
+	}
+
+	t.Run("already cloned", func(t *testing.T) {
+		s, peers, dir := setup(t, protocol.RepoCloneProgress{Cloned: true})
+		if err := s.rebalanceRepo(context.Background(), dir, repo, "gitserver-0:3178", "gitserver-1:3178"); err != nil {
+			t.Fatal(err)
+		}
+		if len(peers.RequestRepoMigrateFunc.History()) != 0 {
+			t.Error("expected repo not to be migrated")
+		}
+		if exists(dir) {
+			t.Error("expected repo to be removed")
+		}
+	})
+
+	t.Run("migrated", func(t *testing.T) {
+		s, peers, dir := setup(t, protocol.RepoCloneProgress{}, protocol.RepoCloneProgress{Cloned: true})
+		if err := s.rebalanceRepo(context.Background(), dir, repo, "gitserver-0:3178", "gitserver-1:3178"); err != nil {
+			t.Fatal(err)
+		}
+		history := peers.RequestRepoMigrateFunc.History()
+		if len(history) != 1 {
+			t.Fatalf("expected repo to be migrated once, got %d", len(history))
+		}
+		if call := history[0]; call.Arg1 != repo || call.Arg2 != "gitserver-0:3178" || call.Arg3 != "gitserver-1:3178" {
+			t.Errorf("unexpected migration %s from %s to %s", call.Arg1, call.Arg2, call.Arg3)
+		}
+		if exists(dir) {
+			t.Error("expected repo to be removed")
+		}
+	})
+
+	t.Run("clone in progress", func(t *testing.T) {
+		s, peers, dir := setup(t, protocol.RepoCloneProgress{CloneInProgress: true})
+		if err := s.rebalanceRepo(context.Background(), dir, repo, "gitserver-0:3178", "gitserver-1:3178"); err != nil {
+			t.Fatal(err)
+		}
+		if len(peers.RequestRepoMigrateFunc.History()) != 0 {
+			t.Error("expected repo not to be migrated")
+		}
+		if !exists(dir) {
+			t.Error("expected repo not to be removed")
+		}
+	})
+
+	t.Run("migration failed", func(t *testing.T) {
+		s, peers, dir := setup(t, protocol.RepoCloneProgress{})
+		peers.RequestRepoMigrateFunc.SetDefaultReturn(&protocol.RepoUpdateResponse{Error: "failed to clone"}, nil)
+		if err := s.rebalanceRepo(context.Background(), dir, repo, "gitserver-0:3178", "gitserver-1:3178"); err == nil {
+			t.Fatal("expected error")
+		}
+		if !exists(dir) {
+			t.Error("expected repo not to be removed")
+		}
+	})
+
+	t.Run("not cloned after migration", func(t *testing.T) {
+		s, _, dir := setup(t, protocol.RepoCloneProgress{}, protocol.RepoCloneProgress{})
+		if err := s.rebalanceRepo(context.Background(), dir, repo, "gitserver-0:3178", "gitserver-1:3178"); err == nil {
+			t.Fatal("expected error")
+		}
+		if !exists(dir) {
+			t.Error("expected repo not to be removed")
+		}
+	})
+
+	t.Run("clone progress failed", func(t *testing.T) {
+		s, peers, dir := setup(t)
+		peers.RepoCloneProgressFunc.SetDefaultReturn(nil, errors.New("connection refused"))
+		if err := s.rebalanceRepo(context.Background(), dir, repo, "gitserver-0:3178", "gitserver-1:3178"); err == nil {
+			t.Fatal("expected error")
+		}
+		if !exists(dir) {
+			t.Error("expected repo not to be removed")
+		}
+	})
+}
+
+func TestSelfAddr(t *testing.T) {
+	s := &Server{Hostname: "gitserver-2"}
+	for _, tc := range []struct {
+		addrs []string
+		want  string
+	}{
+		{[]string{"gitserver-0.gitserver:3178", "gitserver-2.gitserver:3178"}, "gitserver-2.gitserver:3178"},
+		// The shard was removed.
+		{[]string{"gitserver-0.gitserver:3178", "gitserver-1.gitserver:3178"}, "gitserver-2.gitserver:3178"},
+		{[]string{"gitserver-0:3178"}, "gitserver-2:3178"},
+		{[]string{"gitserver-0"}, "gitserver-2"},
+		{nil, "gitserver-2"},
+	} {
+		if got := s.selfAddr(tc.addrs); got != tc.want {
+			t.Errorf("selfAddr(%q) = %q, want %q", tc.addrs, got, tc.want)
+		}
+	}
+}
+
+func TestShardURL(t *testing.T) {
+	for addr, want := range map[string]string{
+		"gitserver-1:3178":         "http://gitserver-1:3178/git/example.com/foo/bar",
+		"http://gitserver-1:3178":  "http://gitserver-1:3178/git/example.com/foo/bar",
+		"http://127.0.0.1:41234/":  "http://127.0.0.1:41234/git/example.com/foo/bar",
+		"https://gitserver-1:3178": "https://gitserver-1:3178/git/example.com/foo/bar",
+	} {
+		u, err := shardURL(addr, "example.com/foo/bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := u.String(); got != want {
+			t.Errorf("shardURL(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Repos flagged as maybe corrupt by checkMaybeCorruptRepo are repaired by the
+// janitor. We check the repo with git fsck and refetch the objects it reports
+// missing from the code host, which is much cheaper than recloning large
+// repos. If the repo is still corrupt, it is recloned and the corrupt clone is
+// moved into quarantine, where it is kept for inspection until it expires.
+// Each step is recorded in the corruption logs of the repo in the database.
+
+// quarantineDirName is the name of the directory under ReposDir that corrupt
+// clones are moved into.
+const quarantineDirName = ".quarantine"
+
+var repoQuarantineTTL = env.MustGetDuration("SRC_REPO_QUARANTINE_TTL", 7*24*time.Hour, "the duration after which corrupt clones of repositories are removed from quarantine")
+
+// The steps recorded in the corruption logs of a repo.
+const (
+	corruptionStepDetected    = "detected"
+	corruptionStepFsck        = "fsck"
+	corruptionStepCommitGraph = "commit_graph"
+	corruptionStepRefetch     = "refetch"
+	corruptionStepRepaired    = "repaired"
+	corruptionStepQuarantined = "quarantined"
+	corruptionStepRecloned    = "recloned"
+	corruptionStepFailed      = "failed"
+)
+
+var repoRepairs = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_repo_repairs_total",
+	Help: "number of repairs of repos flagged as corrupt, by result: not_corrupt, repaired, recloned or failed",
+}, []string{"result"})
+
+// repairCorruptRepo repairs the repo at dir, which was flagged as corrupt for
+// reason. It reports whether the repo was recloned.
+func (s *Server) repairCorruptRepo(ctx context.Context, repo api.RepoName, dir GitDir, reason string) (recloned bool, err error) {
+	logger := s.Logger.Scoped("repairCorruptRepo", "repair a corrupt repo").With(log.String("repo", string(repo)))
+	s.logCorruption(ctx, repo, corruptionStepDetected, reason)
+
+	missing, commitGraph, err := fsck(ctx, dir)
+	if err == nil {
+		// git may fail reading objects that are concurrently repacked, so the
+		// repo isn't necessarily corrupt.
+		s.logCorruption(ctx, repo, corruptionStepFsck, "no corruption found")
+		repoRepairs.WithLabelValues("not_corrupt").Inc()
+		return false, nil
+	}
+	logger.Warn("git fsck found corruption", log.Error(err))
+	s.logCorruption(ctx, repo, corruptionStepFsck, err.Error())
+
+	var repairs []string
+	if commitGraph {
+		// The commit-graph only speeds up walks of the commit graph, and sg
+		// maintenance writes a new one.
+		if err := removeCommitGraph(dir); err != nil {
+			s.logCorruption(ctx, repo, corruptionStepCommitGraph, err.Error())
+		} else {
+			repairs = append(repairs, "removed the commit-graph")
+		}
+	}
+	if len(missing) > 0 && canRefetch(dir) {
+		if err := s.refetchObjects(ctx, repo, dir, missing); err != nil {
+			s.logCorruption(ctx, repo, corruptionStepRefetch, err.Error())
+		} else {
+			repairs = append(repairs, fmt.Sprintf("refetched %d objects", len(missing)))
+		}
+	}
+	if len(repairs) > 0 {
+		reason := strings.Join(repairs, " and ")
+		if _, _, err := fsck(ctx, dir); err != nil {
+			s.logCorruption(ctx, repo, corruptionStepFsck, fmt.Sprintf("%s, but the repo is still corrupt: %s", reason, err))
+		} else {
+			logger.Info("repaired corrupt repo", log.String("repairs", reason))
+			s.logCorruption(ctx, repo, corruptionStepRepaired, reason)
+			repoRepairs.WithLabelValues("repaired").Inc()
+			return false, nil
+		}
+	}
+
+	logger.Warn("re-cloning corrupt repo")
+	path, err := s.quarantineAndReclone(ctx, repo)
+	if err != nil {
+		s.logCorruption(ctx, repo, corruptionStepFailed, err.Error())
+		repoRepairs.WithLabelValues("failed").Inc()
+		return false, err
+	}
+	s.logCorruption(ctx, repo, corruptionStepQuarantined, path)
+	s.logCorruption(ctx, repo, corruptionStepRecloned, "")
+	repoRepairs.WithLabelValues("recloned").Inc()
+	return true, nil
+}
+
+var (
+	// fsckMissingRegex matches the objects git fsck reports missing.
+	fsckMissingRegex = lazyregexp.New(`^missing (?:blob|tree|commit|tag) ([0-9a-f]{40})$`)
+	// fsckCorruptRegex matches the objects git fsck can't read, with their
+	// path if they are loose objects.
+	fsckCorruptRegex = lazyregexp.New(`^error: ([0-9a-f]{40}): object corrupt or missing(?:: (.*))?$`)
+	// looseObjectRegex matches the paths of loose objects relative to the
+	// objects directory.
+	looseObjectRegex = lazyregexp.New(`^[0-9a-f]{2}/[0-9a-f]{38}$`)
+)
+
+// fsck checks the repo at dir with git fsck. If it finds corruption, it
+// returns an error, the objects that are missing or corrupt, and whether the
+// commit-graph is corrupt. Corrupt loose objects are removed, so that they can
+// be refetched.
+func fsck(ctx context.Context, dir GitDir) (missing []string, commitGraph bool, err error) {
+	cmd := exec.CommandContext(ctx, "git", "fsck", "--no-dangling", "--no-progress")
+	dir.Set(cmd)
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err == nil {
+		return nil, false, nil
+	}
+
+	seen := map[string]bool{}
+	var lines []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines = append(lines, line)
+		commitGraph = commitGraph || strings.Contains(line, "commit-graph")
+
+		var oid string
+		if m := fsckMissingRegex.FindStringSubmatch(line); m != nil {
+			oid = m[1]
+		} else if m := fsckCorruptRegex.FindStringSubmatch(line); m != nil {
+			oid = m[1]
+			if path, ok := looseObjectPath(dir, m[2]); ok {
+				_ = os.Remove(path)
+			}
+		}
+		if oid != "" && !seen[oid] {
+			seen[oid] = true
+			missing = append(missing, oid)
+		}
+	}
+
+	// The output can list many objects, so we only keep the first lines.
+	if len(lines) > 10 {
+		lines = append(lines[:10], fmt.Sprintf("... %d more lines", len(lines)-10))
+	}
+	return missing, commitGraph, errors.Errorf("git fsck: %s", strings.Join(lines, "\n"))
+}
+
+// looseObjectPath returns the absolute path of the loose object at path, which
+// git reports relative to dir or absolute. It returns false if path isn't a
+// loose object of the repo at dir.
+func looseObjectPath(dir GitDir, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	if !filepath.IsAbs(path) {
+		path = dir.Path(path)
+	}
+	rel, err := filepath.Rel(dir.Path("objects"), path)
+	if err != nil || !looseObjectRegex.MatchString(filepath.ToSlash(rel)) {
+		return "", false
+	}
+	return path, true
+}
+
+// removeCommitGraph removes the commit-graph of the repo at dir.
+func removeCommitGraph(dir GitDir) error {
+	if err := os.Remove(dir.Path("objects", "info", "commit-graph")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(dir.Path("objects", "info", "commit-graphs"))
+}
+
+// canRefetch reports whether the objects of the repo at dir can be fetched
+// from its code host. Perforce depots, Subversion and Mercurial repositories
+// are converted to git, so the code host doesn't have their objects.
+func canRefetch(dir GitDir) bool {
+	repoType, _ := getRepositoryType(dir)
+	return repoType != "perforce" && repoType != "subversion" && repoType != "mercurial"
+}
+
+// refetchObjects fetches the objects from the code host of repo into the repo
+// at dir. The fetch doesn't negotiate which objects we have, since the
+// objects reachable from the missing ones might be missing, too.
+func (s *Server) refetchObjects(ctx context.Context, repo api.RepoName, dir GitDir, objects []string) error {
+	remoteURL, err := s.getRemoteURL(actor.WithInternalActor(ctx), repo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get remote URL")
+	}
+	args := []string{"-c", "fetch.negotiationAlgorithm=noop", "fetch", "--no-auto-gc", "--no-write-fetch-head", "--no-tags", remoteURL.String()}
+	cmd := exec.CommandContext(ctx, "git", append(args, objects...)...)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, true, nil); err != nil {
+		return errors.Wrapf(err, "failed to refetch objects with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+	return nil
+}
+
+// quarantinePath returns the path corrupt clones of repo are moved to.
+func (s *Server) quarantinePath(repo api.RepoName) string {
+	return filepath.Join(s.ReposDir, quarantineDirName, url.PathEscape(string(repo)))
+}
+
+// quarantineAndReclone reclones repo and moves its corrupt clone into
+// quarantine. The corrupt clone is served until the new clone replaces it. It
+// returns the path of the quarantined clone.
+func (s *Server) quarantineAndReclone(ctx context.Context, repo api.RepoName) (string, error) {
+	// We only keep the latest corrupt clone of a repo.
+	path := s.quarantinePath(repo)
+	if err := os.RemoveAll(path); err != nil {
+		return "", err
+	}
+
+	status, err := s.cloneRepo(ctx, repo, &cloneOptions{Block: true, Overwrite: true, QuarantinePath: path})
+	if err != nil {
+		return "", err
+	}
+	if status != "" {
+		return "", errors.Errorf("failed to reclone: %s", status)
+	}
+
+	// Quarantined clones expire after their modification time, which renaming
+	// them doesn't update.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return "", err
+	}
+	s.setQuarantine(ctx, repo, path)
+	return path, nil
+}
+
+// cleanupQuarantine removes the corrupt clones that were quarantined longer
+// than repoQuarantineTTL.
+func (s *Server) cleanupQuarantine(ctx context.Context) error {
+	entries, err := s.quarantinedClones()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if time.Since(e.ModTime()) < repoQuarantineTTL {
+			continue
+		}
+		if err := s.removeQuarantinedClone(ctx, e.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freeUpQuarantine removes quarantined clones, in order from least to most
+// recently quarantined, until howManyBytesToFree is met or exceeded. They are
+// only kept for inspection, so they are removed before any repo is. It returns
+// the number of bytes freed.
+func (s *Server) freeUpQuarantine(ctx context.Context, howManyBytesToFree int64) (int64, error) {
+	if howManyBytesToFree <= 0 {
+		return 0, nil
+	}
+
+	entries, err := s.quarantinedClones()
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().Before(entries[j].ModTime())
+	})
+
+	var spaceFreed int64
+	for _, e := range entries {
+		if spaceFreed >= howManyBytesToFree {
+			break
+		}
+		delta := dirSize(filepath.Join(s.ReposDir, quarantineDirName, e.Name()))
+		if err := s.removeQuarantinedClone(ctx, e.Name()); err != nil {
+			return spaceFreed, err
+		}
+		spaceFreed += delta
+	}
+	return spaceFreed, nil
+}
+
+// quarantinedClones returns the entries of the quarantine directory.
+func (s *Server) quarantinedClones() ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(s.ReposDir, quarantineDirName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if fi, err := e.Info(); err == nil {
+			infos = append(infos, fi)
+		}
+	}
+	return infos, nil
+}
+
+// removeQuarantinedClone removes the quarantined clone called name and clears
+// the quarantine of its repo.
+func (s *Server) removeQuarantinedClone(ctx context.Context, name string) error {
+	repo, err := url.PathUnescape(name)
+	if err != nil {
+		// Not a quarantined clone.
+		return nil
+	}
+	if err := os.RemoveAll(filepath.Join(s.ReposDir, quarantineDirName, name)); err != nil {
+		return err
+	}
+	s.setQuarantine(ctx, api.RepoName(repo), "")
+	return nil
+}
+
+// logCorruption records a step of repairing repo in the database. Errors are
+// only logged.
+func (s *Server) logCorruption(ctx context.Context, repo api.RepoName, step, reason string) {
+	if s.isSecondary(ctx, repo) {
+		return
+	}
+	entry := types.RepoCorruptionLog{Timestamp: time.Now(), Step: step, Reason: reason}
+	if err := s.DB.GitserverRepos().LogCorruption(ctx, repo, entry, s.Hostname); err != nil {
+		s.Logger.Warn("failed to log corruption", log.String("repo", string(repo)), log.String("step", step), log.Error(err))
+	}
+}
+
+// setQuarantine records the path of the quarantined clone of repo in the
+// database. Errors are only logged.
+func (s *Server) setQuarantine(ctx context.Context, repo api.RepoName, path string) {
+	if s.isSecondary(ctx, repo) {
+		return
+	}
+	if err := s.DB.GitserverRepos().SetQuarantine(ctx, repo, path, s.Hostname); err != nil {
+		s.Logger.Warn("failed to set quarantine", log.String("repo", string(repo)), log.Error(err))
+	}
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestRepairCorruptRepo(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote")
+	if err := os.MkdirAll(remote, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "sh", "-c", "echo a > a && echo b > b")
+	runCmd(t, remote, "git", "add", "-A")
+	runCmd(t, remote, "git", "commit", "-m", "a")
+	blob := strings.TrimSpace(runCmd(t, remote, "git", "rev-parse", "HEAD:b"))
+
+	reposDir := filepath.Join(root, "repos")
+	gsStore := database.NewMockGitserverRepoStore()
+	db := database.NewMockDB()
+	db.GitserverReposFunc.SetDefaultReturn(gsStore)
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: reposDir,
+		GetRemoteURLFunc: func(context.Context, api.RepoName) (string, error) {
+			return remote, nil
+		},
+		DB: db,
+	}
+	dir := s.dir("repo")
+
+	repair := func(want ...string) {
+		t.Helper()
+		logged := len(gsStore.LogCorruptionFunc.History())
+		recloned, err := s.repairCorruptRepo(ctx, "repo", dir, "error: packfile")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recloned {
+			t.Fatal("expected repo to be repaired without re-cloning")
+		}
+		var steps []string
+		for _, call := range gsStore.LogCorruptionFunc.History()[logged:] {
+			steps = append(steps, call.Arg2.Step)
+		}
+		if diff := cmp.Diff(want, steps); diff != "" {
+			t.Errorf("unexpected steps (-want +got):\n%s", diff)
+		}
+		if _, _, err := fsck(ctx, dir); err != nil {
+			t.Errorf("expected repaired repo, got %s", err)
+		}
+	}
+
+	// Clone without hardlinking the objects of the remote, since we corrupt
+	// them below.
+	runCmd(t, root, "git", "clone", "--mirror", "--no-local", remote, string(dir))
+	repair(corruptionStepDetected, corruptionStepFsck)
+
+	// Missing and corrupt objects are refetched from the code host.
+	runCmd(t, string(dir), "sh", "-c", "mkdir unpack && mv objects/pack/* unpack/ && for p in unpack/*.pack; do git unpack-objects < $p; done && rm -r unpack")
+	blobPath := dir.Path("objects", blob[:2], blob[2:])
+	if err := os.Remove(blobPath); err != nil {
+		t.Fatal(err)
+	}
+	repair(corruptionStepDetected, corruptionStepFsck, corruptionStepRepaired)
+
+	if err := os.Chmod(blobPath, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(blobPath, []byte("corrupt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repair(corruptionStepDetected, corruptionStepFsck, corruptionStepRepaired)
+
+	// A corrupt commit-graph is removed.
+	if err := writeCommitGraph(ctx, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	layers, err := commitGraphLayers(dir)
+	if err != nil || len(layers) != 1 {
+		t.Fatalf("expected a commit-graph layer, got %v: %v", layers, err)
+	}
+	if err := os.Chmod(layers[0], 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(layers[0], os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("corrupt"), 100); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	repair(corruptionStepDetected, corruptionStepFsck, corruptionStepRepaired)
+	if layers, _ := commitGraphLayers(dir); len(layers) != 0 {
+		t.Errorf("expected commit-graph to be removed, got %v", layers)
+	}
+}
+
+func TestCleanupQuarantine(t *testing.T) {
+	defer func(ttl time.Duration) { repoQuarantineTTL = ttl }(repoQuarantineTTL)
+	repoQuarantineTTL = time.Hour
+
+	gsStore := database.NewMockGitserverRepoStore()
+	db := database.NewMockDB()
+	db.GitserverReposFunc.SetDefaultReturn(gsStore)
+	s := &Server{Logger: logtest.Scoped(t), ReposDir: t.TempDir(), DB: db}
+
+	now := time.Now()
+	for repo, age := range map[api.RepoName]time.Duration{
+		"github.com/foo/recent":  time.Minute,
+		"github.com/foo/expired": 2 * time.Hour,
+	} {
+		path := s.quarantinePath(repo)
+		if err := os.MkdirAll(filepath.Join(path, "objects"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.cleanupQuarantine(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.quarantinePath("github.com/foo/recent")); err != nil {
+		t.Errorf("expected recent quarantine to be kept: %s", err)
+	}
+	if _, err := os.Stat(s.quarantinePath("github.com/foo/expired")); !os.IsNotExist(err) {
+		t.Errorf("expected expired quarantine to be removed: %v", err)
+	}
+	if calls := gsStore.SetQuarantineFunc.History(); len(calls) != 1 || calls[0].Arg1 != "github.com/foo/expired" || calls[0].Arg2 != "" {
+		t.Errorf("expected quarantine of expired repo to be cleared, got %+v", calls)
+	}
+
+	// The quarantine isn't a repo, so the janitor skips it.
+	if !s.ignorePath(filepath.Join(s.ReposDir, quarantineDirName)) {
+		t.Error("expected quarantine to be ignored")
+	}
+}
+
+func TestFreeUpQuarantine(t *testing.T) {
+	gsStore := database.NewMockGitserverRepoStore()
+	db := database.NewMockDB()
+	db.GitserverReposFunc.SetDefaultReturn(gsStore)
+	s := &Server{Logger: logtest.Scoped(t), ReposDir: t.TempDir(), DB: db}
+
+	now := time.Now()
+	for repo, age := range map[api.RepoName]time.Duration{
+		"github.com/foo/newer": time.Minute,
+		"github.com/foo/older": time.Hour,
+	} {
+		path := s.quarantinePath(repo)
+		if err := makeFakeRepo(path, 1000); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	freed, err := s.freeUpQuarantine(context.Background(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed < 1000 {
+		t.Errorf("expected at least 1000 bytes to be freed, got %d", freed)
+	}
+	if _, err := os.Stat(s.quarantinePath("github.com/foo/newer")); err != nil {
+		t.Errorf("expected newer quarantine to be kept: %s", err)
+	}
+	if _, err := os.Stat(s.quarantinePath("github.com/foo/older")); !os.IsNotExist(err) {
+		t.Errorf("expected older quarantine to be removed: %v", err)
+	}
+	if calls := gsStore.SetQuarantineFunc.History(); len(calls) != 1 || calls[0].Arg1 != "github.com/foo/older" {
+		t.Errorf("expected quarantine of older repo to be cleared, got %+v", calls)
+	}
+}
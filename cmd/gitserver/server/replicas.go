@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// secondaryOf returns the address of the primary instance of repo if this
+// instance has a secondary copy of it. Secondary copies are mirrored from the
+// primary instance instead of the code host, and the state of the repo in the
+// database only tracks the copy of the primary instance.
+func (s *Server) secondaryOf(ctx context.Context, repo api.RepoName, gitServerAddrs gitserver.GitServerAddresses) (primary string, ok bool) {
+	if gitServerAddrs.ReplicationFactor <= 1 || len(gitServerAddrs.Addresses) == 0 {
+		return "", false
+	}
+	replicas, err := gitserver.ReplicaAddrsForRepo(ctx, filepath.Base(os.Args[0]), s.DB, repo, gitServerAddrs)
+	if err != nil || s.hostnameMatch(replicas[0]) {
+		return "", false
+	}
+	for _, addr := range replicas[1:] {
+		if s.hostnameMatch(addr) {
+			return replicas[0], true
+		}
+	}
+	return "", false
+}
+
+// isSecondary reports whether this instance has a secondary copy of repo.
+func (s *Server) isSecondary(ctx context.Context, repo api.RepoName) bool {
+	_, ok := s.secondaryOf(ctx, repo, currentGitserverAddresses())
+	return ok
+}
@@ -66,6 +66,84 @@ func (s *Server) handleRepoCloneProgress(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+func (s *Server) handleRepoMaintenanceStatus(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoMaintenanceStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := repoMaintenanceStatus(s.dir(protocol.NormalizeRepo(req.Repo)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// repoMaintenanceStatus returns the state of the data structures we maintain
+// in the repo at dir.
+func repoMaintenanceStatus(dir GitDir) (*protocol.RepoMaintenanceStatus, error) {
+	if !repoCloned(dir) {
+		return &protocol.RepoMaintenanceStatus{}, nil
+	}
+	status := protocol.RepoMaintenanceStatus{Cloned: true}
+
+	layers, err := commitGraphLayers(dir)
+	if err != nil {
+		return nil, err
+	}
+	status.CommitGraph = len(layers) > 0
+	status.CommitGraphLayers = len(layers)
+	// git doesn't use Bloom filters for the commits of layers without them.
+	status.ChangedPaths = len(layers) > 0
+	for _, layer := range layers {
+		chunks, err := commitGraphChunks(layer)
+		if err != nil {
+			return nil, err
+		}
+		hasBloomFilters := false
+		for _, chunk := range chunks {
+			hasBloomFilters = hasBloomFilters || chunk == "BDAT"
+		}
+		status.ChangedPaths = status.ChangedPaths && hasBloomFilters
+	}
+
+	bitmaps, err := filepath.Glob(dir.Path("objects", "pack", "*.bitmap"))
+	if err != nil {
+		return nil, err
+	}
+	for _, bitmap := range bitmaps {
+		if fi, err := os.Stat(bitmap); err == nil && fi.ModTime().After(status.BitmapModTime) {
+			status.Bitmap = true
+			status.BitmapModTime = fi.ModTime()
+		}
+	}
+
+	packs, err := filepath.Glob(dir.Path("objects", "pack", "*.pack"))
+	if err != nil {
+		return nil, err
+	}
+	status.Packfiles = len(packs)
+	if status.LooseObjects, err = estimateLooseObjects(dir); err != nil {
+		return nil, err
+	}
+
+	needed, reason, err := needsMaintenance(dir)
+	if err != nil {
+		return nil, err
+	}
+	if needed {
+		status.NeedsMaintenance = reason
+	}
+	status.MaintenanceFailures = bestEffortReadFailed(dir)
+	return &status, nil
+}
+
 func (s *Server) handleRepoDelete(w http.ResponseWriter, r *http.Request) {
 	var req protocol.RepoDeleteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
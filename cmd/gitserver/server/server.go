@@ -304,6 +304,10 @@ type Server struct {
 	// shared db handle
 	DB database.DB
 
+	// Peers is used to move repos cloned on this instance to the instance they
+	// belong to. Rebalancing is disabled if it is nil.
+	Peers gitserver.Client
+
 	// CloneQueue is a threadsafe queue used by DoBackgroundClones to process incoming clone
 	// requests asynchronously.
 	CloneQueue *cloneQueue
@@ -335,6 +339,9 @@ type Server struct {
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
 
+	partialCloneURLsMu sync.Mutex // protects the map below
+	partialCloneURLs   map[api.RepoName]partialCloneURL
+
 	// GlobalBatchLogSemaphore is a semaphore shared between all requests to ensure that a
 	// maximum number of Git subprocesses are active for all /batch-log requests combined.
 	GlobalBatchLogSemaphore *semaphore.Weighted
@@ -461,9 +468,11 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/is-repo-cloneable", trace.WithRouteName("is-repo-cloneable", s.handleIsRepoCloneable))
 	mux.HandleFunc("/repos-stats", trace.WithRouteName("repos-stats", s.handleReposStats))
 	mux.HandleFunc("/repo-clone-progress", trace.WithRouteName("repo-clone-progress", s.handleRepoCloneProgress))
+	mux.HandleFunc("/repo-maintenance-status", trace.WithRouteName("repo-maintenance-status", s.handleRepoMaintenanceStatus))
 	mux.HandleFunc("/delete", trace.WithRouteName("delete", s.handleRepoDelete))
 	mux.HandleFunc("/repo-update", trace.WithRouteName("repo-update", s.handleRepoUpdate))
 	mux.HandleFunc("/create-commit-from-patch", trace.WithRouteName("create-commit-from-patch", s.handleCreateCommitFromPatch))
+	mux.HandleFunc("/create-commit-from-edits", trace.WithRouteName("create-commit-from-edits", s.handleCreateCommitFromEdits))
 	mux.HandleFunc("/ping", trace.WithRouteName("ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
@@ -501,9 +510,62 @@ func (s *Server) Handler() http.Handler {
 			handleGetObject(getObjectFunc),
 		)))
 
+	mux.HandleFunc("/commands/blame", trace.WithRouteName("commands/blame",
+		accesslog.HTTPMiddleware(
+			s.Logger.Scoped("commands/blame.accesslog", "commands/blame endpoint access log"),
+			conf.DefaultClient(),
+			s.handleBlame,
+		)))
+
+	for _, newReq := range commandRequests {
+		route := "commands/" + newReq().Command()
+		mux.HandleFunc("/"+route, trace.WithRouteName(route,
+			accesslog.HTTPMiddleware(
+				s.Logger.Scoped(route+".accesslog", route+" endpoint access log"),
+				conf.DefaultClient(),
+				s.handleCommand(newReq),
+			)))
+	}
+
 	return mux
 }
 
+// repoNotCloned responds to a request for a repo that isn't cloned, which
+// starts cloning it unless auto git updates are disabled. It returns the
+// status of the request.
+func (s *Server) repoNotCloned(ctx context.Context, w http.ResponseWriter, repo api.RepoName, dir GitDir) string {
+	if conf.Get().DisableAutoGitUpdates {
+		s.Logger.Debug("not cloning on demand as DisableAutoGitUpdates is set")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
+		return "repo-not-found"
+	}
+
+	cloneProgress, cloneInProgress := s.locker.Status(dir)
+	if cloneInProgress {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
+			CloneInProgress: true,
+			CloneProgress:   cloneProgress,
+		})
+		return "clone-in-progress"
+	}
+
+	cloneProgress, err := s.cloneRepo(ctx, repo, nil)
+	if err != nil {
+		s.Logger.Debug("error starting repo clone", log.String("repo", string(repo)), log.Error(err))
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false})
+		return "repo-not-found"
+	}
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
+		CloneInProgress: true,
+		CloneProgress:   cloneProgress,
+	})
+	return "clone-in-progress"
+}
+
 // Janitor does clean up tasks over s.ReposDir and is expected to run in a
 // background goroutine.
 func (s *Server) Janitor(interval time.Duration) {
@@ -560,6 +622,7 @@ func currentGitserverAddresses() gitserver.GitServerAddresses {
 	}
 	if cfg.ExperimentalFeatures != nil {
 		gitServerAddrs.PinnedServers = cfg.ExperimentalFeatures.GitServerPinnedRepos
+		gitServerAddrs.ReplicationFactor = cfg.ExperimentalFeatures.GitServerReplicationFactor
 	}
 
 	return gitServerAddrs
@@ -637,15 +700,20 @@ func (s *Server) cloneJobConsumer(ctx context.Context, jobs <-chan *cloneJob) {
 // hostnameMatch checks whether the hostname matches the given address.
 // If we don't find an exact match, we look at the initial prefix.
 func (s *Server) hostnameMatch(addr string) bool {
-	if !strings.HasPrefix(addr, s.Hostname) {
+	return hostnameMatch(s.Hostname, addr)
+}
+
+// hostnameMatch checks whether the hostname matches the given address.
+func hostnameMatch(hostname, addr string) bool {
+	if !strings.HasPrefix(addr, hostname) {
 		return false
 	}
-	if addr == s.Hostname {
+	if addr == hostname {
 		return true
 	}
-	// We know that s.Hostname is shorter than addr so we can safely check the next
+	// We know that hostname is shorter than addr so we can safely check the next
 	// char
-	next := addr[len(s.Hostname)]
+	next := addr[len(hostname)]
 	return next == '.' || next == ':'
 }
 
@@ -765,6 +833,13 @@ func (s *Server) syncRepoState(gitServerAddrs gitserver.GitServerAddresses, batc
 		cloned := repoCloned(dir)
 		_, cloning := s.locker.Status(dir)
 
+		// A repo moved to this shard stays assigned to the shard it was moved
+		// from while only that shard has it cloned, so that it is transferred
+		// from there.
+		if s.rebalancingEnabled() && !cloned && !cloning && repo.ShardID != "" && repo.ShardID != s.Hostname && repo.CloneStatus == types.CloneStatusCloned {
+			return nil
+		}
+
 		var shouldUpdate bool
 		if repo.ShardID != s.Hostname {
 			repo.ShardID = s.Hostname
@@ -882,11 +957,12 @@ func (s *Server) tempDir(prefix string) (name string, err error) {
 }
 
 func (s *Server) ignorePath(path string) bool {
-	// We ignore any path which starts with .tmp in ReposDir
+	// We ignore any path which starts with .tmp in ReposDir, and quarantined
+	// clones.
 	if filepath.Dir(path) != s.ReposDir {
 		return false
 	}
-	return strings.HasPrefix(filepath.Base(path), tempDirName)
+	return strings.HasPrefix(filepath.Base(path), tempDirName) || filepath.Base(path) == quarantineDirName
 }
 
 func (s *Server) handleIsRepoCloneable(w http.ResponseWriter, r *http.Request) {
@@ -1068,6 +1144,12 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	req.Args = append(req.Args, treeish, "--")
 	req.Args = append(req.Args, pathspecs...)
 
+	// Secondary copies may not have the commit yet, in which case it's fetched
+	// from the primary instance.
+	if s.isSecondary(r.Context(), req.Repo) {
+		req.EnsureRevision = treeish
+	}
+
 	s.exec(w, r, req)
 }
 
@@ -1218,22 +1300,25 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, match
 			return err
 		}
 
+		fetchEnv, redactor := s.lazyFetchEnv(ctx, args.Repo, dir)
 		searcher := &search.CommitSearcher{
 			Logger:               s.Logger,
 			RepoName:             args.Repo,
 			RepoDir:              dir.Path(),
+			Env:                  fetchEnv,
 			Revisions:            args.Revisions,
 			Query:                mt,
 			IncludeDiff:          args.IncludeDiff,
 			IncludeModifiedFiles: args.IncludeModifiedFiles,
 		}
 
-		return searcher.Search(ctx, func(match *protocol.CommitMatch) {
+		err = searcher.Search(ctx, func(match *protocol.CommitMatch) {
 			select {
 			case <-done:
 			case resultChan <- match:
 			}
 		})
+		return redactError(redactor, err)
 	})
 
 	// Write matching commits to the stream, flushing occasionally
@@ -1468,13 +1553,6 @@ var blockedCommandExecutedCounter = promauto.NewCounter(prometheus.CounterOpts{
 })
 
 func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.ExecRequest) {
-	// Flush writes more aggressively than standard net/http so that clients
-	// with a context deadline see as much partial response body as possible.
-	if fw := newFlushingResponseWriter(w); fw != nil {
-		w = fw
-		defer fw.Close()
-	}
-
 	// 🚨 SECURITY: Ensure that only commands in the allowed list are executed.
 	// See https://github.com/sourcegraph/security-issues/issues/213.
 	if !gitdomain.IsAllowedGitCmd(s.Logger, req.Args) {
@@ -1486,11 +1564,39 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 		return
 	}
 
+	var limits execLimits
+	if !req.NoTimeout {
+		limits.timeout = shortGitCommandTimeout(req.Args)
+	}
+	s.runExec(w, r, req, limits)
+}
+
+// execLimits are the limits of a git command run by runExec.
+type execLimits struct {
+	// timeout is the maximum duration of the command, if positive.
+	timeout time.Duration
+	// maxOutputSize is the maximum size of the standard output of the
+	// command in bytes, if positive.
+	maxOutputSize int64
+}
+
+// runExec runs the git command of req in its repository and streams its
+// standard output to w. The exit status, error and standard error of the
+// command are written as trailers. The arguments of req must have been
+// checked by the caller.
+func (s *Server) runExec(w http.ResponseWriter, r *http.Request, req *protocol.ExecRequest, limits execLimits) {
+	// Flush writes more aggressively than standard net/http so that clients
+	// with a context deadline see as much partial response body as possible.
+	if fw := newFlushingResponseWriter(w); fw != nil {
+		w = fw
+		defer fw.Close()
+	}
+
 	ctx := r.Context()
 
-	if !req.NoTimeout {
+	if limits.timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, shortGitCommandTimeout(req.Args))
+		ctx, cancel = context.WithTimeout(ctx, limits.timeout)
 		defer cancel()
 	}
 
@@ -1498,6 +1604,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	var cmdStart time.Time // set once we have ensured commit
 	exitStatus := -10810   // sentinel value to indicate not set
 	var stdoutN, stderrN int64
+	var cpuTime time.Duration
 	var status string
 	var execErr error
 	ensureRevisionStatus := "noop"
@@ -1534,6 +1641,8 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 			duration := time.Since(start)
 			execRunning.WithLabelValues(cmd, repo).Dec()
 			execDuration.WithLabelValues(cmd, repo, status).Observe(duration.Seconds())
+			execCPUSeconds.WithLabelValues(cmd).Add(cpuTime.Seconds())
+			execOutputBytes.WithLabelValues(cmd).Add(float64(stdoutN))
 
 			var cmdDuration time.Duration
 			var fetchDuration time.Duration
@@ -1559,6 +1668,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 				ev.AddField("stdout_size", stdoutN)
 				ev.AddField("stderr_size", stderrN)
 				ev.AddField("exit_status", exitStatus)
+				ev.AddField("cpu_ms", cpuTime.Milliseconds())
 				ev.AddField("status", status)
 				if execErr != nil {
 					ev.AddField("error", execErr.Error())
@@ -1592,42 +1702,14 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 
 	dir := s.dir(req.Repo)
 	if !repoCloned(dir) {
-		if conf.Get().DisableAutoGitUpdates {
-			s.Logger.Debug("not cloning on demand as DisableAutoGitUpdates is set")
-			status = "repo-not-found"
-			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
-			return
-		}
-
-		cloneProgress, cloneInProgress := s.locker.Status(dir)
-		if cloneInProgress {
-			status = "clone-in-progress"
-			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
-				CloneInProgress: true,
-				CloneProgress:   cloneProgress,
-			})
-			return
-		}
-
-		cloneProgress, err := s.cloneRepo(ctx, req.Repo, nil)
-		if err != nil {
-			s.Logger.Debug("error starting repo clone", log.String("repo", string(req.Repo)), log.Error(err))
-			status = "repo-not-found"
-			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false})
-			return
-		}
-		status = "clone-in-progress"
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
-			CloneInProgress: true,
-			CloneProgress:   cloneProgress,
-		})
+		status = s.repoNotCloned(ctx, w, req.Repo, dir)
 		return
 	}
 
+	// Subversion revisions like r12345 resolve to the commits they were
+	// imported as in repositories mirrored from Subversion.
+	resolveSubversionRevisions(dir, req)
+
 	if !conf.Get().DisableAutoGitUpdates {
 		// ensureRevision may kick off a git fetch operation which we don't want if we've
 		// configured DisableAutoGitUpdates.
@@ -1673,24 +1755,49 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	stdoutW := &writeCounter{w: w}
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
+	// Writes fail once the output exceeds its maximum size, which stops the
+	// command.
+	var stdout io.Writer = stdoutW
+	var maxSizeW *maxSizeWriter
+	if limits.maxOutputSize > 0 {
+		maxSizeW = &maxSizeWriter{w: stdoutW, n: limits.maxOutputSize}
+		stdout = maxSizeW
+	}
+
 	cmdStart = time.Now()
 	cmd := exec.CommandContext(ctx, "git", req.Args...)
 	dir.Set(cmd)
-	cmd.Stdout = stdoutW
+	fetchEnv, redactor := s.lazyFetchEnv(ctx, req.Repo, dir)
+	withLazyFetchEnv(cmd, fetchEnv)
+	cmd.Stdout = stdout
 	cmd.Stderr = stderrW
 
 	exitStatus, execErr = runCommand(ctx, cmd)
 
 	status = strconv.Itoa(exitStatus)
+	switch {
+	case maxSizeW != nil && maxSizeW.exceeded:
+		status = "output_too_large"
+		execErr = errors.Errorf("output exceeds the maximum size of %d bytes", limits.maxOutputSize)
+	case errors.Is(ctx.Err(), context.Canceled):
+		status = "canceled"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		status = "timeout"
+	}
+	if cmd.ProcessState != nil {
+		cpuTime = cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+	}
 	stdoutN = stdoutW.n
 	stderrN = stderrW.n
 
-	stderr := stderrBuf.String()
+	// git prints the remote URL of partial clones when fetching missing
+	// objects fails.
+	stderr := redactor.redact(stderrBuf.String())
 	checkMaybeCorruptRepo(req.Repo, dir, stderr)
 
 	// write trailer
 	w.Header().Set("X-Exec-Error", errorString(execErr))
-	w.Header().Set("X-Exec-Exit-Status", status)
+	w.Header().Set("X-Exec-Exit-Status", strconv.Itoa(exitStatus))
 	w.Header().Set("X-Exec-Stderr", stderr)
 }
 
@@ -1864,6 +1971,9 @@ func (s *Server) p4exec(w http.ResponseWriter, r *http.Request, req *protocol.P4
 }
 
 func (s *Server) setLastFetched(ctx context.Context, name api.RepoName) error {
+	if s.isSecondary(ctx, name) {
+		return nil
+	}
 	dir := s.dir(name)
 
 	lastFetched, err := repoLastFetched(dir)
@@ -1885,6 +1995,10 @@ func (s *Server) setLastFetched(ctx context.Context, name api.RepoName) error {
 
 // setLastErrorNonFatal will set the last_error column for the repo in the gitserver table.
 func (s *Server) setLastErrorNonFatal(ctx context.Context, name api.RepoName, err error) {
+	if s.isSecondary(ctx, name) {
+		return
+	}
+
 	var errString string
 	if err != nil {
 		errString = err.Error()
@@ -1896,6 +2010,9 @@ func (s *Server) setLastErrorNonFatal(ctx context.Context, name api.RepoName, er
 }
 
 func (s *Server) setCloneStatus(ctx context.Context, name api.RepoName, status types.CloneStatus) (err error) {
+	if s.isSecondary(ctx, name) {
+		return nil
+	}
 	return s.DB.GitserverRepos().SetCloneStatus(ctx, name, status, s.Hostname)
 }
 
@@ -1908,7 +2025,10 @@ func (s *Server) setCloneStatusNonFatal(ctx context.Context, name api.RepoName,
 
 // setRepoSize calculates the size of the repo and stores it in the database.
 func (s *Server) setRepoSize(ctx context.Context, name api.RepoName) error {
-	return s.DB.GitserverRepos().SetRepoSize(ctx, name, dirSize(s.dir(name).Path(".")), s.Hostname)
+	if s.isSecondary(ctx, name) {
+		return nil
+	}
+	return s.DB.GitserverRepos().SetRepoSize(ctx, name, gitDirSize(s.dir(name)), s.Hostname)
 }
 
 // setGitAttributes writes our global gitattributes to
@@ -1952,6 +2072,10 @@ type cloneOptions struct {
 	// repository. If this is a non-zero string, then gitserver will attempt to clone the repo from
 	// that gitserver instance instead of the upstream repo URL of the external service.
 	CloneFromShard string
+
+	// QuarantinePath is the path the existing clone is moved to when it is
+	// overwritten, instead of removing it.
+	QuarantinePath string
 }
 
 // cloneRepo performs a clone operation for the given repository. It is
@@ -1981,13 +2105,18 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	}
 
 	var remoteURL *vcs.URL
-	if opts != nil && opts.CloneFromShard != "" {
+	if primary, ok := s.secondaryOf(ctx, repo, currentGitserverAddresses()); ok && !s.partialCloneConfigured(ctx, repo) {
+		// Secondary copies are mirrored from the primary instance.
+		remoteURL, err = shardURL(primary, repo)
+		syncer = &shardSyncer{typ: syncer.Type(), shard: primary}
+	} else if opts != nil && opts.CloneFromShard != "" && transferableRepoType(syncer.Type()) && !s.partialCloneConfigured(ctx, repo) {
 		// are we cloning from the same gitserver instance?
 		if s.hostnameMatch(strings.TrimPrefix(opts.CloneFromShard, "http://")) {
 			return "", errors.Errorf("cannot clone from the same gitserver instance")
 		}
 
-		remoteURL, err = vcs.ParseURL(filepath.Join(opts.CloneFromShard, "git", string(repo)))
+		remoteURL, err = shardURL(opts.CloneFromShard, repo)
+		syncer = &shardSyncer{typ: syncer.Type(), shard: opts.CloneFromShard}
 	} else {
 		// We may be attempting to clone a private repo so we need an internal actor.
 		remoteURL, err = s.getRemoteURL(actor.WithInternalActor(ctx), repo)
@@ -2011,6 +2140,17 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		return "", err
 	}
 
+	// A repo moved to this instance that is still cloned on the instance it
+	// was moved from is transferred from there, unless it isn't reachable.
+	if _, ok := syncer.(*shardSyncer); !ok && s.rebalancingEnabled() && transferableRepoType(syncer.Type()) && partialCloneFilter(remoteURL) == "" {
+		if from := s.previousShard(ctx, repo, currentGitserverAddresses().Addresses); from != "" {
+			fromSyncer := &shardSyncer{typ: syncer.Type(), shard: from}
+			if fromURL, err := shardURL(from, repo); err == nil && fromSyncer.IsCloneable(ctx, fromURL) == nil {
+				remoteURL, syncer = fromURL, fromSyncer
+			}
+		}
+	}
+
 	if err := syncer.IsCloneable(ctx, remoteURL); err != nil {
 		redactedErr := newURLRedactor(remoteURL).redact(err.Error())
 		return "", errors.Errorf("error cloning repo: repo %s not cloneable: %s", repo, redactedErr)
@@ -2019,7 +2159,11 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	// Mark this repo as currently being cloned. We have to check again if someone else isn't already
 	// cloning since we released the lock. We released the lock since isCloneable is a potentially
 	// slow operation.
-	lock, ok := s.locker.TryAcquire(dir, "starting clone")
+	status := "starting clone"
+	if from, ok := syncer.(*shardSyncer); ok {
+		status = "starting transfer from " + from.shard
+	}
+	lock, ok := s.locker.TryAcquire(dir, status)
 	if !ok {
 		// Someone else beat us to it
 		status, _ := s.locker.Status(dir)
@@ -2106,25 +2250,36 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 		s.setCloneStatusNonFatal(context.Background(), repo, cloneStatus(repoCloned(dir), false))
 	}()
 
-	cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
-	if err != nil {
-		return errors.Wrap(err, "get clone command")
-	}
-	if cmd.Env == nil {
-		cmd.Env = os.Environ()
-	}
-
-	// see issue #7322: skip LFS content in repositories with Git LFS configured
-	cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
-	s.Logger.Info("cloning repo", log.String("repo", string(repo)), log.String("tmp", tmpPath), log.String("dst", dstPath))
-
 	pr, pw := io.Pipe()
 	defer pw.Close()
 
-	go readCloneProgress(newURLRedactor(remoteURL), lock, pr, repo)
+	var progressPrefix string
+	if from, ok := syncer.(*shardSyncer); ok {
+		progressPrefix = "transferring from " + from.shard + ": "
+	}
+	go readCloneProgress(newURLRedactor(remoteURL), lock, pr, repo, progressPrefix)
+
+	if cloner, ok := syncer.(multiStepCloner); ok {
+		s.Logger.Info("cloning repo", log.String("repo", string(repo)), log.String("tmp", tmpPath), log.String("dst", dstPath))
+		if err := cloner.Clone(ctx, remoteURL, tmpPath, pw); err != nil {
+			return errors.Wrap(err, "clone failed")
+		}
+	} else {
+		cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
+		if err != nil {
+			return errors.Wrap(err, "get clone command")
+		}
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+
+		// see issue #7322: skip LFS content in repositories with Git LFS configured
+		cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
+		s.Logger.Info("cloning repo", log.String("repo", string(repo)), log.String("tmp", tmpPath), log.String("dst", dstPath))
 
-	if output, err := runWith(ctx, cmd, true, pw); err != nil {
-		return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+		if output, err := runWith(ctx, cmd, true, pw); err != nil {
+			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+		}
 	}
 
 	if testRepoCorrupter != nil {
@@ -2158,8 +2313,16 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 	}
 
 	if overwrite {
-		// remove the current repo by putting it into our temporary directory
-		err := fileutil.RenameAndSync(dstPath, filepath.Join(filepath.Dir(tmpPath), "old"))
+		// remove the current repo by putting it into our temporary directory,
+		// or into quarantine.
+		old := filepath.Join(filepath.Dir(tmpPath), "old")
+		if opts.QuarantinePath != "" {
+			old = opts.QuarantinePath
+			if err := os.MkdirAll(filepath.Dir(old), os.ModePerm); err != nil {
+				return err
+			}
+		}
+		err := fileutil.RenameAndSync(dstPath, old)
 		if err != nil && !os.IsNotExist(err) {
 			return errors.Wrapf(err, "failed to remove old clone")
 		}
@@ -2191,7 +2354,7 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 
 // readCloneProgress scans the reader and saves the most recent line of output
 // as the lock status.
-func readCloneProgress(redactor *urlRedactor, lock *RepositoryLock, pr io.Reader, repo api.RepoName) {
+func readCloneProgress(redactor *urlRedactor, lock *RepositoryLock, pr io.Reader, repo api.RepoName, prefix string) {
 	var logFile *os.File
 	var err error
 	logger := log.Scoped("readCloneProgress", "scans the reader and saves the most recent line of output")
@@ -2220,7 +2383,7 @@ func readCloneProgress(redactor *urlRedactor, lock *RepositoryLock, pr io.Reader
 		// fatal: repository 'http://token@github.com/foo/bar/' not found
 		redactedProgress := redactor.redact(progress)
 
-		lock.SetStatus(redactedProgress)
+		lock.SetStatus(prefix + redactedProgress)
 
 		if logFile != nil {
 			// Failing to write here is non-fatal and we don't want to spam our logs if there
@@ -2311,6 +2474,14 @@ var (
 		Help:    "gitserver.GitCommand latencies in seconds.",
 		Buckets: trace.UserLatencyBuckets,
 	}, []string{"cmd", "repo", "status"})
+	execCPUSeconds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_exec_cpu_seconds_total",
+		Help: "CPU time used by gitserver.GitCommand in seconds.",
+	}, []string{"cmd"})
+	execOutputBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_exec_output_bytes_total",
+		Help: "bytes of standard output written by gitserver.GitCommand.",
+	}, []string{"cmd"})
 
 	searchRunning = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "src_gitserver_search_running",
@@ -2478,6 +2649,15 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 		return errors.Wrap(err, "get VCS syncer")
 	}
 
+	// Secondary copies are mirrored from the primary instance, except for
+	// partial clones.
+	if primary, ok := s.secondaryOf(ctx, repo, currentGitserverAddresses()); ok && !isPartialClone(dir) {
+		if remoteURL, err = shardURL(primary, repo); err != nil {
+			return err
+		}
+		syncer = &shardSyncer{typ: syncer.Type(), shard: primary}
+	}
+
 	// drop temporary pack files after a fetch. this function won't
 	// return until this fetch has completed or definitely-failed,
 	// either way they can't still be in use. we don't care exactly
@@ -2491,6 +2671,10 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 
 	removeBadRefs(ctx, dir)
 
+	if commitGraphAfterFetch {
+		s.updateCommitGraph(ctx, repo, dir)
+	}
+
 	if err := setHEAD(ctx, dir, syncer, repo, remoteURL); err != nil {
 		s.Logger.Error("Failed to ensure HEAD exists", log.String("repo", string(repo)), log.Error(err))
 		return errors.Wrap(err, "failed to ensure HEAD exists")
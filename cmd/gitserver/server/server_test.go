@@ -126,6 +126,35 @@ func TestRequest(t *testing.T) {
 			ExpectedCode: http.StatusBadRequest,
 			ExpectedBody: "invalid command",
 		},
+		{
+			Name:         "TypedCommand",
+			Request:      httptest.NewRequest("POST", "/commands/ls-tree", strings.NewReader(`{"repo": "github.com/gorilla/mux", "tree": "HEAD", "nameOnly": true, "paths": ["a"]}`)),
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: "ls-tree --name-only HEAD -- a",
+			ExpectedTrailers: http.Header{
+				"X-Exec-Error":       {""},
+				"X-Exec-Exit-Status": {"0"},
+				"X-Exec-Stderr":      {""},
+			},
+		},
+		{
+			Name:         "TypedCommandHTTPGet",
+			Request:      httptest.NewRequest("GET", "/commands/log", strings.NewReader("{}")),
+			ExpectedCode: http.StatusMethodNotAllowed,
+			ExpectedBody: "",
+		},
+		{
+			Name:         "TypedCommandNonexistingRepo",
+			Request:      httptest.NewRequest("POST", "/commands/rev-parse", strings.NewReader(`{"repo": "github.com/gorilla/doesnotexist", "revisions": ["HEAD"]}`)),
+			ExpectedCode: http.StatusNotFound,
+			ExpectedBody: `{"cloneInProgress":false}`,
+		},
+		{
+			Name:         "TypedCommandBadRevision",
+			Request:      httptest.NewRequest("POST", "/commands/show", strings.NewReader(`{"repo": "github.com/gorilla/mux", "object": "--output=/tmp/x"}`)),
+			ExpectedCode: http.StatusBadRequest,
+			ExpectedBody: `invalid git revision spec "--output=/tmp/x"`,
+		},
 	}
 
 	db := database.NewMockDB()
@@ -167,6 +196,9 @@ func TestRequest(t *testing.T) {
 			return 42, nil
 		case "testerror":
 			return 0, errors.New("testerror")
+		case "ls-tree":
+			_, _ = cmd.Stdout.Write([]byte(strings.Join(cmd.Args[1:], " ")))
+			return 0, nil
 		}
 		return 0, nil
 	}
@@ -676,6 +708,13 @@ func TestCloneRepoRecordsFailures(t *testing.T) {
 			},
 			wantErr: errors.New("failed to clone example.com/foo/bar: clone failed. Output: fatal: repository '/dev/null' does not exist: exit status 128"),
 		},
+		{
+			name: "Failing multi-step clone",
+			getVCSSyncer: func(ctx context.Context, name api.RepoName) (VCSSyncer, error) {
+				return &multiStepMockSyncer{MockVCSSyncer: NewMockVCSSyncer(), err: errors.New("conversion failed")}, nil
+			},
+			wantErr: errors.New("failed to clone example.com/foo/bar: clone failed: conversion failed"),
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			s.GetVCSSyncer = tc.getVCSSyncer
@@ -687,6 +726,17 @@ func TestCloneRepoRecordsFailures(t *testing.T) {
 	}
 }
 
+// multiStepMockSyncer is a VCSSyncer which clones repositories in multiple
+// steps.
+type multiStepMockSyncer struct {
+	*MockVCSSyncer
+	err error
+}
+
+func (s *multiStepMockSyncer) Clone(context.Context, *vcs.URL, string, io.Writer) error {
+	return s.err
+}
+
 func TestHandleRepoDelete(t *testing.T) {
 	testHandleRepoDelete(t, false)
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/sourcegraph/log"
 
@@ -14,6 +15,26 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
+var (
+	blameCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_blame_cache_requests_total",
+		Help: "number of blames by whether they were cached (hit), computed from the blame in the parent commit (incremental), or computed with git (miss)",
+	}, []string{"result"})
+	blameDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_blame_duration_seconds",
+		Help:    "time taken to blame a file by blame cache result",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"result"})
+	blameCacheEvicted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_blame_cache_evicted_total",
+		Help: "number of cached blames evicted by the janitor",
+	})
+	blameCacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_blame_cache_size_bytes",
+		Help: "size of the blame caches of all repos on disk, as of the last janitor run",
+	})
+)
+
 func (s *Server) RegisterMetrics(db dbutil.DB, observationContext *observation.Context) {
 	// test the latency of exec, which may increase under certain memory
 	// conditions
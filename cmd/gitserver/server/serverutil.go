@@ -193,24 +193,7 @@ func configureRemoteGitCommand(cmd *exec.Cmd, tlsConf *tlsConfig) {
 		panic(fmt.Sprintf("Only git or p4-fusion commands are supported, got %q", executable))
 	}
 
-	cmd.Env = append(cmd.Env, "GIT_ASKPASS=true") // disable password prompt
-
-	// Suppress asking to add SSH host key to known_hosts (which will hang because
-	// the command is non-interactive).
-	//
-	// And set a timeout to avoid indefinite hangs if the server is unreachable.
-	cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes -o ConnectTimeout=30")
-
-	// Identify HTTP requests with a user agent. Please keep the git/ prefix because GitHub breaks the protocol v2
-	// negotiation of clone URLs without a `.git` suffix (which we use) without it. Don't ask.
-	cmd.Env = append(cmd.Env, "GIT_HTTP_USER_AGENT=git/Sourcegraph-Bot")
-
-	if tlsConf.SSLNoVerify {
-		cmd.Env = append(cmd.Env, "GIT_SSL_NO_VERIFY=true")
-	}
-	if tlsConf.SSLCAInfo != "" {
-		cmd.Env = append(cmd.Env, "GIT_SSL_CAINFO="+tlsConf.SSLCAInfo)
-	}
+	cmd.Env = append(cmd.Env, remoteGitCommandEnv(tlsConf)...)
 
 	extraArgs := []string{
 		// Unset credential helper because the command is non-interactive.
@@ -226,6 +209,30 @@ func configureRemoteGitCommand(cmd *exec.Cmd, tlsConf *tlsConfig) {
 	cmd.Args = append(cmd.Args[:1], append(extraArgs, cmd.Args[1:]...)...)
 }
 
+// remoteGitCommandEnv returns the environment variables of git commands that
+// talk to a remote.
+func remoteGitCommandEnv(tlsConf *tlsConfig) []string {
+	env := []string{"GIT_ASKPASS=true"} // disable password prompt
+
+	// Suppress asking to add SSH host key to known_hosts (which will hang because
+	// the command is non-interactive).
+	//
+	// And set a timeout to avoid indefinite hangs if the server is unreachable.
+	env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes -o ConnectTimeout=30")
+
+	// Identify HTTP requests with a user agent. Please keep the git/ prefix because GitHub breaks the protocol v2
+	// negotiation of clone URLs without a `.git` suffix (which we use) without it. Don't ask.
+	env = append(env, "GIT_HTTP_USER_AGENT=git/Sourcegraph-Bot")
+
+	if tlsConf.SSLNoVerify {
+		env = append(env, "GIT_SSL_NO_VERIFY=true")
+	}
+	if tlsConf.SSLCAInfo != "" {
+		env = append(env, "GIT_SSL_CAINFO="+tlsConf.SSLCAInfo)
+	}
+	return env
+}
+
 // writeTempFile writes data to the TempFile with pattern. Returns the path of
 // the tempfile.
 func writeTempFile(pattern string, data []byte) (path string, err error) {
@@ -377,6 +384,30 @@ func (l *limitWriter) Write(p []byte) (int, error) {
 	return n, err
 }
 
+// errMaxSizeExceeded is returned by maxSizeWriter once n bytes were written.
+var errMaxSizeExceeded = errors.New("maximum size exceeded")
+
+// maxSizeWriter is a io.Writer that writes to w, but fails once more than n
+// bytes would be written. Unlike limitWriter, it doesn't discard writes
+// silently, so that a command writing to it stops.
+type maxSizeWriter struct {
+	w        io.Writer
+	n        int64 // max bytes remaining
+	exceeded bool
+}
+
+func (m *maxSizeWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > m.n {
+		m.exceeded = true
+		n, _ := m.w.Write(p[:m.n])
+		m.n -= int64(n)
+		return n, errMaxSizeExceeded
+	}
+	n, err := m.w.Write(p)
+	m.n -= int64(n)
+	return n, err
+}
+
 // flushingResponseWriter is a http.ResponseWriter that flushes all writes
 // to the underlying connection within a certain time period after Write is
 // called (instead of buffering them indefinitely).
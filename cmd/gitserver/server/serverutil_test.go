@@ -310,3 +310,17 @@ type flushFunc func()
 func (f flushFunc) Flush() {
 	f()
 }
+
+func TestMaxSizeWriter(t *testing.T) {
+	var buf strings.Builder
+	w := &maxSizeWriter{w: &buf, n: 5}
+	if _, err := w.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.Write([]byte("def")); err != errMaxSizeExceeded || n != 2 {
+		t.Fatalf("got %d, %v, want 2, %v", n, err, errMaxSizeExceeded)
+	}
+	if !w.exceeded || buf.String() != "abcde" {
+		t.Errorf("unexpected output %q (exceeded: %v)", buf.String(), w.exceeded)
+	}
+}
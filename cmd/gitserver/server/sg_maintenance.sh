@@ -23,6 +23,9 @@
 # flags.
 # - We omit the commands "git rerere" and "git worktree prune" because they
 # don't apply to our use-case.
+# - The commit-graph is written by gitserver after this script, with the same
+# options as the layers it adds to the commit-graph after each fetch. See
+# writeCommitGraph.
 #
 # git-maintenance
 # ---------------
@@ -53,11 +56,3 @@ git reflog expire --all
 # pack.deltaCacheSize and pack.threads in addition to --geometric=2 seemed to
 # have no effect.
 git repack -d -l -A --write-bitmap-index --window-memory 100m --unpack-unreachable=now
-
-# With the --changed-paths option, compute and write information about the
-# paths changed between a commit and its first parent. This operation can take
-# a while on large repositories. It provides significant performance gains for
-# getting history of a directory or a file with git log -- <path>. If this
-# option is given, future commit-graph writes will automatically assume that
-# this option was intended
-git commit-graph write --reachable --changed-paths
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"io"
 	"os/exec"
 
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
@@ -29,6 +30,16 @@ type VCSSyncer interface {
 	RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error)
 }
 
+// multiStepCloner is implemented by VCSSyncers which cannot clone a
+// repository with a single command, like syncers converting repositories of
+// other version control systems. doClone calls Clone instead of running the
+// command returned by CloneCommand.
+type multiStepCloner interface {
+	// Clone clones the repository at remoteURL into a bare Git repository at
+	// tmpPath. The output of the steps is written to progress.
+	Clone(ctx context.Context, remoteURL *vcs.URL, tmpPath string, progress io.Writer) error
+}
+
 type notFoundError struct{ error }
 
 func (e notFoundError) NotFound() bool { return true }
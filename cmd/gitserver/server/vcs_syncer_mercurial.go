@@ -0,0 +1,262 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// MercurialSyncer is a syncer for Mercurial repositories. A Mercurial
+// repository is mirrored into the bare Git repository: the changesets are
+// pulled into a Mercurial repository inside the Git directory and converted
+// with hg-git, after which the converted commits are fetched into the Git
+// repository.
+type MercurialSyncer struct{}
+
+const (
+	// mercurialDir is the Mercurial repository inside the Git directory.
+	mercurialDir = "hg"
+
+	// mercurialMapFile is the file in the Git directory mapping Mercurial
+	// changesets to Git commits. Each line contains a changeset ID and the
+	// SHA of the corresponding commit, separated by a space.
+	mercurialMapFile = "hg-map"
+)
+
+func (s *MercurialSyncer) Type() string {
+	return "mercurial"
+}
+
+// IsCloneable checks to see if the Mercurial remote URL is cloneable.
+func (s *MercurialSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, shortGitCommandTimeout(nil))
+	defer cancel()
+
+	cmd := hgCommand(ctx, "identify", "--id", "--", remoteURL.String())
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns an error, since the conversion cannot be expressed as
+// a single command. Mercurial repositories are cloned with Clone.
+func (s *MercurialSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	return nil, errors.New("Mercurial repositories cannot be cloned with a single command")
+}
+
+// Clone creates a bare Git repository at tmpPath and mirrors the Mercurial
+// repository into it.
+func (s *MercurialSyncer) Clone(ctx context.Context, remoteURL *vcs.URL, tmpPath string, progress io.Writer) error {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	cmd.Dir = tmpPath
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "clone setup failed with output %q", string(output))
+	}
+
+	if err := s.fetch(ctx, remoteURL, GitDir(tmpPath), progress); err != nil {
+		return errors.Wrapf(err, "failed to mirror Mercurial repository")
+	}
+	return nil
+}
+
+// Fetch pulls new changesets from the Mercurial remote, converts them to Git
+// commits and updates the branches, tags and changeset mapping of the Git
+// repository at dir.
+func (s *MercurialSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	return s.fetch(ctx, remoteURL, dir, nil)
+}
+
+// fetch implements Fetch. The output of pulling and converting changesets is
+// written to progress, if not nil.
+func (s *MercurialSyncer) fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, progress io.Writer) error {
+	hgDir := dir.Path(mercurialDir)
+	redactor := newURLRedactor(remoteURL)
+
+	if _, err := os.Stat(hgDir); os.IsNotExist(err) {
+		cmd := hgCommand(ctx, "init", hgDir)
+		if output, err := runWith(ctx, cmd, false, nil); err != nil {
+			return errors.Wrapf(err, "failed to init Mercurial repository with output %q", string(output))
+		}
+	}
+
+	// We pull from the remote URL instead of storing it as the default path
+	// of the Mercurial repository, so that credentials are not written to
+	// disk and changes to the URL take effect.
+	cmd := hgCommand(ctx, "pull", "--", remoteURL.String())
+	cmd.Dir = hgDir
+	if output, err := runWith(ctx, cmd, false, progress); err != nil {
+		return errors.Wrapf(err, "failed to pull with output %q", redactor.redact(string(output)))
+	}
+
+	if err := s.updateBookmarks(ctx, hgDir); err != nil {
+		return err
+	}
+
+	// hg-git converts all changesets that it hasn't converted yet into the
+	// Git repository at .hg/git and records them in .hg/git-mapfile.
+	cmd = hgCommand(ctx, "gexport")
+	cmd.Dir = hgDir
+	if output, err := runWith(ctx, cmd, false, progress); err != nil {
+		return errors.Wrapf(err, "failed to convert changesets with output %q", string(output))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "fetch", "--prune", "--force", dir.Path(mercurialDir, ".hg", "git"),
+		"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch converted commits with output %q", string(output))
+	}
+
+	return writeMercurialMap(dir)
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote
+// of a Mercurial repository.
+func (s *MercurialSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	// Remote info is encoded as in the current repository
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// updateBookmarks points a bookmark at the head of every open named branch,
+// since hg-git only exports bookmarks as Git branches.
+func (s *MercurialSyncer) updateBookmarks(ctx context.Context, hgDir string) error {
+	// hg heads lists the newest head of a branch first.
+	cmd := hgCommand(ctx, "heads", "--template", "{branch}\\t{node}\\n")
+	cmd.Dir = hgDir
+	output, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list heads with output %q", string(output))
+	}
+
+	for branch, node := range parseMercurialHeads(string(output)) {
+		cmd := hgCommand(ctx, "bookmark", "--force", "--rev", node, mercurialBookmarkName(branch))
+		cmd.Dir = hgDir
+		if output, err := runWith(ctx, cmd, false, nil); err != nil {
+			return errors.Wrapf(err, "failed to update bookmark for branch %q with output %q", branch, string(output))
+		}
+	}
+	return nil
+}
+
+// parseMercurialHeads parses the output of hg heads into a map from branch
+// to its newest head.
+func parseMercurialHeads(output string) map[string]string {
+	heads := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		branch, node, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if _, ok := heads[branch]; !ok {
+			heads[branch] = node
+		}
+	}
+	return heads
+}
+
+// mercurialBookmarkName returns the name of the bookmark, and so the Git
+// branch, for the named branch. The "default" branch becomes "master", and
+// spaces, which are not allowed in Git refs, are replaced.
+func mercurialBookmarkName(branch string) string {
+	if branch == "default" {
+		return "master"
+	}
+	return strings.ReplaceAll(branch, " ", "-")
+}
+
+// hgCommand returns a command running hg with the hg-git extension enabled.
+// HGPLAIN disables any user configuration that changes the output of hg.
+func hgCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "hg", append([]string{"--config", "extensions.hggit=", "--noninteractive"}, args...)...)
+	cmd.Env = append(os.Environ(), "HGPLAIN=1")
+	return cmd
+}
+
+// writeMercurialMap writes the mapping from Mercurial changesets to Git
+// commits maintained by hg-git to the mercurialMapFile of dir.
+func writeMercurialMap(dir GitDir) error {
+	m := map[string]string{}
+	// The mapfile doesn't exist until the first changeset is converted.
+	f, err := os.Open(dir.Path(mercurialDir, ".hg", "git-mapfile"))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to open hg-git mapfile")
+	}
+	if err == nil {
+		defer f.Close()
+		if m, err = parseHgGitMapfile(f); err != nil {
+			return err
+		}
+	}
+
+	changesets := make([]string, 0, len(m))
+	for changeset := range m {
+		changesets = append(changesets, changeset)
+	}
+	sort.Strings(changesets)
+
+	var buf bytes.Buffer
+	for _, changeset := range changesets {
+		buf.WriteString(changeset)
+		buf.WriteByte(' ')
+		buf.WriteString(m[changeset])
+		buf.WriteByte('\n')
+	}
+
+	tmp := dir.Path(mercurialMapFile + ".tmp")
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return errors.Wrap(err, "failed to write changeset mapping")
+	}
+	return os.Rename(tmp, dir.Path(mercurialMapFile))
+}
+
+// parseHgGitMapfile parses the mapfile of hg-git, which contains a Git SHA
+// and a Mercurial changeset ID per line, into a map from changeset to SHA.
+func parseHgGitMapfile(r io.Reader) (map[string]string, error) {
+	m := map[string]string{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || !isHexID(fields[0]) || !isHexID(fields[1]) {
+			return nil, errors.Errorf("malformed hg-git mapfile line %q", s.Text())
+		}
+		m[fields[1]] = fields[0]
+	}
+	return m, s.Err()
+}
+
+// isHexID reports whether s is a full 40 character hexadecimal ID, as used
+// for both Git commits and Mercurial changesets.
+func isHexID(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
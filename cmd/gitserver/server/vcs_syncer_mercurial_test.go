@@ -0,0 +1,103 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseMercurialHeads(t *testing.T) {
+	output := "default\t" + strings.Repeat("c", 40) + "\n" +
+		"stable\t" + strings.Repeat("b", 40) + "\n" +
+		"default\t" + strings.Repeat("a", 40) + "\n"
+
+	want := map[string]string{
+		"default": strings.Repeat("c", 40),
+		"stable":  strings.Repeat("b", 40),
+	}
+	if diff := cmp.Diff(want, parseMercurialHeads(output)); diff != "" {
+		t.Errorf("unexpected heads (-want +got):\n%s", diff)
+	}
+}
+
+func TestMercurialBookmarkName(t *testing.T) {
+	for branch, want := range map[string]string{
+		"default":      "master",
+		"stable":       "stable",
+		"release 1.0":  "release-1.0",
+		"feature/test": "feature/test",
+	} {
+		if got := mercurialBookmarkName(branch); got != want {
+			t.Errorf("mercurialBookmarkName(%q) = %q, want %q", branch, got, want)
+		}
+	}
+}
+
+func TestParseHgGitMapfile(t *testing.T) {
+	sha1, sha2 := strings.Repeat("1", 40), strings.Repeat("2", 40)
+	hg1, hg2 := strings.Repeat("a", 40), strings.Repeat("b", 40)
+
+	t.Run("valid", func(t *testing.T) {
+		m, err := parseHgGitMapfile(strings.NewReader(sha1 + " " + hg1 + "\n\n" + sha2 + " " + hg2 + "\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{hg1: sha1, hg2: sha2}
+		if diff := cmp.Diff(want, m); diff != "" {
+			t.Errorf("unexpected mapping (-want +got):\n%s", diff)
+		}
+	})
+
+	for _, input := range []string{
+		sha1,
+		sha1 + " " + hg1 + " extra",
+		sha1 + " abc",
+		sha1 + " " + strings.ToUpper(hg1),
+	} {
+		if _, err := parseHgGitMapfile(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestWriteMercurialMap(t *testing.T) {
+	dir := GitDir(t.TempDir())
+
+	// Without converted changesets the mapping is empty.
+	if err := writeMercurialMap(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, dir.Path(mercurialMapFile)); got != "" {
+		t.Errorf("expected empty mapping, got %q", got)
+	}
+
+	sha1, sha2 := strings.Repeat("1", 40), strings.Repeat("2", 40)
+	hg1, hg2 := strings.Repeat("a", 40), strings.Repeat("b", 40)
+	if err := os.MkdirAll(dir.Path(mercurialDir, ".hg"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	mapfile := sha2 + " " + hg2 + "\n" + sha1 + " " + hg1 + "\n"
+	if err := os.WriteFile(filepath.Join(dir.Path(mercurialDir, ".hg"), "git-mapfile"), []byte(mapfile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeMercurialMap(dir); err != nil {
+		t.Fatal(err)
+	}
+	want := hg1 + " " + sha1 + "\n" + hg2 + " " + sha2 + "\n"
+	if got := readFile(t, dir.Path(mercurialMapFile)); got != want {
+		t.Errorf("unexpected mapping:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
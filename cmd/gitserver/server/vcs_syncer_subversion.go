@@ -0,0 +1,462 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// SubversionSyncer is a syncer for Subversion repositories. The repository is
+// converted into the bare Git repository with git svn, which resumes from the
+// last imported revision on every fetch. The trunk becomes the master branch,
+// and the Subversion branches and tags become Git branches and tags.
+type SubversionSyncer struct {
+	// Layout is the layout of the repository. Nil means the standard layout.
+	Layout *schema.SubversionLayout
+}
+
+const (
+	// subversionRemotePrefix is the prefix of the refs git svn imports into.
+	subversionRemotePrefix = "refs/remotes/svn/"
+
+	// subversionRevMapFile is the file in the Git directory mapping Subversion
+	// revisions to Git commits. Each line contains a revision number and the
+	// SHA of the corresponding commit, separated by a space, sorted by
+	// revision.
+	subversionRevMapFile = "svn-revmap"
+)
+
+func (s *SubversionSyncer) Type() string {
+	return "subversion"
+}
+
+// IsCloneable checks to see if the Subversion remote URL is cloneable.
+func (s *SubversionSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	svnURL, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	ctx, cancel := context.WithTimeout(ctx, shortGitCommandTimeout(nil))
+	defer cancel()
+
+	args := []string{"info", "--non-interactive", "--no-auth-cache"}
+	if username != "" {
+		args = append(args, "--username", username, "--password-from-stdin")
+	}
+	cmd := exec.CommandContext(ctx, "svn", append(args, svnURL)...)
+	cmd.Stdin = strings.NewReader(password)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns an error, since the refs need to be updated after the
+// import. Subversion repositories are cloned with Clone.
+func (s *SubversionSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	return nil, errors.New("Subversion repositories cannot be cloned with a single command")
+}
+
+// Clone creates a bare Git repository at tmpPath, configures git svn for the
+// Subversion repository and imports all revisions.
+func (s *SubversionSyncer) Clone(ctx context.Context, remoteURL *vcs.URL, tmpPath string, progress io.Writer) error {
+	svnURL, username, _ := decomposeSubversionRemoteURL(remoteURL)
+
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	cmd.Dir = tmpPath
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "clone setup failed with output %q", string(output))
+	}
+
+	args := append([]string{"svn", "init", "--prefix=svn/"}, s.layoutOptions()...)
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+	cmd = exec.CommandContext(ctx, "git", append(args, svnURL)...)
+	cmd.Dir = tmpPath
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "git svn init failed with output %q", string(output))
+	}
+
+	if err := s.fetch(ctx, remoteURL, GitDir(tmpPath), progress); err != nil {
+		return errors.Wrapf(err, "failed to import Subversion repository")
+	}
+	return nil
+}
+
+// Fetch imports the revisions committed since the last fetch, and updates the
+// branches, tags and revision mapping of the Git repository at dir.
+func (s *SubversionSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	return s.fetch(ctx, remoteURL, dir, nil)
+}
+
+// fetch implements Fetch. The output of git svn is written to progress, if
+// not nil.
+func (s *SubversionSyncer) fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, progress io.Writer) error {
+	_, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	args := []string{"svn", "fetch"}
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+
+	// git svn asks for the password with the program in GIT_ASKPASS.
+	askpass, err := writeSubversionAskpass()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(askpass)
+	cmd.Env = append(os.Environ(), "GIT_ASKPASS="+askpass, "SRC_SVN_PASSWORD="+password)
+
+	if output, err := runWith(ctx, cmd, false, progress); err != nil {
+		return errors.Wrapf(err, "failed to fetch with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	if err := updateSubversionRefs(ctx, dir); err != nil {
+		return err
+	}
+	return writeSubversionRevMap(dir)
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote
+// of a Subversion repository.
+func (s *SubversionSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	// Remote info is encoded as in the current repository
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// layoutOptions returns the options of git svn init for the layout.
+func (s *SubversionSyncer) layoutOptions() []string {
+	if s.Layout == nil {
+		return []string{"--stdlayout"}
+	}
+	var opts []string
+	if s.Layout.Trunk != "" {
+		opts = append(opts, "--trunk="+s.Layout.Trunk)
+	}
+	if s.Layout.Branches != "" {
+		opts = append(opts, "--branches="+s.Layout.Branches)
+	}
+	if s.Layout.Tags != "" {
+		opts = append(opts, "--tags="+s.Layout.Tags)
+	}
+	return opts
+}
+
+// decomposeSubversionRemoteURL returns the URL of the Subversion repository
+// without credentials, and the credentials.
+func decomposeSubversionRemoteURL(remoteURL *vcs.URL) (svnURL, username, password string) {
+	u := *remoteURL
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		u.User = nil
+	}
+	return u.String(), username, password
+}
+
+// writeSubversionAskpass writes a program printing the password in the
+// SRC_SVN_PASSWORD environment variable, so that the password isn't written
+// to disk or passed as an argument.
+func writeSubversionAskpass() (string, error) {
+	f, err := os.CreateTemp("", "svn-askpass-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create askpass program")
+	}
+	defer f.Close()
+	if _, err := f.WriteString("#!/bin/sh\nprintf '%s\\n' \"$SRC_SVN_PASSWORD\"\n"); err != nil {
+		return "", errors.Wrap(err, "failed to write askpass program")
+	}
+	if err := f.Chmod(0700); err != nil {
+		return "", errors.Wrap(err, "failed to make askpass program executable")
+	}
+	return f.Name(), nil
+}
+
+// updateSubversionRefs mirrors the refs imported by git svn as branches and
+// tags. Refs of branches and tags which were deleted are removed.
+func updateSubversionRefs(ctx context.Context, dir GitDir) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)", subversionRemotePrefix, "refs/heads/", "refs/tags/")
+	dir.Set(cmd)
+	output, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list refs with output %q", string(output))
+	}
+
+	want := map[string]string{}
+	have := map[string]string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		sha, ref, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		if name := strings.TrimPrefix(ref, subversionRemotePrefix); name != ref {
+			if target, ok := subversionRefTarget(name); ok {
+				want[target] = sha
+			}
+		} else {
+			have[ref] = sha
+		}
+	}
+
+	var stdin bytes.Buffer
+	for ref, sha := range want {
+		if have[ref] != sha {
+			stdin.WriteString("update " + ref + " " + sha + "\n")
+		}
+	}
+	for ref := range have {
+		if _, ok := want[ref]; !ok {
+			stdin.WriteString("delete " + ref + "\n")
+		}
+	}
+	if stdin.Len() == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = &stdin
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to update refs with output %q", string(output))
+	}
+	return nil
+}
+
+// subversionRefTarget returns the branch or tag for the ref git svn imported
+// as refs/remotes/svn/<name>. git svn keeps the history of branches which were
+// deleted and recreated as <name>@<revision>, which are not mirrored.
+func subversionRefTarget(name string) (string, bool) {
+	if i := strings.LastIndexByte(name, '@'); i >= 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			return "", false
+		}
+	}
+	switch {
+	case name == "trunk" || name == "git-svn":
+		return "refs/heads/master", true
+	case strings.HasPrefix(name, "tags/"):
+		return "refs/tags/" + strings.TrimPrefix(name, "tags/"), true
+	default:
+		return "refs/heads/" + name, true
+	}
+}
+
+// writeSubversionRevMap writes the mapping from Subversion revisions to Git
+// commits to the subversionRevMapFile of dir. It is read from the rev_map
+// files git svn maintains for every imported ref, which are the binary
+// equivalent of the git-svn-id trailers of the commits. A revision which
+// changed several refs is mapped to the commit of the ref ranked first by
+// subversionRefRank.
+func writeSubversionRevMap(dir GitDir) error {
+	type entry struct {
+		sha  string
+		rank int
+	}
+	revs := map[uint32]entry{}
+	root := dir.Path("svn", subversionRemotePrefix)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), ".rev_map.") {
+			return nil
+		}
+		name, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		rank := subversionRefRank(filepath.ToSlash(name))
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for rev, sha := range parseSubversionRevMap(b) {
+			if e, ok := revs[rev]; !ok || rank < e.rank {
+				revs[rev] = entry{sha: sha, rank: rank}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read git svn rev_map")
+	}
+
+	sorted := make([]uint32, 0, len(revs))
+	for rev := range revs {
+		sorted = append(sorted, rev)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var buf bytes.Buffer
+	for _, rev := range sorted {
+		buf.WriteString(strconv.FormatUint(uint64(rev), 10))
+		buf.WriteByte(' ')
+		buf.WriteString(revs[rev].sha)
+		buf.WriteByte('\n')
+	}
+
+	tmp := dir.Path(subversionRevMapFile + ".tmp")
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return errors.Wrap(err, "failed to write revision mapping")
+	}
+	return os.Rename(tmp, dir.Path(subversionRevMapFile))
+}
+
+// subversionRefRank ranks the ref git svn imported as
+// refs/remotes/svn/<name> when a revision changed several refs. The trunk is
+// preferred, then branches, then tags, and last the history of deleted
+// branches and tags which isn't mirrored. Refs of equal rank are preferred in
+// the order in which they are walked, which is lexical.
+func subversionRefRank(name string) int {
+	target, ok := subversionRefTarget(name)
+	switch {
+	case !ok:
+		return 3
+	case target == "refs/heads/master":
+		return 0
+	case strings.HasPrefix(target, "refs/heads/"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// parseSubversionRevMap parses a rev_map file of git svn. It consists of
+// 24 byte records of a 4 byte big-endian revision and a 20 byte SHA. A SHA of
+// zeros marks a revision which didn't change the ref.
+func parseSubversionRevMap(b []byte) map[uint32]string {
+	const recordSize = 24
+	var zero [20]byte
+
+	revs := map[uint32]string{}
+	for ; len(b) >= recordSize; b = b[recordSize:] {
+		if bytes.Equal(b[4:recordSize], zero[:]) {
+			continue
+		}
+		revs[binary.BigEndian.Uint32(b[:4])] = hex.EncodeToString(b[4:recordSize])
+	}
+	return revs
+}
+
+// subversionRevisionPattern matches a Subversion revision like r12345,
+// optionally followed by a Git revision suffix like ^0.
+var subversionRevisionPattern = lazyregexp.New(`^r([0-9]+)([~^].*)?$`)
+
+// resolveSubversionRevisions replaces Subversion revisions in the arguments and
+// ensured revision of a git rev-parse request with the commits they were
+// imported as, so that revspecs like r12345 resolve in mirrored Subversion
+// repositories. Other requests and repositories are left unchanged.
+func resolveSubversionRevisions(dir GitDir, req *protocol.ExecRequest) {
+	if len(req.Args) < 2 || req.Args[0] != "rev-parse" {
+		return
+	}
+
+	var revs map[string]string
+	resolve := func(spec string) string {
+		m := subversionRevisionPattern.FindStringSubmatch(spec)
+		if m == nil {
+			return spec
+		}
+		if revs == nil {
+			var err error
+			if revs, err = readSubversionRevMap(dir); err != nil {
+				// Not a Subversion repository, or nothing imported yet.
+				revs = map[string]string{}
+			}
+		}
+		if sha, ok := revs[m[1]]; ok {
+			return sha + m[2]
+		}
+		return spec
+	}
+
+	args := make([]string, len(req.Args))
+	for i, arg := range req.Args {
+		args[i] = resolve(arg)
+	}
+	req.Args = args
+	req.EnsureRevision = resolve(req.EnsureRevision)
+}
+
+// subversionRevMaps caches the subversionRevMapFile of recently used
+// repositories by path, so that it is only read again once a fetch replaced
+// it.
+var subversionRevMaps = func() *lru.Cache {
+	c, _ := lru.New(32)
+	return c
+}()
+
+// cachedSubversionRevMap is a subversionRevMapFile read into memory, together
+// with the modification time and size of the file it was read from.
+type cachedSubversionRevMap struct {
+	modTime time.Time
+	size    int64
+	revs    map[string]string
+}
+
+// readSubversionRevMap reads the subversionRevMapFile of dir.
+func readSubversionRevMap(dir GitDir) (map[string]string, error) {
+	path := dir.Path(subversionRevMapFile)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := subversionRevMaps.Get(path); ok {
+		if c := v.(*cachedSubversionRevMap); c.modTime.Equal(fi.ModTime()) && c.size == fi.Size() {
+			return c.revs, nil
+		}
+	}
+
+	revs := map[string]string{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if rev, sha, ok := strings.Cut(s.Text(), " "); ok {
+			revs[rev] = sha
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	subversionRevMaps.Add(path, &cachedSubversionRevMap{modTime: fi.ModTime(), size: fi.Size(), revs: revs})
+	return revs, nil
+}
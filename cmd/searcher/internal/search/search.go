@@ -182,6 +182,8 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 			log.String("pattern", p.Pattern),
 			log.Bool("isRegExp", p.IsRegExp),
 			log.Bool("isStructuralPat", p.IsStructuralPat),
+			log.Bool("isFuzzy", p.IsFuzzy),
+			log.String("scope", p.Scope),
 			log.Strings("languages", p.Languages),
 			log.Bool("isWordMatch", p.IsWordMatch),
 			log.Bool("isCaseSensitive", p.IsCaseSensitive),
@@ -200,6 +202,11 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 		return structuralSearchWithZoekt(ctx, p, sender)
 	}
 
+	if p.IsFuzzy && p.Indexed {
+		// Verify the candidate files Zoekt finds for the pattern.
+		return fuzzySearchWithZoekt(ctx, p, sender)
+	}
+
 	// Compile pattern before fetching from store incase it is bad.
 	var rg *readerGrep
 	if !p.IsStructuralPat {
@@ -228,7 +235,9 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 		return path, zf, err
 	}
 
-	hybrid := !p.IsStructuralPat && p.FeatHybrid
+	// Hybrid search returns the matches Zoekt finds as is, so it can't
+	// restrict matches to a scope.
+	hybrid := !p.IsStructuralPat && !p.IsFuzzy && p.Scope == "" && p.FeatHybrid
 	if hybrid {
 		unsearched, ok, err := s.hybrid(ctx, p, sender)
 		if err != nil {
@@ -276,6 +285,8 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 
 	if p.IsStructuralPat {
 		return filteredStructuralSearch(ctx, zipPath, zf, &p.PatternInfo, p.Repo, sender)
+	} else if p.IsFuzzy {
+		return fuzzySearch(ctx, rg, zf, &p.PatternInfo, sender)
 	} else {
 		return regexSearch(ctx, rg, zf, p.PatternMatchesContent, p.PatternMatchesPath, p.IsNegated, sender)
 	}
@@ -295,6 +306,9 @@ func validateParams(p *protocol.Request) error {
 	if p.IsNegated && p.IsStructuralPat {
 		return errors.New("Negated patterns are not supported for structural searches")
 	}
+	if p.IsNegated && p.IsFuzzy {
+		return errors.New("Negated patterns are not supported for fuzzy searches")
+	}
 	return nil
 }
 
@@ -0,0 +1,393 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/zoekt"
+	zoektquery "github.com/sourcegraph/zoekt/query"
+	"go.uber.org/atomic"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/casetransform"
+	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// fuzzyMatcher finds approximate occurrences of a pattern within a line,
+// allowing up to maxDistance insertions, deletions and substitutions. It is
+// safe for concurrent use.
+type fuzzyMatcher struct {
+	pattern     []rune
+	maxDistance int
+	ignoreCase  bool
+
+	// pieces are substrings of which every match contains at least one. We
+	// use them to skip files cheaply.
+	pieces [][]byte
+}
+
+func newFuzzyMatcher(p *protocol.PatternInfo) *fuzzyMatcher {
+	m := &fuzzyMatcher{
+		maxDistance: zoektutil.FuzzyMaxDistance(p.Pattern),
+		ignoreCase:  !p.IsCaseSensitive,
+	}
+	m.pattern = []rune(string(m.transform([]byte(p.Pattern))))
+	for _, piece := range zoektutil.FuzzyPieces(p.Pattern) {
+		m.pieces = append(m.pieces, m.transform([]byte(piece)))
+	}
+	return m
+}
+
+// transform lowercases b if the matcher ignores case. Like readerGrep, we
+// trade some correctness for perf by using a non-utf8 aware lowercase
+// function. It preserves byte offsets.
+func (m *fuzzyMatcher) transform(b []byte) []byte {
+	if !m.ignoreCase {
+		return b
+	}
+	lower := make([]byte, len(b))
+	casetransform.BytesToLowerASCII(lower, b)
+	return lower
+}
+
+// fuzzyLoc is an approximate occurrence of the pattern at buf[start:end].
+type fuzzyLoc struct {
+	start, end int
+	distance   int
+}
+
+// find returns up to limit non-overlapping occurrences of the pattern in buf,
+// sorted by offset. Matches do not span lines.
+func (m *fuzzyMatcher) find(buf []byte, limit int) []fuzzyLoc {
+	buf = m.transform(buf)
+
+	candidate := false
+	for _, piece := range m.pieces {
+		if bytes.Contains(buf, piece) {
+			candidate = true
+			break
+		}
+	}
+	if !candidate {
+		return nil
+	}
+
+	// The rows of the distance matrix are reused for every line.
+	rows := newFuzzyRows(len(m.pattern))
+	var locs []fuzzyLoc
+	for offset := 0; offset < len(buf) && len(locs) < limit; {
+		end := bytes.IndexByte(buf[offset:], '\n')
+		if end < 0 {
+			end = len(buf)
+		} else {
+			end += offset
+		}
+		locs = m.findLine(locs, buf, offset, end, rows)
+		offset = end + 1
+	}
+	if len(locs) > limit {
+		locs = locs[:limit]
+	}
+	return locs
+}
+
+// fuzzyRows are the current and previous row of the distance matrix computed
+// by findLine.
+type fuzzyRows struct {
+	dist, start         []int
+	prevDist, prevStart []int
+}
+
+func newFuzzyRows(n int) *fuzzyRows {
+	return &fuzzyRows{
+		dist:      make([]int, n+1),
+		start:     make([]int, n+1),
+		prevDist:  make([]int, n+1),
+		prevStart: make([]int, n+1),
+	}
+}
+
+// findLine runs Sellers' variant of the Levenshtein algorithm over the line
+// buf[lineStart:lineEnd], in which a match may start at any position of the
+// text, and appends the matches to locs. Overlapping matches are resolved in
+// favor of the smaller distance.
+func (m *fuzzyMatcher) findLine(locs []fuzzyLoc, buf []byte, lineStart, lineEnd int, rows *fuzzyRows) []fuzzyLoc {
+	n := len(m.pattern)
+
+	// dist[i] is the smallest edit distance between pattern[:i] and a
+	// substring of the line ending at the current position. start[i] is the
+	// byte offset at which that substring starts.
+	dist, start, prevDist, prevStart := rows.dist, rows.start, rows.prevDist, rows.prevStart
+	for i := range prevDist {
+		prevDist[i], prevStart[i] = i, lineStart
+	}
+
+	// Matches before lineStart are on earlier lines, so they never overlap
+	// with the ones we find here.
+	first := len(locs)
+	var (
+		best    fuzzyLoc
+		hasBest bool
+	)
+	for offset := lineStart; offset < lineEnd; {
+		r, size := utf8.DecodeRune(buf[offset:lineEnd])
+		offset += size
+
+		dist[0], start[0] = 0, offset
+		for i := 1; i <= n; i++ {
+			cost := 1
+			if m.pattern[i-1] == r {
+				cost = 0
+			}
+			// Prefer substitutions, then deletions, then insertions.
+			dist[i], start[i] = prevDist[i-1]+cost, prevStart[i-1]
+			if d := dist[i-1] + 1; d < dist[i] {
+				dist[i], start[i] = d, start[i-1]
+			}
+			if d := prevDist[i] + 1; d < dist[i] {
+				dist[i], start[i] = d, prevStart[i]
+			}
+		}
+
+		if dist[n] <= m.maxDistance {
+			loc := fuzzyLoc{start: start[n], end: offset, distance: dist[n]}
+			switch {
+			case !hasBest:
+				best, hasBest = loc, true
+			case loc.start < best.end:
+				if loc.distance < best.distance && (len(locs) == first || loc.start >= locs[len(locs)-1].end) {
+					best = loc
+				}
+			default:
+				locs = append(locs, best)
+				best = loc
+			}
+		}
+		dist, prevDist = prevDist, dist
+		start, prevStart = prevStart, start
+	}
+	if hasBest {
+		locs = append(locs, best)
+	}
+	return locs
+}
+
+// fuzzyFileMatch is a file match together with the distance of its closest
+// match, which we rank by.
+type fuzzyFileMatch struct {
+	protocol.FileMatch
+	distance int
+}
+
+// matchFile returns the matches of the pattern in a file. The file name is
+// only matched if the content does not match.
+func (m *fuzzyMatcher) matchFile(name string, content []byte, patternMatchesContent, patternMatchesPath bool, limit int) (fuzzyFileMatch, bool) {
+	if patternMatchesContent {
+		if locs := m.find(content, limit); len(locs) > 0 {
+			fm := fuzzyFileMatch{
+				FileMatch: protocol.FileMatch{Path: name},
+				distance:  m.maxDistance,
+			}
+			offsets := make([][]int, 0, len(locs))
+			for _, loc := range locs {
+				offsets = append(offsets, []int{loc.start, loc.end})
+				if loc.distance < fm.distance {
+					fm.distance = loc.distance
+				}
+			}
+			fm.ChunkMatches = chunksToMatches(content, chunkRanges(locsToRanges(content, offsets), 0))
+			return fm, true
+		}
+	}
+	if patternMatchesPath {
+		if locs := m.find([]byte(name), 1); len(locs) > 0 {
+			return fuzzyFileMatch{
+				FileMatch: protocol.FileMatch{Path: name},
+				distance:  locs[0].distance,
+			}, true
+		}
+	}
+	return fuzzyFileMatch{}, false
+}
+
+// fuzzyCollector gathers file matches until they contain limit matches. It is
+// safe for concurrent use.
+type fuzzyCollector struct {
+	limit int
+
+	mu      sync.Mutex
+	count   int
+	matches []fuzzyFileMatch
+}
+
+// add collects fm and reports whether more matches are needed.
+func (c *fuzzyCollector) add(fm fuzzyFileMatch) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.count < c.limit {
+		c.matches = append(c.matches, fm)
+		c.count += fm.MatchCount()
+	}
+	return c.count < c.limit
+}
+
+// full reports whether limit matches were collected.
+func (c *fuzzyCollector) full() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count >= c.limit
+}
+
+// sendFuzzyMatches sends matches ranked by distance, closest first.
+func sendFuzzyMatches(matches []fuzzyFileMatch, sender matchSender) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].Path < matches[j].Path
+	})
+	for _, fm := range matches {
+		sender.Send(fm.FileMatch)
+	}
+}
+
+// fuzzySearch searches the files in zf for approximate matches of the
+// pattern. rg is only used to match file paths against include and exclude
+// patterns. We stop searching once we found as many matches as the sender
+// accepts, so only those are ranked.
+func fuzzySearch(ctx context.Context, rg *readerGrep, zf *zipFile, p *protocol.PatternInfo, sender matchSender) (err error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "FuzzySearch")
+	ext.Component.Set(span, "fuzzy_search")
+	span.SetTag("pattern", p.Pattern)
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
+
+	patternMatchesContent, patternMatchesPath := p.PatternMatchesContent, p.PatternMatchesPath
+	if !patternMatchesContent && !patternMatchesPath {
+		patternMatchesContent = true
+	}
+
+	m := newFuzzyMatcher(p)
+	limit := sender.Remaining()
+
+	var (
+		collector   = &fuzzyCollector{limit: limit}
+		lastFileIdx = atomic.NewInt32(-1)
+	)
+
+	g, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < numWorkers; i++ {
+		g.Go(func() error {
+			for !collector.full() {
+				idx := int(lastFileIdx.Inc())
+				if idx >= len(zf.Files) {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				f := &zf.Files[idx]
+				if !rg.matchPath.MatchPath(f.Name) {
+					continue
+				}
+				fm, ok := m.matchFile(f.Name, zf.DataFor(f), patternMatchesContent, patternMatchesPath, limit)
+				if ok && !collector.add(fm) {
+					return nil
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	span.LogFields(otlog.Int("matches", len(collector.matches)))
+	sendFuzzyMatches(collector.matches, sender)
+	return nil
+}
+
+// fuzzySearchWithZoekt asks Zoekt for files that contain a piece of the
+// pattern and verifies them with a fuzzyMatcher. Like fuzzySearch, it stops
+// once the sender doesn't accept more matches.
+func fuzzySearchWithZoekt(ctx context.Context, p *protocol.Request, sender matchSender) error {
+	patternMatchesContent, patternMatchesPath := p.PatternMatchesContent, p.PatternMatchesPath
+	if !patternMatchesContent && !patternMatchesPath {
+		patternMatchesContent = true
+	}
+
+	filePathPatterns, err := handleFilePathPatterns(&search.TextPatternInfo{
+		IncludePatterns: p.IncludePatterns,
+		ExcludePattern:  p.ExcludePattern,
+		IsCaseSensitive: p.IsCaseSensitive,
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.Branch == "" {
+		p.Branch = "HEAD"
+	}
+	q := zoektquery.NewAnd(
+		&zoektquery.BranchesRepos{List: []zoektquery.BranchRepos{{Branch: p.Branch, Repos: roaring.BitmapOf(uint32(p.RepoID))}}},
+		filePathPatterns,
+		zoektutil.FuzzyCandidatesQuery(
+			p.Pattern,
+			patternMatchesPath && !patternMatchesContent,
+			patternMatchesContent && !patternMatchesPath,
+			p.IsCaseSensitive,
+		),
+	)
+
+	k := zoektutil.ResultCountFactor(1, int32(p.Limit), false)
+	searchOpts := zoektutil.SearchOpts(ctx, k, int32(p.Limit), nil)
+	searchOpts.Whole = true
+
+	m := newFuzzyMatcher(&p.PatternInfo)
+	collector := &fuzzyCollector{limit: sender.Remaining()}
+
+	// We cancel the search once we collected enough matches.
+	searchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	t0 := time.Now()
+	client := getZoektClient(p.IndexerEndpoints)
+	err = client.StreamSearch(searchCtx, q, &searchOpts, backend.ZoektStreamFunc(func(event *zoekt.SearchResult) {
+		for _, file := range event.Files {
+			if collector.full() {
+				return
+			}
+			fm, ok := m.matchFile(file.FileName, file.Content, patternMatchesContent, patternMatchesPath, collector.limit)
+			if ok && !collector.add(fm) {
+				cancel()
+				return
+			}
+		}
+	}))
+	if err != nil && !(collector.full() && ctx.Err() == nil) {
+		return err
+	}
+	if len(collector.matches) == 0 && time.Since(t0) >= searchOpts.MaxWallTime {
+		return errNoResultsInTimeout
+	}
+
+	sendFuzzyMatches(collector.matches, sender)
+	return nil
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+)
+
+func TestFuzzyMatcher(t *testing.T) {
+	type match struct {
+		Text     string
+		Distance int
+	}
+
+	cases := []struct {
+		name    string
+		pattern protocol.PatternInfo
+		content string
+		want    []match
+	}{{
+		name:    "exact",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "name := getUserName()",
+		want:    []match{{"getUserName", 0}},
+	}, {
+		name:    "missing character",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "name := geUserName()",
+		want:    []match{{"geUserName", 1}},
+	}, {
+		name:    "transposition",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "name := getUesrName()",
+		want:    []match{{"getUesrName", 2}},
+	}, {
+		name:    "two deletions",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "name := getUsrNme()",
+		want:    []match{{"getUsrNme", 2}},
+	}, {
+		name:    "too many edits",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "name := gtUsrNme()",
+		want:    nil,
+	}, {
+		name:    "no match",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "account := getAccount()",
+		want:    nil,
+	}, {
+		name:    "ignores case",
+		pattern: protocol.PatternInfo{Pattern: "getUserName"},
+		content: "GETUSERNAME",
+		want:    []match{{"GETUSERNAME", 0}},
+	}, {
+		name:    "case sensitive",
+		pattern: protocol.PatternInfo{Pattern: "getUserName", IsCaseSensitive: true},
+		content: "GETUSERNAME",
+		want:    nil,
+	}, {
+		name:    "short patterns match exactly",
+		pattern: protocol.PatternInfo{Pattern: "foo"},
+		content: "fo\nfoo\nfooo",
+		want:    []match{{"foo", 0}, {"foo", 0}},
+	}, {
+		name:    "one match per occurrence",
+		pattern: protocol.PatternInfo{Pattern: "handler"},
+		content: "handlr(hander)",
+		want:    []match{{"handlr", 1}, {"hander", 1}},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newFuzzyMatcher(&tc.pattern)
+			var got []match
+			for _, loc := range m.find([]byte(tc.content), 10) {
+				got = append(got, match{tc.content[loc.start:loc.end], loc.distance})
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSendFuzzyMatches(t *testing.T) {
+	matches := []fuzzyFileMatch{
+		{FileMatch: protocol.FileMatch{Path: "c"}, distance: 2},
+		{FileMatch: protocol.FileMatch{Path: "b"}, distance: 0},
+		{FileMatch: protocol.FileMatch{Path: "a"}, distance: 2},
+	}
+
+	var got []string
+	_, cancel, sender := newLimitedStream(context.Background(), 10, func(fm protocol.FileMatch) {
+		got = append(got, fm.Path)
+	})
+	defer cancel()
+	sendFuzzyMatches(matches, sender)
+	require.Equal(t, []string{"b", "a", "c"}, got)
+}
+
+func TestFuzzyCollector(t *testing.T) {
+	match := func(path string, count int) fuzzyFileMatch {
+		fm := fuzzyFileMatch{FileMatch: protocol.FileMatch{Path: path}}
+		if count > 0 {
+			fm.ChunkMatches = []protocol.ChunkMatch{{Ranges: make([]protocol.Range, count)}}
+		}
+		return fm
+	}
+
+	c := &fuzzyCollector{limit: 3}
+	require.True(t, c.add(match("a", 1)))
+	require.False(t, c.full())
+	require.False(t, c.add(match("b", 2)))
+	require.True(t, c.full())
+	require.False(t, c.add(match("c", 0)))
+
+	var got []string
+	for _, fm := range c.matches {
+		got = append(got, fm.Path)
+	}
+	require.Equal(t, []string{"a", "b"}, got)
+}
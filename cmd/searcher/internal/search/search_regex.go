@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
 	"github.com/sourcegraph/sourcegraph/internal/search/casetransform"
+	"github.com/sourcegraph/sourcegraph/internal/search/scope"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/zoekt/query"
@@ -61,6 +62,10 @@ type readerGrep struct {
 	// re. It is the output of the longestLiteral function. It is only set if
 	// the regex has an empty LiteralPrefix.
 	literalSubstring []byte
+
+	// scope if non-empty restricts matches to code, comments or string
+	// literals.
+	scope scope.Kind
 }
 
 // compile returns a readerGrep for matching p.
@@ -131,11 +136,20 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		return nil, err
 	}
 
+	var kind scope.Kind
+	if p.Scope != "" {
+		var ok bool
+		if kind, ok = scope.Parse(p.Scope); !ok {
+			return nil, errors.Errorf("invalid scope %q", p.Scope)
+		}
+	}
+
 	return &readerGrep{
 		re:               re,
 		ignoreCase:       !p.IsCaseSensitive,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
+		scope:            kind,
 	}, nil
 }
 
@@ -147,6 +161,7 @@ func (rg *readerGrep) Copy() *readerGrep {
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
+		scope:            rg.scope,
 	}
 }
 
@@ -196,7 +211,10 @@ func (rg *readerGrep) Find(zf *zipFile, f *srcFile, limit int) (matches []protoc
 	}
 
 	// find limit+1 matches so we know whether we hit the limit
-	locs := rg.re.FindAllIndex(fileMatchBuf, limit+1)
+	locs := rg.re.FindAllIndex(fileMatchBuf, rg.findLimit(limit))
+	if rg.scope != "" {
+		locs = filterScope(rg.scope, f.Name, fileBuf, locs, limit+1)
+	}
 	if len(locs) == 0 {
 		return nil, nil // short-circuit if we have no matches
 	}
@@ -205,6 +223,35 @@ func (rg *readerGrep) Find(zf *zipFile, f *srcFile, limit int) (matches []protoc
 	return chunksToMatches(fileBuf, chunks), nil
 }
 
+// findLimit returns the number of matches Find should look for. If matches
+// are restricted to a scope we can't know how many are out of scope before
+// finding them, so we find all of them.
+func (rg *readerGrep) findLimit(limit int) int {
+	if rg.scope != "" {
+		return -1
+	}
+	return limit + 1
+}
+
+// filterScope returns the first limit locs in buf which are in scope k. The
+// language of buf is inferred from path.
+func filterScope(k scope.Kind, path string, buf []byte, locs [][]int, limit int) [][]int {
+	if len(locs) == 0 {
+		return locs
+	}
+	regions := scope.Regions(path, buf)
+	filtered := locs[:0]
+	for _, loc := range locs {
+		if len(filtered) == limit {
+			break
+		}
+		if scope.Contains(regions, k, loc[0], loc[1]) {
+			filtered = append(filtered, loc)
+		}
+	}
+	return filtered
+}
+
 // locs must be sorted, non-overlapping, and must be valid slices of buf.
 func locsToRanges(buf []byte, locs [][]int) []protocol.Range {
 	ranges := make([]protocol.Range, 0, len(locs))
@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"reflect"
@@ -424,6 +425,54 @@ func TestPathMatches(t *testing.T) {
 	}
 }
 
+func TestRegexSearch_Scope(t *testing.T) {
+	zipData, err := createZip(map[string]string{
+		"a.go":  "// TODO comment\nx := \"TODO string\" // TODO\nTODO()\n",
+		"b.txt": "// TODO unknown language\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, err := mockZipFile(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		scope string
+		want  []string
+	}{
+		{scope: "comment", want: []string{"a.go:0", "a.go:1"}},
+		{scope: "string", want: []string{"a.go:1"}},
+		{scope: "code", want: []string{"a.go:2", "b.txt:0"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.scope, func(t *testing.T) {
+			rg, err := compile(&protocol.PatternInfo{Pattern: "TODO", Scope: tc.scope})
+			if err != nil {
+				t.Fatal(err)
+			}
+			fileMatches, _, err := regexSearchBatch(context.Background(), rg, zf, 10, true, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, fm := range fileMatches {
+				for _, cm := range fm.ChunkMatches {
+					for _, r := range cm.Ranges {
+						got = append(got, fmt.Sprintf("%s:%d", fm.Path, r.Start.Line))
+					}
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got matches %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 // githubStore fetches from github and caches across test runs.
 var githubStore = &Store{
 	FetchTar:           fetchTarFromGithub,
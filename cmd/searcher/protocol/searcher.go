@@ -50,7 +50,8 @@ type Request struct {
 	IndexerEndpoints []string
 
 	// Whether the revision to be searched is indexed or unindexed. This matters for
-	// structural search because it will query Zoekt for indexed structural search.
+	// structural and fuzzy search because they query Zoekt for candidate files
+	// in indexed revisions.
 	Indexed bool
 
 	// FeatHybrid is a feature flag which enables hybrid search. Hybrid search
@@ -76,6 +77,10 @@ type PatternInfo struct {
 	// IsStructuralPat if true will treat the pattern as a Comby structural search pattern.
 	IsStructuralPat bool
 
+	// IsFuzzy if true will match the pattern within a small edit distance,
+	// see zoekt.FuzzyMaxDistance. Matches are ranked by distance.
+	IsFuzzy bool
+
 	// IsWordMatch if true will only match the pattern at word boundaries.
 	IsWordMatch bool
 
@@ -131,6 +136,11 @@ type PatternInfo struct {
 	// use it since selection is done after the query completes, but exposing it can enable
 	// optimizations.
 	Select string
+
+	// Scope is the value of the scope field in the query (e.g., "scope:comment"). If
+	// non-empty, only content matches in code, comments or string literals are
+	// returned. See package internal/search/scope.
+	Scope string
 }
 
 func (p *PatternInfo) String() string {
@@ -145,6 +155,9 @@ func (p *PatternInfo) String() string {
 			args = append(args, "comby")
 		}
 	}
+	if p.IsFuzzy {
+		args = append(args, "fuzzy")
+	}
 	if p.IsWordMatch {
 		args = append(args, "word")
 	}
@@ -163,6 +176,9 @@ func (p *PatternInfo) String() string {
 	for _, lang := range p.Languages {
 		args = append(args, fmt.Sprintf("lang:%s", lang))
 	}
+	if p.Scope != "" {
+		args = append(args, fmt.Sprintf("scope:%s", p.Scope))
+	}
 	if p.Select != "" {
 		args = append(args, fmt.Sprintf("select:%s", p.Select))
 	}
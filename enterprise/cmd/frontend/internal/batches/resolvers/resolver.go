@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
@@ -15,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/service"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/compute"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -26,9 +28,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/usagestats"
 	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+	"github.com/sourcegraph/sourcegraph/lib/batches/template"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -1571,6 +1576,115 @@ func (r *Resolver) UpsertEmptyBatchChange(ctx context.Context, args *graphqlback
 	return &batchChangeResolver{store: r.store, batchChange: batchChange}, nil
 }
 
+func (r *Resolver) CreateBatchChangeFromComputeQuery(ctx context.Context, args *graphqlbackend.CreateBatchChangeFromComputeQueryArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CreateBatchChangeFromComputeQuery", fmt.Sprintf("Namespace: %s, Query: %q", args.Namespace, args.Query))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesCreateAccess(ctx, r.store.DatabaseDB()); err != nil {
+		return nil, err
+	}
+
+	var uid, oid int32
+	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
+		return nil, err
+	}
+
+	computeQuery, err := compute.Parse(args.Query)
+	if err != nil {
+		return nil, err
+	}
+	replace, ok := computeQuery.Command.(*compute.Replace)
+	if !ok || !replace.IsDiff() {
+		return nil, errors.New("the compute query must use a replace.diff command")
+	}
+	searchQuery, err := computeQuery.ToSearchQuery()
+	if err != nil {
+		return nil, err
+	}
+	// The batch change must cover every match, not only the first page of
+	// results a search returns by default.
+	hasCount := false
+	query.VisitField(computeQuery.Parameters, query.FieldCount, func(string, bool, query.Annotation) {
+		hasCount = true
+	})
+	if !hasCount {
+		searchQuery += " count:all"
+	}
+
+	db := r.store.DatabaseDB()
+	patternType := "regexp"
+	job, err := graphqlbackend.NewBatchSearchImplementer(ctx, log.Scoped("batches.compute", "search for compute batch changes"), db, &graphqlbackend.SearchArgs{Query: searchQuery, PatternType: &patternType})
+	if err != nil {
+		return nil, err
+	}
+	results, err := job.Results(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if results.Stats.IsLimitHit {
+		return nil, errors.New("the compute query matched more results than its search returned; narrow the query or raise its count")
+	}
+
+	diffs, err := compute.FileDiffs(ctx, db, replace, results.Matches)
+	if err != nil {
+		return nil, err
+	}
+	if len(diffs) == 0 {
+		return nil, errors.New("the compute query produced no changes")
+	}
+
+	commitMessage := args.Title
+	if args.CommitMessage != nil && *args.CommitMessage != "" {
+		commitMessage = *args.CommitMessage
+	}
+	specs, err := compute.ChangesetSpecs(ctx, gitserver.NewClient(db), diffs,
+		&template.BatchChangeAttributes{Name: args.Name, Description: args.Description},
+		&batcheslib.ChangesetTemplate{
+			Title:  args.Title,
+			Body:   args.Body,
+			Branch: args.Branch,
+			Commit: batcheslib.ExpandedGitCommitDescription{Message: commitMessage},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLicense(); err != nil {
+		if licensing.IsFeatureNotActivated(err) {
+			if len(specs) > maxUnlicensedChangesets {
+				return nil, ErrBatchChangesUnlicensed{err}
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	svc := service.New(r.store)
+	batchChange, err := svc.ApplyChangesetSpecs(ctx, service.ApplyChangesetSpecsOpts{
+		NamespaceUserID: uid,
+		NamespaceOrgID:  oid,
+		Name:            args.Name,
+		Description:     args.Description,
+		ChangesetSpecs:  specs,
+	})
+	if err != nil {
+		if err == service.ErrMatchingBatchChangeExists {
+			return nil, ErrMatchingBatchChangeExists{}
+		}
+		return nil, err
+	}
+
+	arg := &batchChangeEventArg{BatchChangeID: batchChange.ID}
+	if err := logBackendEvent(ctx, db, "BatchChangeCreated", arg, arg); err != nil {
+		return nil, err
+	}
+
+	return &batchChangeResolver{store: r.store, batchChange: batchChange}, nil
+}
+
 func (r *Resolver) CreateBatchSpecFromRaw(ctx context.Context, args *graphqlbackend.CreateBatchSpecFromRawArgs) (_ graphqlbackend.BatchSpecResolver, err error) {
 	tr, ctx := trace.New(ctx, "Resolver.CreateBatchSpecFromRaw", fmt.Sprintf("Namespace: %+v", args.Namespace))
 	defer func() {
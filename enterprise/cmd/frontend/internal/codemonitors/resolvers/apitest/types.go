@@ -59,6 +59,7 @@ type Action struct {
 	Email        *ActionEmail
 	Webhook      *ActionWebhook
 	SlackWebhook *ActionSlackWebhook
+	IssueTracker *ActionIssueTracker
 }
 
 func (a *Action) UnmarshalJSON(b []byte) error {
@@ -80,6 +81,9 @@ func (a *Action) UnmarshalJSON(b []byte) error {
 	case "MonitorSlackWebhook":
 		a.SlackWebhook = &ActionSlackWebhook{}
 		return json.Unmarshal(b, &a.SlackWebhook)
+	case "MonitorIssueTracker":
+		a.IssueTracker = &ActionIssueTracker{}
+		return json.Unmarshal(b, &a.IssueTracker)
 	default:
 		return errors.Errorf("unexpected typename %q", t.TypeName)
 	}
@@ -108,6 +112,17 @@ type ActionSlackWebhook struct {
 	Events  ActionEventConnection
 }
 
+type ActionIssueTracker struct {
+	Id         string
+	Enabled    bool
+	Kind       string
+	URL        string
+	Repository string
+	Labels     []string
+	Assignees  []string
+	Events     ActionEventConnection
+}
+
 type RecipientsConnection struct {
 	Nodes      []UserOrg
 	TotalCount int
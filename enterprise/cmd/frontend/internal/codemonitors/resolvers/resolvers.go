@@ -273,11 +273,20 @@ func (r *Resolver) createActions(ctx context.Context, monitorID int64, args []*g
 				return err
 			}
 
+			err = r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.EmailActions, e.ID, actionDelivery(a.Email.DeliveryPolicy, a.Email.MaxNotificationsPerHour))
+			if err != nil {
+				return err
+			}
+
 			if err := r.createRecipients(ctx, e.ID, a.Email.Recipients); err != nil {
 				return err
 			}
 		case a.Webhook != nil:
-			_, err := r.db.CodeMonitors().CreateWebhookAction(ctx, monitorID, a.Webhook.Enabled, a.Webhook.IncludeResults, a.Webhook.URL)
+			w, err := r.db.CodeMonitors().CreateWebhookAction(ctx, monitorID, a.Webhook.Enabled, a.Webhook.IncludeResults, a.Webhook.URL)
+			if err != nil {
+				return err
+			}
+			err = r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.WebhookActions, w.ID, actionDelivery(a.Webhook.DeliveryPolicy, a.Webhook.MaxNotificationsPerHour))
 			if err != nil {
 				return err
 			}
@@ -285,19 +294,32 @@ func (r *Resolver) createActions(ctx context.Context, monitorID int64, args []*g
 			if err := validateSlackURL(a.SlackWebhook.URL); err != nil {
 				return err
 			}
-			_, err := r.db.CodeMonitors().CreateSlackWebhookAction(ctx, monitorID, a.SlackWebhook.Enabled, a.SlackWebhook.IncludeResults, a.SlackWebhook.URL)
+			w, err := r.db.CodeMonitors().CreateSlackWebhookAction(ctx, monitorID, a.SlackWebhook.Enabled, a.SlackWebhook.IncludeResults, a.SlackWebhook.URL)
+			if err != nil {
+				return err
+			}
+			err = r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.SlackWebhookActions, w.ID, actionDelivery(a.SlackWebhook.DeliveryPolicy, a.SlackWebhook.MaxNotificationsPerHour))
+			if err != nil {
+				return err
+			}
+		case a.IssueTracker != nil:
+			it, err := r.db.CodeMonitors().CreateIssueTrackerAction(ctx, monitorID, issueTrackerActionArgs(a.IssueTracker))
+			if err != nil {
+				return err
+			}
+			err = r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.IssueTrackerActions, it.ID, actionDelivery(a.IssueTracker.DeliveryPolicy, a.IssueTracker.MaxNotificationsPerHour))
 			if err != nil {
 				return err
 			}
 		default:
-			return errors.New("exactly one of Email, Webhook, or SlackWebhook must be set")
+			return errors.New("exactly one of Email, Webhook, SlackWebhook, or IssueTracker must be set")
 		}
 	}
 	return nil
 }
 
 func (r *Resolver) deleteActions(ctx context.Context, monitorID int64, ids []graphql.ID) error {
-	var email, webhook, slackWebhook []int64
+	var email, webhook, slackWebhook, issueTracker []int64
 	for _, id := range ids {
 		var intID int64
 		err := relay.UnmarshalSpec(id, &intID)
@@ -312,8 +334,10 @@ func (r *Resolver) deleteActions(ctx context.Context, monitorID int64, ids []gra
 			webhook = append(webhook, intID)
 		case monitorActionSlackWebhookKind:
 			slackWebhook = append(slackWebhook, intID)
+		case monitorActionIssueTrackerKind:
+			issueTracker = append(issueTracker, intID)
 		default:
-			return errors.New("action IDs must be exactly one of email, webhook, or slack webhook")
+			return errors.New("action IDs must be exactly one of email, webhook, slack webhook, or issue tracker")
 		}
 	}
 
@@ -329,6 +353,10 @@ func (r *Resolver) deleteActions(ctx context.Context, monitorID int64, ids []gra
 		return err
 	}
 
+	if err := r.db.CodeMonitors().DeleteIssueTrackerActions(ctx, monitorID, issueTracker...); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -440,7 +468,11 @@ func (r *Resolver) actionIDsForMonitorIDInt64(ctx context.Context, monitorID int
 	if err != nil {
 		return nil, err
 	}
-	ids := make([]graphql.ID, 0, len(emailActions)+len(webhookActions)+len(slackWebhookActions))
+	issueTrackerActions, err := r.db.CodeMonitors().ListIssueTrackerActions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]graphql.ID, 0, len(emailActions)+len(webhookActions)+len(slackWebhookActions)+len(issueTrackerActions))
 	for _, emailAction := range emailActions {
 		ids = append(ids, (&monitorEmail{EmailAction: emailAction}).ID())
 	}
@@ -450,6 +482,9 @@ func (r *Resolver) actionIDsForMonitorIDInt64(ctx context.Context, monitorID int
 	for _, slackWebhookAction := range slackWebhookActions {
 		ids = append(ids, (&monitorSlackWebhook{SlackWebhookAction: slackWebhookAction}).ID())
 	}
+	for _, issueTrackerAction := range issueTrackerActions {
+		ids = append(ids, (&monitorIssueTracker{IssueTrackerAction: issueTrackerAction}).ID())
+	}
 	return ids, nil
 }
 
@@ -494,6 +529,16 @@ func splitActionIDs(args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []grap
 			}
 			toUpdateActions = append(toUpdateActions, a)
 			delete(aMap, *a.SlackWebhook.Id)
+		case a.IssueTracker != nil:
+			if a.IssueTracker.Id == nil {
+				toCreate = append(toCreate, &graphqlbackend.CreateActionArgs{IssueTracker: a.IssueTracker.Update})
+				continue
+			}
+			if _, ok := aMap[*a.IssueTracker.Id]; !ok {
+				return nil, nil, errors.Errorf("unknown ID=%s for action", *a.IssueTracker.Id)
+			}
+			toUpdateActions = append(toUpdateActions, a)
+			delete(aMap, *a.IssueTracker.Id)
 		}
 	}
 
@@ -578,8 +623,10 @@ func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 				return nil, err
 			}
 			err = r.updateSlackWebhookAction(ctx, *action.SlackWebhook)
+		case action.IssueTracker != nil:
+			err = r.updateIssueTrackerAction(ctx, *action.IssueTracker)
 		default:
-			err = errors.New("action must be one of email, webhook, or slack webhook")
+			err = errors.New("action must be one of email, webhook, slack webhook, or issue tracker")
 		}
 		if err != nil {
 			return nil, err
@@ -610,6 +657,10 @@ func (r *Resolver) updateEmailAction(ctx context.Context, args graphqlbackend.Ed
 	if err != nil {
 		return err
 	}
+	err = r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.EmailActions, e.ID, actionDelivery(args.Update.DeliveryPolicy, args.Update.MaxNotificationsPerHour))
+	if err != nil {
+		return err
+	}
 	return r.createRecipients(ctx, e.ID, args.Update.Recipients)
 }
 
@@ -621,7 +672,10 @@ func (r *Resolver) updateWebhookAction(ctx context.Context, args graphqlbackend.
 	}
 
 	_, err = r.db.CodeMonitors().UpdateWebhookAction(ctx, id, args.Update.Enabled, args.Update.IncludeResults, args.Update.URL)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.WebhookActions, id, actionDelivery(args.Update.DeliveryPolicy, args.Update.MaxNotificationsPerHour))
 }
 
 func (r *Resolver) updateSlackWebhookAction(ctx context.Context, args graphqlbackend.EditActionSlackWebhookArgs) error {
@@ -632,7 +686,76 @@ func (r *Resolver) updateSlackWebhookAction(ctx context.Context, args graphqlbac
 	}
 
 	_, err = r.db.CodeMonitors().UpdateSlackWebhookAction(ctx, id, args.Update.Enabled, args.Update.IncludeResults, args.Update.URL)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.SlackWebhookActions, id, actionDelivery(args.Update.DeliveryPolicy, args.Update.MaxNotificationsPerHour))
+}
+
+func (r *Resolver) updateIssueTrackerAction(ctx context.Context, args graphqlbackend.EditActionIssueTrackerArgs) error {
+	var id int64
+	err := relay.UnmarshalSpec(*args.Id, &id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.CodeMonitors().UpdateIssueTrackerAction(ctx, id, issueTrackerActionArgs(args.Update))
+	if err != nil {
+		return err
+	}
+	return r.db.CodeMonitors().UpdateActionDelivery(ctx, edb.IssueTrackerActions, id, actionDelivery(args.Update.DeliveryPolicy, args.Update.MaxNotificationsPerHour))
+}
+
+// issueTrackerActionArgs converts an issue tracker input to the arguments of
+// the store, filling in the defaults of unset fields.
+func issueTrackerActionArgs(args *graphqlbackend.CreateActionIssueTrackerArgs) *edb.IssueTrackerActionArgs {
+	a := &edb.IssueTrackerActionArgs{
+		Enabled:        args.Enabled,
+		IncludeResults: args.IncludeResults,
+		Kind:           edb.IssueTrackerKind(args.Kind),
+		URL:            args.URL,
+		IssuePerCommit: true,
+	}
+	if args.Repository != nil {
+		a.Repository = *args.Repository
+	}
+	if args.Labels != nil {
+		a.Labels = *args.Labels
+	}
+	if args.Assignees != nil {
+		a.Assignees = *args.Assignees
+	}
+	if args.AssignCodeOwners != nil {
+		a.AssignCodeOwners = *args.AssignCodeOwners
+	}
+	if args.IssuePerCommit != nil {
+		a.IssuePerCommit = *args.IssuePerCommit
+	}
+	if args.Token != nil {
+		a.Token = *args.Token
+	}
+	if t := args.RESTTemplate; t != nil {
+		a.RESTTemplate = &edb.RESTIssueTemplate{
+			IssueBody:   t.IssueBody,
+			KeyField:    t.KeyField,
+			CommentURL:  t.CommentURL,
+			CommentBody: t.CommentBody,
+		}
+		if t.URLField != nil {
+			a.RESTTemplate.URLField = *t.URLField
+		}
+	}
+	return a
+}
+
+// actionDelivery returns the delivery settings of an action input. Actions
+// without a delivery policy send their notifications immediately.
+func actionDelivery(policy *string, maxNotificationsPerHour *int32) edb.ActionDelivery {
+	d := edb.ActionDelivery{Policy: edb.DeliveryImmediate, MaxNotificationsPerHour: maxNotificationsPerHour}
+	if policy != nil {
+		d.Policy = edb.DeliveryPolicy(*policy)
+	}
+	return d
 }
 
 func (r *Resolver) transact(ctx context.Context) (*Resolver, error) {
@@ -722,6 +845,7 @@ const (
 	monitorActionEmailKind             = "CodeMonitorActionEmail"
 	monitorActionWebhookKind           = "CodeMonitorActionWebhook"
 	monitorActionSlackWebhookKind      = "CodeMonitorActionSlackWebhook"
+	monitorActionIssueTrackerKind      = "CodeMonitorActionIssueTracker"
 	monitorActionEmailEventKind        = "CodeMonitorActionEmailEvent"
 	monitorActionWebhookEventKind      = "CodeMonitorActionWebhookEvent"
 	monitorActionSlackWebhookEventKind = "CodeMonitorActionSlackWebhookEvent"
@@ -819,7 +943,12 @@ func (r *Resolver) actionConnectionResolverWithTriggerID(ctx context.Context, tr
 		return nil, err
 	}
 
-	actions := make([]graphqlbackend.MonitorAction, 0, len(es)+len(ws)+len(sws))
+	its, err := r.db.CodeMonitors().ListIssueTrackerActions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]graphqlbackend.MonitorAction, 0, len(es)+len(ws)+len(sws)+len(its))
 	for _, e := range es {
 		actions = append(actions, &action{
 			email: &monitorEmail{
@@ -847,6 +976,15 @@ func (r *Resolver) actionConnectionResolverWithTriggerID(ctx context.Context, tr
 			},
 		})
 	}
+	for _, it := range its {
+		actions = append(actions, &action{
+			issueTracker: &monitorIssueTracker{
+				Resolver:           r,
+				IssueTrackerAction: it,
+				triggerEventID:     triggerEventID,
+			},
+		})
+	}
 
 	totalCount := len(actions)
 	if args.After != nil {
@@ -983,6 +1121,7 @@ func (m *monitorTriggerEvent) ResultCount() int32 {
 	for _, cm := range m.TriggerJob.SearchResults {
 		count += cm.ResultCount()
 	}
+	count += len(m.TriggerJob.ContentChanges)
 	return int32(count)
 }
 
@@ -1034,6 +1173,7 @@ type action struct {
 	email        graphqlbackend.MonitorEmailResolver
 	webhook      graphqlbackend.MonitorWebhookResolver
 	slackWebhook graphqlbackend.MonitorSlackWebhookResolver
+	issueTracker graphqlbackend.MonitorIssueTrackerResolver
 }
 
 func (a *action) ID() graphql.ID {
@@ -1044,6 +1184,8 @@ func (a *action) ID() graphql.ID {
 		return a.webhook.ID()
 	case a.slackWebhook != nil:
 		return a.slackWebhook.ID()
+	case a.issueTracker != nil:
+		return a.issueTracker.ID()
 	default:
 		panic("action must have a type")
 	}
@@ -1061,6 +1203,10 @@ func (a *action) ToMonitorSlackWebhook() (graphqlbackend.MonitorSlackWebhookReso
 	return a.slackWebhook, a.slackWebhook != nil
 }
 
+func (a *action) ToMonitorIssueTracker() (graphqlbackend.MonitorIssueTrackerResolver, bool) {
+	return a.issueTracker, a.issueTracker != nil
+}
+
 //
 // Email
 //
@@ -1132,6 +1278,14 @@ func (m *monitorEmail) Header() string {
 	return m.EmailAction.Header
 }
 
+func (m *monitorEmail) DeliveryPolicy() string {
+	return string(m.EmailAction.Delivery.Policy)
+}
+
+func (m *monitorEmail) MaxNotificationsPerHour() *int32 {
+	return m.EmailAction.Delivery.MaxNotificationsPerHour
+}
+
 func (m *monitorEmail) ID() graphql.ID {
 	return relay.MarshalID(monitorActionEmailKind, m.EmailAction.ID)
 }
@@ -1192,6 +1346,14 @@ func (m *monitorWebhook) URL() string {
 	return m.WebhookAction.URL
 }
 
+func (m *monitorWebhook) DeliveryPolicy() string {
+	return string(m.WebhookAction.Delivery.Policy)
+}
+
+func (m *monitorWebhook) MaxNotificationsPerHour() *int32 {
+	return m.WebhookAction.Delivery.MaxNotificationsPerHour
+}
+
 func (m *monitorWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
 	after, err := unmarshalAfter(args.After)
 	if err != nil {
@@ -1248,6 +1410,14 @@ func (m *monitorSlackWebhook) URL() string {
 	return m.SlackWebhookAction.URL
 }
 
+func (m *monitorSlackWebhook) DeliveryPolicy() string {
+	return string(m.SlackWebhookAction.Delivery.Policy)
+}
+
+func (m *monitorSlackWebhook) MaxNotificationsPerHour() *int32 {
+	return m.SlackWebhookAction.Delivery.MaxNotificationsPerHour
+}
+
 func (m *monitorSlackWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
 	after, err := unmarshalAfter(args.After)
 	if err != nil {
@@ -1278,6 +1448,117 @@ func (m *monitorSlackWebhook) Events(ctx context.Context, args *graphqlbackend.L
 	return &monitorActionEventConnection{events: events, totalCount: int32(totalCount)}, nil
 }
 
+type monitorIssueTracker struct {
+	*Resolver
+	*edb.IssueTrackerAction
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int32
+}
+
+func (m *monitorIssueTracker) ID() graphql.ID {
+	return relay.MarshalID(monitorActionIssueTrackerKind, m.IssueTrackerAction.ID)
+}
+
+func (m *monitorIssueTracker) Enabled() bool {
+	return m.IssueTrackerAction.Enabled
+}
+
+func (m *monitorIssueTracker) IncludeResults() bool {
+	return m.IssueTrackerAction.IncludeResults
+}
+
+func (m *monitorIssueTracker) Kind() string {
+	return string(m.IssueTrackerAction.Kind)
+}
+
+func (m *monitorIssueTracker) URL() string {
+	return m.IssueTrackerAction.URL
+}
+
+func (m *monitorIssueTracker) Repository() string {
+	return m.IssueTrackerAction.Repository
+}
+
+func (m *monitorIssueTracker) Labels() []string {
+	return m.IssueTrackerAction.Labels
+}
+
+func (m *monitorIssueTracker) Assignees() []string {
+	return m.IssueTrackerAction.Assignees
+}
+
+func (m *monitorIssueTracker) AssignCodeOwners() bool {
+	return m.IssueTrackerAction.AssignCodeOwners
+}
+
+func (m *monitorIssueTracker) IssuePerCommit() bool {
+	return m.IssueTrackerAction.IssuePerCommit
+}
+
+func (m *monitorIssueTracker) RESTTemplate() graphqlbackend.MonitorRESTIssueTemplateResolver {
+	if m.IssueTrackerAction.RESTTemplate == nil {
+		return nil
+	}
+	return &restIssueTemplate{m.IssueTrackerAction.RESTTemplate}
+}
+
+func (m *monitorIssueTracker) DeliveryPolicy() string {
+	return string(m.IssueTrackerAction.Delivery.Policy)
+}
+
+func (m *monitorIssueTracker) MaxNotificationsPerHour() *int32 {
+	return m.IssueTrackerAction.Delivery.MaxNotificationsPerHour
+}
+
+func (m *monitorIssueTracker) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	after, err := unmarshalAfter(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	ajs, err := m.db.CodeMonitors().ListActionJobs(ctx, edb.ListActionJobsOpts{
+		IssueTrackerID: intPtr(int(m.IssueTrackerAction.ID)),
+		TriggerEventID: m.triggerEventID,
+		First:          intPtr(int(args.First)),
+		After:          after,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := m.db.CodeMonitors().CountActionJobs(ctx, edb.ListActionJobsOpts{
+		IssueTrackerID: intPtr(int(m.IssueTrackerAction.ID)),
+		TriggerEventID: m.triggerEventID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
+	for i, aj := range ajs {
+		events[i] = &monitorActionEvent{Resolver: m.Resolver, ActionJob: aj}
+	}
+	return &monitorActionEventConnection{events: events, totalCount: int32(totalCount)}, nil
+}
+
+type restIssueTemplate struct {
+	*edb.RESTIssueTemplate
+}
+
+func (t *restIssueTemplate) IssueBody() string   { return t.RESTIssueTemplate.IssueBody }
+func (t *restIssueTemplate) KeyField() string    { return t.RESTIssueTemplate.KeyField }
+func (t *restIssueTemplate) CommentURL() string  { return t.RESTIssueTemplate.CommentURL }
+func (t *restIssueTemplate) CommentBody() string { return t.RESTIssueTemplate.CommentBody }
+
+func (t *restIssueTemplate) URLField() *string {
+	if t.RESTIssueTemplate.URLField == "" {
+		return nil
+	}
+	return &t.RESTIssueTemplate.URLField
+}
+
 func intPtr(i int) *int { return &i }
 func intPtrToInt64Ptr(i *int) *int64 {
 	if i == nil {
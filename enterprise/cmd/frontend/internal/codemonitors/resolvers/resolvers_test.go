@@ -85,6 +85,22 @@ func TestCreateCodeMonitor(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("invalid issue tracker", func(t *testing.T) {
+		namespace := relay.MarshalID("User", user.ID)
+		_, err := r.CreateCodeMonitor(ctx, &graphqlbackend.CreateCodeMonitorArgs{
+			Monitor: &graphqlbackend.CreateMonitorArgs{Namespace: namespace},
+			Trigger: &graphqlbackend.CreateTriggerArgs{Query: "repo:."},
+			Actions: []*graphqlbackend.CreateActionArgs{{
+				// GitLab issue trackers need a project to create issues in.
+				IssueTracker: &graphqlbackend.CreateActionIssueTrackerArgs{
+					Kind: "GITLAB",
+					URL:  "https://gitlab.com",
+				},
+			}},
+		})
+		require.Error(t, err)
+	})
+
 	t.Run("invalid query", func(t *testing.T) {
 		namespace := relay.MarshalID("User", user.ID)
 		_, err := r.CreateCodeMonitor(ctx, &graphqlbackend.CreateCodeMonitorArgs{
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func NewResolver(logger log.Logger, db database.DB) gql.ComputeResolver {
@@ -166,6 +167,8 @@ func toComputeResultResolver(result compute.Result, repoResolver *gql.Repository
 		return &computeResultResolver{result: toComputeMatchContextResolver(r, repoResolver, path, commit)}
 	case *compute.Text:
 		return &computeResultResolver{result: toComputeTextResolver(r, repoResolver, path, commit)}
+	case *compute.FileDiff:
+		return &computeResultResolver{result: toComputeTextResolver(&r.Text, repoResolver, path, commit)}
 	default:
 		panic(fmt.Sprintf("unsupported compute result %T", r))
 	}
@@ -226,6 +229,9 @@ func NewBatchComputeImplementer(ctx context.Context, logger log.Logger, db datab
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := computeQuery.Command.(*compute.Aggregate); ok {
+		return nil, errors.New("aggregation commands like count, group and top are only supported by the compute stream API")
+	}
 
 	searchQuery, err := computeQuery.ToSearchQuery()
 	if err != nil {
@@ -102,7 +102,21 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	matchesBuf := streamhttp.NewJSONArrayBuf(32*1024, func(data []byte) error {
 		return eventWriter.EventBytes("results", data)
 	})
+
+	// Aggregation commands return a table per match. Rather than streaming
+	// these, we merge them and stream the aggregate table over all matches
+	// seen so far, which supersedes the tables sent before it.
+	var aggregator *compute.Aggregator
+	if cmd, ok := computeQuery.Command.(*compute.Aggregate); ok {
+		aggregator = compute.NewAggregator(cmd)
+	}
+	tableDirty := false
+
 	matchesFlush := func() {
+		if tableDirty {
+			_ = matchesBuf.Append(aggregator.Table())
+			tableDirty = false
+		}
 		if err := matchesBuf.Flush(); err != nil {
 			// EOF
 			return
@@ -124,11 +138,16 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		progress.Stats.Update(&event.Stats)
 
 		for _, result := range event.Results {
+			if table, ok := result.(*compute.Table); ok && aggregator != nil {
+				aggregator.Add(table)
+				tableDirty = true
+				continue
+			}
 			_ = matchesBuf.Append(result)
 		}
 
 		// Instantly send results if we have not sent any yet.
-		if first && matchesBuf.Len() > 0 {
+		if first && (matchesBuf.Len() > 0 || tableDirty) {
 			first = false
 			matchesFlush()
 		}
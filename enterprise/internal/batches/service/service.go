@@ -86,6 +86,7 @@ type operations struct {
 	validateAuthenticator                *observation.Operation
 	createChangesetJobs                  *observation.Operation
 	applyBatchChange                     *observation.Operation
+	applyChangesetSpecs                  *observation.Operation
 	reconcileBatchChange                 *observation.Operation
 	validateChangesetSpecs               *observation.Operation
 }
@@ -136,6 +137,7 @@ func newOperations(observationContext *observation.Context) *operations {
 			validateAuthenticator:                op("ValidateAuthenticator"),
 			createChangesetJobs:                  op("CreateChangesetJobs"),
 			applyBatchChange:                     op("ApplyBatchChange"),
+			applyChangesetSpecs:                  op("ApplyChangesetSpecs"),
 			reconcileBatchChange:                 op("ReconcileBatchChange"),
 			validateChangesetSpecs:               op("ValidateChangesetSpecs"),
 		}
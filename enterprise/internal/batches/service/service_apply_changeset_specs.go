@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opentracing/opentracing-go/log"
+	"gopkg.in/yaml.v2"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type ApplyChangesetSpecsOpts struct {
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+
+	Name        string
+	Description string
+
+	ChangesetSpecs []*batcheslib.ChangesetSpec
+}
+
+// ApplyChangesetSpecs creates a batch change from changeset specs that were
+// computed without executing a batch spec, such as the diffs of a compute
+// replace.diff query. The batch spec of the batch change only has a name and a
+// description. Changesets without a published value aren't published, so that
+// the batch change can be previewed and published from the UI.
+func (s *Service) ApplyChangesetSpecs(ctx context.Context, opts ApplyChangesetSpecsOpts) (batchChange *btypes.BatchChange, err error) {
+	ctx, _, endObservation := s.operations.applyChangesetSpecs.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("changesetSpecs", len(opts.ChangesetSpecs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(opts.ChangesetSpecs) == 0 {
+		return nil, errors.New("no changeset specs to apply")
+	}
+
+	rawSpec, err := yaml.Marshal(struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description,omitempty"`
+	}{Name: opts.Name, Description: opts.Description})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling batch spec")
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+	svc := s.WithStore(tx)
+
+	// 🚨 SECURITY: CreateChangesetSpec checks that the user has access to the
+	// repository of each changeset spec, and CreateBatchSpec checks that the
+	// user has access to the namespace.
+	a := actor.FromContext(ctx)
+	randIDs := make([]string, 0, len(opts.ChangesetSpecs))
+	for _, spec := range opts.ChangesetSpecs {
+		rawChangesetSpec, err := json.Marshal(spec)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling changeset spec")
+		}
+		changesetSpec, err := svc.CreateChangesetSpec(ctx, string(rawChangesetSpec), a.UID)
+		if err != nil {
+			return nil, err
+		}
+		randIDs = append(randIDs, changesetSpec.RandID)
+	}
+
+	batchSpec, err := svc.CreateBatchSpec(ctx, CreateBatchSpecOpts{
+		RawSpec:              string(rawSpec),
+		NamespaceUserID:      opts.NamespaceUserID,
+		NamespaceOrgID:       opts.NamespaceOrgID,
+		ChangesetSpecRandIDs: randIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.ApplyBatchChange(ctx, ApplyBatchChangeOpts{
+		BatchSpecRandID:         batchSpec.RandID,
+		FailIfBatchChangeExists: true,
+	})
+}
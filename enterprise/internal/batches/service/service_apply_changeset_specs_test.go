@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	bt "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestServiceApplyChangesetSpecs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := actor.WithInternalActor(context.Background())
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+
+	user := bt.CreateTestUser(t, db, false)
+	userCtx := actor.WithActor(context.Background(), actor.FromUser(user.ID))
+
+	repos, _ := bt.CreateTestRepos(t, ctx, db, 2)
+
+	s := store.New(db, &observation.TestContext, nil)
+	svc := New(s)
+
+	changesetSpec := func(repoID graphql.ID) *batcheslib.ChangesetSpec {
+		return &batcheslib.ChangesetSpec{
+			BaseRepository: string(repoID),
+			HeadRepository: string(repoID),
+			BaseRef:        "refs/heads/main",
+			BaseRev:        "d34db33f",
+			HeadRef:        "refs/heads/fix-spelling",
+			Title:          "Fix spelling",
+			Body:           "Replaces colarado with colorado.",
+			Commits: []batcheslib.GitCommitDescription{{
+				Message:     "Fix spelling",
+				Diff:        bt.ChangesetSpecDiff,
+				AuthorName:  "Sourcegraph",
+				AuthorEmail: "batch-changes@sourcegraph.com",
+			}},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		batchChange, err := svc.ApplyChangesetSpecs(userCtx, ApplyChangesetSpecsOpts{
+			NamespaceUserID: user.ID,
+			Name:            "fix-spelling",
+			Description:     "Fixes the spelling of colorado.",
+			ChangesetSpecs: []*batcheslib.ChangesetSpec{
+				changesetSpec(graphqlbackend.MarshalRepositoryID(repos[0].ID)),
+				changesetSpec(graphqlbackend.MarshalRepositoryID(repos[1].ID)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := batchChange.Name, "fix-spelling"; have != want {
+			t.Fatalf("wrong name. want=%q, have=%q", want, have)
+		}
+		if have, want := batchChange.Description, "Fixes the spelling of colorado."; have != want {
+			t.Fatalf("wrong description. want=%q, have=%q", want, have)
+		}
+		if have, want := batchChange.CreatorID, user.ID; have != want {
+			t.Fatalf("wrong creator. want=%d, have=%d", want, have)
+		}
+
+		changesets, _, err := s.ListChangesets(ctx, store.ListChangesetsOpts{BatchChangeID: batchChange.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, want := len(changesets), 2; have != want {
+			t.Fatalf("wrong number of changesets. want=%d, have=%d", want, have)
+		}
+		for _, c := range changesets {
+			if have, want := c.PublicationState, btypes.ChangesetPublicationStateUnpublished; have != want {
+				t.Fatalf("wrong publication state. want=%s, have=%s", want, have)
+			}
+		}
+
+		// Applying changeset specs under the same name fails.
+		_, err = svc.ApplyChangesetSpecs(userCtx, ApplyChangesetSpecsOpts{
+			NamespaceUserID: user.ID,
+			Name:            "fix-spelling",
+			ChangesetSpecs:  []*batcheslib.ChangesetSpec{changesetSpec(graphqlbackend.MarshalRepositoryID(repos[0].ID))},
+		})
+		if err != ErrMatchingBatchChangeExists {
+			t.Fatalf("unexpected error. want=%s, got=%v", ErrMatchingBatchChangeExists, err)
+		}
+	})
+
+	t.Run("no changeset specs", func(t *testing.T) {
+		_, err := svc.ApplyChangesetSpecs(userCtx, ApplyChangesetSpecsOpts{
+			NamespaceUserID: user.ID,
+			Name:            "empty",
+		})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+}
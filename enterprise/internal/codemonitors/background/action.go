@@ -1,8 +1,10 @@
 package background
 
 import (
+	"fmt"
 	"net/url"
 
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
 
@@ -17,5 +19,47 @@ type actionArgs struct {
 
 	Query          string
 	Results        []*result.CommitMatch
+	ContentChanges []*edb.ContentChange
 	IncludeResults bool
+
+	// Delivery is the delivery policy of the action, and Runs the number of
+	// runs of the code monitor whose results are sent.
+	Delivery edb.DeliveryPolicy
+	Runs     int
+}
+
+// isDigest returns whether the results of several runs of the code monitor
+// are sent together.
+func (a actionArgs) isDigest() bool {
+	return a.Delivery.IsDigest() || a.Runs > 1
+}
+
+// digestPeriod describes the period the results of a digest were found in.
+func (a actionArgs) digestPeriod() string {
+	switch a.Delivery {
+	case edb.DeliveryHourlyDigest:
+		return "in the past hour"
+	case edb.DeliveryDailyDigest:
+		return "in the past day"
+	default:
+		return "since the last notification"
+	}
+}
+
+// contentChangeType is the type of a changed match of a monitor on file
+// contents shown in notifications.
+func contentChangeType(change *edb.ContentChange) string {
+	if change.Removed {
+		return "Removed"
+	}
+	return "Added"
+}
+
+// formatContentMatch formats a match of a monitor on file contents as its
+// path and line number, followed by the matched line.
+func formatContentMatch(m edb.ContentMatch) string {
+	if m.LineNumber == 0 {
+		return m.Path
+	}
+	return fmt.Sprintf("%s:%d\n%s", m.Path, m.LineNumber, m.Preview)
 }
@@ -32,6 +32,9 @@ func SendEmailForNewSearchResult(ctx context.Context, db database.DB, userID int
 	if MockSendEmailForNewSearchResult != nil {
 		return MockSendEmailForNewSearchResult(ctx, db, userID, data)
 	}
+	if data.IsDigest {
+		return sendEmail(ctx, db, userID, digestEmailTemplates, data)
+	}
 	return sendEmail(ctx, db, userID, newSearchResultsEmailTemplates, data)
 }
 
@@ -41,6 +44,12 @@ var (
 
 	//go:embed email_template.txt.tmpl
 	textTemplate string
+
+	//go:embed email_digest_template.html.tmpl
+	digestHTMLTemplate string
+
+	//go:embed email_digest_template.txt.tmpl
+	digestTextTemplate string
 )
 
 var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
@@ -49,6 +58,12 @@ var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
 	HTML:    htmlTemplate,
 })
 
+var digestEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Priority}}Sourcegraph code monitor {{.Description}} detected {{.TotalCount}} new {{.ResultPluralized}} {{.DigestPeriod}}`,
+	Text:    digestTextTemplate,
+	HTML:    digestHTMLTemplate,
+})
+
 type TemplateDataNewSearchResults struct {
 	Priority                  string
 	CodeMonitorURL            string
@@ -62,6 +77,14 @@ type TemplateDataNewSearchResults struct {
 	TruncatedResultPluralized string
 	DisplayMoreLink           bool
 	IsTest                    bool
+
+	// IsDigest is set if the email contains the results of several runs of
+	// the code monitor, either because it's a digest or because the
+	// notifications of the code monitor are rate limited.
+	IsDigest      bool
+	DigestPeriod  string
+	Runs          int
+	RunPluralized string
 }
 
 func NewTemplateDataForNewSearchResults(args actionArgs, email *edb.EmailAction) (d *TemplateDataNewSearchResults, err error) {
@@ -78,11 +101,26 @@ func NewTemplateDataForNewSearchResults(args actionArgs, email *edb.EmailAction)
 		priority = ""
 	}
 
-	truncatedResults, totalCount, truncatedCount := truncateResults(args.Results, 5)
-
-	displayResults := make([]*DisplayResult, len(truncatedResults))
-	for i, result := range truncatedResults {
-		displayResults[i] = toDisplayResult(result, args.ExternalURL)
+	var (
+		displayResults             []*DisplayResult
+		totalCount, truncatedCount int
+	)
+	limit := 5
+	if args.isDigest() {
+		limit = 10
+	}
+	if len(args.ContentChanges) > 0 {
+		var truncatedChanges []*edb.ContentChange
+		truncatedChanges, totalCount, truncatedCount = truncateContentChanges(args.ContentChanges, limit)
+		for _, change := range truncatedChanges {
+			displayResults = append(displayResults, contentChangeToDisplayResult(change, args.ExternalURL))
+		}
+	} else {
+		var truncatedResults []*result.CommitMatch
+		truncatedResults, totalCount, truncatedCount = truncateResults(args.Results, limit)
+		for _, result := range truncatedResults {
+			displayResults = append(displayResults, toDisplayResult(result, args.ExternalURL))
+		}
 	}
 
 	return &TemplateDataNewSearchResults{
@@ -97,6 +135,10 @@ func NewTemplateDataForNewSearchResults(args actionArgs, email *edb.EmailAction)
 		ResultPluralized:          pluralize("result", totalCount),
 		TruncatedResultPluralized: pluralize("result", truncatedCount),
 		DisplayMoreLink:           args.IncludeResults && truncatedCount > 0,
+		IsDigest:                  args.isDigest(),
+		DigestPeriod:              args.digestPeriod(),
+		Runs:                      args.Runs,
+		RunPluralized:             pluralize("run", args.Runs),
 	}, nil
 }
 
@@ -151,6 +193,10 @@ func getCommitURL(externalURL *url.URL, repoName, oid, utmSource string) string
 	return sourcegraphURL(externalURL, fmt.Sprintf("%s/-/commit/%s", repoName, oid), "", utmSource)
 }
 
+func getFileURL(externalURL *url.URL, repoName, oid, path, utmSource string) string {
+	return sourcegraphURL(externalURL, fmt.Sprintf("%s@%s/-/blob/%s", repoName, oid, path), "", utmSource)
+}
+
 var (
 	externalURLOnce  sync.Once
 	externalURLValue *url.URL
@@ -222,3 +268,13 @@ func toDisplayResult(result *result.CommitMatch, externalURL *url.URL) *DisplayR
 		Content:    content,
 	}
 }
+
+func contentChangeToDisplayResult(change *edb.ContentChange, externalURL *url.URL) *DisplayResult {
+	return &DisplayResult{
+		ResultType: contentChangeType(change),
+		CommitURL:  getFileURL(externalURL, string(change.Repo), string(change.Commit), change.Path, utmSourceEmail),
+		RepoName:   string(change.Repo),
+		CommitID:   change.Commit.Short(),
+		Content:    formatContentMatch(change.ContentMatch),
+	}
+}
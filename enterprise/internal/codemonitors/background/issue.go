@@ -0,0 +1,566 @@
+package background
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sourcegraph/log"
+
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const utmSourceIssue = "code-monitor-issue"
+
+// issueTrackerURN is the URN of the clients of issue tracker actions, which
+// don't belong to an external service.
+const issueTrackerURN = "code-monitors"
+
+// issueTracker creates and comments on issues in an issue tracker.
+type issueTracker interface {
+	// CreateIssue creates an issue, and returns its key and URL.
+	CreateIssue(ctx context.Context, i *issue) (key, url string, err error)
+	CommentOnIssue(ctx context.Context, key string, i *issue) error
+}
+
+// issue is an issue about matches of a code monitor.
+type issue struct {
+	// Subject is what the issue is about, see edb.Issue.
+	Subject   string
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+
+	args actionArgs
+}
+
+// issuesFor groups the matches of args into the issues an issue tracker
+// action creates: one for each matching commit if perCommit is set, or else a
+// single one.
+func issuesFor(args actionArgs, perCommit bool) []*issue {
+	if !perCommit {
+		return []*issue{{
+			Title: fmt.Sprintf("Code monitor %q detected new matches", args.MonitorDescription),
+			Body:  issueBody(args),
+			args:  args,
+		}}
+	}
+
+	var (
+		issues    []*issue
+		bySubject = map[string]*issue{}
+	)
+	get := func(rc repoCommit) *issue {
+		subject := fmt.Sprintf("%s@%s", rc.Repo, rc.Commit)
+		i, ok := bySubject[subject]
+		if !ok {
+			i = &issue{
+				Subject: subject,
+				Title:   fmt.Sprintf("Code monitor %q matched %s@%s", args.MonitorDescription, rc.Repo, rc.Commit.Short()),
+				args:    args,
+			}
+			i.args.Results, i.args.ContentChanges = nil, nil
+			bySubject[subject] = i
+			issues = append(issues, i)
+		}
+		return i
+	}
+	for _, r := range args.Results {
+		i := get(repoCommit{r.Repo.Name, r.Commit.ID})
+		i.args.Results = append(i.args.Results, r)
+	}
+	for _, c := range args.ContentChanges {
+		i := get(repoCommit{c.Repo, c.Commit})
+		i.args.ContentChanges = append(i.args.ContentChanges, c)
+	}
+	for _, i := range issues {
+		i.Body = issueBody(i.args)
+	}
+	return issues
+}
+
+// issueBody returns the Markdown body of an issue, and of the comments on it
+// about further matches.
+func issueBody(args actionArgs) string {
+	limit := 5
+	if args.isDigest() {
+		limit = 10
+	}
+	truncatedResults, totalCount, truncatedCount := truncateResults(args.Results, limit)
+	truncatedChanges, totalChanges, truncatedChangesCount := truncateContentChanges(args.ContentChanges, limit)
+	if totalChanges > 0 {
+		totalCount, truncatedCount = totalChanges, truncatedChangesCount
+	}
+
+	var b strings.Builder
+	summary := "%s's Sourcegraph code monitor, **%s**, detected **%d** new matches"
+	if totalChanges > 0 {
+		summary = "%s's Sourcegraph code monitor, **%s**, detected **%d** changed matches"
+	}
+	fmt.Fprintf(&b, summary, args.MonitorOwnerName, args.MonitorDescription, totalCount)
+	if args.isDigest() {
+		fmt.Fprintf(&b, " %s across %d %s", args.digestPeriod(), args.Runs, pluralize("run", args.Runs))
+	}
+	b.WriteString(".\n\n")
+
+	if args.IncludeResults {
+		for _, change := range truncatedChanges {
+			fmt.Fprintf(&b, "%s match: [%s@%s](%s)\n\n%s\n\n",
+				contentChangeType(change),
+				change.Repo,
+				change.Commit.Short(),
+				getFileURL(args.ExternalURL, string(change.Repo), string(change.Commit), change.Path, args.UTMSource),
+				formatMarkdownCodeBlock(formatContentMatch(change.ContentMatch)),
+			)
+		}
+		for _, result := range truncatedResults {
+			resultType, content := "Message", ""
+			if result.DiffPreview != nil {
+				resultType, content = "Diff", truncateString(result.DiffPreview.Content, 10)
+			} else if result.MessagePreview != nil {
+				content = truncateString(result.MessagePreview.Content, 10)
+			}
+			fmt.Fprintf(&b, "%s match: [%s@%s](%s)\n\n%s\n\n",
+				resultType,
+				result.Repo.Name,
+				result.Commit.ID.Short(),
+				getCommitURL(args.ExternalURL, string(result.Repo.Name), string(result.Commit.ID), args.UTMSource),
+				formatMarkdownCodeBlock(content),
+			)
+		}
+		if truncatedCount > 0 {
+			fmt.Fprintf(&b, "...and [%d more matches](%s).\n\n", truncatedCount, getSearchURL(args.ExternalURL, args.Query, args.UTMSource))
+		}
+	} else {
+		fmt.Fprintf(&b, "[View results](%s)\n\n", getSearchURL(args.ExternalURL, args.Query, args.UTMSource))
+	}
+
+	fmt.Fprintf(&b, "If you are %s, you can [edit your code monitor](%s).\n", args.MonitorOwnerName, getCodeMonitorURL(args.ExternalURL, args.MonitorID, args.UTMSource))
+	return b.String()
+}
+
+func formatMarkdownCodeBlock(s string) string {
+	return fmt.Sprintf("```\n%s\n```", strings.TrimSuffix(strings.ReplaceAll(s, "```", "\\`\\`\\`"), "\n"))
+}
+
+// repoCommit is a commit of a repository.
+type repoCommit struct {
+	Repo   api.RepoName
+	Commit api.CommitID
+}
+
+// matchedPaths returns the paths of the files matched in each commit of args.
+func matchedPaths(args actionArgs) map[repoCommit][]string {
+	paths := map[repoCommit][]string{}
+	for _, r := range args.Results {
+		rc := repoCommit{r.Repo.Name, r.Commit.ID}
+		for _, f := range r.Diff {
+			if f.NewName != "" && f.NewName != "/dev/null" {
+				paths[rc] = append(paths[rc], f.NewName)
+			} else {
+				paths[rc] = append(paths[rc], f.OrigName)
+			}
+		}
+		paths[rc] = append(paths[rc], r.ModifiedFiles...)
+	}
+	for _, c := range args.ContentChanges {
+		rc := repoCommit{c.Repo, c.Commit}
+		paths[rc] = append(paths[rc], c.Path)
+	}
+	return paths
+}
+
+// codeOwnerFetcher fetches the code owners of a repository at a commit.
+type codeOwnerFetcher func(ctx context.Context, repo api.RepoName, commit api.CommitID) (codeownership.Ruleset, error)
+
+// codeOwners returns the usernames of the code owners of the files matched
+// in args. Teams and owners identified by email are left out, since they
+// can't be assigned to issues.
+func codeOwners(ctx context.Context, fetch codeOwnerFetcher, args actionArgs) ([]string, error) {
+	seen := map[string]struct{}{}
+	for rc, paths := range matchedPaths(args) {
+		ruleset, err := fetch(ctx, rc.Repo, rc.Commit)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching code owners of %s@%s", rc.Repo, rc.Commit)
+		}
+		for _, path := range paths {
+			owners, err := ruleset.Match(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range owners {
+				if o.Type == codeownership.UsernameOwner {
+					seen[o.Value] = struct{}{}
+				}
+			}
+		}
+	}
+	usernames := make([]string, 0, len(seen))
+	for u := range seen {
+		usernames = append(usernames, u)
+	}
+	sort.Strings(usernames)
+	return usernames, nil
+}
+
+// labelAndAssign sets the labels and assignees of issues configured on the
+// issue tracker action a. If fetch is non-nil, the code owners of the
+// matched files are assigned too.
+func labelAndAssign(ctx context.Context, a *edb.IssueTrackerAction, fetch codeOwnerFetcher, issues []*issue) error {
+	for _, i := range issues {
+		i.Labels = a.Labels
+		i.Assignees = append([]string(nil), a.Assignees...)
+		if fetch == nil {
+			continue
+		}
+
+		owners, err := codeOwners(ctx, fetch, i.args)
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]struct{}, len(a.Assignees))
+		for _, assignee := range a.Assignees {
+			seen[assignee] = struct{}{}
+		}
+		for _, owner := range owners {
+			if _, ok := seen[owner]; !ok {
+				i.Assignees = append(i.Assignees, owner)
+			}
+		}
+	}
+	return nil
+}
+
+// sendIssues creates an issue in t for each issue whose subject doesn't have
+// one yet, and comments on the existing issue otherwise. Every issue sent is
+// recorded with jobID as soon as it is sent, so s must not be a transaction
+// that is rolled back when the job fails: a retry of the job then skips the
+// issues it already sent.
+func sendIssues(ctx context.Context, s edb.CodeMonitorStore, t issueTracker, trackerID int64, jobID int32, issues []*issue) error {
+	for _, i := range issues {
+		existing, err := s.GetIssue(ctx, trackerID, i.Subject)
+		if err != nil {
+			return errors.Wrap(err, "GetIssue")
+		}
+		if existing != nil {
+			if existing.ActionJob != nil && *existing.ActionJob == jobID {
+				// An earlier attempt of this job already sent this issue.
+				continue
+			}
+			if err := t.CommentOnIssue(ctx, existing.Key, i); err != nil {
+				return errors.Wrapf(err, "commenting on issue %s", existing.Key)
+			}
+			existing.ActionJob = &jobID
+			if err := s.UpsertIssue(ctx, existing); err != nil {
+				return errors.Wrap(err, "UpsertIssue")
+			}
+			continue
+		}
+
+		key, url, err := t.CreateIssue(ctx, i)
+		if err != nil {
+			return errors.Wrap(err, "creating issue")
+		}
+		err = s.UpsertIssue(ctx, &edb.Issue{
+			IssueTracker: trackerID,
+			Subject:      i.Subject,
+			Key:          key,
+			URL:          url,
+			ActionJob:    &jobID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "UpsertIssue")
+		}
+	}
+	return nil
+}
+
+// newIssueTracker returns the issue tracker of action a.
+func newIssueTracker(logger log.Logger, a *edb.IssueTrackerAction) (issueTracker, error) {
+	switch a.Kind {
+	case edb.IssueTrackerGitHub:
+		baseURL, err := url.Parse(a.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing GitHub URL")
+		}
+		owner, name, ok := strings.Cut(a.Repository, "/")
+		if !ok {
+			return nil, errors.Errorf("invalid GitHub repository %q", a.Repository)
+		}
+		apiURL, _ := github.APIRoot(baseURL)
+		return &githubIssueTracker{
+			client: github.NewV3Client(logger, issueTrackerURN, apiURL, &auth.OAuthBearerToken{Token: a.Token}, nil),
+			owner:  owner,
+			name:   name,
+		}, nil
+
+	case edb.IssueTrackerGitLab:
+		baseURL, err := url.Parse(a.URL)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing GitLab URL")
+		}
+		return &gitlabIssueTracker{
+			client:  gitlab.NewClientProvider(issueTrackerURN, baseURL, nil, nil).GetPATClient(a.Token, ""),
+			project: a.Repository,
+		}, nil
+
+	case edb.IssueTrackerREST:
+		if a.RESTTemplate == nil {
+			return nil, errors.New("REST issue tracker without a template")
+		}
+		return newRESTIssueTracker(httpcli.ExternalDoer, a.URL, a.Token, a.RESTTemplate)
+
+	default:
+		return nil, errors.Errorf("unknown issue tracker kind %q", a.Kind)
+	}
+}
+
+type githubIssueTracker struct {
+	client      *github.V3Client
+	owner, name string
+}
+
+func (t *githubIssueTracker) CreateIssue(ctx context.Context, i *issue) (string, string, error) {
+	created, err := t.client.CreateIssue(ctx, t.owner, t.name, &github.CreateIssueInput{
+		Title:     i.Title,
+		Body:      i.Body,
+		Labels:    i.Labels,
+		Assignees: i.Assignees,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(created.Number), created.HTMLURL, nil
+}
+
+func (t *githubIssueTracker) CommentOnIssue(ctx context.Context, key string, i *issue) error {
+	number, err := strconv.Atoi(key)
+	if err != nil {
+		return errors.Wrap(err, "invalid issue number")
+	}
+	return t.client.CreateIssueComment(ctx, t.owner, t.name, number, i.Body)
+}
+
+type gitlabIssueTracker struct {
+	client  *gitlab.Client
+	project string
+}
+
+func (t *gitlabIssueTracker) getProject(ctx context.Context) (*gitlab.Project, error) {
+	return t.client.GetProject(ctx, gitlab.GetProjectOp{PathWithNamespace: t.project})
+}
+
+func (t *gitlabIssueTracker) CreateIssue(ctx context.Context, i *issue) (string, string, error) {
+	project, err := t.getProject(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	// GitLab assigns issues by user ID, so we look up the ID of each username.
+	// Unknown usernames are skipped.
+	var assigneeIDs []int32
+	for _, username := range i.Assignees {
+		users, _, err := t.client.ListUsers(ctx, "users?"+url.Values{"username": {username}}.Encode())
+		if err != nil {
+			return "", "", errors.Wrapf(err, "looking up GitLab user %q", username)
+		}
+		for _, u := range users {
+			assigneeIDs = append(assigneeIDs, u.ID)
+		}
+	}
+
+	created, err := t.client.CreateIssue(ctx, project, gitlab.CreateIssueOpts{
+		Title:       i.Title,
+		Description: i.Body,
+		Labels:      strings.Join(i.Labels, ","),
+		AssigneeIDs: assigneeIDs,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return strconv.FormatInt(int64(created.IID), 10), created.WebURL, nil
+}
+
+func (t *gitlabIssueTracker) CommentOnIssue(ctx context.Context, key string, i *issue) error {
+	iid, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid issue IID")
+	}
+	project, err := t.getProject(ctx)
+	if err != nil {
+		return err
+	}
+	return t.client.CreateIssueNote(ctx, project, gitlab.ID(iid), i.Body)
+}
+
+// restIssueTracker creates issues with requests built from the templates of
+// an issue tracker action, e.g. in Jira.
+type restIssueTracker struct {
+	doer     httpcli.Doer
+	url      string
+	token    string
+	keyField string
+	urlField string
+
+	issueBody   *template.Template
+	commentURL  *template.Template
+	commentBody *template.Template
+}
+
+// restTemplateData is the data the templates of REST issue trackers are
+// executed with.
+type restTemplateData struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+	// Key is the key of the issue commented on.
+	Key string
+
+	MonitorDescription string
+	MonitorURL         string
+}
+
+var restTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, so that strings can be embedded in JSON
+	// bodies.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func newRESTIssueTracker(doer httpcli.Doer, url, token string, tmpl *edb.RESTIssueTemplate) (*restIssueTracker, error) {
+	if tmpl.KeyField == "" {
+		return nil, errors.New("REST issue tracker template without a key field")
+	}
+	t := &restIssueTracker{doer: doer, url: url, token: token, keyField: tmpl.KeyField, urlField: tmpl.URLField}
+	for _, tt := range []struct {
+		name string
+		text string
+		dst  **template.Template
+	}{
+		{"issueBody", tmpl.IssueBody, &t.issueBody},
+		{"commentURL", tmpl.CommentURL, &t.commentURL},
+		{"commentBody", tmpl.CommentBody, &t.commentBody},
+	} {
+		parsed, err := template.New(tt.name).Funcs(restTemplateFuncs).Option("missingkey=error").Parse(tt.text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s template", tt.name)
+		}
+		*tt.dst = parsed
+	}
+	return t, nil
+}
+
+func (t *restIssueTracker) data(i *issue, key string) restTemplateData {
+	return restTemplateData{
+		Title:              i.Title,
+		Body:               i.Body,
+		Labels:             i.Labels,
+		Assignees:          i.Assignees,
+		Key:                key,
+		MonitorDescription: i.args.MonitorDescription,
+		MonitorURL:         getCodeMonitorURL(i.args.ExternalURL, i.args.MonitorID, i.args.UTMSource),
+	}
+}
+
+func (t *restIssueTracker) CreateIssue(ctx context.Context, i *issue) (string, string, error) {
+	var body bytes.Buffer
+	if err := t.issueBody.Execute(&body, t.data(i, "")); err != nil {
+		return "", "", errors.Wrap(err, "executing issue body template")
+	}
+
+	var resp map[string]any
+	if err := t.post(ctx, t.url, body.Bytes(), &resp); err != nil {
+		return "", "", err
+	}
+
+	key, ok := lookupField(resp, t.keyField)
+	if !ok || key == "" {
+		return "", "", errors.Errorf("response has no field %q", t.keyField)
+	}
+	var issueURL string
+	if t.urlField != "" {
+		issueURL, _ = lookupField(resp, t.urlField)
+	}
+	return key, issueURL, nil
+}
+
+func (t *restIssueTracker) CommentOnIssue(ctx context.Context, key string, i *issue) error {
+	data := t.data(i, key)
+	var u, body bytes.Buffer
+	if err := t.commentURL.Execute(&u, data); err != nil {
+		return errors.Wrap(err, "executing comment URL template")
+	}
+	if err := t.commentBody.Execute(&body, data); err != nil {
+		return errors.Wrap(err, "executing comment body template")
+	}
+	return t.post(ctx, u.String(), body.Bytes(), nil)
+}
+
+func (t *restIssueTracker) post(ctx context.Context, url string, body []byte, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed new request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("Authorization", t.token)
+	}
+
+	resp, err := t.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post to issue tracker")
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return StatusCodeError{
+			Code:   resp.StatusCode,
+			Status: resp.Status,
+			Body:   string(respBody),
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(respBody, result), "decoding issue tracker response")
+}
+
+// lookupField returns the string or number at the dot-separated path in v.
+func lookupField(v map[string]any, path string) (string, bool) {
+	var cur any = v
+	for _, field := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		cur = m[field]
+	}
+	switch x := cur.(type) {
+	case string:
+		return x, true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,207 @@
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hexops/autogold"
+	"github.com/stretchr/testify/require"
+
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestIssuesFor(t *testing.T) {
+	eu, err := url.Parse("https://sourcegraph.com")
+	require.NoError(t, err)
+
+	otherCommitMock := result.CommitMatch{
+		Commit: gitdomain.Commit{ID: "1b2e4c6a9b0d8a7c5e3f1d2c"},
+		Repo:   types.MinimalRepo{Name: "github.com/test/other"},
+		MessagePreview: &result.MatchedString{
+			Content: "fix: disable TLS verification\n",
+		},
+	}
+	action := actionArgs{
+		MonitorDescription: "My test monitor",
+		MonitorOwnerName:   "Camden Cheek",
+		ExternalURL:        eu,
+		MonitorID:          42,
+		UTMSource:          utmSourceIssue,
+		Query:              "repo:camdentest -file:id_rsa.pub BEGIN",
+		Results:            []*result.CommitMatch{&diffResultMock, &commitResultMock, &otherCommitMock},
+		IncludeResults:     true,
+	}
+
+	format := func(issues []*issue) string {
+		var b strings.Builder
+		for _, i := range issues {
+			fmt.Fprintf(&b, "subject: %q\ntitle: %s\n\n%s\n---\n", i.Subject, i.Title, i.Body)
+		}
+		return b.String()
+	}
+
+	t.Run("per commit", func(t *testing.T) {
+		issues := issuesFor(action, true)
+		require.Len(t, issues, 2)
+		autogold.Equal(t, autogold.Raw(format(issues)))
+	})
+
+	t.Run("single digest", func(t *testing.T) {
+		actionCopy := action
+		actionCopy.IncludeResults = false
+		actionCopy.Delivery = edb.DeliveryDailyDigest
+		actionCopy.Runs = 3
+
+		issues := issuesFor(actionCopy, false)
+		require.Len(t, issues, 1)
+		autogold.Equal(t, autogold.Raw(format(issues)))
+	})
+
+	t.Run("content changes", func(t *testing.T) {
+		actionCopy := action
+		actionCopy.Results = nil
+		actionCopy.ContentChanges = []*edb.ContentChange{{
+			ContentMatch: edb.ContentMatch{Path: "tls.go", LineNumber: 12, Preview: "\tInsecureSkipVerify: true,"},
+			Repo:         "github.com/test/test",
+			Commit:       "7815187511872asbasdfgasd",
+		}}
+
+		issues := issuesFor(actionCopy, true)
+		require.Len(t, issues, 1)
+		autogold.Equal(t, autogold.Raw(format(issues)))
+	})
+}
+
+func TestLabelAndAssign(t *testing.T) {
+	gs := gitserver.NewMockClient()
+	gs.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, path string, _ authz.SubRepoPermissionChecker) ([]byte, error) {
+		if path != "CODEOWNERS" {
+			return nil, nil
+		}
+		return []byte("*.go @alice @sourcegraph/search alice@example.com\n/docs/ @bob\n"), nil
+	})
+	fetch := func(ctx context.Context, repo api.RepoName, commit api.CommitID) (codeownership.Ruleset, error) {
+		return codeownership.NewRuleset(ctx, gs, repo, commit)
+	}
+
+	match := diffResultMock
+	match.Diff = []result.DiffFile{{OrigName: "docs/old.md", NewName: "/dev/null"}, {OrigName: "main.go", NewName: "main.go"}}
+	issues := issuesFor(actionArgs{Results: []*result.CommitMatch{&match}}, true)
+
+	a := &edb.IssueTrackerAction{
+		Labels:           []string{"security"},
+		Assignees:        []string{"bob", "carol"},
+		AssignCodeOwners: true,
+	}
+	err := labelAndAssign(context.Background(), a, fetch, issues)
+	require.NoError(t, err)
+	require.Equal(t, []string{"security"}, issues[0].Labels)
+	require.Equal(t, []string{"bob", "carol", "alice"}, issues[0].Assignees)
+
+	// Without code owners, only the configured assignees are assigned.
+	err = labelAndAssign(context.Background(), a, nil, issues)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob", "carol"}, issues[0].Assignees)
+}
+
+func TestRESTIssueTracker(t *testing.T) {
+	var requests []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Basic dXNlcjp0b2tlbg==", r.Header.Get("Authorization"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.URL.Path+" "+string(b))
+
+		if r.URL.Path == "/rest/api/2/issue" {
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":   "10000",
+				"key":  "MON-1",
+				"self": "https://jira.example.com/rest/api/2/issue/10000",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+
+	tracker, err := newRESTIssueTracker(s.Client(), s.URL+"/rest/api/2/issue", "Basic dXNlcjp0b2tlbg==", &edb.RESTIssueTemplate{
+		IssueBody:   `{"fields": {"project": {"key": "MON"}, "summary": {{json .Title}}, "description": {{json .Body}}, "labels": {{json .Labels}}}}`,
+		KeyField:    "key",
+		URLField:    "self",
+		CommentURL:  s.URL + "/rest/api/2/issue/{{.Key}}/comment",
+		CommentBody: `{"body": {{json .Body}}}`,
+	})
+	require.NoError(t, err)
+
+	store := edb.NewMockCodeMonitorStore()
+	i := &issue{
+		Title:  `Code monitor "TLS" detected new matches`,
+		Body:   "New matches.\n",
+		Labels: []string{"security"},
+	}
+
+	// The first notification creates an issue.
+	err = sendIssues(context.Background(), store, tracker, 7, 1, []*issue{i})
+	require.NoError(t, err)
+	require.Len(t, store.UpsertIssueFunc.History(), 1)
+	created := store.UpsertIssueFunc.History()[0].Arg1
+	job := int32(1)
+	require.Equal(t, &edb.Issue{
+		IssueTracker: 7,
+		Key:          "MON-1",
+		URL:          "https://jira.example.com/rest/api/2/issue/10000",
+		ActionJob:    &job,
+	}, created)
+
+	// A retry of the same job doesn't send it again.
+	store.GetIssueFunc.SetDefaultReturn(created, nil)
+	err = sendIssues(context.Background(), store, tracker, 7, 1, []*issue{i})
+	require.NoError(t, err)
+	require.Len(t, store.UpsertIssueFunc.History(), 1)
+
+	// Further notifications comment on it.
+	err = sendIssues(context.Background(), store, tracker, 7, 2, []*issue{i})
+	require.NoError(t, err)
+	require.Len(t, store.UpsertIssueFunc.History(), 2)
+	require.Equal(t, int32(2), *store.UpsertIssueFunc.History()[1].Arg1.ActionJob)
+
+	require.Equal(t, []string{
+		`/rest/api/2/issue {"fields": {"project": {"key": "MON"}, "summary": "Code monitor \"TLS\" detected new matches", "description": "New matches.\n", "labels": ["security"]}}`,
+		`/rest/api/2/issue/MON-1/comment {"body": "New matches.\n"}`,
+	}, requests)
+}
+
+func TestLookupField(t *testing.T) {
+	v := map[string]any{
+		"key":    "MON-1",
+		"iid":    float64(42),
+		"fields": map[string]any{"url": "https://example.com"},
+	}
+	for path, want := range map[string]string{
+		"key":        "MON-1",
+		"iid":        "42",
+		"fields.url": "https://example.com",
+	} {
+		got, ok := lookupField(v, path)
+		require.True(t, ok, path)
+		require.Equal(t, want, got)
+	}
+
+	_, ok := lookupField(v, "fields.missing")
+	require.False(t, ok)
+}
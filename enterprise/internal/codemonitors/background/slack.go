@@ -11,6 +11,7 @@ import (
 
 	"github.com/slack-go/slack"
 
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -25,18 +26,39 @@ func slackPayload(args actionArgs) *slack.WebhookMessage {
 		return slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", s, false, false), nil, nil)
 	}
 
-	truncatedResults, totalCount, truncatedCount := truncateResults(args.Results, 5)
+	limit := 5
+	if args.isDigest() {
+		limit = 10
+	}
+	truncatedResults, totalCount, truncatedCount := truncateResults(args.Results, limit)
+	truncatedChanges, totalChanges, truncatedChangesCount := truncateContentChanges(args.ContentChanges, limit)
+	if totalChanges > 0 {
+		totalCount, truncatedCount = totalChanges, truncatedChangesCount
+	}
 
+	summary := "%s's Sourcegraph Code monitor, *%s*, detected *%d* new matches"
+	if totalChanges > 0 {
+		summary = "%s's Sourcegraph Code monitor, *%s*, detected *%d* changed matches"
+	}
+	summary = fmt.Sprintf(summary, args.MonitorOwnerName, args.MonitorDescription, totalCount)
+	if args.isDigest() {
+		summary += fmt.Sprintf(" %s across %d %s", args.digestPeriod(), args.Runs, pluralize("run", args.Runs))
+	}
 	blocks := []slack.Block{
-		newMarkdownSection(fmt.Sprintf(
-			"%s's Sourcegraph Code monitor, *%s*, detected *%d* new matches.",
-			args.MonitorOwnerName,
-			args.MonitorDescription,
-			totalCount,
-		)),
+		newMarkdownSection(summary + "."),
 	}
 
 	if args.IncludeResults {
+		for _, change := range truncatedChanges {
+			blocks = append(blocks, newMarkdownSection(fmt.Sprintf(
+				"%s match: <%s|%s@%s>",
+				contentChangeType(change),
+				getFileURL(args.ExternalURL, string(change.Repo), string(change.Commit), change.Path, args.UTMSource),
+				change.Repo,
+				change.Commit.Short(),
+			)))
+			blocks = append(blocks, newMarkdownSection(formatCodeBlock(formatContentMatch(change.ContentMatch))))
+		}
 		for _, result := range truncatedResults {
 			resultType := "Message"
 			if result.DiffPreview != nil {
@@ -114,6 +136,13 @@ func truncateResults(results []*result.CommitMatch, maxResults int) (_ []*result
 	return output, totalCount, totalCount - outputCount
 }
 
+func truncateContentChanges(changes []*edb.ContentChange, maxResults int) (_ []*edb.ContentChange, totalCount, truncatedCount int) {
+	if len(changes) <= maxResults {
+		return changes, len(changes), 0
+	}
+	return changes[:maxResults], len(changes), len(changes) - maxResults
+}
+
 // adapted from slack.PostWebhookCustomHTTPContext
 func postSlackWebhook(ctx context.Context, doer httpcli.Doer, url string, msg *slack.WebhookMessage) error {
 	raw, err := json.Marshal(msg)
@@ -12,6 +12,7 @@ import (
 	"github.com/hexops/autogold"
 	"github.com/stretchr/testify/require"
 
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
 
@@ -83,6 +84,13 @@ func TestSlackWebhook(t *testing.T) {
 	t.Run("golden without results", func(t *testing.T) {
 		autogold.Equal(t, jsonSlackPayload(action))
 	})
+
+	t.Run("golden digest", func(t *testing.T) {
+		actionCopy := action
+		actionCopy.Delivery = edb.DeliveryDailyDigest
+		actionCopy.Runs = 3
+		autogold.Equal(t, jsonSlackPayload(actionCopy))
+	})
 }
 
 func TestTriggerTestSlackWebhookAction(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -61,6 +62,15 @@ type webhookPayload struct {
 	MonitorURL         string          `json:"monitorURL"`
 	Query              string          `json:"query"`
 	Results            []webhookResult `json:"results,omitempty"`
+	ContentChanges     []webhookChange `json:"contentChanges,omitempty"`
+	Digest             *webhookDigest  `json:"digest,omitempty"`
+}
+
+// webhookDigest is set if the payload contains the results of several runs
+// of the code monitor.
+type webhookDigest struct {
+	DeliveryPolicy string `json:"deliveryPolicy"`
+	Runs           int    `json:"runs"`
 }
 
 func generateWebhookPayload(args actionArgs) webhookPayload {
@@ -70,8 +80,16 @@ func generateWebhookPayload(args actionArgs) webhookPayload {
 		Query:              args.Query,
 	}
 
+	if args.isDigest() {
+		p.Digest = &webhookDigest{
+			DeliveryPolicy: string(args.Delivery),
+			Runs:           args.Runs,
+		}
+	}
+
 	if args.IncludeResults {
 		p.Results = generateResults(args.Results)
+		p.ContentChanges = generateContentChanges(args.ContentChanges)
 	}
 
 	return p
@@ -106,6 +124,33 @@ func generateResults(in []*result.CommitMatch) []webhookResult {
 	return out
 }
 
+type webhookChange struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit"`
+	Path       string `json:"path"`
+	LineNumber int    `json:"lineNumber,omitempty"`
+	Preview    string `json:"preview,omitempty"`
+	Removed    bool   `json:"removed"`
+}
+
+func generateContentChanges(in []*edb.ContentChange) []webhookChange {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]webhookChange, len(in))
+	for i, change := range in {
+		out[i] = webhookChange{
+			Repository: string(change.Repo),
+			Commit:     string(change.Commit),
+			Path:       change.Path,
+			LineNumber: change.LineNumber,
+			Preview:    change.Preview,
+			Removed:    change.Removed,
+		}
+	}
+	return out
+}
+
 func rangesToInts(ranges result.Ranges) [][2]int {
 	out := make([][2]int, len(ranges))
 	for i, r := range ranges {
@@ -12,6 +12,7 @@ import (
 	"github.com/hexops/autogold"
 	"github.com/stretchr/testify/require"
 
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
 
@@ -54,6 +55,38 @@ func TestWebhook(t *testing.T) {
 		autogold.Equal(t, autogold.Raw(j))
 	})
 
+	t.Run("golden with content changes", func(t *testing.T) {
+		actionCopy := action
+		actionCopy.Results = nil
+		actionCopy.ContentChanges = []*edb.ContentChange{{
+			ContentMatch: edb.ContentMatch{Path: "tls.go", LineNumber: 12, Preview: "\tInsecureSkipVerify: true,"},
+			Repo:         "github.com/test/test",
+			Commit:       "7815187511872asbasdfgasd",
+		}, {
+			ContentMatch: edb.ContentMatch{Path: "client.go", LineNumber: 40, Preview: "\tInsecureSkipVerify: true,"},
+			Repo:         "github.com/test/test",
+			Commit:       "1b2e4c6a9b0d8a7c5e3f1d2c",
+			Removed:      true,
+		}}
+		actionCopy.IncludeResults = true
+
+		j, err := json.Marshal(generateWebhookPayload(actionCopy))
+		require.NoError(t, err)
+
+		autogold.Equal(t, autogold.Raw(j))
+	})
+
+	t.Run("golden digest", func(t *testing.T) {
+		actionCopy := action
+		actionCopy.Delivery = edb.DeliveryHourlyDigest
+		actionCopy.Runs = 3
+
+		j, err := json.Marshal(generateWebhookPayload(actionCopy))
+		require.NoError(t, err)
+
+		autogold.Equal(t, autogold.Raw(j))
+	})
+
 	t.Run("error is returned", func(t *testing.T) {
 		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			b, err := io.ReadAll(r.Body)
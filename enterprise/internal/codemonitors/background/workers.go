@@ -13,10 +13,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
 	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
@@ -179,7 +182,11 @@ func (r *queryRunner) Handle(ctx context.Context, logger log.Logger, record work
 	results, searchErr := codemonitors.Search(ctx, logger, r.db, query, m.ID, settings)
 
 	// Log next_run and latest_result to table cm_queries.
-	newLatestResult := latestResultTime(q.LatestResult, results, searchErr)
+	var commits []*result.CommitMatch
+	if results != nil {
+		commits = results.Commits
+	}
+	newLatestResult := latestResultTime(q.LatestResult, commits, searchErr)
 	err = s.SetQueryTriggerNextRun(ctx, q.ID, s.Clock()().Add(5*time.Minute), newLatestResult.UTC())
 	if err != nil {
 		return err
@@ -191,12 +198,19 @@ func (r *queryRunner) Handle(ctx context.Context, logger log.Logger, record work
 	}
 
 	// Log the actual query we ran and whether we got any new results.
-	err = s.UpdateTriggerJobWithResults(ctx, triggerJob.ID, query, results)
-	if err != nil {
-		return errors.Wrap(err, "UpdateTriggerJobWithResults")
+	if len(results.ContentChanges) > 0 {
+		err = s.UpdateTriggerJobWithContentChanges(ctx, triggerJob.ID, query, results.ContentChanges)
+		if err != nil {
+			return errors.Wrap(err, "UpdateTriggerJobWithContentChanges")
+		}
+	} else {
+		err = s.UpdateTriggerJobWithResults(ctx, triggerJob.ID, query, results.Commits)
+		if err != nil {
+			return errors.Wrap(err, "UpdateTriggerJobWithResults")
+		}
 	}
 
-	if len(results) > 0 {
+	if len(results.Commits) > 0 || len(results.ContentChanges) > 0 {
 		_, err := s.EnqueueActionJobsForMonitor(ctx, m.ID, triggerJob.ID)
 		if err != nil {
 			return errors.Wrap(err, "store.EnqueueActionJobsForQuery")
@@ -229,12 +243,14 @@ func (r *actionRunner) Handle(ctx context.Context, logger log.Logger, record wor
 		return r.handleWebhook(ctx, j)
 	case j.SlackWebhook != nil:
 		return r.handleSlackWebhook(ctx, j)
+	case j.IssueTracker != nil:
+		return r.handleIssueTracker(ctx, j)
 	default:
-		return errors.New("job must be one of type email, webhook, or slack webhook")
+		return errors.New("job must be one of type email, webhook, slack webhook, or issue tracker")
 	}
 }
 
-func (r *actionRunner) handleEmail(ctx context.Context, j *edb.ActionJob) error {
+func (r *actionRunner) handleEmail(ctx context.Context, j *edb.ActionJob) (err error) {
 	s, err := r.CodeMonitorStore.Transact(ctx)
 	if err != nil {
 		return err
@@ -251,6 +267,11 @@ func (r *actionRunner) handleEmail(ctx context.Context, j *edb.ActionJob) error
 		return errors.Wrap(err, "GetEmailAction")
 	}
 
+	runs, err := collectPendingResults(ctx, s, j, m)
+	if err != nil {
+		return err
+	}
+
 	recs, err := s.ListRecipients(ctx, edb.ListRecipientsOpts{EmailID: j.Email})
 	if err != nil {
 		return errors.Wrap(err, "ListRecipients")
@@ -269,7 +290,10 @@ func (r *actionRunner) handleEmail(ctx context.Context, j *edb.ActionJob) error
 		Query:              m.Query,
 		MonitorOwnerName:   m.OwnerName,
 		Results:            m.Results,
+		ContentChanges:     m.ContentChanges,
 		IncludeResults:     e.IncludeResults,
+		Delivery:           e.Delivery.Policy,
+		Runs:               runs,
 	}
 
 	data, err := NewTemplateDataForNewSearchResults(args, e)
@@ -292,7 +316,7 @@ func (r *actionRunner) handleEmail(ctx context.Context, j *edb.ActionJob) error
 	return nil
 }
 
-func (r *actionRunner) handleWebhook(ctx context.Context, j *edb.ActionJob) error {
+func (r *actionRunner) handleWebhook(ctx context.Context, j *edb.ActionJob) (err error) {
 	s, err := r.CodeMonitorStore.Transact(ctx)
 	if err != nil {
 		return err
@@ -309,6 +333,11 @@ func (r *actionRunner) handleWebhook(ctx context.Context, j *edb.ActionJob) erro
 		return errors.Wrap(err, "GetWebhookAction")
 	}
 
+	runs, err := collectPendingResults(ctx, s, j, m)
+	if err != nil {
+		return err
+	}
+
 	externalURL, err := getExternalURL(ctx)
 	if err != nil {
 		return err
@@ -322,13 +351,16 @@ func (r *actionRunner) handleWebhook(ctx context.Context, j *edb.ActionJob) erro
 		Query:              m.Query,
 		MonitorOwnerName:   m.OwnerName,
 		Results:            m.Results,
+		ContentChanges:     m.ContentChanges,
 		IncludeResults:     w.IncludeResults,
+		Delivery:           w.Delivery.Policy,
+		Runs:               runs,
 	}
 
 	return sendWebhookNotification(ctx, w.URL, args)
 }
 
-func (r *actionRunner) handleSlackWebhook(ctx context.Context, j *edb.ActionJob) error {
+func (r *actionRunner) handleSlackWebhook(ctx context.Context, j *edb.ActionJob) (err error) {
 	s, err := r.CodeMonitorStore.Transact(ctx)
 	if err != nil {
 		return err
@@ -345,6 +377,11 @@ func (r *actionRunner) handleSlackWebhook(ctx context.Context, j *edb.ActionJob)
 		return errors.Wrap(err, "GetSlackWebhookAction")
 	}
 
+	runs, err := collectPendingResults(ctx, s, j, m)
+	if err != nil {
+		return err
+	}
+
 	externalURL, err := getExternalURL(ctx)
 	if err != nil {
 		return err
@@ -358,12 +395,106 @@ func (r *actionRunner) handleSlackWebhook(ctx context.Context, j *edb.ActionJob)
 		Query:              m.Query,
 		MonitorOwnerName:   m.OwnerName,
 		Results:            m.Results,
+		ContentChanges:     m.ContentChanges,
 		IncludeResults:     w.IncludeResults,
+		Delivery:           w.Delivery.Policy,
+		Runs:               runs,
 	}
 
 	return sendSlackNotification(ctx, w.URL, args)
 }
 
+func (r *actionRunner) handleIssueTracker(ctx context.Context, j *edb.ActionJob) (err error) {
+	s, err := r.CodeMonitorStore.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = s.Done(err) }()
+
+	m, err := s.GetActionJobMetadata(ctx, j.ID)
+	if err != nil {
+		return errors.Wrap(err, "GetActionJobMetadata")
+	}
+
+	a, err := s.GetIssueTrackerAction(ctx, *j.IssueTracker)
+	if err != nil {
+		return errors.Wrap(err, "GetIssueTrackerAction")
+	}
+
+	runs, err := collectPendingResults(ctx, s, j, m)
+	if err != nil {
+		return err
+	}
+
+	externalURL, err := getExternalURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := actionArgs{
+		MonitorDescription: m.Description,
+		MonitorID:          a.Monitor,
+		ExternalURL:        externalURL,
+		UTMSource:          utmSourceIssue,
+		Query:              m.Query,
+		MonitorOwnerName:   m.OwnerName,
+		Results:            m.Results,
+		ContentChanges:     m.ContentChanges,
+		IncludeResults:     a.IncludeResults,
+		Delivery:           a.Delivery.Policy,
+		Runs:               runs,
+	}
+
+	logger := log.Scoped("handleIssueTracker", "")
+	tracker, err := newIssueTracker(logger, a)
+	if err != nil {
+		return err
+	}
+
+	var fetch codeOwnerFetcher
+	if a.AssignCodeOwners {
+		gs := gitserver.NewClient(database.NewDBWith(logger, r.CodeMonitorStore))
+		rules := codeownership.NewRulesCache()
+		fetch = func(ctx context.Context, repo api.RepoName, commit api.CommitID) (codeownership.Ruleset, error) {
+			return rules.GetFromCacheOrFetch(ctx, gs, repo, commit)
+		}
+	}
+
+	issues := issuesFor(args, a.IssuePerCommit)
+	if err := labelAndAssign(ctx, a, fetch, issues); err != nil {
+		return err
+	}
+	// Issues are recorded outside of the transaction, since they exist in the
+	// issue tracker even if a later issue fails.
+	return sendIssues(ctx, r.CodeMonitorStore, tracker, a.ID, j.ID, issues)
+}
+
+// collectPendingResults batches the other due jobs of the action of j into j,
+// and adds their results to m. It returns the number of runs of the code
+// monitor whose results are in m.
+func collectPendingResults(ctx context.Context, s edb.CodeMonitorStore, j *edb.ActionJob, m *edb.ActionJobMetadata) (int, error) {
+	pending, err := s.CollectPendingActionJobs(ctx, j)
+	if err != nil {
+		return 0, errors.Wrap(err, "CollectPendingActionJobs")
+	}
+	if len(pending) == 0 {
+		return 1, nil
+	}
+
+	// Pending jobs are from earlier runs, so their results go first.
+	var (
+		results []*result.CommitMatch
+		changes []*edb.ContentChange
+	)
+	for _, p := range pending {
+		results = append(results, p.Results...)
+		changes = append(changes, p.ContentChanges...)
+	}
+	m.Results = append(results, m.Results...)
+	m.ContentChanges = append(changes, m.ContentChanges...)
+	return len(pending) + 1, nil
+}
+
 type StatusCodeError struct {
 	Code   int
 	Status string
@@ -2,6 +2,8 @@ package background
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -111,3 +113,51 @@ func TestActionRunner(t *testing.T) {
 		})
 	}
 }
+
+func TestActionRunnerRollsBackFailedSend(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ctx, ts := edb.NewTestStore(t, db)
+	_, _, _, userCtx := edb.NewTestUser(ctx, t, db)
+	m, err := ts.InsertTestMonitor(userCtx, t)
+	require.NoError(t, err)
+	w, err := ts.CreateWebhookAction(userCtx, m.ID, true, false, srv.URL)
+	require.NoError(t, err)
+	err = ts.UpdateActionDelivery(ctx, edb.WebhookActions, w.ID, edb.ActionDelivery{Policy: edb.DeliveryHourlyDigest})
+	require.NoError(t, err)
+
+	triggerJobs, err := ts.EnqueueQueryTriggerJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, triggerJobs, 1)
+	err = ts.UpdateTriggerJobWithResults(ctx, triggerJobs[0].ID, "test", []*result.CommitMatch{&commitResultMock})
+	require.NoError(t, err)
+
+	// Two runs of the monitor leave two jobs of the digest webhook.
+	var webhookJobs []*edb.ActionJob
+	for i := 0; i < 2; i++ {
+		jobs, err := ts.EnqueueActionJobsForMonitor(ctx, m.ID, triggerJobs[0].ID)
+		require.NoError(t, err)
+		for _, j := range jobs {
+			if j.Webhook != nil {
+				webhookJobs = append(webhookJobs, j)
+			}
+		}
+	}
+	require.Len(t, webhookJobs, 2)
+	_, err = db.ExecContext(ctx, "UPDATE cm_action_jobs SET process_after = NOW()")
+	require.NoError(t, err)
+
+	a := actionRunner{ts}
+	err = a.Handle(ctx, logger, webhookJobs[0])
+	require.Error(t, err)
+
+	// The pending job collected by the failed send is still queued.
+	pending, err := ts.GetActionJob(ctx, webhookJobs[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, "queued", pending.State)
+}
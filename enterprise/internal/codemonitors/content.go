@@ -0,0 +1,268 @@
+package codemonitors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Code monitors on file contents can't search the history of a repository
+// like monitors on commits and diffs do. Instead, they search the default
+// branch of each repository and store the matches as a snapshot. On the next
+// run, the new matches are compared with the snapshot, and the matches that
+// appeared or disappeared are sent to the actions.
+
+var (
+	ErrContentMonitorLimitHit     = errors.New("code monitor on file contents matched more results than the search limit, add a count: filter to the query to increase it")
+	ErrInvalidContentMonitorQuery = errors.New("code monitor on file contents can only search one revision of each repository")
+)
+
+// isContentSearch returns whether the job searches file contents rather than
+// commits or diffs.
+func isContentSearch(j job.Job) bool {
+	return !job.HasDescendent[*commit.SearchJob](j)
+}
+
+// planContentSearch plans the content search of query. The query always
+// contains a cursor, so that the search pages through repositories instead of
+// searching globally and reports the repositories it searched completely.
+// Matches only disappear from those, see emptiedRepos.
+func planContentSearch(ctx context.Context, searchClient client.SearchClient, query string, settings *schema.Settings) (job.Job, error) {
+	query = fmt.Sprintf("(%s) cursor:%s", query, (&search.Cursor{}).Encode())
+	inputs, err := searchClient.Plan(ctx, "V3", nil, query, search.Streaming, settings, envvar.SourcegraphDotComMode())
+	if err != nil {
+		return nil, errcode.MakeNonRetryable(err)
+	}
+	planJob, err := jobutil.NewPlanJob(inputs, inputs.Plan)
+	if err != nil {
+		return nil, errcode.MakeNonRetryable(err)
+	}
+	return planJob, nil
+}
+
+// repoContent is the snapshot of the matches in a repository.
+type repoContent struct {
+	name     api.RepoName
+	snapshot *edb.ContentSnapshot
+}
+
+// runContentSearch runs the content search and groups its matches by
+// repository. It also returns the repositories the search reported as
+// searched completely.
+func runContentSearch(ctx context.Context, clients job.RuntimeClients, planJob job.Job) (map[api.RepoID]*repoContent, map[api.RepoID]struct{}, error) {
+	agg := streaming.NewAggregatingStream()
+	searched := make(map[api.RepoID]struct{})
+	if _, err := planJob.Run(ctx, clients, recordSearched(agg, searched)); err != nil {
+		return nil, nil, err
+	}
+	if agg.Stats.IsLimitHit {
+		// The matches beyond the limit would be detected as disappeared.
+		return nil, nil, errcode.MakeNonRetryable(ErrContentMonitorLimitHit)
+	}
+
+	repos, err := groupContentMatches(agg.Results)
+	return repos, searched, err
+}
+
+// recordSearched returns a stream which sends events on to parent, and adds
+// the repositories that the repo pagers of the search report as searched
+// completely in their position to searched.
+func recordSearched(parent streaming.Sender, searched map[api.RepoID]struct{}) streaming.Sender {
+	var mu sync.Mutex
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		mu.Lock()
+		for _, pos := range event.Stats.Cursor.Pagers {
+			for _, id := range pos.Searched {
+				searched[id] = struct{}{}
+			}
+		}
+		mu.Unlock()
+		parent.Send(event)
+	})
+}
+
+func groupContentMatches(matches result.Matches) (map[api.RepoID]*repoContent, error) {
+	repos := make(map[api.RepoID]*repoContent)
+	for _, m := range matches {
+		fm, ok := m.(*result.FileMatch)
+		if !ok {
+			return nil, errors.Errorf("expected content search to only return file matches, but got type %T", m)
+		}
+
+		rc, ok := repos[fm.Repo.ID]
+		if !ok {
+			rc = &repoContent{name: fm.Repo.Name, snapshot: &edb.ContentSnapshot{CommitID: fm.CommitID}}
+			repos[fm.Repo.ID] = rc
+		} else if rc.snapshot.CommitID != fm.CommitID {
+			return nil, errcode.MakeNonRetryable(ErrInvalidContentMonitorQuery)
+		}
+
+		if len(fm.ChunkMatches) == 0 {
+			rc.snapshot.Matches = append(rc.snapshot.Matches, edb.ContentMatch{Path: fm.Path})
+			continue
+		}
+		for _, lm := range fm.ChunkMatches.AsLineMatches() {
+			rc.snapshot.Matches = append(rc.snapshot.Matches, edb.ContentMatch{
+				Path:       fm.Path,
+				LineNumber: int(lm.LineNumber) + 1,
+				Preview:    lm.Preview,
+			})
+		}
+	}
+
+	for _, rc := range repos {
+		matches := rc.snapshot.Matches
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].Path != matches[j].Path {
+				return matches[i].Path < matches[j].Path
+			}
+			return matches[i].LineNumber < matches[j].LineNumber
+		})
+	}
+	return repos, nil
+}
+
+// searchContent runs a content search for the monitor, returns the matches
+// that changed since the previous run, and stores the new snapshots.
+func searchContent(ctx context.Context, db database.DB, clients job.RuntimeClients, planJob job.Job, monitorID int64) ([]*edb.ContentChange, error) {
+	repos, searched, err := runContentSearch(ctx, clients, planJob)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := edb.NewEnterpriseDB(db).CodeMonitors()
+
+	// Repositories without matches aren't part of the results, so look for
+	// the ones that had matches the last time.
+	snapshotRepos, err := cm.ListContentSnapshotRepos(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	emptied := emptiedRepos(snapshotRepos, repos, searched)
+	if len(emptied) > 0 {
+		// Repositories the owner of the monitor can't see anymore are skipped.
+		rs, err := db.Repos().GetByIDs(ctx, emptied...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			repos[r.ID] = &repoContent{name: r.Name, snapshot: &edb.ContentSnapshot{}}
+		}
+	}
+
+	ids := make([]api.RepoID, 0, len(repos))
+	for id := range repos {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var changes []*edb.ContentChange
+	for _, id := range ids {
+		rc := repos[id]
+		prev, err := cm.GetContentSnapshot(ctx, monitorID, id)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil && rc.snapshot.CommitID != "" && prev.CommitID == rc.snapshot.CommitID {
+			// Early continue if the repo hasn't changed since last search
+			continue
+		}
+		changes = append(changes, diffContentMatches(rc.name, prev, rc.snapshot)...)
+		if err := cm.UpsertContentSnapshot(ctx, monitorID, id, rc.snapshot); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// emptiedRepos returns the repositories of snapshotRepos which have no matches
+// anymore. Repositories without matches aren't part of the results, so only
+// those the search reports as searched completely are considered emptied. The
+// snapshots of the others, for example the ones that were cloning or timed
+// out, are kept.
+func emptiedRepos(snapshotRepos []api.RepoID, repos map[api.RepoID]*repoContent, searched map[api.RepoID]struct{}) []api.RepoID {
+	var emptied []api.RepoID
+	for _, id := range snapshotRepos {
+		if _, ok := repos[id]; ok {
+			continue
+		}
+		if _, ok := searched[id]; ok {
+			emptied = append(emptied, id)
+		}
+	}
+	return emptied
+}
+
+// snapshotContent replaces the snapshots of the monitor with the current
+// matches of a content search.
+func snapshotContent(ctx context.Context, db database.DB, clients job.RuntimeClients, planJob job.Job, monitorID int64) error {
+	repos, _, err := runContentSearch(ctx, clients, planJob)
+	if err != nil {
+		return err
+	}
+
+	cm := edb.NewEnterpriseDB(db).CodeMonitors()
+	if err := cm.DeleteContentSnapshots(ctx, monitorID); err != nil {
+		return err
+	}
+	for id, rc := range repos {
+		if err := cm.UpsertContentSnapshot(ctx, monitorID, id, rc.snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffContentMatches returns the matches of next that aren't in prev, and the
+// matches of prev that aren't in next. Matches are compared by path and
+// preview, so that a line moving within a file isn't detected as a change.
+func diffContentMatches(repo api.RepoName, prev, next *edb.ContentSnapshot) []*edb.ContentChange {
+	type key struct{ path, preview string }
+
+	prevCounts := make(map[key]int)
+	if prev != nil {
+		for _, m := range prev.Matches {
+			prevCounts[key{m.Path, m.Preview}]++
+		}
+	}
+
+	// kept counts the matches of prev that are still in next.
+	kept := make(map[key]int)
+	var changes []*edb.ContentChange
+	for _, m := range next.Matches {
+		k := key{m.Path, m.Preview}
+		if kept[k] < prevCounts[k] {
+			kept[k]++
+			continue
+		}
+		changes = append(changes, &edb.ContentChange{ContentMatch: m, Repo: repo, Commit: next.CommitID})
+	}
+
+	if prev != nil {
+		for _, m := range prev.Matches {
+			k := key{m.Path, m.Preview}
+			if kept[k] > 0 {
+				kept[k]--
+				continue
+			}
+			changes = append(changes, &edb.ContentChange{ContentMatch: m, Repo: repo, Commit: prev.CommitID, Removed: true})
+		}
+	}
+	return changes
+}
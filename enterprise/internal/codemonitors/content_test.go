@@ -0,0 +1,145 @@
+package codemonitors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestIsContentSearch(t *testing.T) {
+	require.True(t, isContentSearch(jobutil.NewTimeoutJob(0, &jobutil.RepoSearchJob{})))
+	require.False(t, isContentSearch(jobutil.NewLimitJob(1000, &commit.SearchJob{})))
+}
+
+func TestGroupContentMatches(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "github.com/test/test"}
+	fileMatch := func(commitID api.CommitID, path string, chunks ...result.ChunkMatch) *result.FileMatch {
+		return &result.FileMatch{
+			File:         result.File{Repo: repo, CommitID: commitID, Path: path},
+			ChunkMatches: chunks,
+		}
+	}
+	chunk := result.ChunkMatch{
+		Content:      "a := 1\nb := 2",
+		ContentStart: result.Location{Line: 9},
+		Ranges: result.Ranges{{
+			Start: result.Location{Line: 9, Column: 0},
+			End:   result.Location{Line: 10, Column: 1},
+		}},
+	}
+
+	t.Run("groups by repo", func(t *testing.T) {
+		repos, err := groupContentMatches(result.Matches{
+			fileMatch("c1", "b.go", chunk),
+			fileMatch("c1", "a.go"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[api.RepoID]*repoContent{
+			1: {
+				name: "github.com/test/test",
+				snapshot: &edb.ContentSnapshot{
+					CommitID: "c1",
+					Matches: []edb.ContentMatch{
+						{Path: "a.go"},
+						{Path: "b.go", LineNumber: 10, Preview: "a := 1"},
+						{Path: "b.go", LineNumber: 11, Preview: "b := 2"},
+					},
+				},
+			},
+		}, repos)
+	})
+
+	t.Run("errors on multiple revisions", func(t *testing.T) {
+		_, err := groupContentMatches(result.Matches{
+			fileMatch("c1", "a.go"),
+			fileMatch("c2", "a.go"),
+		})
+		require.ErrorIs(t, err, ErrInvalidContentMonitorQuery)
+	})
+
+	t.Run("errors on other results", func(t *testing.T) {
+		_, err := groupContentMatches(result.Matches{&result.RepoMatch{Name: repo.Name, ID: repo.ID}})
+		require.Error(t, err)
+	})
+}
+
+func TestEmptiedRepos(t *testing.T) {
+	repos := map[api.RepoID]*repoContent{1: {name: "matched"}}
+
+	// The repo pager reports the repositories it searched completely in its
+	// position. Repo 3 timed out, so it is never reported.
+	searched := make(map[api.RepoID]struct{})
+	agg := streaming.NewAggregatingStream()
+	stream := recordSearched(agg, searched)
+	position := func(ids ...api.RepoID) streaming.SearchEvent {
+		return streaming.SearchEvent{Stats: streaming.Stats{Cursor: search.Cursor{
+			Pagers: map[string]*search.PagerPosition{"pager": {Searched: ids}},
+		}}}
+	}
+	stream.Send(position(1))
+	stream.Send(position())
+	stream.Send(position(2))
+
+	// Repo 1 still has matches, repo 2 was searched without matches, repo 3
+	// timed out and repo 4 wasn't reported at all.
+	require.Equal(t, []api.RepoID{2}, emptiedRepos([]api.RepoID{1, 2, 3, 4}, repos, searched))
+}
+
+func TestDiffContentMatches(t *testing.T) {
+	match := func(path string, line int, preview string) edb.ContentMatch {
+		return edb.ContentMatch{Path: path, LineNumber: line, Preview: preview}
+	}
+	prev := &edb.ContentSnapshot{
+		CommitID: "c1",
+		Matches: []edb.ContentMatch{
+			match("a.go", 3, "InsecureSkipVerify: true"),
+			match("a.go", 8, "InsecureSkipVerify: true"),
+			match("b.go", 1, "InsecureSkipVerify: true"),
+		},
+	}
+
+	t.Run("new repo", func(t *testing.T) {
+		changes := diffContentMatches("repo", nil, prev)
+		require.Len(t, changes, 3)
+		for _, change := range changes {
+			require.False(t, change.Removed)
+			require.Equal(t, api.CommitID("c1"), change.Commit)
+		}
+	})
+
+	t.Run("moved lines are unchanged", func(t *testing.T) {
+		next := &edb.ContentSnapshot{
+			CommitID: "c2",
+			Matches: []edb.ContentMatch{
+				match("a.go", 5, "InsecureSkipVerify: true"),
+				match("a.go", 10, "InsecureSkipVerify: true"),
+				match("b.go", 1, "InsecureSkipVerify: true"),
+			},
+		}
+		require.Empty(t, diffContentMatches("repo", prev, next))
+	})
+
+	t.Run("added and removed", func(t *testing.T) {
+		next := &edb.ContentSnapshot{
+			CommitID: "c2",
+			Matches: []edb.ContentMatch{
+				match("a.go", 3, "InsecureSkipVerify: true"),
+				match("c.go", 7, "InsecureSkipVerify: true"),
+			},
+		}
+		require.Equal(t, []*edb.ContentChange{
+			{ContentMatch: match("c.go", 7, "InsecureSkipVerify: true"), Repo: "repo", Commit: "c2"},
+			{ContentMatch: match("a.go", 8, "InsecureSkipVerify: true"), Repo: "repo", Commit: "c1", Removed: true},
+			{ContentMatch: match("b.go", 1, "InsecureSkipVerify: true"), Repo: "repo", Commit: "c1", Removed: true},
+		}, diffContentMatches("repo", prev, next))
+	})
+}
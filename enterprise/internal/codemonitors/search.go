@@ -107,7 +107,16 @@ func Settings(ctx context.Context) (_ *schema.Settings, err error) {
 	return &unmarshaledSettings, nil
 }
 
-func Search(ctx context.Context, logger log.Logger, db database.DB, query string, monitorID int64, settings *schema.Settings) (_ []*result.CommitMatch, err error) {
+// SearchResults are the new results of a code monitor search.
+type SearchResults struct {
+	// Commits are the new commits matched by monitors on commits and diffs.
+	Commits []*result.CommitMatch
+	// ContentChanges are the matches of monitors on file contents that
+	// appeared or disappeared since the previous search.
+	ContentChanges []*edb.ContentChange
+}
+
+func Search(ctx context.Context, logger log.Logger, db database.DB, query string, monitorID int64, settings *schema.Settings) (_ *SearchResults, err error) {
 	searchClient := client.NewSearchClient(logger, db, search.Indexed(), search.SearcherURLs())
 	inputs, err := searchClient.Plan(ctx, "V3", nil, query, search.Streaming, settings, envvar.SourcegraphDotComMode())
 	if err != nil {
@@ -121,6 +130,18 @@ func Search(ctx context.Context, logger log.Logger, db database.DB, query string
 		return nil, errcode.MakeNonRetryable(err)
 	}
 
+	if isContentSearch(planJob) {
+		planJob, err = planContentSearch(ctx, searchClient, query, settings)
+		if err != nil {
+			return nil, err
+		}
+		changes, err := searchContent(ctx, db, clients, planJob, monitorID)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchResults{ContentChanges: changes}, nil
+	}
+
 	if featureflag.FromContext(ctx).GetBoolOr("cc-repo-aware-monitors", true) {
 		hook := func(ctx context.Context, db database.DB, gs commit.GitserverClient, args *gitprotocol.SearchRequest, repoID api.RepoID, doSearch commit.DoSearchFunc) error {
 			return hookWithID(ctx, db, gs, monitorID, repoID, args, doSearch)
@@ -172,12 +193,12 @@ func Search(ctx context.Context, logger log.Logger, db database.DB, query string
 		results[i] = cm
 	}
 
-	return results, nil
+	return &SearchResults{Commits: results}, nil
 }
 
 // Snapshot runs a dummy search that just saves the current state of the searched repos in the database.
 // On subsequent runs, this allows us to treat all new repos or sets of args as something new that should
-// be searched from the beginning.
+// be searched from the beginning. For monitors on file contents, it saves the current matches instead.
 func Snapshot(ctx context.Context, logger log.Logger, db database.DB, query string, monitorID int64, settings *schema.Settings) error {
 	searchClient := client.NewSearchClient(logger, db, search.Indexed(), search.SearcherURLs())
 	inputs, err := searchClient.Plan(ctx, "V3", nil, query, search.Streaming, settings, envvar.SourcegraphDotComMode())
@@ -191,6 +212,10 @@ func Snapshot(ctx context.Context, logger log.Logger, db database.DB, query stri
 		return err
 	}
 
+	if isContentSearch(planJob) {
+		return snapshotContent(ctx, db, clients, planJob, monitorID)
+	}
+
 	hook := func(ctx context.Context, db database.DB, gs commit.GitserverClient, args *gitprotocol.SearchRequest, repoID api.RepoID, _ commit.DoSearchFunc) error {
 		return snapshotHook(ctx, db, gs, args, monitorID, repoID)
 	}
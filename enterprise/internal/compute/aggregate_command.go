@@ -0,0 +1,209 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// maxTableRows is the number of rows after which count and group
+	// tables are truncated.
+	maxTableRows = 1000
+
+	// maxGroupRepositories is the number of repositories listed for each
+	// row of a group table.
+	maxGroupRepositories = 10
+
+	// maxAggregateKeys is the number of distinct keys an Aggregator keeps
+	// counts for. Keys first seen after that are dropped.
+	maxAggregateKeys = 10 * maxTableRows
+)
+
+// Aggregate counts the values of a key over all occurrences of a search
+// pattern. The key is a template of metavariables like $repo or $author,
+// and capture groups of the search pattern like $1.
+type Aggregate struct {
+	SearchPattern *Regexp
+	By            string
+	Limit         int
+	Kind          string
+}
+
+func (c *Aggregate) ToSearchPattern() string {
+	return c.SearchPattern.String()
+}
+
+func (c *Aggregate) String() string {
+	name := c.Kind
+	if c.Kind == "top" {
+		name = fmt.Sprintf("top %d", c.Limit)
+	}
+	return fmt.Sprintf("Aggregate %s: (%s) by: (%s)", name, c.SearchPattern.String(), c.By)
+}
+
+// parseAggregateArgs parses the right hand side of an aggregation command,
+// like `by: repo` for count, `by: $1` for group and `10, by: author` for
+// top, and returns the limit and the key template.
+func parseAggregateArgs(name, args string) (int, string, error) {
+	var limit int
+	if name == "top" {
+		n, rest, _ := strings.Cut(args, ",")
+		var err error
+		limit, err = strconv.Atoi(strings.TrimSpace(n))
+		if err != nil || limit <= 0 {
+			return 0, "", errors.Errorf("top command expects a positive number of rows, got %q", n)
+		}
+		args = rest
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		if name == "count" {
+			// Without a key, count is the total number of matches.
+			return limit, "", nil
+		}
+		// Group and top default to the matched value.
+		return limit, "$0", nil
+	}
+	if !strings.HasPrefix(args, "by:") {
+		return 0, "", errors.Errorf("%s command expects `by: <key>`, got %q", name, args)
+	}
+
+	by := strings.TrimSpace(strings.TrimPrefix(args, "by:"))
+	if strings.Contains(by, "$") {
+		return limit, by, nil
+	}
+	if _, ok := builtinVariables[by]; !ok {
+		return 0, "", errors.Errorf("%s command cannot aggregate by unknown field %q", name, by)
+	}
+	return limit, "$" + by, nil
+}
+
+// Run returns the table of the keys of the occurrences of the search pattern
+// in a single match.
+func (c *Aggregate) Run(_ context.Context, _ database.DB, r result.Match) (Result, error) {
+	chunks := resultChunks(r, c.Kind, false)
+	if fm, ok := r.(*result.FileMatch); ok && len(fm.ChunkMatches) == 0 {
+		// The file matched on its path only.
+		chunks = resultChunks(r, c.Kind, true)
+	}
+
+	counts := make(map[string]int)
+	for _, content := range chunks {
+		keyPattern, err := substituteMetaVariables(c.By, NewMetaEnvironment(r, content))
+		if err != nil {
+			return nil, err
+		}
+
+		submatches := c.SearchPattern.Value.FindAllStringSubmatchIndex(content, -1)
+		if len(submatches) == 0 {
+			// The pattern matched on something other than the content,
+			// like the metadata of a commit. Count the match once.
+			counts[string(c.SearchPattern.Value.ExpandString(nil, keyPattern, content, nil))]++
+			continue
+		}
+		for _, submatch := range submatches {
+			counts[string(c.SearchPattern.Value.ExpandString(nil, keyPattern, content, submatch))]++
+		}
+	}
+
+	table := &Table{Kind: c.Kind, By: c.By, Rows: make([]TableRow, 0, len(counts))}
+	for key, count := range counts {
+		row := TableRow{Key: key, Count: count}
+		if repo := r.RepoName().Name; c.Kind == "group" && repo != "" {
+			row.Repositories = []string{string(repo)}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	sort.Slice(table.Rows, func(i, j int) bool { return table.Rows[i].Key < table.Rows[j].Key })
+	return table, nil
+}
+
+// Aggregator merges the tables of single matches into a table over all
+// matches. It is not safe for concurrent use.
+type Aggregator struct {
+	cmd  *Aggregate
+	rows map[string]*aggregateRow
+
+	// dropped is whether keys were dropped because there were more than
+	// maxAggregateKeys.
+	dropped bool
+}
+
+type aggregateRow struct {
+	count        int
+	repositories map[string]struct{}
+}
+
+func NewAggregator(cmd *Aggregate) *Aggregator {
+	return &Aggregator{cmd: cmd, rows: make(map[string]*aggregateRow)}
+}
+
+// Add merges the rows of t into the aggregate. Rows of new keys are dropped
+// once the aggregate has maxAggregateKeys keys.
+func (a *Aggregator) Add(t *Table) {
+	for _, r := range t.Rows {
+		row, ok := a.rows[r.Key]
+		if !ok {
+			if len(a.rows) >= maxAggregateKeys {
+				a.dropped = true
+				continue
+			}
+			row = &aggregateRow{repositories: make(map[string]struct{})}
+			a.rows[r.Key] = row
+		}
+		row.count += r.Count
+		for _, repo := range r.Repositories {
+			row.repositories[repo] = struct{}{}
+		}
+	}
+}
+
+// Table returns the aggregate table of all tables added so far. Count and
+// top tables are ordered by descending count, group tables by key.
+func (a *Aggregator) Table() *Table {
+	rows := make([]TableRow, 0, len(a.rows))
+	for key, r := range a.rows {
+		row := TableRow{Key: key, Count: r.count}
+		if a.cmd.Kind == "group" {
+			row.RepositoryCount = len(r.repositories)
+			for repo := range r.repositories {
+				row.Repositories = append(row.Repositories, repo)
+			}
+			sort.Strings(row.Repositories)
+			if len(row.Repositories) > maxGroupRepositories {
+				row.Repositories = row.Repositories[:maxGroupRepositories]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if a.cmd.Kind == "group" {
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Key < rows[j].Key })
+	} else {
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].Count != rows[j].Count {
+				return rows[i].Count > rows[j].Count
+			}
+			return rows[i].Key < rows[j].Key
+		})
+	}
+
+	limit := maxTableRows
+	if a.cmd.Kind == "top" {
+		limit = a.cmd.Limit
+	}
+	table := &Table{Kind: a.cmd.Kind, By: a.cmd.By, Rows: rows, Truncated: a.dropped}
+	if len(rows) > limit {
+		table.Rows = rows[:limit]
+		table.Truncated = table.Truncated || a.cmd.Kind != "top"
+	}
+	return table
+}
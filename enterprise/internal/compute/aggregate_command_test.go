@@ -0,0 +1,113 @@
+package compute
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/hexops/autogold"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAggregate(t *testing.T) {
+	test := func(q string, matches ...result.Match) string {
+		computeQuery, err := Parse(q)
+		if err != nil {
+			return err.Error()
+		}
+		cmd, ok := computeQuery.Command.(*Aggregate)
+		if !ok {
+			return "Error, not an aggregation command"
+		}
+
+		aggregator := NewAggregator(cmd)
+		for _, m := range matches {
+			res, err := cmd.Run(context.Background(), database.NewMockDB(), m)
+			if err != nil {
+				return err.Error()
+			}
+			aggregator.Add(res.(*Table))
+		}
+		result, _ := json.Marshal(aggregator.Table())
+		return string(result)
+	}
+
+	inRepo := func(repo string, m result.Match) result.Match {
+		m.(*result.FileMatch).Repo = types.MinimalRepo{Name: "github.com/" + api.RepoName(repo)}
+		return m
+	}
+
+	autogold.Want(
+		"count all matches",
+		`{"kind":"count","by":"","rows":[{"key":"","count":3}]}`).
+		Equal(t, test(`content:count(\d)`, fileMatch("1", "2"), fileMatch("3")))
+
+	autogold.Want(
+		"count by repo",
+		`{"kind":"count","by":"$repo","rows":[{"key":"github.com/b","count":2},{"key":"github.com/a","count":1}]}`).
+		Equal(t, test(`content:count(TODO -> by: repo)`,
+			inRepo("a", fileMatch("TODO")),
+			inRepo("b", fileMatch("TODO")),
+			inRepo("b", fileMatch("TODO"))))
+
+	autogold.Want(
+		"group by capture group",
+		`{"kind":"group","by":"$1","rows":[{"key":"errors","count":2,"repositories":["github.com/a","github.com/b"],"repositoryCount":2},{"key":"fmt","count":1,"repositories":["github.com/a"],"repositoryCount":1}]}`).
+		Equal(t, test(`content:group(import "(\w+)" -> by: $1)`,
+			inRepo("a", fileMatch(`import "fmt"`, `import "errors"`)),
+			inRepo("b", fileMatch(`import "errors"`))))
+
+	autogold.Want(
+		"top by author",
+		`{"kind":"top","by":"$author","rows":[{"key":"bob","count":2}]}`).
+		Equal(t, test(`content:top(fix -> 1, by: author)`, commitMatch("fix"), commitMatch("fix typo")))
+
+	autogold.Want(
+		"top defaults to the matched value",
+		`{"kind":"top","by":"$0","rows":[{"key":"b","count":2},{"key":"a","count":1}]}`).
+		Equal(t, test(`content:top(\w -> 2)`, fileMatch("a", "b", "b")))
+
+	autogold.Want(
+		"group code owners",
+		`{"kind":"group","by":"$content","rows":[{"key":"@alice","count":2},{"key":"@bob","count":1}]}`).
+		Equal(t, test(`content:group(\w+ -> by: $content) select:file.owners`,
+			&result.OwnerMatch{Handle: "@alice"},
+			&result.OwnerMatch{Handle: "@alice"},
+			&result.OwnerMatch{Handle: "@bob"}))
+
+	pathMatch := &result.FileMatch{File: result.File{Repo: types.MinimalRepo{Name: "github.com/a"}, Path: "cmd/main.go"}}
+	autogold.Want(
+		"count path matches",
+		`{"kind":"count","by":"$0","rows":[{"key":"main.go","count":1}]}`).
+		Equal(t, test(`content:count(\w+\.go -> by: $0)`, pathMatch))
+
+	autogold.Want(
+		"unknown field",
+		"count command cannot aggregate by unknown field \"owner\"").
+		Equal(t, test(`content:count(TODO -> by: owner)`))
+
+	autogold.Want(
+		"top without a limit",
+		"top command expects a positive number of rows, got \"by: author\"").
+		Equal(t, test(`content:top(TODO -> by: author)`))
+}
+
+func TestAggregatorDropsKeys(t *testing.T) {
+	aggregator := NewAggregator(&Aggregate{Kind: "count", By: "$0"})
+	for i := 0; i < maxAggregateKeys+1; i++ {
+		aggregator.Add(&Table{Rows: []TableRow{{Key: strconv.Itoa(i), Count: 1}}})
+	}
+	// Known keys are still counted.
+	aggregator.Add(&Table{Rows: []TableRow{{Key: "0", Count: 1}}})
+
+	require.Len(t, aggregator.rows, maxAggregateKeys)
+	table := aggregator.Table()
+	require.True(t, table.Truncated)
+	require.Equal(t, TableRow{Key: "0", Count: 2}, table.Rows[0])
+}
@@ -0,0 +1,12 @@
+package compute
+
+// FileDiff is the result of a replace.diff command. Its value is the unified
+// diff of the replacements in a file.
+type FileDiff struct {
+	TextExtra
+	Commit string `json:"commit"`
+	// Rev is the revision the search was asked for, or empty for the default
+	// branch.
+	Rev  string `json:"rev,omitempty"`
+	Path string `json:"path"`
+}
@@ -100,6 +100,10 @@ func resultChunks(r result.Match, kind string, onlyPath bool) []string {
 			content = string(m.Commit.Message)
 		}
 		return []string{content}
+	case *result.OwnerMatch:
+		return []string{m.Handle}
+	case *result.CaptureGroupMatch:
+		return []string{m.Value}
 	default:
 		panic("unsupported result kind in compute output command")
 	}
@@ -121,6 +121,16 @@ func TestRun(t *testing.T) {
 		"bob: (1)\nbob: (2)\nbob: (3)\n").
 		Equal(t, test(`content:output((\d) -> $author: ($1))`, commitMatch("a 1 b 2 c 3")))
 
+	autogold.Want(
+		"code owner of select:file.owners",
+		"owner @alice\n").
+		Equal(t, test(`content:output(\w+ -> owner $content\n) select:file.owners`, &result.OwnerMatch{Handle: "@alice", Type: "username"}))
+
+	autogold.Want(
+		"capture group value of select:content.group",
+		"import fmt\n").
+		Equal(t, test(`content:output(\w+ -> import $content\n) select:content.group(1)`, &result.CaptureGroupMatch{Value: "fmt"}))
+
 	autogold.Want(
 		"works with boundary assertions",
 		"test\nstring\n").
@@ -97,13 +97,18 @@ func toRegexpPattern(value string) (MatchPattern, error) {
 
 var ComputePredicateRegistry = query.PredicateRegistry{
 	query.FieldContent: {
-		"replace":            func() query.Predicate { return query.EmptyPredicate{} },
-		"replace.regexp":     func() query.Predicate { return query.EmptyPredicate{} },
-		"replace.structural": func() query.Predicate { return query.EmptyPredicate{} },
-		"output":             func() query.Predicate { return query.EmptyPredicate{} },
-		"output.regexp":      func() query.Predicate { return query.EmptyPredicate{} },
-		"output.structural":  func() query.Predicate { return query.EmptyPredicate{} },
-		"output.extra":       func() query.Predicate { return query.EmptyPredicate{} },
+		"replace":                 func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.regexp":          func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.structural":      func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.diff":            func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.diff.structural": func() query.Predicate { return query.EmptyPredicate{} },
+		"output":                  func() query.Predicate { return query.EmptyPredicate{} },
+		"output.regexp":           func() query.Predicate { return query.EmptyPredicate{} },
+		"output.structural":       func() query.Predicate { return query.EmptyPredicate{} },
+		"output.extra":            func() query.Predicate { return query.EmptyPredicate{} },
+		"count":                   func() query.Predicate { return query.EmptyPredicate{} },
+		"group":                   func() query.Predicate { return query.EmptyPredicate{} },
+		"top":                     func() query.Predicate { return query.EmptyPredicate{} },
 	},
 }
 
@@ -147,13 +152,13 @@ func parseReplace(q *query.Basic) (Command, bool, error) {
 
 	var matchPattern MatchPattern
 	switch name {
-	case "replace", "replace.regexp":
+	case "replace", "replace.regexp", "replace.diff":
 		var err error
 		matchPattern, err = toRegexpPattern(left)
 		if err != nil {
 			return nil, false, errors.Wrap(err, "replace command")
 		}
-	case "replace.structural":
+	case "replace.structural", "replace.diff.structural":
 		// structural search doesn't do any match pattern validation
 		matchPattern = &Comby{Value: left}
 	default:
@@ -161,7 +166,7 @@ func parseReplace(q *query.Basic) (Command, bool, error) {
 		return nil, false, nil
 	}
 
-	return &Replace{SearchPattern: matchPattern, ReplacePattern: right}, true, nil
+	return &Replace{SearchPattern: matchPattern, ReplacePattern: right, Kind: name}, true, nil
 }
 
 func parseOutput(q *query.Basic) (Command, bool, error) {
@@ -217,6 +222,49 @@ func parseOutput(q *query.Basic) (Command, bool, error) {
 	}, true, nil
 }
 
+func parseAggregate(q *query.Basic) (Command, bool, error) {
+	pattern, err := extractPattern(q)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name, args, ok := parseContentPredicate(pattern)
+	if !ok {
+		return nil, false, nil
+	}
+	switch name {
+	case "count", "group", "top":
+	default:
+		// unrecognized name
+		return nil, false, nil
+	}
+
+	// The right hand side of `->` is optional for count and group, so
+	// that `count(pattern)` counts all matches.
+	left, right := args, ""
+	if arrowSyntax.MatchString(args) {
+		left, right, err = parseArrowSyntax(args)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	matchPattern, err := toRegexpPattern(left)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "%s command", name)
+	}
+	limit, by, err := parseAggregateArgs(name, right)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &Aggregate{
+		SearchPattern: matchPattern.(*Regexp),
+		By:            by,
+		Limit:         limit,
+		Kind:          name,
+	}, true, nil
+}
+
 func parseMatchOnly(q *query.Basic) (Command, bool, error) {
 	pattern, err := extractPattern(q)
 	if err != nil {
@@ -258,6 +306,8 @@ func first(parsers ...commandParser) commandParser {
 }
 
 var parseCommand = first(
+	// Aggregation commands go first because their arrow is optional.
+	parseAggregate,
 	parseReplace,
 	parseOutput,
 	parseMatchOnly,
@@ -50,6 +50,18 @@ func TestParse(t *testing.T) {
 	autogold.Want("replace no left hand side",
 		"Command: `Replace in place: () -> (b)`").
 		Equal(t, test("content:replace(->b)"))
+
+	autogold.Want("replace diff",
+		"Command: `Replace as diff: (colarado) -> (colorado)`, Parameters: `lang:go`").
+		Equal(t, test("content:replace.diff(colarado -> colorado) lang:go"))
+
+	autogold.Want("count",
+		"Command: `Aggregate count: (TODO) by: ($repo)`").
+		Equal(t, test("content:count(TODO -> by: repo)"))
+
+	autogold.Want("top",
+		"Command: `Aggregate top 10: (fix) by: ($author)`, Parameters: `type:commit`").
+		Equal(t, test("content:top(fix -> 10, by: author) type:commit"))
 }
 
 func TestToSearchQuery(t *testing.T) {
@@ -3,6 +3,7 @@ package compute
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
@@ -15,6 +16,13 @@ import (
 type Replace struct {
 	SearchPattern  MatchPattern
 	ReplacePattern string
+	Kind           string
+}
+
+// IsDiff returns whether the command outputs the diffs of the replacements
+// rather than the replaced file contents.
+func (c *Replace) IsDiff() bool {
+	return strings.HasPrefix(c.Kind, "replace.diff")
 }
 
 func (c *Replace) ToSearchPattern() string {
@@ -22,6 +30,9 @@ func (c *Replace) ToSearchPattern() string {
 }
 
 func (c *Replace) String() string {
+	if c.IsDiff() {
+		return fmt.Sprintf("Replace as diff: (%s) -> (%s)", c.SearchPattern.String(), c.ReplacePattern)
+	}
 	return fmt.Sprintf("Replace in place: (%s) -> (%s)", c.SearchPattern.String(), c.ReplacePattern)
 }
 
@@ -57,7 +68,14 @@ func (c *Replace) Run(ctx context.Context, db database.DB, r result.Match) (Resu
 		if err != nil {
 			return nil, err
 		}
-		return replace(ctx, content, c.SearchPattern, c.ReplacePattern)
+		replaced, err := replace(ctx, content, c.SearchPattern, c.ReplacePattern)
+		if err != nil {
+			return nil, err
+		}
+		if c.IsDiff() {
+			return toFileDiff(m, string(content), replaced.Value), nil
+		}
+		return replaced, nil
 	}
 	return nil, nil
 }
@@ -0,0 +1,157 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+	"github.com/sourcegraph/sourcegraph/lib/batches/execution"
+	"github.com/sourcegraph/sourcegraph/lib/batches/git"
+	"github.com/sourcegraph/sourcegraph/lib/batches/template"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// unifiedDiff returns the diff of changing the file at path from before to
+// after in the format of `git diff`, or the empty string if nothing changed.
+func unifiedDiff(path, before, after string) string {
+	edits := myers.ComputeEdits(span.URIFromPath(path), before, after)
+	if len(edits) == 0 {
+		return ""
+	}
+	unified := gotextdiff.ToUnified("a/"+path, "b/"+path, before, edits)
+	return fmt.Sprintf("diff --git a/%s b/%s\n%v", path, path, unified)
+}
+
+// toFileDiff returns the diff of the replacements in the file of m, or nil if
+// the replacements didn't change the file.
+func toFileDiff(m *result.FileMatch, before, after string) Result {
+	diff := unifiedDiff(m.Path, before, after)
+	if diff == "" {
+		return nil
+	}
+	var rev string
+	if m.InputRev != nil {
+		rev = *m.InputRev
+	}
+	return &FileDiff{
+		TextExtra: TextExtra{
+			Text:         Text{Value: diff, Kind: "replace-diff"},
+			RepositoryID: int32(m.Repo.ID),
+			Repository:   string(m.Repo.Name),
+		},
+		Commit: string(m.CommitID),
+		Rev:    rev,
+		Path:   m.Path,
+	}
+}
+
+// FileDiffs runs a replace.diff command over search results and returns the
+// diffs of the files it changes.
+func FileDiffs(ctx context.Context, db database.DB, cmd *Replace, matches []result.Match) ([]*FileDiff, error) {
+	if !cmd.IsDiff() {
+		return nil, errors.Errorf("expected a replace.diff command, got %s", cmd)
+	}
+
+	var diffs []*FileDiff
+	for _, m := range matches {
+		r, err := cmd.Run(ctx, db, m)
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := r.(*FileDiff); ok {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs, nil
+}
+
+// ChangesetSpecs packages file diffs as changeset specs, one for each
+// repository, that propose the changes against the default branch of the
+// repository, so the diffs must have been computed on the default branch. The
+// changeset template is rendered for each repository like the changeset
+// template of a batch spec.
+func ChangesetSpecs(ctx context.Context, gitserverClient gitserver.Client, diffs []*FileDiff, attributes *template.BatchChangeAttributes, tmpl *batcheslib.ChangesetTemplate) ([]*batcheslib.ChangesetSpec, error) {
+	byRepo := make(map[int32][]*FileDiff)
+	for _, d := range diffs {
+		byRepo[d.RepositoryID] = append(byRepo[d.RepositoryID], d)
+	}
+	repoIDs := make([]int32, 0, len(byRepo))
+	for id := range byRepo {
+		repoIDs = append(repoIDs, id)
+	}
+	sort.Slice(repoIDs, func(i, j int) bool { return repoIDs[i] < repoIDs[j] })
+
+	var specs []*batcheslib.ChangesetSpec
+	for _, id := range repoIDs {
+		repoDiffs := byRepo[id]
+		sort.Slice(repoDiffs, func(i, j int) bool { return repoDiffs[i].Path < repoDiffs[j].Path })
+
+		first := repoDiffs[0]
+		var diff strings.Builder
+		var changes git.Changes
+		for _, d := range repoDiffs {
+			// A changeset is based on a single revision, so we can't combine
+			// the diffs of several revisions of a repository.
+			if d.Commit != first.Commit || d.Rev != first.Rev {
+				return nil, errors.Errorf("the results in %s span more than one revision", first.Repository)
+			}
+			diff.WriteString(d.Value)
+			changes.Modified = append(changes.Modified, d.Path)
+		}
+
+		baseRef, _, err := gitserverClient.GetDefaultBranch(ctx, api.RepoName(first.Repository), false)
+		if err != nil {
+			return nil, err
+		}
+		if baseRef == "" {
+			return nil, errors.Errorf("no default branch found for %s", first.Repository)
+		}
+		// The changeset is opened against the default branch, so the diffs
+		// must have been computed on it.
+		if !isDefaultBranch(first.Rev, baseRef) {
+			return nil, errors.Errorf("the results in %s are on revision %q, but changesets can only be created against the default branch %s", first.Repository, first.Rev, strings.TrimPrefix(baseRef, "refs/heads/"))
+		}
+
+		repoSpecs, err := batcheslib.BuildChangesetSpecs(&batcheslib.ChangesetSpecInput{
+			Repository: batcheslib.Repository{
+				ID:          string(relay.MarshalID("Repository", api.RepoID(id))),
+				Name:        first.Repository,
+				BaseRef:     baseRef,
+				BaseRev:     first.Commit,
+				FileMatches: changes.Modified,
+			},
+			BatchChangeAttributes: attributes,
+			Template:              tmpl,
+			Result: execution.AfterStepResult{
+				ChangedFiles: changes,
+				Diff:         diff.String(),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, repoSpecs...)
+	}
+	return specs, nil
+}
+
+// isDefaultBranch reports whether the revision rev of a search refers to the
+// default branch ref.
+func isDefaultBranch(rev, ref string) bool {
+	switch rev {
+	case "", "HEAD", ref, strings.TrimPrefix(ref, "refs/heads/"):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package compute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hexops/autogold"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+	"github.com/sourcegraph/sourcegraph/lib/batches/template"
+)
+
+func TestReplaceDiff(t *testing.T) {
+	gitserver.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		return []byte("package main\n\nfunc main() {\n\tprintln(\"colarado\")\n}\n"), nil
+	}
+	defer gitserver.ResetMocks()
+
+	test := func(q string) Result {
+		computeQuery, err := Parse(q)
+		require.NoError(t, err)
+		res, err := computeQuery.Command.Run(context.Background(), database.NewMockDB(), &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"},
+				CommitID: "deadbeef",
+				Path:     "cmd/main.go",
+			},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := test(`content:replace.diff(colarado -> colorado)`)
+	require.IsType(t, &FileDiff{}, res)
+	d := res.(*FileDiff)
+	require.Equal(t, "replace-diff", d.Kind)
+	require.Equal(t, "cmd/main.go", d.Path)
+	require.Equal(t, "deadbeef", d.Commit)
+	autogold.Want("replace diff", `diff --git a/cmd/main.go b/cmd/main.go
+--- a/cmd/main.go
++++ b/cmd/main.go
+@@ -1,5 +1,5 @@
+ package main
+ 
+ func main() {
+-	println("colarado")
++	println("colorado")
+ }
+`).Equal(t, d.Value)
+
+	// Files that the replacement doesn't change have no diff.
+	require.Nil(t, test(`content:replace.diff(colarado -> colarado)`))
+}
+
+func TestChangesetSpecs(t *testing.T) {
+	gs := gitserver.NewMockClient()
+	gs.GetDefaultBranchFunc.SetDefaultReturn("refs/heads/main", "", nil)
+
+	fileDiff := func(repoID int32, repo, path string) *FileDiff {
+		return &FileDiff{
+			TextExtra: TextExtra{
+				Text:         Text{Value: "diff of " + path + "\n", Kind: "replace-diff"},
+				RepositoryID: repoID,
+				Repository:   repo,
+			},
+			Commit: "deadbeef",
+			Path:   path,
+		}
+	}
+
+	specs, err := ChangesetSpecs(context.Background(), gs, []*FileDiff{
+		fileDiff(2, "github.com/sourcegraph/other", "main.go"),
+		fileDiff(1, "github.com/sourcegraph/sourcegraph", "b.go"),
+		fileDiff(1, "github.com/sourcegraph/sourcegraph", "a.go"),
+	}, &template.BatchChangeAttributes{Name: "colorado"}, &batcheslib.ChangesetTemplate{
+		Title:  "Fix spelling",
+		Body:   "Replaces colarado with colorado.",
+		Branch: "fix-spelling",
+		Commit: batcheslib.ExpandedGitCommitDescription{Message: "Fix spelling in ${{ repository.name }}"},
+	})
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+
+	require.Equal(t, &batcheslib.ChangesetSpec{
+		BaseRepository: "UmVwb3NpdG9yeTox",
+		HeadRepository: "UmVwb3NpdG9yeTox",
+		BaseRef:        "refs/heads/main",
+		BaseRev:        "deadbeef",
+		HeadRef:        "refs/heads/fix-spelling",
+		Title:          "Fix spelling",
+		Body:           "Replaces colarado with colorado.",
+		Commits: []batcheslib.GitCommitDescription{{
+			Message:     "Fix spelling in github.com/sourcegraph/sourcegraph",
+			Diff:        "diff of a.go\ndiff of b.go\n",
+			AuthorName:  "Sourcegraph",
+			AuthorEmail: "batch-changes@sourcegraph.com",
+		}},
+	}, specs[0])
+	require.Equal(t, "UmVwb3NpdG9yeToy", specs[1].BaseRepository)
+
+	// The diffs of a repository must be computed on the same revision.
+	other := fileDiff(1, "github.com/sourcegraph/sourcegraph", "c.go")
+	other.Commit = "cafebabe"
+	_, err = ChangesetSpecs(context.Background(), gs, []*FileDiff{fileDiff(1, "github.com/sourcegraph/sourcegraph", "a.go"), other}, &template.BatchChangeAttributes{}, &batcheslib.ChangesetTemplate{})
+	require.Error(t, err)
+
+	// The diffs must be computed on the default branch the changeset is
+	// opened against.
+	onMain := fileDiff(1, "github.com/sourcegraph/sourcegraph", "a.go")
+	onMain.Rev = "main"
+	_, err = ChangesetSpecs(context.Background(), gs, []*FileDiff{onMain}, &template.BatchChangeAttributes{}, &batcheslib.ChangesetTemplate{})
+	require.NoError(t, err)
+
+	onBranch := fileDiff(1, "github.com/sourcegraph/sourcegraph", "a.go")
+	onBranch.Rev = "feature"
+	_, err = ChangesetSpecs(context.Background(), gs, []*FileDiff{onBranch}, &template.BatchChangeAttributes{}, &batcheslib.ChangesetTemplate{})
+	require.Error(t, err)
+}
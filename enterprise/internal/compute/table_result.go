@@ -0,0 +1,19 @@
+package compute
+
+// Table is the result of an aggregation command. Tables computed for single
+// matches are merged by an Aggregator into a table over all matches.
+type Table struct {
+	Kind      string     `json:"kind"`
+	By        string     `json:"by"`
+	Rows      []TableRow `json:"rows"`
+	Truncated bool       `json:"truncated,omitempty"`
+}
+
+type TableRow struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+	// Repositories is only set for tables of the group command, and lists
+	// at most maxGroupRepositories of the repositories a key occurs in.
+	Repositories    []string `json:"repositories,omitempty"`
+	RepositoryCount int      `json:"repositoryCount,omitempty"`
+}
@@ -300,6 +300,12 @@ func NewMetaEnvironment(r result.Match, content string) *MetaEnvironment {
 			Lang:    lang,
 			Content: content,
 		}
+	case *result.OwnerMatch, *result.CaptureGroupMatch:
+		// Owners and capture group values span repositories, so only the
+		// value itself is known.
+		return &MetaEnvironment{
+			Content: content,
+		}
 	}
 	return &MetaEnvironment{}
 }
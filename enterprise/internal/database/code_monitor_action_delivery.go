@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DeliveryPolicy is when the notifications of a code monitor action are sent.
+type DeliveryPolicy string
+
+const (
+	// DeliveryImmediate sends a notification after each run of the monitor
+	// with results.
+	DeliveryImmediate DeliveryPolicy = "IMMEDIATE"
+	// DeliveryHourlyDigest sends the results of all runs of the past hour at
+	// the end of each hour.
+	DeliveryHourlyDigest DeliveryPolicy = "HOURLY_DIGEST"
+	// DeliveryDailyDigest sends the results of all runs of the past day at
+	// the end of each day.
+	DeliveryDailyDigest DeliveryPolicy = "DAILY_DIGEST"
+)
+
+// Valid returns whether p is a known delivery policy.
+func (p DeliveryPolicy) Valid() bool {
+	switch p {
+	case DeliveryImmediate, DeliveryHourlyDigest, DeliveryDailyDigest:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDigest returns whether the results of several runs are sent together.
+func (p DeliveryPolicy) IsDigest() bool {
+	return p == DeliveryHourlyDigest || p == DeliveryDailyDigest
+}
+
+// ActionDelivery configures when the notifications of an action are sent.
+type ActionDelivery struct {
+	Policy DeliveryPolicy
+	// MaxNotificationsPerHour, if set, limits the number of notifications
+	// sent per hour. The results of runs beyond the limit are sent together
+	// with the next notification.
+	MaxNotificationsPerHour *int32
+}
+
+// ActionTable is a table of code monitor actions.
+type ActionTable int
+
+const (
+	EmailActions ActionTable = iota
+	WebhookActions
+	SlackWebhookActions
+	IssueTrackerActions
+)
+
+const updateActionDeliveryFmtStr = `
+UPDATE %s
+SET delivery_policy = %s,
+	max_notifications_per_hour = %s
+WHERE id = %s
+`
+
+func (s *codeMonitorStore) UpdateActionDelivery(ctx context.Context, table ActionTable, id int64, delivery ActionDelivery) error {
+	if !delivery.Policy.Valid() {
+		return errors.Errorf("invalid delivery policy %q", delivery.Policy)
+	}
+	if delivery.MaxNotificationsPerHour != nil && *delivery.MaxNotificationsPerHour <= 0 {
+		return errors.New("the maximum number of notifications per hour must be positive")
+	}
+	t := []string{"cm_emails", "cm_webhooks", "cm_slack_webhooks", "cm_issue_trackers"}[table]
+	return s.Exec(ctx, sqlf.Sprintf(updateActionDeliveryFmtStr, quote(t), string(delivery.Policy), delivery.MaxNotificationsPerHour, id))
+}
+
+// actionProcessAfterFmtStr computes when a new job of the action aliased "a"
+// is processed. Digests are sent at the end of the hour or day. Actions that
+// sent their maximum number of notifications in the past hour wait until the
+// oldest of those is an hour old. Notifications are jobs that completed
+// without being batched into another job.
+const actionProcessAfterFmtStr = `
+CASE
+	WHEN a.delivery_policy = 'HOURLY_DIGEST' THEN date_trunc('hour', NOW()) + '1 hour'::interval
+	WHEN a.delivery_policy = 'DAILY_DIGEST' THEN date_trunc('day', NOW()) + '1 day'::interval
+	WHEN a.max_notifications_per_hour IS NOT NULL THEN (
+		SELECT sent.finished_at + '1 hour'::interval
+		FROM (
+			SELECT
+				finished_at,
+				ROW_NUMBER() OVER (ORDER BY finished_at DESC) AS rank
+			FROM cm_action_jobs
+			WHERE %s = a.id
+				AND state = 'completed'
+				AND batched_into IS NULL
+				AND finished_at > NOW() - '1 hour'::interval
+		) sent
+		WHERE sent.rank = a.max_notifications_per_hour
+	)
+	ELSE NULL
+END
+`
+
+func actionProcessAfter(column string) *sqlf.Query {
+	return sqlf.Sprintf(actionProcessAfterFmtStr, quote(column))
+}
+
+const collectPendingActionJobsFmtStr = `
+WITH collected AS (
+	UPDATE cm_action_jobs
+	SET state = 'completed',
+		finished_at = NOW(),
+		batched_into = %s
+	WHERE id <> %s
+		AND state = 'queued'
+		AND (process_after IS NULL OR process_after <= NOW())
+		AND (email = %s OR webhook = %s OR slack_webhook = %s OR issue_tracker = %s)
+	RETURNING trigger_event
+)
+SELECT ctj.search_results, ctj.content_changes
+FROM collected
+INNER JOIN cm_trigger_jobs ctj ON ctj.id = collected.trigger_event
+ORDER BY ctj.id
+`
+
+// CollectPendingActionJobs marks the other queued jobs of the action of job
+// that are due as completed, since their results are sent with job, and
+// returns the results of their runs of the monitor.
+func (s *codeMonitorStore) CollectPendingActionJobs(ctx context.Context, job *ActionJob) (_ []*ActionJobMetadata, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(collectPendingActionJobsFmtStr, job.ID, job.ID, job.Email, job.Webhook, job.SlackWebhook, job.IssueTracker))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var ms []*ActionJobMetadata
+	for rows.Next() {
+		var resultsJSON, changesJSON []byte
+		if err := rows.Scan(&resultsJSON, &changesJSON); err != nil {
+			return nil, err
+		}
+		var m ActionJobMetadata
+		if err := json.Unmarshal(resultsJSON, &m.Results); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changesJSON, &m.ContentChanges); err != nil {
+			return nil, err
+		}
+		ms = append(ms, &m)
+	}
+	return ms, nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+func TestUpdateActionDelivery(t *testing.T) {
+	ctx, db, s := newTestStore(t)
+	_, _, userCTX := newTestUser(ctx, t, db)
+	fixtures := s.insertTestMonitor(userCTX, t)
+
+	email, err := s.GetEmailAction(ctx, fixtures.emails[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, ActionDelivery{Policy: DeliveryImmediate}, email.Delivery)
+
+	maxPerHour := int32(3)
+	want := ActionDelivery{Policy: DeliveryDailyDigest, MaxNotificationsPerHour: &maxPerHour}
+	err = s.UpdateActionDelivery(ctx, EmailActions, fixtures.emails[0].ID, want)
+	require.NoError(t, err)
+
+	email, err = s.GetEmailAction(ctx, fixtures.emails[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, want, email.Delivery)
+
+	err = s.UpdateActionDelivery(ctx, EmailActions, fixtures.emails[0].ID, ActionDelivery{Policy: "WEEKLY"})
+	require.Error(t, err)
+
+	zero := int32(0)
+	err = s.UpdateActionDelivery(ctx, EmailActions, fixtures.emails[0].ID, ActionDelivery{Policy: DeliveryImmediate, MaxNotificationsPerHour: &zero})
+	require.Error(t, err)
+}
+
+func TestEnqueueActionJobsForDigest(t *testing.T) {
+	ctx, db, s := newTestStore(t)
+	_, _, userCTX := newTestUser(ctx, t, db)
+	fixtures := s.insertTestMonitor(userCTX, t)
+
+	digestID, immediateID := fixtures.emails[0].ID, fixtures.emails[1].ID
+	digestEmailID, immediateEmailID := int(digestID), int(immediateID)
+	err := s.UpdateActionDelivery(ctx, EmailActions, digestID, ActionDelivery{Policy: DeliveryHourlyDigest})
+	require.NoError(t, err)
+
+	triggerJobs, err := s.EnqueueQueryTriggerJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, triggerJobs, 1)
+	triggerJobID := triggerJobs[0].ID
+	err = s.UpdateTriggerJobWithResults(ctx, triggerJobID, testQuery, make([]*result.CommitMatch, 2))
+	require.NoError(t, err)
+
+	// The immediate action already has a pending job after the first run, so
+	// only the digest gets a job for the second run.
+	first, err := s.EnqueueActionJobsForMonitor(ctx, fixtures.monitor.ID, triggerJobID)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	second, err := s.EnqueueActionJobsForMonitor(ctx, fixtures.monitor.ID, triggerJobID)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	require.Equal(t, &digestID, second[0].Email)
+
+	for _, job := range append(first, second...) {
+		if *job.Email == digestID {
+			require.NotNil(t, job.ProcessAfter)
+			require.True(t, job.ProcessAfter.After(s.Now()))
+		} else {
+			require.Nil(t, job.ProcessAfter)
+		}
+	}
+
+	// Make the digest jobs due.
+	err = s.Exec(ctx, sqlf.Sprintf("UPDATE cm_action_jobs SET process_after = NOW() WHERE email = %s", digestID))
+	require.NoError(t, err)
+
+	collected, err := s.CollectPendingActionJobs(ctx, second[0])
+	require.NoError(t, err)
+	require.Len(t, collected, 1)
+	require.Len(t, collected[0].Results, 2)
+
+	digestJobs, err := s.ListActionJobs(ctx, ListActionJobsOpts{EmailID: &digestEmailID})
+	require.NoError(t, err)
+	require.Len(t, digestJobs, 2)
+	require.Equal(t, "completed", digestJobs[0].State)
+	require.Equal(t, "queued", digestJobs[1].State)
+
+	// The job of the immediate action isn't collected.
+	immediateJobs, err := s.ListActionJobs(ctx, ListActionJobsOpts{EmailID: &immediateEmailID})
+	require.NoError(t, err)
+	require.Len(t, immediateJobs, 1)
+	require.Equal(t, "queued", immediateJobs[0].State)
+}
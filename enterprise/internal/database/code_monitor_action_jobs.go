@@ -17,6 +17,7 @@ type ActionJob struct {
 	Email        *int64
 	Webhook      *int64
 	SlackWebhook *int64
+	IssueTracker *int64
 	TriggerEvent int32
 
 	// Fields demanded by any dbworker.
@@ -38,7 +39,9 @@ type ActionJobMetadata struct {
 	Description string
 	MonitorID   int64
 	Results     []*result.CommitMatch
-	OwnerName   string
+	// ContentChanges are the changed matches of monitors on file contents.
+	ContentChanges []*ContentChange
+	OwnerName      string
 
 	// The query with after: filter.
 	Query string
@@ -51,6 +54,7 @@ var ActionJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.email"),
 	sqlf.Sprintf("cm_action_jobs.webhook"),
 	sqlf.Sprintf("cm_action_jobs.slack_webhook"),
+	sqlf.Sprintf("cm_action_jobs.issue_tracker"),
 	sqlf.Sprintf("cm_action_jobs.trigger_event"),
 	sqlf.Sprintf("cm_action_jobs.state"),
 	sqlf.Sprintf("cm_action_jobs.failure_message"),
@@ -82,6 +86,10 @@ type ListActionJobsOpts struct {
 	// the given slack webhook action. Refers to cm_slack_webhooks(id)
 	SlackWebhookID *int
 
+	// IssueTrackerID, if set, will filter to only actions jobs that are
+	// executing the given issue tracker action. Refers to cm_issue_trackers(id)
+	IssueTrackerID *int
+
 	// First, if defined, limits the operation to only the first n results
 	First *int
 
@@ -105,6 +113,9 @@ func (o ListActionJobsOpts) Conds() *sqlf.Query {
 	if o.SlackWebhookID != nil {
 		conds = append(conds, sqlf.Sprintf("slack_webhook = %s", *o.SlackWebhookID))
 	}
+	if o.IssueTrackerID != nil {
+		conds = append(conds, sqlf.Sprintf("issue_tracker = %s", *o.IssueTrackerID))
+	}
 	if o.After != nil {
 		conds = append(conds, sqlf.Sprintf("id > %s", *o.After))
 	}
@@ -164,51 +175,84 @@ func (s *codeMonitorStore) CountActionJobs(ctx context.Context, opts ListActionJ
 	return count, err
 }
 
+// enqueueActionEmailFmtStr enqueues a job for each enabled action of the
+// monitor. Actions that notify after each run without a limit are skipped if
+// they already have a pending job. Other actions always get a job, so that its
+// results are part of the next digest or notification.
 const enqueueActionEmailFmtStr = `
 WITH due_emails AS (
-	SELECT id
-	FROM cm_emails
-	WHERE monitor = %s
-		AND enabled = true
-	EXCEPT
-	SELECT DISTINCT email as id FROM cm_action_jobs
-	WHERE state = 'queued'
-		OR state = 'processing'
+	SELECT a.id, %s AS process_after
+	FROM cm_emails a
+	WHERE a.monitor = %s
+		AND a.enabled = true
+		AND NOT (%s AND EXISTS (
+			SELECT 1 FROM cm_action_jobs
+			WHERE email = a.id
+				AND (state = 'queued' OR state = 'processing')
+		))
 ), due_webhooks AS (
-	SELECT id
-	FROM cm_webhooks
-	WHERE monitor = %s
-		AND enabled = true
-	EXCEPT
-	SELECT DISTINCT webhook as id FROM cm_action_jobs
-	WHERE state = 'queued'
-		OR state = 'processing'
+	SELECT a.id, %s AS process_after
+	FROM cm_webhooks a
+	WHERE a.monitor = %s
+		AND a.enabled = true
+		AND NOT (%s AND EXISTS (
+			SELECT 1 FROM cm_action_jobs
+			WHERE webhook = a.id
+				AND (state = 'queued' OR state = 'processing')
+		))
 ), due_slack_webhooks AS (
-	SELECT id
-	FROM cm_slack_webhooks
-	WHERE monitor = %s
-		AND enabled = true
-	EXCEPT
-	SELECT DISTINCT slack_webhook as id FROM cm_action_jobs
-	WHERE state = 'queued'
-		OR state = 'processing'
+	SELECT a.id, %s AS process_after
+	FROM cm_slack_webhooks a
+	WHERE a.monitor = %s
+		AND a.enabled = true
+		AND NOT (%s AND EXISTS (
+			SELECT 1 FROM cm_action_jobs
+			WHERE slack_webhook = a.id
+				AND (state = 'queued' OR state = 'processing')
+		))
+), due_issue_trackers AS (
+	SELECT a.id, %s AS process_after
+	FROM cm_issue_trackers a
+	WHERE a.monitor = %s
+		AND a.enabled = true
+		AND NOT (%s AND EXISTS (
+			SELECT 1 FROM cm_action_jobs
+			WHERE issue_tracker = a.id
+				AND (state = 'queued' OR state = 'processing')
+		))
 )
-INSERT INTO cm_action_jobs (email, webhook, slack_webhook, trigger_event)
-SELECT id, CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), %s::integer from due_emails
+INSERT INTO cm_action_jobs (email, webhook, slack_webhook, issue_tracker, trigger_event, process_after)
+SELECT id, CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), %s::integer, process_after from due_emails
 UNION
-SELECT CAST(NULL AS BIGINT), id, CAST(NULL AS BIGINT), %s::integer from due_webhooks
+SELECT CAST(NULL AS BIGINT), id, CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), %s::integer, process_after from due_webhooks
 UNION
-SELECT CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), id, %s::integer from due_slack_webhooks
-ORDER BY 1, 2, 3
+SELECT CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), id, CAST(NULL AS BIGINT), %s::integer, process_after from due_slack_webhooks
+UNION
+SELECT CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), CAST(NULL AS BIGINT), id, %s::integer, process_after from due_issue_trackers
+ORDER BY 1, 2, 3, 4
 RETURNING %s
 `
 
+// immediateUnlimitedCond matches the actions aliased "a" that send a
+// notification after each run of the monitor without a limit.
+var immediateUnlimitedCond = sqlf.Sprintf("a.delivery_policy = 'IMMEDIATE' AND a.max_notifications_per_hour IS NULL")
+
 func (s *codeMonitorStore) EnqueueActionJobsForMonitor(ctx context.Context, monitorID int64, triggerJobID int32) ([]*ActionJob, error) {
 	q := sqlf.Sprintf(
 		enqueueActionEmailFmtStr,
+		actionProcessAfter("email"),
 		monitorID,
+		immediateUnlimitedCond,
+		actionProcessAfter("webhook"),
 		monitorID,
+		immediateUnlimitedCond,
+		actionProcessAfter("slack_webhook"),
 		monitorID,
+		immediateUnlimitedCond,
+		actionProcessAfter("issue_tracker"),
+		monitorID,
+		immediateUnlimitedCond,
+		triggerJobID,
 		triggerJobID,
 		triggerJobID,
 		triggerJobID,
@@ -228,6 +272,7 @@ SELECT
 	ctj.query_string,
 	cm.id AS monitorID,
 	ctj.search_results,
+	ctj.content_changes,
 	CASE WHEN LENGTH(users.display_name) > 0 THEN users.display_name ELSE users.username END
 FROM cm_action_jobs caj
 INNER JOIN cm_trigger_jobs ctj on caj.trigger_event = ctj.id
@@ -240,15 +285,18 @@ WHERE caj.id = %s
 // GetActionJobMetada returns the set of fields needed to execute all action jobs
 func (s *codeMonitorStore) GetActionJobMetadata(ctx context.Context, jobID int32) (*ActionJobMetadata, error) {
 	row := s.Store.QueryRow(ctx, sqlf.Sprintf(getActionJobMetadataFmtStr, jobID))
-	var resultsJSON []byte
+	var resultsJSON, changesJSON []byte
 	m := &ActionJobMetadata{}
-	err := row.Scan(&m.Description, &m.Query, &m.MonitorID, &resultsJSON, &m.OwnerName)
+	err := row.Scan(&m.Description, &m.Query, &m.MonitorID, &resultsJSON, &changesJSON, &m.OwnerName)
 	if err != nil {
 		return nil, err
 	}
 	if err := json.Unmarshal(resultsJSON, &m.Results); err != nil {
 		return nil, err
 	}
+	if err := json.Unmarshal(changesJSON, &m.ContentChanges); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
@@ -283,6 +331,7 @@ func ScanActionJob(row dbutil.Scanner) (*ActionJob, error) {
 		&aj.Email,
 		&aj.Webhook,
 		&aj.SlackWebhook,
+		&aj.IssueTracker,
 		&aj.TriggerEvent,
 		&aj.State,
 		&aj.FailureMessage,
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ContentMatch is a line of a file matched by a code monitor on file
+// contents. Files matched by their path have no line number or preview.
+type ContentMatch struct {
+	Path       string `json:"path"`
+	LineNumber int    `json:"lineNumber"`
+	Preview    string `json:"preview"`
+}
+
+// ContentSnapshot is the set of matches of a code monitor on file contents in
+// a repository at the commit it last searched.
+type ContentSnapshot struct {
+	CommitID api.CommitID
+	Matches  []ContentMatch
+}
+
+// ContentChange is a match of a code monitor on file contents that appeared
+// or disappeared since its last run. Commit is the commit the match exists
+// in, which is the previously searched commit for removed matches.
+type ContentChange struct {
+	ContentMatch
+	Repo    api.RepoName `json:"repo"`
+	Commit  api.CommitID `json:"commit"`
+	Removed bool         `json:"removed,omitempty"`
+}
+
+func (s *codeMonitorStore) UpsertContentSnapshot(ctx context.Context, monitorID int64, repoID api.RepoID, snapshot *ContentSnapshot) error {
+	rawQuery := `
+	INSERT INTO cm_content_snapshots (monitor_id, repo_id, commit_oid, matches)
+	VALUES (%s, %s, %s, %s)
+	ON CONFLICT (monitor_id, repo_id) DO UPDATE
+	SET commit_oid = EXCLUDED.commit_oid,
+		matches = EXCLUDED.matches
+	`
+
+	matches := snapshot.Matches
+	// Appease non-null constraint on column
+	if matches == nil {
+		matches = []ContentMatch{}
+	}
+	matchesJSON, err := json.Marshal(matches)
+	if err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(rawQuery, monitorID, int64(repoID), string(snapshot.CommitID), matchesJSON)
+	return s.Exec(ctx, q)
+}
+
+// GetContentSnapshot returns the snapshot of the matches of the monitor in the
+// repository, or nil if the repository hasn't been searched before.
+func (s *codeMonitorStore) GetContentSnapshot(ctx context.Context, monitorID int64, repoID api.RepoID) (*ContentSnapshot, error) {
+	rawQuery := `
+	SELECT commit_oid, matches
+	FROM cm_content_snapshots
+	WHERE monitor_id = %s
+		AND repo_id = %s
+	LIMIT 1
+	`
+
+	q := sqlf.Sprintf(rawQuery, monitorID, int64(repoID))
+	var (
+		snapshot    ContentSnapshot
+		matchesJSON []byte
+	)
+	err := s.QueryRow(ctx, q).Scan(&snapshot.CommitID, &matchesJSON)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(matchesJSON, &snapshot.Matches); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ListContentSnapshotRepos returns the repositories the monitor has a
+// snapshot with matches of. Empty snapshots are skipped, since their matches
+// can't disappear.
+func (s *codeMonitorStore) ListContentSnapshotRepos(ctx context.Context, monitorID int64) ([]api.RepoID, error) {
+	rawQuery := `
+	SELECT repo_id
+	FROM cm_content_snapshots
+	WHERE monitor_id = %s
+		AND matches != '[]'::jsonb
+	ORDER BY repo_id
+	`
+
+	ids, err := basestore.ScanInts(s.Query(ctx, sqlf.Sprintf(rawQuery, monitorID)))
+	if err != nil {
+		return nil, err
+	}
+	repoIDs := make([]api.RepoID, len(ids))
+	for i, id := range ids {
+		repoIDs[i] = api.RepoID(id)
+	}
+	return repoIDs, nil
+}
+
+func (s *codeMonitorStore) DeleteContentSnapshots(ctx context.Context, monitorID int64) error {
+	rawQuery := `
+	DELETE FROM cm_content_snapshots
+	WHERE monitor_id = %s
+	`
+
+	return s.Exec(ctx, sqlf.Sprintf(rawQuery, monitorID))
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestCodeMonitorStoreContentSnapshots(t *testing.T) {
+	t.Parallel()
+
+	logger := logtest.Scoped(t)
+	t.Run("insert get upsert get", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := NewEnterpriseDB(database.NewDB(logger, dbtest.NewDB(logger, t)))
+		fixtures := populateCodeMonitorFixtures(t, db)
+		cm := db.CodeMonitors()
+
+		// Insert
+		insertSnapshot := &ContentSnapshot{
+			CommitID: "commit1",
+			Matches:  []ContentMatch{{Path: "a.go", LineNumber: 3, Preview: "InsecureSkipVerify: true"}},
+		}
+		err := cm.UpsertContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID, insertSnapshot)
+		require.NoError(t, err)
+
+		// Get
+		snapshot, err := cm.GetContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID)
+		require.NoError(t, err)
+		require.Equal(t, insertSnapshot, snapshot)
+
+		// Update
+		updateSnapshot := &ContentSnapshot{
+			CommitID: "commit2",
+			Matches:  []ContentMatch{{Path: "b.go"}},
+		}
+		err = cm.UpsertContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID, updateSnapshot)
+		require.NoError(t, err)
+
+		// Get
+		snapshot, err = cm.GetContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID)
+		require.NoError(t, err)
+		require.Equal(t, updateSnapshot, snapshot)
+
+		// List
+		repos, err := cm.ListContentSnapshotRepos(ctx, fixtures.Monitor.ID)
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{fixtures.Repo.ID}, repos)
+
+		// Delete
+		err = cm.DeleteContentSnapshots(ctx, fixtures.Monitor.ID)
+		require.NoError(t, err)
+
+		repos, err = cm.ListContentSnapshotRepos(ctx, fixtures.Monitor.ID)
+		require.NoError(t, err)
+		require.Empty(t, repos)
+	})
+
+	t.Run("no error for missing get", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := NewEnterpriseDB(database.NewDB(logger, dbtest.NewDB(logger, t)))
+		fixtures := populateCodeMonitorFixtures(t, db)
+		cm := db.CodeMonitors()
+
+		snapshot, err := cm.GetContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID)
+		require.NoError(t, err)
+		require.Nil(t, snapshot)
+	})
+
+	t.Run("empty snapshot", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := NewEnterpriseDB(database.NewDB(logger, dbtest.NewDB(logger, t)))
+		fixtures := populateCodeMonitorFixtures(t, db)
+		cm := db.CodeMonitors()
+
+		// Insert with nil matches
+		err := cm.UpsertContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID, &ContentSnapshot{})
+		require.NoError(t, err)
+
+		snapshot, err := cm.GetContentSnapshot(ctx, fixtures.Monitor.ID, fixtures.Repo.ID)
+		require.NoError(t, err)
+		require.Empty(t, snapshot.Matches)
+
+		// Empty snapshots aren't listed
+		repos, err := cm.ListContentSnapshotRepos(ctx, fixtures.Monitor.ID)
+		require.NoError(t, err)
+		require.Empty(t, repos)
+	})
+}
@@ -18,6 +18,7 @@ type EmailAction struct {
 	Priority       string
 	Header         string
 	IncludeResults bool
+	Delivery       ActionDelivery
 	CreatedBy      int32
 	CreatedAt      time.Time
 	ChangedBy      int32
@@ -202,6 +203,8 @@ var emailsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_emails.priority"),
 	sqlf.Sprintf("cm_emails.header"),
 	sqlf.Sprintf("cm_emails.include_results"),
+	sqlf.Sprintf("cm_emails.delivery_policy"),
+	sqlf.Sprintf("cm_emails.max_notifications_per_hour"),
 	sqlf.Sprintf("cm_emails.created_by"),
 	sqlf.Sprintf("cm_emails.created_at"),
 	sqlf.Sprintf("cm_emails.changed_by"),
@@ -231,6 +234,8 @@ func scanEmail(scanner dbutil.Scanner) (*EmailAction, error) {
 		&m.Priority,
 		&m.Header,
 		&m.IncludeResults,
+		&m.Delivery.Policy,
+		&m.Delivery.MaxNotificationsPerHour,
 		&m.CreatedBy,
 		&m.CreatedAt,
 		&m.ChangedBy,
@@ -0,0 +1,460 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// IssueTrackerKind is the kind of issue tracker an issue tracker action
+// creates issues in.
+type IssueTrackerKind string
+
+const (
+	IssueTrackerGitHub IssueTrackerKind = "GITHUB"
+	IssueTrackerGitLab IssueTrackerKind = "GITLAB"
+	// IssueTrackerREST creates issues with requests built from a
+	// RESTIssueTemplate, e.g. in Jira.
+	IssueTrackerREST IssueTrackerKind = "REST"
+)
+
+// Valid returns whether k is a known issue tracker kind.
+func (k IssueTrackerKind) Valid() bool {
+	switch k {
+	case IssueTrackerGitHub, IssueTrackerGitLab, IssueTrackerREST:
+		return true
+	default:
+		return false
+	}
+}
+
+// RESTIssueTemplate describes the requests that create and comment on issues
+// of a REST issue tracker. The bodies and the comment URL are Go templates.
+type RESTIssueTemplate struct {
+	// IssueBody is the JSON body of the request that creates an issue, which
+	// is posted to the URL of the action.
+	IssueBody string `json:"issueBody"`
+	// KeyField is the dot-separated path of the field of the response that
+	// holds the key of the created issue, e.g. "key" for Jira.
+	KeyField string `json:"keyField"`
+	// URLField is the dot-separated path of the field of the response that
+	// holds the URL of the created issue, if any.
+	URLField string `json:"urlField,omitempty"`
+	// CommentURL is the URL comments on an issue are posted to.
+	CommentURL string `json:"commentURL"`
+	// CommentBody is the JSON body of the request that comments on an issue.
+	CommentBody string `json:"commentBody"`
+}
+
+type IssueTrackerAction struct {
+	ID             int64
+	Monitor        int64
+	Enabled        bool
+	IncludeResults bool
+	Kind           IssueTrackerKind
+	URL            string
+	Repository     string
+	Token          string
+	Labels         []string
+	Assignees      []string
+	// AssignCodeOwners is whether the code owners of the matched files are
+	// assigned to the issues, in addition to Assignees.
+	AssignCodeOwners bool
+	// IssuePerCommit is whether an issue is created for each matching commit,
+	// or one issue for all notifications of the monitor.
+	IssuePerCommit bool
+	RESTTemplate   *RESTIssueTemplate
+	Delivery       ActionDelivery
+
+	CreatedBy int32
+	CreatedAt time.Time
+	ChangedBy int32
+	ChangedAt time.Time
+}
+
+type IssueTrackerActionArgs struct {
+	Enabled        bool
+	IncludeResults bool
+	Kind           IssueTrackerKind
+	URL            string
+	Repository     string
+	// Token is the access token of the action. When updating an action, an
+	// empty token keeps the current one.
+	Token            string
+	Labels           []string
+	Assignees        []string
+	AssignCodeOwners bool
+	IssuePerCommit   bool
+	RESTTemplate     *RESTIssueTemplate
+}
+
+func (args *IssueTrackerActionArgs) validate() error {
+	if !args.Kind.Valid() {
+		return errors.Errorf("invalid issue tracker kind %q", args.Kind)
+	}
+	if args.Kind == IssueTrackerREST {
+		if args.RESTTemplate == nil {
+			return errors.New("REST issue trackers require a template")
+		}
+	} else if args.Repository == "" {
+		return errors.Errorf("%s issue trackers require a repository", args.Kind)
+	}
+	return nil
+}
+
+// restTemplateJSON returns the value of the rest_template column of args.
+func (args *IssueTrackerActionArgs) restTemplateJSON() (*string, error) {
+	if args.RESTTemplate == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(args.RESTTemplate)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
+
+const updateIssueTrackerActionQuery = `
+UPDATE cm_issue_trackers
+SET enabled = %s,
+	include_results = %s,
+	kind = %s,
+	url = %s,
+	repository = %s,
+	token = CASE WHEN %s THEN token ELSE %s END,
+	encryption_key_id = CASE WHEN %s THEN encryption_key_id ELSE %s END,
+	labels = %s,
+	assignees = %s,
+	assign_code_owners = %s,
+	issue_per_commit = %s,
+	rest_template = %s,
+	changed_by = %s,
+	changed_at = %s
+WHERE
+	id = %s
+	AND EXISTS (
+		SELECT 1 FROM cm_monitors
+		WHERE cm_monitors.id = cm_issue_trackers.monitor
+			AND cm_monitors.namespace_user_id = %s
+	)
+RETURNING %s;
+`
+
+func (s *codeMonitorStore) UpdateIssueTrackerAction(ctx context.Context, id int64, args *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+	template, err := args.restTemplateJSON()
+	if err != nil {
+		return nil, err
+	}
+	keepToken := args.Token == ""
+	token, keyID, err := encryption.MaybeEncrypt(ctx, issueTrackerTokenKey(), args.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	a := actor.FromContext(ctx)
+	q := sqlf.Sprintf(
+		updateIssueTrackerActionQuery,
+		args.Enabled,
+		args.IncludeResults,
+		string(args.Kind),
+		args.URL,
+		args.Repository,
+		keepToken,
+		token,
+		keepToken,
+		keyID,
+		pq.Array(nonNilStrings(args.Labels)),
+		pq.Array(nonNilStrings(args.Assignees)),
+		args.AssignCodeOwners,
+		args.IssuePerCommit,
+		template,
+		a.UID,
+		s.Now(),
+		id,
+		a.UID,
+		sqlf.Join(issueTrackerActionColumns, ","),
+	)
+
+	row := s.QueryRow(ctx, q)
+	return scanIssueTrackerAction(ctx, row)
+}
+
+const createIssueTrackerActionQuery = `
+INSERT INTO cm_issue_trackers
+(monitor, enabled, include_results, kind, url, repository, token, encryption_key_id, labels, assignees, assign_code_owners, issue_per_commit, rest_template, created_by, created_at, changed_by, changed_at)
+VALUES (%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s)
+RETURNING %s;
+`
+
+func (s *codeMonitorStore) CreateIssueTrackerAction(ctx context.Context, monitorID int64, args *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+	template, err := args.restTemplateJSON()
+	if err != nil {
+		return nil, err
+	}
+	token, keyID, err := encryption.MaybeEncrypt(ctx, issueTrackerTokenKey(), args.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	q := sqlf.Sprintf(
+		createIssueTrackerActionQuery,
+		monitorID,
+		args.Enabled,
+		args.IncludeResults,
+		string(args.Kind),
+		args.URL,
+		args.Repository,
+		token,
+		keyID,
+		pq.Array(nonNilStrings(args.Labels)),
+		pq.Array(nonNilStrings(args.Assignees)),
+		args.AssignCodeOwners,
+		args.IssuePerCommit,
+		template,
+		a.UID,
+		now,
+		a.UID,
+		now,
+		sqlf.Join(issueTrackerActionColumns, ","),
+	)
+
+	row := s.QueryRow(ctx, q)
+	return scanIssueTrackerAction(ctx, row)
+}
+
+const deleteIssueTrackerActionQuery = `
+DELETE FROM cm_issue_trackers
+WHERE id in (%s)
+	AND MONITOR = %s
+`
+
+func (s *codeMonitorStore) DeleteIssueTrackerActions(ctx context.Context, monitorID int64, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	deleteIDs := make([]*sqlf.Query, 0, len(ids))
+	for _, id := range ids {
+		deleteIDs = append(deleteIDs, sqlf.Sprintf("%d", id))
+	}
+	q := sqlf.Sprintf(
+		deleteIssueTrackerActionQuery,
+		sqlf.Join(deleteIDs, ","),
+		monitorID,
+	)
+
+	return s.Exec(ctx, q)
+}
+
+const getIssueTrackerActionQuery = `
+SELECT %s -- IssueTrackerActionColumns
+FROM cm_issue_trackers
+WHERE id = %s
+`
+
+func (s *codeMonitorStore) GetIssueTrackerAction(ctx context.Context, id int64) (*IssueTrackerAction, error) {
+	q := sqlf.Sprintf(
+		getIssueTrackerActionQuery,
+		sqlf.Join(issueTrackerActionColumns, ","),
+		id,
+	)
+	row := s.QueryRow(ctx, q)
+	return scanIssueTrackerAction(ctx, row)
+}
+
+const listIssueTrackerActionsQuery = `
+SELECT %s -- IssueTrackerActionColumns
+FROM cm_issue_trackers
+WHERE %s
+ORDER BY id ASC
+LIMIT %s;
+`
+
+func (s *codeMonitorStore) ListIssueTrackerActions(ctx context.Context, opts ListActionsOpts) ([]*IssueTrackerAction, error) {
+	q := sqlf.Sprintf(
+		listIssueTrackerActionsQuery,
+		sqlf.Join(issueTrackerActionColumns, ","),
+		opts.Conds(),
+		opts.Limit(),
+	)
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanIssueTrackerActions(ctx, rows)
+}
+
+// Issue is an issue created by an issue tracker action. Further matches about
+// the same subject are commented on the issue instead of creating a new one.
+type Issue struct {
+	IssueTracker int64
+	// Subject is the repository and commit the issue is about, or empty if
+	// the action creates one issue for all its notifications.
+	Subject string
+	// Key is the number or key of the issue in the issue tracker.
+	Key       string
+	URL       string
+	CreatedAt time.Time
+	// ActionJob is the action job that last created or commented on the
+	// issue, if any. Retries of that job skip the issue.
+	ActionJob *int32
+}
+
+const getIssueQuery = `
+SELECT issue_tracker, subject, issue_key, url, created_at, action_job
+FROM cm_issues
+WHERE issue_tracker = %s
+	AND subject = %s
+`
+
+// GetIssue returns the issue of the issue tracker action about subject, or nil
+// if there is none yet.
+func (s *codeMonitorStore) GetIssue(ctx context.Context, issueTrackerID int64, subject string) (*Issue, error) {
+	var i Issue
+	err := s.QueryRow(ctx, sqlf.Sprintf(getIssueQuery, issueTrackerID, subject)).Scan(
+		&i.IssueTracker,
+		&i.Subject,
+		&i.Key,
+		&i.URL,
+		&i.CreatedAt,
+		&i.ActionJob,
+	)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &i, err
+}
+
+const upsertIssueQuery = `
+INSERT INTO cm_issues (issue_tracker, subject, issue_key, url, created_at, action_job)
+VALUES (%s, %s, %s, %s, %s, %s)
+ON CONFLICT (issue_tracker, subject) DO UPDATE
+SET issue_key = EXCLUDED.issue_key,
+	url = EXCLUDED.url,
+	action_job = EXCLUDED.action_job
+`
+
+// UpsertIssue records the issue created by an issue tracker action about the
+// subject of issue, or the action job that last commented on it. The creation
+// time of an existing issue is kept.
+func (s *codeMonitorStore) UpsertIssue(ctx context.Context, issue *Issue) error {
+	return s.Exec(ctx, sqlf.Sprintf(upsertIssueQuery, issue.IssueTracker, issue.Subject, issue.Key, issue.URL, s.Now(), issue.ActionJob))
+}
+
+// issueTrackerActionColumns is the set of columns in the cm_issue_trackers table
+// This must be kept in sync with scanIssueTrackerAction
+var issueTrackerActionColumns = []*sqlf.Query{
+	sqlf.Sprintf("cm_issue_trackers.id"),
+	sqlf.Sprintf("cm_issue_trackers.monitor"),
+	sqlf.Sprintf("cm_issue_trackers.enabled"),
+	sqlf.Sprintf("cm_issue_trackers.include_results"),
+	sqlf.Sprintf("cm_issue_trackers.kind"),
+	sqlf.Sprintf("cm_issue_trackers.url"),
+	sqlf.Sprintf("cm_issue_trackers.repository"),
+	sqlf.Sprintf("cm_issue_trackers.token"),
+	sqlf.Sprintf("cm_issue_trackers.encryption_key_id"),
+	sqlf.Sprintf("cm_issue_trackers.labels"),
+	sqlf.Sprintf("cm_issue_trackers.assignees"),
+	sqlf.Sprintf("cm_issue_trackers.assign_code_owners"),
+	sqlf.Sprintf("cm_issue_trackers.issue_per_commit"),
+	sqlf.Sprintf("cm_issue_trackers.rest_template"),
+	sqlf.Sprintf("cm_issue_trackers.delivery_policy"),
+	sqlf.Sprintf("cm_issue_trackers.max_notifications_per_hour"),
+	sqlf.Sprintf("cm_issue_trackers.created_by"),
+	sqlf.Sprintf("cm_issue_trackers.created_at"),
+	sqlf.Sprintf("cm_issue_trackers.changed_by"),
+	sqlf.Sprintf("cm_issue_trackers.changed_at"),
+}
+
+func scanIssueTrackerActions(ctx context.Context, rows *sql.Rows) ([]*IssueTrackerAction, error) {
+	var as []*IssueTrackerAction
+	for rows.Next() {
+		a, err := scanIssueTrackerAction(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		as = append(as, a)
+	}
+	return as, rows.Err()
+}
+
+// scanIssueTrackerAction scans an IssueTrackerAction from a *sql.Row or *sql.Rows,
+// and decrypts its token. It must be kept in sync with issueTrackerActionColumns.
+func scanIssueTrackerAction(ctx context.Context, scanner dbutil.Scanner) (*IssueTrackerAction, error) {
+	var (
+		a            IssueTrackerAction
+		keyID        string
+		templateJSON []byte
+	)
+	err := scanner.Scan(
+		&a.ID,
+		&a.Monitor,
+		&a.Enabled,
+		&a.IncludeResults,
+		&a.Kind,
+		&a.URL,
+		&a.Repository,
+		&a.Token,
+		&keyID,
+		pq.Array(&a.Labels),
+		pq.Array(&a.Assignees),
+		&a.AssignCodeOwners,
+		&a.IssuePerCommit,
+		&templateJSON,
+		&a.Delivery.Policy,
+		&a.Delivery.MaxNotificationsPerHour,
+		&a.CreatedBy,
+		&a.CreatedAt,
+		&a.ChangedBy,
+		&a.ChangedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if templateJSON != nil {
+		a.RESTTemplate = &RESTIssueTemplate{}
+		if err := json.Unmarshal(templateJSON, a.RESTTemplate); err != nil {
+			return nil, err
+		}
+	}
+	a.Token, err = encryption.MaybeDecrypt(ctx, issueTrackerTokenKey(), a.Token, keyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting token")
+	}
+	return &a, nil
+}
+
+// issueTrackerTokenKey returns the key the tokens of issue tracker actions are
+// encrypted with. Like the configuration of external services, they hold
+// credentials of code hosts and other external services.
+func issueTrackerTokenKey() encryption.Key {
+	return keyring.Default().ExternalServiceKey
+}
+
+// nonNilStrings appeases the non-null constraint on text[] columns.
+func nonNilStrings(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+	return ss
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+func TestCodeMonitorStoreIssueTrackers(t *testing.T) {
+	githubArgs := &IssueTrackerActionArgs{
+		Enabled:        true,
+		Kind:           IssueTrackerGitHub,
+		URL:            "https://api.github.com",
+		Repository:     "sourcegraph/sourcegraph",
+		Token:          "secret",
+		Labels:         []string{"code-monitor"},
+		IssuePerCommit: true,
+	}
+
+	t.Run("CreateUpdateGet", func(t *testing.T) {
+		ctx, db, s := newTestStore(t)
+		_, _, ctx = newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(ctx, t)
+
+		action, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, githubArgs)
+		require.NoError(t, err)
+		require.Equal(t, []string{"code-monitor"}, action.Labels)
+		require.Equal(t, []string{}, action.Assignees)
+		require.Nil(t, action.RESTTemplate)
+
+		got, err := s.GetIssueTrackerAction(ctx, action.ID)
+		require.NoError(t, err)
+		require.Equal(t, action, got)
+
+		// An empty token keeps the current one.
+		template := &RESTIssueTemplate{
+			IssueBody:   `{"fields": {"summary": {{json .Title}}}}`,
+			KeyField:    "key",
+			CommentURL:  "https://jira.example.com/rest/api/2/issue/{{.Key}}/comment",
+			CommentBody: `{"body": {{json .Body}}}`,
+		}
+		updated, err := s.UpdateIssueTrackerAction(ctx, action.ID, &IssueTrackerActionArgs{
+			Enabled:      true,
+			Kind:         IssueTrackerREST,
+			URL:          "https://jira.example.com/rest/api/2/issue",
+			RESTTemplate: template,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", updated.Token)
+		require.Equal(t, template, updated.RESTTemplate)
+
+		got, err = s.GetIssueTrackerAction(ctx, action.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, got)
+	})
+
+	t.Run("EncryptsToken", func(t *testing.T) {
+		keyring.MockDefault(keyring.Ring{ExternalServiceKey: et.TestKey{}})
+		t.Cleanup(func() { keyring.MockDefault(keyring.Ring{}) })
+
+		ctx, db, s := newTestStore(t)
+		_, _, ctx = newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(ctx, t)
+
+		action, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, githubArgs)
+		require.NoError(t, err)
+		require.Equal(t, "secret", action.Token)
+
+		var token, keyID string
+		err = s.QueryRow(ctx, sqlf.Sprintf("SELECT token, encryption_key_id FROM cm_issue_trackers WHERE id = %s", action.ID)).Scan(&token, &keyID)
+		require.NoError(t, err)
+		require.NotEqual(t, "secret", token)
+		require.NotEmpty(t, keyID)
+
+		got, err := s.GetIssueTrackerAction(ctx, action.ID)
+		require.NoError(t, err)
+		require.Equal(t, "secret", got.Token)
+	})
+
+	t.Run("ErrorOnInvalidArgs", func(t *testing.T) {
+		ctx, db, s := newTestStore(t)
+		_, _, ctx = newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(ctx, t)
+
+		_, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, &IssueTrackerActionArgs{Kind: "BUGZILLA"})
+		require.Error(t, err)
+
+		_, err = s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, &IssueTrackerActionArgs{Kind: IssueTrackerGitLab})
+		require.Error(t, err)
+
+		_, err = s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, &IssueTrackerActionArgs{Kind: IssueTrackerREST})
+		require.Error(t, err)
+	})
+
+	t.Run("ListDelete", func(t *testing.T) {
+		ctx, db, s := newTestStore(t)
+		_, _, ctx = newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(ctx, t)
+
+		action1, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, githubArgs)
+		require.NoError(t, err)
+		action2, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, githubArgs)
+		require.NoError(t, err)
+
+		actions, err := s.ListIssueTrackerActions(ctx, ListActionsOpts{MonitorID: &fixtures.monitor.ID})
+		require.NoError(t, err)
+		require.Len(t, actions, 2)
+
+		err = s.DeleteIssueTrackerActions(ctx, fixtures.monitor.ID, action1.ID)
+		require.NoError(t, err)
+
+		_, err = s.GetIssueTrackerAction(ctx, action1.ID)
+		require.Error(t, err)
+
+		_, err = s.GetIssueTrackerAction(ctx, action2.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("Issues", func(t *testing.T) {
+		ctx, db, s := newTestStore(t)
+		_, _, ctx = newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(ctx, t)
+
+		action, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, githubArgs)
+		require.NoError(t, err)
+
+		issue, err := s.GetIssue(ctx, action.ID, "github.com/sourcegraph/sourcegraph@abc")
+		require.NoError(t, err)
+		require.Nil(t, issue)
+
+		err = s.UpsertIssue(ctx, &Issue{
+			IssueTracker: action.ID,
+			Subject:      "github.com/sourcegraph/sourcegraph@abc",
+			Key:          "42",
+			URL:          "https://github.com/sourcegraph/sourcegraph/issues/42",
+		})
+		require.NoError(t, err)
+
+		issue, err = s.GetIssue(ctx, action.ID, "github.com/sourcegraph/sourcegraph@abc")
+		require.NoError(t, err)
+		require.Equal(t, "42", issue.Key)
+		require.Equal(t, "https://github.com/sourcegraph/sourcegraph/issues/42", issue.URL)
+		require.Nil(t, issue.ActionJob)
+
+		job := int32(3)
+		issue.ActionJob = &job
+		err = s.UpsertIssue(ctx, issue)
+		require.NoError(t, err)
+
+		updated, err := s.GetIssue(ctx, action.ID, "github.com/sourcegraph/sourcegraph@abc")
+		require.NoError(t, err)
+		require.Equal(t, &job, updated.ActionJob)
+		require.Equal(t, issue.CreatedAt, updated.CreatedAt)
+
+		issue, err = s.GetIssue(ctx, action.ID, "")
+		require.NoError(t, err)
+		require.Nil(t, issue)
+	})
+
+	t.Run("EnqueueActionJobs", func(t *testing.T) {
+		ctx, db, s := newTestStore(t)
+		_, _, ctx = newTestUser(ctx, t, db)
+		fixtures := s.insertTestMonitor(ctx, t)
+
+		action, err := s.CreateIssueTrackerAction(ctx, fixtures.monitor.ID, githubArgs)
+		require.NoError(t, err)
+
+		triggerJobs, err := s.EnqueueQueryTriggerJobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, triggerJobs, 1)
+		err = s.UpdateTriggerJobWithResults(ctx, triggerJobs[0].ID, testQuery, make([]*result.CommitMatch, 1))
+		require.NoError(t, err)
+
+		_, err = s.EnqueueActionJobsForMonitor(ctx, fixtures.monitor.ID, triggerJobs[0].ID)
+		require.NoError(t, err)
+
+		issueTrackerID := int(action.ID)
+		jobs, err := s.ListActionJobs(ctx, ListActionJobsOpts{IssueTrackerID: &issueTrackerID})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, &action.ID, jobs[0].IssueTracker)
+	})
+}
@@ -17,6 +17,7 @@ type SlackWebhookAction struct {
 	Enabled        bool
 	URL            string
 	IncludeResults bool
+	Delivery       ActionDelivery
 
 	CreatedBy int32
 	CreatedAt time.Time
@@ -169,6 +170,8 @@ var slackWebhookActionColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_slack_webhooks.enabled"),
 	sqlf.Sprintf("cm_slack_webhooks.url"),
 	sqlf.Sprintf("cm_slack_webhooks.include_results"),
+	sqlf.Sprintf("cm_slack_webhooks.delivery_policy"),
+	sqlf.Sprintf("cm_slack_webhooks.max_notifications_per_hour"),
 	sqlf.Sprintf("cm_slack_webhooks.created_by"),
 	sqlf.Sprintf("cm_slack_webhooks.created_at"),
 	sqlf.Sprintf("cm_slack_webhooks.changed_by"),
@@ -197,6 +200,8 @@ func scanSlackWebhookAction(scanner dbutil.Scanner) (*SlackWebhookAction, error)
 		&w.Enabled,
 		&w.URL,
 		&w.IncludeResults,
+		&w.Delivery.Policy,
+		&w.Delivery.MaxNotificationsPerHour,
 		&w.CreatedBy,
 		&w.CreatedAt,
 		&w.ChangedBy,
@@ -21,6 +21,9 @@ type TriggerJob struct {
 
 	SearchResults []*result.CommitMatch
 
+	// The matches that appeared or disappeared for monitors on file contents.
+	ContentChanges []*ContentChange
+
 	// Fields demanded for any dbworker.
 	State          string
 	FailureMessage *string
@@ -82,6 +85,29 @@ func (s *codeMonitorStore) UpdateTriggerJobWithResults(ctx context.Context, trig
 	return s.Store.Exec(ctx, sqlf.Sprintf(logSearchFmtStr, queryString, resultsJSON, triggerJobID))
 }
 
+const logContentChangesFmtStr = `
+UPDATE cm_trigger_jobs
+SET query_string = %s,
+    search_results = '[]'::jsonb,
+    content_changes = %s
+WHERE id = %s
+`
+
+// UpdateTriggerJobWithContentChanges records the query and the changed matches
+// of a run of a code monitor on file contents.
+func (s *codeMonitorStore) UpdateTriggerJobWithContentChanges(ctx context.Context, triggerJobID int32, queryString string, changes []*ContentChange) error {
+	if changes == nil {
+		// appease db non-null constraint
+		changes = []*ContentChange{}
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	return s.Store.Exec(ctx, sqlf.Sprintf(logContentChangesFmtStr, queryString, changesJSON, triggerJobID))
+}
+
 const deleteOldJobLogsFmtStr = `
 DELETE FROM cm_trigger_jobs
 WHERE finished_at < (NOW() - (%s * '1 day'::interval));
@@ -143,7 +169,7 @@ func (s *codeMonitorStore) ListQueryTriggerJobs(ctx context.Context, opts ListTr
 const totalCountEventsForQueryIDInt64FmtStr = `
 SELECT COUNT(*)
 FROM cm_trigger_jobs
-WHERE ((state = 'completed' AND jsonb_array_length(search_results) > 0) OR (state != 'completed'))
+WHERE ((state = 'completed' AND (jsonb_array_length(search_results) > 0 OR jsonb_array_length(content_changes) > 0)) OR (state != 'completed'))
 AND query = %s
 `
 
@@ -170,13 +196,14 @@ func scanTriggerJobs(rows *sql.Rows) ([]*TriggerJob, error) {
 }
 
 func ScanTriggerJob(scanner dbutil.Scanner) (*TriggerJob, error) {
-	var resultsJSON []byte
+	var resultsJSON, changesJSON []byte
 	m := &TriggerJob{}
 	err := scanner.Scan(
 		&m.ID,
 		&m.Query,
 		&m.QueryString,
 		&resultsJSON,
+		&changesJSON,
 		&m.State,
 		&m.FailureMessage,
 		&m.StartedAt,
@@ -195,6 +222,11 @@ func ScanTriggerJob(scanner dbutil.Scanner) (*TriggerJob, error) {
 			return nil, err
 		}
 	}
+	if len(changesJSON) > 0 {
+		if err := json.Unmarshal(changesJSON, &m.ContentChanges); err != nil {
+			return nil, err
+		}
+	}
 
 	return m, nil
 }
@@ -204,6 +236,7 @@ var TriggerJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_trigger_jobs.query"),
 	sqlf.Sprintf("cm_trigger_jobs.query_string"),
 	sqlf.Sprintf("cm_trigger_jobs.search_results"),
+	sqlf.Sprintf("cm_trigger_jobs.content_changes"),
 	sqlf.Sprintf("cm_trigger_jobs.state"),
 	sqlf.Sprintf("cm_trigger_jobs.failure_message"),
 	sqlf.Sprintf("cm_trigger_jobs.started_at"),
@@ -50,6 +50,7 @@ type CodeMonitorStore interface {
 	CountQueryTriggerJobs(ctx context.Context, queryID int64) (int32, error)
 
 	UpdateTriggerJobWithResults(ctx context.Context, triggerJobID int32, queryString string, results []*result.CommitMatch) error
+	UpdateTriggerJobWithContentChanges(ctx context.Context, triggerJobID int32, queryString string, changes []*ContentChange) error
 	DeleteOldTriggerJobs(ctx context.Context, retentionInDays int) error
 
 	UpdateEmailAction(_ context.Context, id int64, _ *EmailActionArgs) (*EmailAction, error)
@@ -72,6 +73,17 @@ type CodeMonitorStore interface {
 	GetSlackWebhookAction(ctx context.Context, id int64) (*SlackWebhookAction, error)
 	ListSlackWebhookActions(context.Context, ListActionsOpts) ([]*SlackWebhookAction, error)
 
+	UpdateIssueTrackerAction(_ context.Context, id int64, _ *IssueTrackerActionArgs) (*IssueTrackerAction, error)
+	CreateIssueTrackerAction(ctx context.Context, monitorID int64, _ *IssueTrackerActionArgs) (*IssueTrackerAction, error)
+	DeleteIssueTrackerActions(ctx context.Context, monitorID int64, ids ...int64) error
+	GetIssueTrackerAction(ctx context.Context, id int64) (*IssueTrackerAction, error)
+	ListIssueTrackerActions(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error)
+
+	// GetIssue and UpsertIssue manage the issues created by issue tracker
+	// actions, which further matches about the same subject are commented on.
+	GetIssue(ctx context.Context, issueTrackerID int64, subject string) (*Issue, error)
+	UpsertIssue(ctx context.Context, issue *Issue) error
+
 	CreateRecipient(ctx context.Context, emailID int64, userID, orgID *int32) (*Recipient, error)
 	DeleteRecipients(ctx context.Context, emailID int64) error
 	ListRecipients(context.Context, ListRecipientsOpts) ([]*Recipient, error)
@@ -83,6 +95,12 @@ type CodeMonitorStore interface {
 	GetActionJob(ctx context.Context, jobID int32) (*ActionJob, error)
 	EnqueueActionJobsForMonitor(ctx context.Context, monitorID int64, triggerJob int32) ([]*ActionJob, error)
 
+	// UpdateActionDelivery sets when the notifications of an action are sent.
+	UpdateActionDelivery(ctx context.Context, table ActionTable, id int64, delivery ActionDelivery) error
+	// CollectPendingActionJobs batches the other due jobs of the action of the
+	// given job into it, and returns their results.
+	CollectPendingActionJobs(ctx context.Context, job *ActionJob) ([]*ActionJobMetadata, error)
+
 	// HasAnyLastSearched returns whether there have ever been any repo-aware code monitor
 	// searches executed for this code monitor. This should only be needed during the transition
 	// version so that we don't detect every repo as a new repo and search their entire history
@@ -90,6 +108,15 @@ type CodeMonitorStore interface {
 	HasAnyLastSearched(ctx context.Context, monitorID int64) (bool, error)
 	UpsertLastSearched(ctx context.Context, monitorID int64, repoID api.RepoID, lastSearched []string) error
 	GetLastSearched(ctx context.Context, monitorID int64, repoID api.RepoID) ([]string, error)
+
+	// UpsertContentSnapshot, GetContentSnapshot, ListContentSnapshotRepos, and
+	// DeleteContentSnapshots manage the matches of code monitors on file
+	// contents at the last searched commit of each repository, which new
+	// matches are compared against.
+	UpsertContentSnapshot(ctx context.Context, monitorID int64, repoID api.RepoID, snapshot *ContentSnapshot) error
+	GetContentSnapshot(ctx context.Context, monitorID int64, repoID api.RepoID) (*ContentSnapshot, error)
+	ListContentSnapshotRepos(ctx context.Context, monitorID int64) ([]api.RepoID, error)
+	DeleteContentSnapshots(ctx context.Context, monitorID int64) error
 }
 
 // codeMonitorStore exposes methods to read and write codemonitors domain models
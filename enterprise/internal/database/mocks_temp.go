@@ -31,6 +31,10 @@ type MockCodeMonitorStore struct {
 	// ClockFunc is an instance of a mock function object controlling the
 	// behavior of the method Clock.
 	ClockFunc *CodeMonitorStoreClockFunc
+	// CollectPendingActionJobsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CollectPendingActionJobs.
+	CollectPendingActionJobsFunc *CodeMonitorStoreCollectPendingActionJobsFunc
 	// CountActionJobsFunc is an instance of a mock function object
 	// controlling the behavior of the method CountActionJobs.
 	CountActionJobsFunc *CodeMonitorStoreCountActionJobsFunc
@@ -52,6 +56,10 @@ type MockCodeMonitorStore struct {
 	// CreateEmailActionFunc is an instance of a mock function object
 	// controlling the behavior of the method CreateEmailAction.
 	CreateEmailActionFunc *CodeMonitorStoreCreateEmailActionFunc
+	// CreateIssueTrackerActionFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CreateIssueTrackerAction.
+	CreateIssueTrackerActionFunc *CodeMonitorStoreCreateIssueTrackerActionFunc
 	// CreateMonitorFunc is an instance of a mock function object
 	// controlling the behavior of the method CreateMonitor.
 	CreateMonitorFunc *CodeMonitorStoreCreateMonitorFunc
@@ -67,9 +75,17 @@ type MockCodeMonitorStore struct {
 	// CreateWebhookActionFunc is an instance of a mock function object
 	// controlling the behavior of the method CreateWebhookAction.
 	CreateWebhookActionFunc *CodeMonitorStoreCreateWebhookActionFunc
+	// DeleteContentSnapshotsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// DeleteContentSnapshots.
+	DeleteContentSnapshotsFunc *CodeMonitorStoreDeleteContentSnapshotsFunc
 	// DeleteEmailActionsFunc is an instance of a mock function object
 	// controlling the behavior of the method DeleteEmailActions.
 	DeleteEmailActionsFunc *CodeMonitorStoreDeleteEmailActionsFunc
+	// DeleteIssueTrackerActionsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// DeleteIssueTrackerActions.
+	DeleteIssueTrackerActionsFunc *CodeMonitorStoreDeleteIssueTrackerActionsFunc
 	// DeleteMonitorFunc is an instance of a mock function object
 	// controlling the behavior of the method DeleteMonitor.
 	DeleteMonitorFunc *CodeMonitorStoreDeleteMonitorFunc
@@ -105,9 +121,18 @@ type MockCodeMonitorStore struct {
 	// GetActionJobMetadataFunc is an instance of a mock function object
 	// controlling the behavior of the method GetActionJobMetadata.
 	GetActionJobMetadataFunc *CodeMonitorStoreGetActionJobMetadataFunc
+	// GetContentSnapshotFunc is an instance of a mock function object
+	// controlling the behavior of the method GetContentSnapshot.
+	GetContentSnapshotFunc *CodeMonitorStoreGetContentSnapshotFunc
 	// GetEmailActionFunc is an instance of a mock function object
 	// controlling the behavior of the method GetEmailAction.
 	GetEmailActionFunc *CodeMonitorStoreGetEmailActionFunc
+	// GetIssueFunc is an instance of a mock function object controlling
+	// the behavior of the method GetIssue.
+	GetIssueFunc *CodeMonitorStoreGetIssueFunc
+	// GetIssueTrackerActionFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIssueTrackerAction.
+	GetIssueTrackerActionFunc *CodeMonitorStoreGetIssueTrackerActionFunc
 	// GetLastSearchedFunc is an instance of a mock function object
 	// controlling the behavior of the method GetLastSearched.
 	GetLastSearchedFunc *CodeMonitorStoreGetLastSearchedFunc
@@ -136,9 +161,17 @@ type MockCodeMonitorStore struct {
 	// ListActionJobsFunc is an instance of a mock function object
 	// controlling the behavior of the method ListActionJobs.
 	ListActionJobsFunc *CodeMonitorStoreListActionJobsFunc
+	// ListContentSnapshotReposFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// ListContentSnapshotRepos.
+	ListContentSnapshotReposFunc *CodeMonitorStoreListContentSnapshotReposFunc
 	// ListEmailActionsFunc is an instance of a mock function object
 	// controlling the behavior of the method ListEmailActions.
 	ListEmailActionsFunc *CodeMonitorStoreListEmailActionsFunc
+	// ListIssueTrackerActionsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// ListIssueTrackerActions.
+	ListIssueTrackerActionsFunc *CodeMonitorStoreListIssueTrackerActionsFunc
 	// ListMonitorsFunc is an instance of a mock function object controlling
 	// the behavior of the method ListMonitors.
 	ListMonitorsFunc *CodeMonitorStoreListMonitorsFunc
@@ -167,9 +200,16 @@ type MockCodeMonitorStore struct {
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *CodeMonitorStoreTransactFunc
+	// UpdateActionDeliveryFunc is an instance of a mock function object
+	// controlling the behavior of the method UpdateActionDelivery.
+	UpdateActionDeliveryFunc *CodeMonitorStoreUpdateActionDeliveryFunc
 	// UpdateEmailActionFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateEmailAction.
 	UpdateEmailActionFunc *CodeMonitorStoreUpdateEmailActionFunc
+	// UpdateIssueTrackerActionFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// UpdateIssueTrackerAction.
+	UpdateIssueTrackerActionFunc *CodeMonitorStoreUpdateIssueTrackerActionFunc
 	// UpdateMonitorFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateMonitor.
 	UpdateMonitorFunc *CodeMonitorStoreUpdateMonitorFunc
@@ -182,6 +222,10 @@ type MockCodeMonitorStore struct {
 	// UpdateSlackWebhookActionFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateSlackWebhookAction.
 	UpdateSlackWebhookActionFunc *CodeMonitorStoreUpdateSlackWebhookActionFunc
+	// UpdateTriggerJobWithContentChangesFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// UpdateTriggerJobWithContentChanges.
+	UpdateTriggerJobWithContentChangesFunc *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc
 	// UpdateTriggerJobWithResultsFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// UpdateTriggerJobWithResults.
@@ -189,6 +233,12 @@ type MockCodeMonitorStore struct {
 	// UpdateWebhookActionFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateWebhookAction.
 	UpdateWebhookActionFunc *CodeMonitorStoreUpdateWebhookActionFunc
+	// UpsertContentSnapshotFunc is an instance of a mock function object
+	// controlling the behavior of the method UpsertContentSnapshot.
+	UpsertContentSnapshotFunc *CodeMonitorStoreUpsertContentSnapshotFunc
+	// UpsertIssueFunc is an instance of a mock function object
+	// controlling the behavior of the method UpsertIssue.
+	UpsertIssueFunc *CodeMonitorStoreUpsertIssueFunc
 	// UpsertLastSearchedFunc is an instance of a mock function object
 	// controlling the behavior of the method UpsertLastSearched.
 	UpsertLastSearchedFunc *CodeMonitorStoreUpsertLastSearchedFunc
@@ -204,6 +254,11 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		CollectPendingActionJobsFunc: &CodeMonitorStoreCollectPendingActionJobsFunc{
+			defaultHook: func(context.Context, *ActionJob) (r0 []*ActionJobMetadata, r1 error) {
+				return
+			},
+		},
 		CountActionJobsFunc: &CodeMonitorStoreCountActionJobsFunc{
 			defaultHook: func(context.Context, ListActionJobsOpts) (r0 int, r1 error) {
 				return
@@ -239,6 +294,11 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		CreateIssueTrackerActionFunc: &CodeMonitorStoreCreateIssueTrackerActionFunc{
+			defaultHook: func(context.Context, int64, *IssueTrackerActionArgs) (r0 *IssueTrackerAction, r1 error) {
+				return
+			},
+		},
 		CreateMonitorFunc: &CodeMonitorStoreCreateMonitorFunc{
 			defaultHook: func(context.Context, MonitorArgs) (r0 *Monitor, r1 error) {
 				return
@@ -264,11 +324,21 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		DeleteContentSnapshotsFunc: &CodeMonitorStoreDeleteContentSnapshotsFunc{
+			defaultHook: func(context.Context, int64) (r0 error) {
+				return
+			},
+		},
 		DeleteEmailActionsFunc: &CodeMonitorStoreDeleteEmailActionsFunc{
 			defaultHook: func(context.Context, []int64, int64) (r0 error) {
 				return
 			},
 		},
+		DeleteIssueTrackerActionsFunc: &CodeMonitorStoreDeleteIssueTrackerActionsFunc{
+			defaultHook: func(context.Context, int64, ...int64) (r0 error) {
+				return
+			},
+		},
 		DeleteMonitorFunc: &CodeMonitorStoreDeleteMonitorFunc{
 			defaultHook: func(context.Context, int64) (r0 error) {
 				return
@@ -324,11 +394,26 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		GetContentSnapshotFunc: &CodeMonitorStoreGetContentSnapshotFunc{
+			defaultHook: func(context.Context, int64, api.RepoID) (r0 *ContentSnapshot, r1 error) {
+				return
+			},
+		},
 		GetEmailActionFunc: &CodeMonitorStoreGetEmailActionFunc{
 			defaultHook: func(context.Context, int64) (r0 *EmailAction, r1 error) {
 				return
 			},
 		},
+		GetIssueFunc: &CodeMonitorStoreGetIssueFunc{
+			defaultHook: func(context.Context, int64, string) (r0 *Issue, r1 error) {
+				return
+			},
+		},
+		GetIssueTrackerActionFunc: &CodeMonitorStoreGetIssueTrackerActionFunc{
+			defaultHook: func(context.Context, int64) (r0 *IssueTrackerAction, r1 error) {
+				return
+			},
+		},
 		GetLastSearchedFunc: &CodeMonitorStoreGetLastSearchedFunc{
 			defaultHook: func(context.Context, int64, api.RepoID) (r0 []string, r1 error) {
 				return
@@ -374,11 +459,21 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		ListContentSnapshotReposFunc: &CodeMonitorStoreListContentSnapshotReposFunc{
+			defaultHook: func(context.Context, int64) (r0 []api.RepoID, r1 error) {
+				return
+			},
+		},
 		ListEmailActionsFunc: &CodeMonitorStoreListEmailActionsFunc{
 			defaultHook: func(context.Context, ListActionsOpts) (r0 []*EmailAction, r1 error) {
 				return
 			},
 		},
+		ListIssueTrackerActionsFunc: &CodeMonitorStoreListIssueTrackerActionsFunc{
+			defaultHook: func(context.Context, ListActionsOpts) (r0 []*IssueTrackerAction, r1 error) {
+				return
+			},
+		},
 		ListMonitorsFunc: &CodeMonitorStoreListMonitorsFunc{
 			defaultHook: func(context.Context, ListMonitorsOpts) (r0 []*Monitor, r1 error) {
 				return
@@ -424,11 +519,21 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		UpdateActionDeliveryFunc: &CodeMonitorStoreUpdateActionDeliveryFunc{
+			defaultHook: func(context.Context, ActionTable, int64, ActionDelivery) (r0 error) {
+				return
+			},
+		},
 		UpdateEmailActionFunc: &CodeMonitorStoreUpdateEmailActionFunc{
 			defaultHook: func(context.Context, int64, *EmailActionArgs) (r0 *EmailAction, r1 error) {
 				return
 			},
 		},
+		UpdateIssueTrackerActionFunc: &CodeMonitorStoreUpdateIssueTrackerActionFunc{
+			defaultHook: func(context.Context, int64, *IssueTrackerActionArgs) (r0 *IssueTrackerAction, r1 error) {
+				return
+			},
+		},
 		UpdateMonitorFunc: &CodeMonitorStoreUpdateMonitorFunc{
 			defaultHook: func(context.Context, int64, MonitorArgs) (r0 *Monitor, r1 error) {
 				return
@@ -449,6 +554,11 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		UpdateTriggerJobWithContentChangesFunc: &CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc{
+			defaultHook: func(context.Context, int32, string, []*ContentChange) (r0 error) {
+				return
+			},
+		},
 		UpdateTriggerJobWithResultsFunc: &CodeMonitorStoreUpdateTriggerJobWithResultsFunc{
 			defaultHook: func(context.Context, int32, string, []*result.CommitMatch) (r0 error) {
 				return
@@ -459,6 +569,16 @@ func NewMockCodeMonitorStore() *MockCodeMonitorStore {
 				return
 			},
 		},
+		UpsertContentSnapshotFunc: &CodeMonitorStoreUpsertContentSnapshotFunc{
+			defaultHook: func(context.Context, int64, api.RepoID, *ContentSnapshot) (r0 error) {
+				return
+			},
+		},
+		UpsertIssueFunc: &CodeMonitorStoreUpsertIssueFunc{
+			defaultHook: func(context.Context, *Issue) (r0 error) {
+				return
+			},
+		},
 		UpsertLastSearchedFunc: &CodeMonitorStoreUpsertLastSearchedFunc{
 			defaultHook: func(context.Context, int64, api.RepoID, []string) (r0 error) {
 				return
@@ -476,6 +596,11 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.Clock")
 			},
 		},
+		CollectPendingActionJobsFunc: &CodeMonitorStoreCollectPendingActionJobsFunc{
+			defaultHook: func(context.Context, *ActionJob) ([]*ActionJobMetadata, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.CollectPendingActionJobs")
+			},
+		},
 		CountActionJobsFunc: &CodeMonitorStoreCountActionJobsFunc{
 			defaultHook: func(context.Context, ListActionJobsOpts) (int, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.CountActionJobs")
@@ -511,6 +636,11 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.CreateEmailAction")
 			},
 		},
+		CreateIssueTrackerActionFunc: &CodeMonitorStoreCreateIssueTrackerActionFunc{
+			defaultHook: func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.CreateIssueTrackerAction")
+			},
+		},
 		CreateMonitorFunc: &CodeMonitorStoreCreateMonitorFunc{
 			defaultHook: func(context.Context, MonitorArgs) (*Monitor, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.CreateMonitor")
@@ -536,11 +666,21 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.CreateWebhookAction")
 			},
 		},
+		DeleteContentSnapshotsFunc: &CodeMonitorStoreDeleteContentSnapshotsFunc{
+			defaultHook: func(context.Context, int64) error {
+				panic("unexpected invocation of MockCodeMonitorStore.DeleteContentSnapshots")
+			},
+		},
 		DeleteEmailActionsFunc: &CodeMonitorStoreDeleteEmailActionsFunc{
 			defaultHook: func(context.Context, []int64, int64) error {
 				panic("unexpected invocation of MockCodeMonitorStore.DeleteEmailActions")
 			},
 		},
+		DeleteIssueTrackerActionsFunc: &CodeMonitorStoreDeleteIssueTrackerActionsFunc{
+			defaultHook: func(context.Context, int64, ...int64) error {
+				panic("unexpected invocation of MockCodeMonitorStore.DeleteIssueTrackerActions")
+			},
+		},
 		DeleteMonitorFunc: &CodeMonitorStoreDeleteMonitorFunc{
 			defaultHook: func(context.Context, int64) error {
 				panic("unexpected invocation of MockCodeMonitorStore.DeleteMonitor")
@@ -596,11 +736,26 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.GetActionJobMetadata")
 			},
 		},
+		GetContentSnapshotFunc: &CodeMonitorStoreGetContentSnapshotFunc{
+			defaultHook: func(context.Context, int64, api.RepoID) (*ContentSnapshot, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.GetContentSnapshot")
+			},
+		},
 		GetEmailActionFunc: &CodeMonitorStoreGetEmailActionFunc{
 			defaultHook: func(context.Context, int64) (*EmailAction, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.GetEmailAction")
 			},
 		},
+		GetIssueFunc: &CodeMonitorStoreGetIssueFunc{
+			defaultHook: func(context.Context, int64, string) (*Issue, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.GetIssue")
+			},
+		},
+		GetIssueTrackerActionFunc: &CodeMonitorStoreGetIssueTrackerActionFunc{
+			defaultHook: func(context.Context, int64) (*IssueTrackerAction, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.GetIssueTrackerAction")
+			},
+		},
 		GetLastSearchedFunc: &CodeMonitorStoreGetLastSearchedFunc{
 			defaultHook: func(context.Context, int64, api.RepoID) ([]string, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.GetLastSearched")
@@ -646,11 +801,21 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.ListActionJobs")
 			},
 		},
+		ListContentSnapshotReposFunc: &CodeMonitorStoreListContentSnapshotReposFunc{
+			defaultHook: func(context.Context, int64) ([]api.RepoID, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.ListContentSnapshotRepos")
+			},
+		},
 		ListEmailActionsFunc: &CodeMonitorStoreListEmailActionsFunc{
 			defaultHook: func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.ListEmailActions")
 			},
 		},
+		ListIssueTrackerActionsFunc: &CodeMonitorStoreListIssueTrackerActionsFunc{
+			defaultHook: func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.ListIssueTrackerActions")
+			},
+		},
 		ListMonitorsFunc: &CodeMonitorStoreListMonitorsFunc{
 			defaultHook: func(context.Context, ListMonitorsOpts) ([]*Monitor, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.ListMonitors")
@@ -696,11 +861,21 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.Transact")
 			},
 		},
+		UpdateActionDeliveryFunc: &CodeMonitorStoreUpdateActionDeliveryFunc{
+			defaultHook: func(context.Context, ActionTable, int64, ActionDelivery) error {
+				panic("unexpected invocation of MockCodeMonitorStore.UpdateActionDelivery")
+			},
+		},
 		UpdateEmailActionFunc: &CodeMonitorStoreUpdateEmailActionFunc{
 			defaultHook: func(context.Context, int64, *EmailActionArgs) (*EmailAction, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.UpdateEmailAction")
 			},
 		},
+		UpdateIssueTrackerActionFunc: &CodeMonitorStoreUpdateIssueTrackerActionFunc{
+			defaultHook: func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+				panic("unexpected invocation of MockCodeMonitorStore.UpdateIssueTrackerAction")
+			},
+		},
 		UpdateMonitorFunc: &CodeMonitorStoreUpdateMonitorFunc{
 			defaultHook: func(context.Context, int64, MonitorArgs) (*Monitor, error) {
 				panic("unexpected invocation of MockCodeMonitorStore.UpdateMonitor")
@@ -721,6 +896,11 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.UpdateSlackWebhookAction")
 			},
 		},
+		UpdateTriggerJobWithContentChangesFunc: &CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc{
+			defaultHook: func(context.Context, int32, string, []*ContentChange) error {
+				panic("unexpected invocation of MockCodeMonitorStore.UpdateTriggerJobWithContentChanges")
+			},
+		},
 		UpdateTriggerJobWithResultsFunc: &CodeMonitorStoreUpdateTriggerJobWithResultsFunc{
 			defaultHook: func(context.Context, int32, string, []*result.CommitMatch) error {
 				panic("unexpected invocation of MockCodeMonitorStore.UpdateTriggerJobWithResults")
@@ -731,6 +911,16 @@ func NewStrictMockCodeMonitorStore() *MockCodeMonitorStore {
 				panic("unexpected invocation of MockCodeMonitorStore.UpdateWebhookAction")
 			},
 		},
+		UpsertContentSnapshotFunc: &CodeMonitorStoreUpsertContentSnapshotFunc{
+			defaultHook: func(context.Context, int64, api.RepoID, *ContentSnapshot) error {
+				panic("unexpected invocation of MockCodeMonitorStore.UpsertContentSnapshot")
+			},
+		},
+		UpsertIssueFunc: &CodeMonitorStoreUpsertIssueFunc{
+			defaultHook: func(context.Context, *Issue) error {
+				panic("unexpected invocation of MockCodeMonitorStore.UpsertIssue")
+			},
+		},
 		UpsertLastSearchedFunc: &CodeMonitorStoreUpsertLastSearchedFunc{
 			defaultHook: func(context.Context, int64, api.RepoID, []string) error {
 				panic("unexpected invocation of MockCodeMonitorStore.UpsertLastSearched")
@@ -747,6 +937,9 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		ClockFunc: &CodeMonitorStoreClockFunc{
 			defaultHook: i.Clock,
 		},
+		CollectPendingActionJobsFunc: &CodeMonitorStoreCollectPendingActionJobsFunc{
+			defaultHook: i.CollectPendingActionJobs,
+		},
 		CountActionJobsFunc: &CodeMonitorStoreCountActionJobsFunc{
 			defaultHook: i.CountActionJobs,
 		},
@@ -768,6 +961,9 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		CreateEmailActionFunc: &CodeMonitorStoreCreateEmailActionFunc{
 			defaultHook: i.CreateEmailAction,
 		},
+		CreateIssueTrackerActionFunc: &CodeMonitorStoreCreateIssueTrackerActionFunc{
+			defaultHook: i.CreateIssueTrackerAction,
+		},
 		CreateMonitorFunc: &CodeMonitorStoreCreateMonitorFunc{
 			defaultHook: i.CreateMonitor,
 		},
@@ -783,9 +979,15 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		CreateWebhookActionFunc: &CodeMonitorStoreCreateWebhookActionFunc{
 			defaultHook: i.CreateWebhookAction,
 		},
+		DeleteContentSnapshotsFunc: &CodeMonitorStoreDeleteContentSnapshotsFunc{
+			defaultHook: i.DeleteContentSnapshots,
+		},
 		DeleteEmailActionsFunc: &CodeMonitorStoreDeleteEmailActionsFunc{
 			defaultHook: i.DeleteEmailActions,
 		},
+		DeleteIssueTrackerActionsFunc: &CodeMonitorStoreDeleteIssueTrackerActionsFunc{
+			defaultHook: i.DeleteIssueTrackerActions,
+		},
 		DeleteMonitorFunc: &CodeMonitorStoreDeleteMonitorFunc{
 			defaultHook: i.DeleteMonitor,
 		},
@@ -819,9 +1021,18 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		GetActionJobMetadataFunc: &CodeMonitorStoreGetActionJobMetadataFunc{
 			defaultHook: i.GetActionJobMetadata,
 		},
+		GetContentSnapshotFunc: &CodeMonitorStoreGetContentSnapshotFunc{
+			defaultHook: i.GetContentSnapshot,
+		},
 		GetEmailActionFunc: &CodeMonitorStoreGetEmailActionFunc{
 			defaultHook: i.GetEmailAction,
 		},
+		GetIssueFunc: &CodeMonitorStoreGetIssueFunc{
+			defaultHook: i.GetIssue,
+		},
+		GetIssueTrackerActionFunc: &CodeMonitorStoreGetIssueTrackerActionFunc{
+			defaultHook: i.GetIssueTrackerAction,
+		},
 		GetLastSearchedFunc: &CodeMonitorStoreGetLastSearchedFunc{
 			defaultHook: i.GetLastSearched,
 		},
@@ -849,9 +1060,15 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		ListActionJobsFunc: &CodeMonitorStoreListActionJobsFunc{
 			defaultHook: i.ListActionJobs,
 		},
+		ListContentSnapshotReposFunc: &CodeMonitorStoreListContentSnapshotReposFunc{
+			defaultHook: i.ListContentSnapshotRepos,
+		},
 		ListEmailActionsFunc: &CodeMonitorStoreListEmailActionsFunc{
 			defaultHook: i.ListEmailActions,
 		},
+		ListIssueTrackerActionsFunc: &CodeMonitorStoreListIssueTrackerActionsFunc{
+			defaultHook: i.ListIssueTrackerActions,
+		},
 		ListMonitorsFunc: &CodeMonitorStoreListMonitorsFunc{
 			defaultHook: i.ListMonitors,
 		},
@@ -879,9 +1096,15 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		TransactFunc: &CodeMonitorStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
+		UpdateActionDeliveryFunc: &CodeMonitorStoreUpdateActionDeliveryFunc{
+			defaultHook: i.UpdateActionDelivery,
+		},
 		UpdateEmailActionFunc: &CodeMonitorStoreUpdateEmailActionFunc{
 			defaultHook: i.UpdateEmailAction,
 		},
+		UpdateIssueTrackerActionFunc: &CodeMonitorStoreUpdateIssueTrackerActionFunc{
+			defaultHook: i.UpdateIssueTrackerAction,
+		},
 		UpdateMonitorFunc: &CodeMonitorStoreUpdateMonitorFunc{
 			defaultHook: i.UpdateMonitor,
 		},
@@ -894,12 +1117,21 @@ func NewMockCodeMonitorStoreFrom(i CodeMonitorStore) *MockCodeMonitorStore {
 		UpdateSlackWebhookActionFunc: &CodeMonitorStoreUpdateSlackWebhookActionFunc{
 			defaultHook: i.UpdateSlackWebhookAction,
 		},
+		UpdateTriggerJobWithContentChangesFunc: &CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc{
+			defaultHook: i.UpdateTriggerJobWithContentChanges,
+		},
 		UpdateTriggerJobWithResultsFunc: &CodeMonitorStoreUpdateTriggerJobWithResultsFunc{
 			defaultHook: i.UpdateTriggerJobWithResults,
 		},
 		UpdateWebhookActionFunc: &CodeMonitorStoreUpdateWebhookActionFunc{
 			defaultHook: i.UpdateWebhookAction,
 		},
+		UpsertContentSnapshotFunc: &CodeMonitorStoreUpsertContentSnapshotFunc{
+			defaultHook: i.UpsertContentSnapshot,
+		},
+		UpsertIssueFunc: &CodeMonitorStoreUpsertIssueFunc{
+			defaultHook: i.UpsertIssue,
+		},
 		UpsertLastSearchedFunc: &CodeMonitorStoreUpsertLastSearchedFunc{
 			defaultHook: i.UpsertLastSearched,
 		},
@@ -1005,6 +1237,118 @@ func (c CodeMonitorStoreClockFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// CodeMonitorStoreCollectPendingActionJobsFunc describes the behavior when
+// the CollectPendingActionJobs method of the parent MockCodeMonitorStore
+// instance is invoked.
+type CodeMonitorStoreCollectPendingActionJobsFunc struct {
+	defaultHook func(context.Context, *ActionJob) ([]*ActionJobMetadata, error)
+	hooks       []func(context.Context, *ActionJob) ([]*ActionJobMetadata, error)
+	history     []CodeMonitorStoreCollectPendingActionJobsFuncCall
+	mutex       sync.Mutex
+}
+
+// CollectPendingActionJobs delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) CollectPendingActionJobs(v0 context.Context, v1 *ActionJob) ([]*ActionJobMetadata, error) {
+	r0, r1 := m.CollectPendingActionJobsFunc.nextHook()(v0, v1)
+	m.CollectPendingActionJobsFunc.appendCall(CodeMonitorStoreCollectPendingActionJobsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// CollectPendingActionJobs method of the parent MockCodeMonitorStore
+// instance is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) SetDefaultHook(hook func(context.Context, *ActionJob) ([]*ActionJobMetadata, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CollectPendingActionJobs method of the parent MockCodeMonitorStore
+// instance invokes the hook at the front of the queue and discards it. After
+// the queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) PushHook(hook func(context.Context, *ActionJob) ([]*ActionJobMetadata, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) SetDefaultReturn(r0 []*ActionJobMetadata, r1 error) {
+	f.SetDefaultHook(func(context.Context, *ActionJob) ([]*ActionJobMetadata, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) PushReturn(r0 []*ActionJobMetadata, r1 error) {
+	f.PushHook(func(context.Context, *ActionJob) ([]*ActionJobMetadata, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) nextHook() func(context.Context, *ActionJob) ([]*ActionJobMetadata, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) appendCall(r0 CodeMonitorStoreCollectPendingActionJobsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreCollectPendingActionJobsFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreCollectPendingActionJobsFunc) History() []CodeMonitorStoreCollectPendingActionJobsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreCollectPendingActionJobsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreCollectPendingActionJobsFuncCall is an object that
+// describes an invocation of method CollectPendingActionJobs on an instance
+// of MockCodeMonitorStore.
+type CodeMonitorStoreCollectPendingActionJobsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *ActionJob
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*ActionJobMetadata
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreCollectPendingActionJobsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreCollectPendingActionJobsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeMonitorStoreCountActionJobsFunc describes the behavior when the
 // CountActionJobs method of the parent MockCodeMonitorStore instance is
 // invoked.
@@ -1786,36 +2130,37 @@ func (c CodeMonitorStoreCreateEmailActionFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeMonitorStoreCreateMonitorFunc describes the behavior when the
-// CreateMonitor method of the parent MockCodeMonitorStore instance is
-// invoked.
-type CodeMonitorStoreCreateMonitorFunc struct {
-	defaultHook func(context.Context, MonitorArgs) (*Monitor, error)
-	hooks       []func(context.Context, MonitorArgs) (*Monitor, error)
-	history     []CodeMonitorStoreCreateMonitorFuncCall
+// CodeMonitorStoreCreateIssueTrackerActionFunc describes the behavior when
+// the CreateIssueTrackerAction method of the parent MockCodeMonitorStore
+// instance is invoked.
+type CodeMonitorStoreCreateIssueTrackerActionFunc struct {
+	defaultHook func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)
+	hooks       []func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)
+	history     []CodeMonitorStoreCreateIssueTrackerActionFuncCall
 	mutex       sync.Mutex
 }
 
-// CreateMonitor delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockCodeMonitorStore) CreateMonitor(v0 context.Context, v1 MonitorArgs) (*Monitor, error) {
-	r0, r1 := m.CreateMonitorFunc.nextHook()(v0, v1)
-	m.CreateMonitorFunc.appendCall(CodeMonitorStoreCreateMonitorFuncCall{v0, v1, r0, r1})
+// CreateIssueTrackerAction delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) CreateIssueTrackerAction(v0 context.Context, v1 int64, v2 *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+	r0, r1 := m.CreateIssueTrackerActionFunc.nextHook()(v0, v1, v2)
+	m.CreateIssueTrackerActionFunc.appendCall(CodeMonitorStoreCreateIssueTrackerActionFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the CreateMonitor method
-// of the parent MockCodeMonitorStore instance is invoked and the hook queue
-// is empty.
-func (f *CodeMonitorStoreCreateMonitorFunc) SetDefaultHook(hook func(context.Context, MonitorArgs) (*Monitor, error)) {
+// SetDefaultHook sets function that is called when the
+// CreateIssueTrackerAction method of the parent MockCodeMonitorStore
+// instance is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) SetDefaultHook(hook func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// CreateMonitor method of the parent MockCodeMonitorStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *CodeMonitorStoreCreateMonitorFunc) PushHook(hook func(context.Context, MonitorArgs) (*Monitor, error)) {
+// CreateIssueTrackerAction method of the parent MockCodeMonitorStore
+// instance invokes the hook at the front of the queue and discards it. After
+// the queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) PushHook(hook func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1823,20 +2168,20 @@ func (f *CodeMonitorStoreCreateMonitorFunc) PushHook(hook func(context.Context,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeMonitorStoreCreateMonitorFunc) SetDefaultReturn(r0 *Monitor, r1 error) {
-	f.SetDefaultHook(func(context.Context, MonitorArgs) (*Monitor, error) {
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) SetDefaultReturn(r0 *IssueTrackerAction, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeMonitorStoreCreateMonitorFunc) PushReturn(r0 *Monitor, r1 error) {
-	f.PushHook(func(context.Context, MonitorArgs) (*Monitor, error) {
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) PushReturn(r0 *IssueTrackerAction, r1 error) {
+	f.PushHook(func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
 		return r0, r1
 	})
 }
 
-func (f *CodeMonitorStoreCreateMonitorFunc) nextHook() func(context.Context, MonitorArgs) (*Monitor, error) {
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) nextHook() func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1849,36 +2194,40 @@ func (f *CodeMonitorStoreCreateMonitorFunc) nextHook() func(context.Context, Mon
 	return hook
 }
 
-func (f *CodeMonitorStoreCreateMonitorFunc) appendCall(r0 CodeMonitorStoreCreateMonitorFuncCall) {
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) appendCall(r0 CodeMonitorStoreCreateIssueTrackerActionFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeMonitorStoreCreateMonitorFuncCall
-// objects describing the invocations of this function.
-func (f *CodeMonitorStoreCreateMonitorFunc) History() []CodeMonitorStoreCreateMonitorFuncCall {
+// History returns a sequence of
+// CodeMonitorStoreCreateIssueTrackerActionFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreCreateIssueTrackerActionFunc) History() []CodeMonitorStoreCreateIssueTrackerActionFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreCreateMonitorFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreCreateIssueTrackerActionFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreCreateMonitorFuncCall is an object that describes an
-// invocation of method CreateMonitor on an instance of
-// MockCodeMonitorStore.
-type CodeMonitorStoreCreateMonitorFuncCall struct {
+// CodeMonitorStoreCreateIssueTrackerActionFuncCall is an object that
+// describes an invocation of method CreateIssueTrackerAction on an instance
+// of MockCodeMonitorStore.
+type CodeMonitorStoreCreateIssueTrackerActionFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 MonitorArgs
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 *IssueTrackerActionArgs
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *Monitor
+	Result0 *IssueTrackerAction
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -1886,41 +2235,151 @@ type CodeMonitorStoreCreateMonitorFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeMonitorStoreCreateMonitorFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
+func (c CodeMonitorStoreCreateIssueTrackerActionFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeMonitorStoreCreateMonitorFuncCall) Results() []interface{} {
+func (c CodeMonitorStoreCreateIssueTrackerActionFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeMonitorStoreCreateQueryTriggerFunc describes the behavior when the
-// CreateQueryTrigger method of the parent MockCodeMonitorStore instance is
+// CodeMonitorStoreCreateMonitorFunc describes the behavior when the
+// CreateMonitor method of the parent MockCodeMonitorStore instance is
 // invoked.
-type CodeMonitorStoreCreateQueryTriggerFunc struct {
-	defaultHook func(context.Context, int64, string) (*QueryTrigger, error)
-	hooks       []func(context.Context, int64, string) (*QueryTrigger, error)
-	history     []CodeMonitorStoreCreateQueryTriggerFuncCall
+type CodeMonitorStoreCreateMonitorFunc struct {
+	defaultHook func(context.Context, MonitorArgs) (*Monitor, error)
+	hooks       []func(context.Context, MonitorArgs) (*Monitor, error)
+	history     []CodeMonitorStoreCreateMonitorFuncCall
 	mutex       sync.Mutex
 }
 
-// CreateQueryTrigger delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockCodeMonitorStore) CreateQueryTrigger(v0 context.Context, v1 int64, v2 string) (*QueryTrigger, error) {
-	r0, r1 := m.CreateQueryTriggerFunc.nextHook()(v0, v1, v2)
-	m.CreateQueryTriggerFunc.appendCall(CodeMonitorStoreCreateQueryTriggerFuncCall{v0, v1, v2, r0, r1})
+// CreateMonitor delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) CreateMonitor(v0 context.Context, v1 MonitorArgs) (*Monitor, error) {
+	r0, r1 := m.CreateMonitorFunc.nextHook()(v0, v1)
+	m.CreateMonitorFunc.appendCall(CodeMonitorStoreCreateMonitorFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the CreateQueryTrigger
-// method of the parent MockCodeMonitorStore instance is invoked and the
-// hook queue is empty.
-func (f *CodeMonitorStoreCreateQueryTriggerFunc) SetDefaultHook(hook func(context.Context, int64, string) (*QueryTrigger, error)) {
-	f.defaultHook = hook
-}
-
+// SetDefaultHook sets function that is called when the CreateMonitor method
+// of the parent MockCodeMonitorStore instance is invoked and the hook queue
+// is empty.
+func (f *CodeMonitorStoreCreateMonitorFunc) SetDefaultHook(hook func(context.Context, MonitorArgs) (*Monitor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CreateMonitor method of the parent MockCodeMonitorStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeMonitorStoreCreateMonitorFunc) PushHook(hook func(context.Context, MonitorArgs) (*Monitor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreCreateMonitorFunc) SetDefaultReturn(r0 *Monitor, r1 error) {
+	f.SetDefaultHook(func(context.Context, MonitorArgs) (*Monitor, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreCreateMonitorFunc) PushReturn(r0 *Monitor, r1 error) {
+	f.PushHook(func(context.Context, MonitorArgs) (*Monitor, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreCreateMonitorFunc) nextHook() func(context.Context, MonitorArgs) (*Monitor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreCreateMonitorFunc) appendCall(r0 CodeMonitorStoreCreateMonitorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreCreateMonitorFuncCall
+// objects describing the invocations of this function.
+func (f *CodeMonitorStoreCreateMonitorFunc) History() []CodeMonitorStoreCreateMonitorFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreCreateMonitorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreCreateMonitorFuncCall is an object that describes an
+// invocation of method CreateMonitor on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreCreateMonitorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 MonitorArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *Monitor
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreCreateMonitorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreCreateMonitorFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreCreateQueryTriggerFunc describes the behavior when the
+// CreateQueryTrigger method of the parent MockCodeMonitorStore instance is
+// invoked.
+type CodeMonitorStoreCreateQueryTriggerFunc struct {
+	defaultHook func(context.Context, int64, string) (*QueryTrigger, error)
+	hooks       []func(context.Context, int64, string) (*QueryTrigger, error)
+	history     []CodeMonitorStoreCreateQueryTriggerFuncCall
+	mutex       sync.Mutex
+}
+
+// CreateQueryTrigger delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) CreateQueryTrigger(v0 context.Context, v1 int64, v2 string) (*QueryTrigger, error) {
+	r0, r1 := m.CreateQueryTriggerFunc.nextHook()(v0, v1, v2)
+	m.CreateQueryTriggerFunc.appendCall(CodeMonitorStoreCreateQueryTriggerFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CreateQueryTrigger
+// method of the parent MockCodeMonitorStore instance is invoked and the
+// hook queue is empty.
+func (f *CodeMonitorStoreCreateQueryTriggerFunc) SetDefaultHook(hook func(context.Context, int64, string) (*QueryTrigger, error)) {
+	f.defaultHook = hook
+}
+
 // PushHook adds a function to the end of hook queue. Each invocation of the
 // CreateQueryTrigger method of the parent MockCodeMonitorStore instance
 // invokes the hook at the front of the queue and discards it. After the
@@ -2368,6 +2827,115 @@ func (c CodeMonitorStoreCreateWebhookActionFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeMonitorStoreDeleteContentSnapshotsFunc describes the behavior when the
+// DeleteContentSnapshots method of the parent MockCodeMonitorStore instance
+// is invoked.
+type CodeMonitorStoreDeleteContentSnapshotsFunc struct {
+	defaultHook func(context.Context, int64) error
+	hooks       []func(context.Context, int64) error
+	history     []CodeMonitorStoreDeleteContentSnapshotsFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteContentSnapshots delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) DeleteContentSnapshots(v0 context.Context, v1 int64) error {
+	r0 := m.DeleteContentSnapshotsFunc.nextHook()(v0, v1)
+	m.DeleteContentSnapshotsFunc.appendCall(CodeMonitorStoreDeleteContentSnapshotsFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// DeleteContentSnapshots method of the parent MockCodeMonitorStore instance
+// is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) SetDefaultHook(hook func(context.Context, int64) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteContentSnapshots method of the parent MockCodeMonitorStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) PushHook(hook func(context.Context, int64) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64) error {
+		return r0
+	})
+}
+
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) nextHook() func(context.Context, int64) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) appendCall(r0 CodeMonitorStoreDeleteContentSnapshotsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreDeleteContentSnapshotsFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreDeleteContentSnapshotsFunc) History() []CodeMonitorStoreDeleteContentSnapshotsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreDeleteContentSnapshotsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreDeleteContentSnapshotsFuncCall is an object that describes
+// an invocation of method DeleteContentSnapshots on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreDeleteContentSnapshotsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreDeleteContentSnapshotsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreDeleteContentSnapshotsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // CodeMonitorStoreDeleteEmailActionsFunc describes the behavior when the
 // DeleteEmailActions method of the parent MockCodeMonitorStore instance is
 // invoked.
@@ -2479,36 +3047,37 @@ func (c CodeMonitorStoreDeleteEmailActionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// CodeMonitorStoreDeleteMonitorFunc describes the behavior when the
-// DeleteMonitor method of the parent MockCodeMonitorStore instance is
-// invoked.
-type CodeMonitorStoreDeleteMonitorFunc struct {
-	defaultHook func(context.Context, int64) error
-	hooks       []func(context.Context, int64) error
-	history     []CodeMonitorStoreDeleteMonitorFuncCall
+// CodeMonitorStoreDeleteIssueTrackerActionsFunc describes the behavior when
+// the DeleteIssueTrackerActions method of the parent MockCodeMonitorStore
+// instance is invoked.
+type CodeMonitorStoreDeleteIssueTrackerActionsFunc struct {
+	defaultHook func(context.Context, int64, ...int64) error
+	hooks       []func(context.Context, int64, ...int64) error
+	history     []CodeMonitorStoreDeleteIssueTrackerActionsFuncCall
 	mutex       sync.Mutex
 }
 
-// DeleteMonitor delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockCodeMonitorStore) DeleteMonitor(v0 context.Context, v1 int64) error {
-	r0 := m.DeleteMonitorFunc.nextHook()(v0, v1)
-	m.DeleteMonitorFunc.appendCall(CodeMonitorStoreDeleteMonitorFuncCall{v0, v1, r0})
+// DeleteIssueTrackerActions delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) DeleteIssueTrackerActions(v0 context.Context, v1 int64, v2 ...int64) error {
+	r0 := m.DeleteIssueTrackerActionsFunc.nextHook()(v0, v1, v2...)
+	m.DeleteIssueTrackerActionsFunc.appendCall(CodeMonitorStoreDeleteIssueTrackerActionsFuncCall{v0, v1, v2, r0})
 	return r0
 }
 
-// SetDefaultHook sets function that is called when the DeleteMonitor method
-// of the parent MockCodeMonitorStore instance is invoked and the hook queue
-// is empty.
-func (f *CodeMonitorStoreDeleteMonitorFunc) SetDefaultHook(hook func(context.Context, int64) error) {
+// SetDefaultHook sets function that is called when the
+// DeleteIssueTrackerActions method of the parent MockCodeMonitorStore
+// instance is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) SetDefaultHook(hook func(context.Context, int64, ...int64) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// DeleteMonitor method of the parent MockCodeMonitorStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *CodeMonitorStoreDeleteMonitorFunc) PushHook(hook func(context.Context, int64) error) {
+// DeleteIssueTrackerActions method of the parent MockCodeMonitorStore
+// instance invokes the hook at the front of the queue and discards it. After
+// the queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) PushHook(hook func(context.Context, int64, ...int64) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2516,20 +3085,20 @@ func (f *CodeMonitorStoreDeleteMonitorFunc) PushHook(hook func(context.Context,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeMonitorStoreDeleteMonitorFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int64) error {
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, ...int64) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeMonitorStoreDeleteMonitorFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int64) error {
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, ...int64) error {
 		return r0
 	})
 }
 
-func (f *CodeMonitorStoreDeleteMonitorFunc) nextHook() func(context.Context, int64) error {
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) nextHook() func(context.Context, int64, ...int64) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2542,44 +3111,162 @@ func (f *CodeMonitorStoreDeleteMonitorFunc) nextHook() func(context.Context, int
 	return hook
 }
 
-func (f *CodeMonitorStoreDeleteMonitorFunc) appendCall(r0 CodeMonitorStoreDeleteMonitorFuncCall) {
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) appendCall(r0 CodeMonitorStoreDeleteIssueTrackerActionsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeMonitorStoreDeleteMonitorFuncCall
-// objects describing the invocations of this function.
-func (f *CodeMonitorStoreDeleteMonitorFunc) History() []CodeMonitorStoreDeleteMonitorFuncCall {
+// History returns a sequence of
+// CodeMonitorStoreDeleteIssueTrackerActionsFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreDeleteIssueTrackerActionsFunc) History() []CodeMonitorStoreDeleteIssueTrackerActionsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreDeleteMonitorFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreDeleteIssueTrackerActionsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreDeleteMonitorFuncCall is an object that describes an
-// invocation of method DeleteMonitor on an instance of
-// MockCodeMonitorStore.
-type CodeMonitorStoreDeleteMonitorFuncCall struct {
+// CodeMonitorStoreDeleteIssueTrackerActionsFuncCall is an object that
+// describes an invocation of method DeleteIssueTrackerActions on an instance
+// of MockCodeMonitorStore.
+type CodeMonitorStoreDeleteIssueTrackerActionsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int64
+	// Arg2 is a slice containing the values of the variadic arguments
+	// passed to this method invocation.
+	Arg2 []int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
 }
 
 // Args returns an interface slice containing the arguments of this
-// invocation.
-func (c CodeMonitorStoreDeleteMonitorFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
-}
-
+// invocation. The variadic slice argument is flattened in this array such
+// that one positional argument and three variadic arguments would result in
+// a slice of four, not two.
+func (c CodeMonitorStoreDeleteIssueTrackerActionsFuncCall) Args() []interface{} {
+	trailing := []interface{}{}
+	for _, val := range c.Arg2 {
+		trailing = append(trailing, val)
+	}
+
+	return append([]interface{}{c.Arg0, c.Arg1}, trailing...)
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreDeleteIssueTrackerActionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// CodeMonitorStoreDeleteMonitorFunc describes the behavior when the
+// DeleteMonitor method of the parent MockCodeMonitorStore instance is
+// invoked.
+type CodeMonitorStoreDeleteMonitorFunc struct {
+	defaultHook func(context.Context, int64) error
+	hooks       []func(context.Context, int64) error
+	history     []CodeMonitorStoreDeleteMonitorFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteMonitor delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) DeleteMonitor(v0 context.Context, v1 int64) error {
+	r0 := m.DeleteMonitorFunc.nextHook()(v0, v1)
+	m.DeleteMonitorFunc.appendCall(CodeMonitorStoreDeleteMonitorFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the DeleteMonitor method
+// of the parent MockCodeMonitorStore instance is invoked and the hook queue
+// is empty.
+func (f *CodeMonitorStoreDeleteMonitorFunc) SetDefaultHook(hook func(context.Context, int64) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteMonitor method of the parent MockCodeMonitorStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeMonitorStoreDeleteMonitorFunc) PushHook(hook func(context.Context, int64) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreDeleteMonitorFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreDeleteMonitorFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64) error {
+		return r0
+	})
+}
+
+func (f *CodeMonitorStoreDeleteMonitorFunc) nextHook() func(context.Context, int64) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreDeleteMonitorFunc) appendCall(r0 CodeMonitorStoreDeleteMonitorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreDeleteMonitorFuncCall
+// objects describing the invocations of this function.
+func (f *CodeMonitorStoreDeleteMonitorFunc) History() []CodeMonitorStoreDeleteMonitorFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreDeleteMonitorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreDeleteMonitorFuncCall is an object that describes an
+// invocation of method DeleteMonitor on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreDeleteMonitorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreDeleteMonitorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
 // Results returns an interface slice containing the results of this
 // invocation.
 func (c CodeMonitorStoreDeleteMonitorFuncCall) Results() []interface{} {
@@ -3632,7 +4319,341 @@ func (f *CodeMonitorStoreGetActionJobMetadataFunc) PushReturn(r0 *ActionJobMetad
 	})
 }
 
-func (f *CodeMonitorStoreGetActionJobMetadataFunc) nextHook() func(context.Context, int32) (*ActionJobMetadata, error) {
+func (f *CodeMonitorStoreGetActionJobMetadataFunc) nextHook() func(context.Context, int32) (*ActionJobMetadata, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreGetActionJobMetadataFunc) appendCall(r0 CodeMonitorStoreGetActionJobMetadataFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreGetActionJobMetadataFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreGetActionJobMetadataFunc) History() []CodeMonitorStoreGetActionJobMetadataFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreGetActionJobMetadataFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreGetActionJobMetadataFuncCall is an object that describes
+// an invocation of method GetActionJobMetadata on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreGetActionJobMetadataFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *ActionJobMetadata
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreGetActionJobMetadataFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreGetActionJobMetadataFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreGetContentSnapshotFunc describes the behavior when the
+// GetContentSnapshot method of the parent MockCodeMonitorStore instance is
+// invoked.
+type CodeMonitorStoreGetContentSnapshotFunc struct {
+	defaultHook func(context.Context, int64, api.RepoID) (*ContentSnapshot, error)
+	hooks       []func(context.Context, int64, api.RepoID) (*ContentSnapshot, error)
+	history     []CodeMonitorStoreGetContentSnapshotFuncCall
+	mutex       sync.Mutex
+}
+
+// GetContentSnapshot delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) GetContentSnapshot(v0 context.Context, v1 int64, v2 api.RepoID) (*ContentSnapshot, error) {
+	r0, r1 := m.GetContentSnapshotFunc.nextHook()(v0, v1, v2)
+	m.GetContentSnapshotFunc.appendCall(CodeMonitorStoreGetContentSnapshotFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetContentSnapshot
+// method of the parent MockCodeMonitorStore instance is invoked and the hook
+// queue is empty.
+func (f *CodeMonitorStoreGetContentSnapshotFunc) SetDefaultHook(hook func(context.Context, int64, api.RepoID) (*ContentSnapshot, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetContentSnapshot method of the parent MockCodeMonitorStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreGetContentSnapshotFunc) PushHook(hook func(context.Context, int64, api.RepoID) (*ContentSnapshot, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreGetContentSnapshotFunc) SetDefaultReturn(r0 *ContentSnapshot, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, api.RepoID) (*ContentSnapshot, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreGetContentSnapshotFunc) PushReturn(r0 *ContentSnapshot, r1 error) {
+	f.PushHook(func(context.Context, int64, api.RepoID) (*ContentSnapshot, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreGetContentSnapshotFunc) nextHook() func(context.Context, int64, api.RepoID) (*ContentSnapshot, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreGetContentSnapshotFunc) appendCall(r0 CodeMonitorStoreGetContentSnapshotFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreGetContentSnapshotFuncCall
+// objects describing the invocations of this function.
+func (f *CodeMonitorStoreGetContentSnapshotFunc) History() []CodeMonitorStoreGetContentSnapshotFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreGetContentSnapshotFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreGetContentSnapshotFuncCall is an object that describes an
+// invocation of method GetContentSnapshot on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreGetContentSnapshotFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *ContentSnapshot
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreGetContentSnapshotFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreGetContentSnapshotFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreGetEmailActionFunc describes the behavior when the
+// GetEmailAction method of the parent MockCodeMonitorStore instance is
+// invoked.
+type CodeMonitorStoreGetEmailActionFunc struct {
+	defaultHook func(context.Context, int64) (*EmailAction, error)
+	hooks       []func(context.Context, int64) (*EmailAction, error)
+	history     []CodeMonitorStoreGetEmailActionFuncCall
+	mutex       sync.Mutex
+}
+
+// GetEmailAction delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) GetEmailAction(v0 context.Context, v1 int64) (*EmailAction, error) {
+	r0, r1 := m.GetEmailActionFunc.nextHook()(v0, v1)
+	m.GetEmailActionFunc.appendCall(CodeMonitorStoreGetEmailActionFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetEmailAction
+// method of the parent MockCodeMonitorStore instance is invoked and the
+// hook queue is empty.
+func (f *CodeMonitorStoreGetEmailActionFunc) SetDefaultHook(hook func(context.Context, int64) (*EmailAction, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetEmailAction method of the parent MockCodeMonitorStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeMonitorStoreGetEmailActionFunc) PushHook(hook func(context.Context, int64) (*EmailAction, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreGetEmailActionFunc) SetDefaultReturn(r0 *EmailAction, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) (*EmailAction, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreGetEmailActionFunc) PushReturn(r0 *EmailAction, r1 error) {
+	f.PushHook(func(context.Context, int64) (*EmailAction, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreGetEmailActionFunc) nextHook() func(context.Context, int64) (*EmailAction, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreGetEmailActionFunc) appendCall(r0 CodeMonitorStoreGetEmailActionFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreGetEmailActionFuncCall
+// objects describing the invocations of this function.
+func (f *CodeMonitorStoreGetEmailActionFunc) History() []CodeMonitorStoreGetEmailActionFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreGetEmailActionFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreGetEmailActionFuncCall is an object that describes an
+// invocation of method GetEmailAction on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreGetEmailActionFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *EmailAction
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreGetEmailActionFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreGetEmailActionFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreGetIssueFunc describes the behavior when the GetIssue
+// method of the parent MockCodeMonitorStore instance is invoked.
+type CodeMonitorStoreGetIssueFunc struct {
+	defaultHook func(context.Context, int64, string) (*Issue, error)
+	hooks       []func(context.Context, int64, string) (*Issue, error)
+	history     []CodeMonitorStoreGetIssueFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIssue delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) GetIssue(v0 context.Context, v1 int64, v2 string) (*Issue, error) {
+	r0, r1 := m.GetIssueFunc.nextHook()(v0, v1, v2)
+	m.GetIssueFunc.appendCall(CodeMonitorStoreGetIssueFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetIssue method of
+// the parent MockCodeMonitorStore instance is invoked and the hook queue is
+// empty.
+func (f *CodeMonitorStoreGetIssueFunc) SetDefaultHook(hook func(context.Context, int64, string) (*Issue, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIssue method of the parent MockCodeMonitorStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeMonitorStoreGetIssueFunc) PushHook(hook func(context.Context, int64, string) (*Issue, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreGetIssueFunc) SetDefaultReturn(r0 *Issue, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, string) (*Issue, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreGetIssueFunc) PushReturn(r0 *Issue, r1 error) {
+	f.PushHook(func(context.Context, int64, string) (*Issue, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreGetIssueFunc) nextHook() func(context.Context, int64, string) (*Issue, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -3645,37 +4666,38 @@ func (f *CodeMonitorStoreGetActionJobMetadataFunc) nextHook() func(context.Conte
 	return hook
 }
 
-func (f *CodeMonitorStoreGetActionJobMetadataFunc) appendCall(r0 CodeMonitorStoreGetActionJobMetadataFuncCall) {
+func (f *CodeMonitorStoreGetIssueFunc) appendCall(r0 CodeMonitorStoreGetIssueFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of
-// CodeMonitorStoreGetActionJobMetadataFuncCall objects describing the
-// invocations of this function.
-func (f *CodeMonitorStoreGetActionJobMetadataFunc) History() []CodeMonitorStoreGetActionJobMetadataFuncCall {
+// History returns a sequence of CodeMonitorStoreGetIssueFuncCall objects
+// describing the invocations of this function.
+func (f *CodeMonitorStoreGetIssueFunc) History() []CodeMonitorStoreGetIssueFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreGetActionJobMetadataFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreGetIssueFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreGetActionJobMetadataFuncCall is an object that describes
-// an invocation of method GetActionJobMetadata on an instance of
-// MockCodeMonitorStore.
-type CodeMonitorStoreGetActionJobMetadataFuncCall struct {
+// CodeMonitorStoreGetIssueFuncCall is an object that describes an invocation
+// of method GetIssue on an instance of MockCodeMonitorStore.
+type CodeMonitorStoreGetIssueFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int32
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *ActionJobMetadata
+	Result0 *Issue
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -3683,46 +4705,47 @@ type CodeMonitorStoreGetActionJobMetadataFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeMonitorStoreGetActionJobMetadataFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
+func (c CodeMonitorStoreGetIssueFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeMonitorStoreGetActionJobMetadataFuncCall) Results() []interface{} {
+func (c CodeMonitorStoreGetIssueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeMonitorStoreGetEmailActionFunc describes the behavior when the
-// GetEmailAction method of the parent MockCodeMonitorStore instance is
-// invoked.
-type CodeMonitorStoreGetEmailActionFunc struct {
-	defaultHook func(context.Context, int64) (*EmailAction, error)
-	hooks       []func(context.Context, int64) (*EmailAction, error)
-	history     []CodeMonitorStoreGetEmailActionFuncCall
+// CodeMonitorStoreGetIssueTrackerActionFunc describes the behavior when the
+// GetIssueTrackerAction method of the parent MockCodeMonitorStore instance
+// is invoked.
+type CodeMonitorStoreGetIssueTrackerActionFunc struct {
+	defaultHook func(context.Context, int64) (*IssueTrackerAction, error)
+	hooks       []func(context.Context, int64) (*IssueTrackerAction, error)
+	history     []CodeMonitorStoreGetIssueTrackerActionFuncCall
 	mutex       sync.Mutex
 }
 
-// GetEmailAction delegates to the next hook function in the queue and
+// GetIssueTrackerAction delegates to the next hook function in the queue and
 // stores the parameter and result values of this invocation.
-func (m *MockCodeMonitorStore) GetEmailAction(v0 context.Context, v1 int64) (*EmailAction, error) {
-	r0, r1 := m.GetEmailActionFunc.nextHook()(v0, v1)
-	m.GetEmailActionFunc.appendCall(CodeMonitorStoreGetEmailActionFuncCall{v0, v1, r0, r1})
+func (m *MockCodeMonitorStore) GetIssueTrackerAction(v0 context.Context, v1 int64) (*IssueTrackerAction, error) {
+	r0, r1 := m.GetIssueTrackerActionFunc.nextHook()(v0, v1)
+	m.GetIssueTrackerActionFunc.appendCall(CodeMonitorStoreGetIssueTrackerActionFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetEmailAction
-// method of the parent MockCodeMonitorStore instance is invoked and the
-// hook queue is empty.
-func (f *CodeMonitorStoreGetEmailActionFunc) SetDefaultHook(hook func(context.Context, int64) (*EmailAction, error)) {
+// SetDefaultHook sets function that is called when the GetIssueTrackerAction
+// method of the parent MockCodeMonitorStore instance is invoked and the hook
+// queue is empty.
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) SetDefaultHook(hook func(context.Context, int64) (*IssueTrackerAction, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetEmailAction method of the parent MockCodeMonitorStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *CodeMonitorStoreGetEmailActionFunc) PushHook(hook func(context.Context, int64) (*EmailAction, error)) {
+// GetIssueTrackerAction method of the parent MockCodeMonitorStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) PushHook(hook func(context.Context, int64) (*IssueTrackerAction, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -3730,20 +4753,20 @@ func (f *CodeMonitorStoreGetEmailActionFunc) PushHook(hook func(context.Context,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeMonitorStoreGetEmailActionFunc) SetDefaultReturn(r0 *EmailAction, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64) (*EmailAction, error) {
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) SetDefaultReturn(r0 *IssueTrackerAction, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) (*IssueTrackerAction, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeMonitorStoreGetEmailActionFunc) PushReturn(r0 *EmailAction, r1 error) {
-	f.PushHook(func(context.Context, int64) (*EmailAction, error) {
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) PushReturn(r0 *IssueTrackerAction, r1 error) {
+	f.PushHook(func(context.Context, int64) (*IssueTrackerAction, error) {
 		return r0, r1
 	})
 }
 
-func (f *CodeMonitorStoreGetEmailActionFunc) nextHook() func(context.Context, int64) (*EmailAction, error) {
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) nextHook() func(context.Context, int64) (*IssueTrackerAction, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -3756,27 +4779,28 @@ func (f *CodeMonitorStoreGetEmailActionFunc) nextHook() func(context.Context, in
 	return hook
 }
 
-func (f *CodeMonitorStoreGetEmailActionFunc) appendCall(r0 CodeMonitorStoreGetEmailActionFuncCall) {
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) appendCall(r0 CodeMonitorStoreGetIssueTrackerActionFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeMonitorStoreGetEmailActionFuncCall
-// objects describing the invocations of this function.
-func (f *CodeMonitorStoreGetEmailActionFunc) History() []CodeMonitorStoreGetEmailActionFuncCall {
+// History returns a sequence of
+// CodeMonitorStoreGetIssueTrackerActionFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreGetIssueTrackerActionFunc) History() []CodeMonitorStoreGetIssueTrackerActionFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreGetEmailActionFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreGetIssueTrackerActionFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreGetEmailActionFuncCall is an object that describes an
-// invocation of method GetEmailAction on an instance of
+// CodeMonitorStoreGetIssueTrackerActionFuncCall is an object that describes
+// an invocation of method GetIssueTrackerAction on an instance of
 // MockCodeMonitorStore.
-type CodeMonitorStoreGetEmailActionFuncCall struct {
+type CodeMonitorStoreGetIssueTrackerActionFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -3785,7 +4809,7 @@ type CodeMonitorStoreGetEmailActionFuncCall struct {
 	Arg1 int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *EmailAction
+	Result0 *IssueTrackerAction
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -3793,13 +4817,13 @@ type CodeMonitorStoreGetEmailActionFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeMonitorStoreGetEmailActionFuncCall) Args() []interface{} {
+func (c CodeMonitorStoreGetIssueTrackerActionFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeMonitorStoreGetEmailActionFuncCall) Results() []interface{} {
+func (c CodeMonitorStoreGetIssueTrackerActionFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -4703,15 +5727,238 @@ func (m *MockCodeMonitorStore) ListActionJobs(v0 context.Context, v1 ListActionJ
 // SetDefaultHook sets function that is called when the ListActionJobs
 // method of the parent MockCodeMonitorStore instance is invoked and the
 // hook queue is empty.
-func (f *CodeMonitorStoreListActionJobsFunc) SetDefaultHook(hook func(context.Context, ListActionJobsOpts) ([]*ActionJob, error)) {
+func (f *CodeMonitorStoreListActionJobsFunc) SetDefaultHook(hook func(context.Context, ListActionJobsOpts) ([]*ActionJob, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListActionJobs method of the parent MockCodeMonitorStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeMonitorStoreListActionJobsFunc) PushHook(hook func(context.Context, ListActionJobsOpts) ([]*ActionJob, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreListActionJobsFunc) SetDefaultReturn(r0 []*ActionJob, r1 error) {
+	f.SetDefaultHook(func(context.Context, ListActionJobsOpts) ([]*ActionJob, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreListActionJobsFunc) PushReturn(r0 []*ActionJob, r1 error) {
+	f.PushHook(func(context.Context, ListActionJobsOpts) ([]*ActionJob, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreListActionJobsFunc) nextHook() func(context.Context, ListActionJobsOpts) ([]*ActionJob, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreListActionJobsFunc) appendCall(r0 CodeMonitorStoreListActionJobsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreListActionJobsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeMonitorStoreListActionJobsFunc) History() []CodeMonitorStoreListActionJobsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreListActionJobsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreListActionJobsFuncCall is an object that describes an
+// invocation of method ListActionJobs on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreListActionJobsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 ListActionJobsOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*ActionJob
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreListActionJobsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreListActionJobsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreListContentSnapshotReposFunc describes the behavior when
+// the ListContentSnapshotRepos method of the parent MockCodeMonitorStore
+// instance is invoked.
+type CodeMonitorStoreListContentSnapshotReposFunc struct {
+	defaultHook func(context.Context, int64) ([]api.RepoID, error)
+	hooks       []func(context.Context, int64) ([]api.RepoID, error)
+	history     []CodeMonitorStoreListContentSnapshotReposFuncCall
+	mutex       sync.Mutex
+}
+
+// ListContentSnapshotRepos delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) ListContentSnapshotRepos(v0 context.Context, v1 int64) ([]api.RepoID, error) {
+	r0, r1 := m.ListContentSnapshotReposFunc.nextHook()(v0, v1)
+	m.ListContentSnapshotReposFunc.appendCall(CodeMonitorStoreListContentSnapshotReposFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// ListContentSnapshotRepos method of the parent MockCodeMonitorStore
+// instance is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) SetDefaultHook(hook func(context.Context, int64) ([]api.RepoID, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListContentSnapshotRepos method of the parent MockCodeMonitorStore
+// instance invokes the hook at the front of the queue and discards it. After
+// the queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) PushHook(hook func(context.Context, int64) ([]api.RepoID, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) SetDefaultReturn(r0 []api.RepoID, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) ([]api.RepoID, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) PushReturn(r0 []api.RepoID, r1 error) {
+	f.PushHook(func(context.Context, int64) ([]api.RepoID, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) nextHook() func(context.Context, int64) ([]api.RepoID, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) appendCall(r0 CodeMonitorStoreListContentSnapshotReposFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreListContentSnapshotReposFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreListContentSnapshotReposFunc) History() []CodeMonitorStoreListContentSnapshotReposFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreListContentSnapshotReposFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreListContentSnapshotReposFuncCall is an object that
+// describes an invocation of method ListContentSnapshotRepos on an instance
+// of MockCodeMonitorStore.
+type CodeMonitorStoreListContentSnapshotReposFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.RepoID
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreListContentSnapshotReposFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreListContentSnapshotReposFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreListEmailActionsFunc describes the behavior when the
+// ListEmailActions method of the parent MockCodeMonitorStore instance is
+// invoked.
+type CodeMonitorStoreListEmailActionsFunc struct {
+	defaultHook func(context.Context, ListActionsOpts) ([]*EmailAction, error)
+	hooks       []func(context.Context, ListActionsOpts) ([]*EmailAction, error)
+	history     []CodeMonitorStoreListEmailActionsFuncCall
+	mutex       sync.Mutex
+}
+
+// ListEmailActions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) ListEmailActions(v0 context.Context, v1 ListActionsOpts) ([]*EmailAction, error) {
+	r0, r1 := m.ListEmailActionsFunc.nextHook()(v0, v1)
+	m.ListEmailActionsFunc.appendCall(CodeMonitorStoreListEmailActionsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListEmailActions
+// method of the parent MockCodeMonitorStore instance is invoked and the
+// hook queue is empty.
+func (f *CodeMonitorStoreListEmailActionsFunc) SetDefaultHook(hook func(context.Context, ListActionsOpts) ([]*EmailAction, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// ListActionJobs method of the parent MockCodeMonitorStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *CodeMonitorStoreListActionJobsFunc) PushHook(hook func(context.Context, ListActionJobsOpts) ([]*ActionJob, error)) {
+// ListEmailActions method of the parent MockCodeMonitorStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreListEmailActionsFunc) PushHook(hook func(context.Context, ListActionsOpts) ([]*EmailAction, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -4719,20 +5966,20 @@ func (f *CodeMonitorStoreListActionJobsFunc) PushHook(hook func(context.Context,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeMonitorStoreListActionJobsFunc) SetDefaultReturn(r0 []*ActionJob, r1 error) {
-	f.SetDefaultHook(func(context.Context, ListActionJobsOpts) ([]*ActionJob, error) {
+func (f *CodeMonitorStoreListEmailActionsFunc) SetDefaultReturn(r0 []*EmailAction, r1 error) {
+	f.SetDefaultHook(func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeMonitorStoreListActionJobsFunc) PushReturn(r0 []*ActionJob, r1 error) {
-	f.PushHook(func(context.Context, ListActionJobsOpts) ([]*ActionJob, error) {
+func (f *CodeMonitorStoreListEmailActionsFunc) PushReturn(r0 []*EmailAction, r1 error) {
+	f.PushHook(func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
 		return r0, r1
 	})
 }
 
-func (f *CodeMonitorStoreListActionJobsFunc) nextHook() func(context.Context, ListActionJobsOpts) ([]*ActionJob, error) {
+func (f *CodeMonitorStoreListEmailActionsFunc) nextHook() func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -4745,36 +5992,36 @@ func (f *CodeMonitorStoreListActionJobsFunc) nextHook() func(context.Context, Li
 	return hook
 }
 
-func (f *CodeMonitorStoreListActionJobsFunc) appendCall(r0 CodeMonitorStoreListActionJobsFuncCall) {
+func (f *CodeMonitorStoreListEmailActionsFunc) appendCall(r0 CodeMonitorStoreListEmailActionsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeMonitorStoreListActionJobsFuncCall
+// History returns a sequence of CodeMonitorStoreListEmailActionsFuncCall
 // objects describing the invocations of this function.
-func (f *CodeMonitorStoreListActionJobsFunc) History() []CodeMonitorStoreListActionJobsFuncCall {
+func (f *CodeMonitorStoreListEmailActionsFunc) History() []CodeMonitorStoreListEmailActionsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreListActionJobsFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreListEmailActionsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreListActionJobsFuncCall is an object that describes an
-// invocation of method ListActionJobs on an instance of
+// CodeMonitorStoreListEmailActionsFuncCall is an object that describes an
+// invocation of method ListEmailActions on an instance of
 // MockCodeMonitorStore.
-type CodeMonitorStoreListActionJobsFuncCall struct {
+type CodeMonitorStoreListEmailActionsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 ListActionJobsOpts
+	Arg1 ListActionsOpts
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []*ActionJob
+	Result0 []*EmailAction
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -4782,47 +6029,47 @@ type CodeMonitorStoreListActionJobsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeMonitorStoreListActionJobsFuncCall) Args() []interface{} {
+func (c CodeMonitorStoreListEmailActionsFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeMonitorStoreListActionJobsFuncCall) Results() []interface{} {
+func (c CodeMonitorStoreListEmailActionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeMonitorStoreListEmailActionsFunc describes the behavior when the
-// ListEmailActions method of the parent MockCodeMonitorStore instance is
-// invoked.
-type CodeMonitorStoreListEmailActionsFunc struct {
-	defaultHook func(context.Context, ListActionsOpts) ([]*EmailAction, error)
-	hooks       []func(context.Context, ListActionsOpts) ([]*EmailAction, error)
-	history     []CodeMonitorStoreListEmailActionsFuncCall
+// CodeMonitorStoreListIssueTrackerActionsFunc describes the behavior when
+// the ListIssueTrackerActions method of the parent MockCodeMonitorStore
+// instance is invoked.
+type CodeMonitorStoreListIssueTrackerActionsFunc struct {
+	defaultHook func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error)
+	hooks       []func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error)
+	history     []CodeMonitorStoreListIssueTrackerActionsFuncCall
 	mutex       sync.Mutex
 }
 
-// ListEmailActions delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockCodeMonitorStore) ListEmailActions(v0 context.Context, v1 ListActionsOpts) ([]*EmailAction, error) {
-	r0, r1 := m.ListEmailActionsFunc.nextHook()(v0, v1)
-	m.ListEmailActionsFunc.appendCall(CodeMonitorStoreListEmailActionsFuncCall{v0, v1, r0, r1})
+// ListIssueTrackerActions delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) ListIssueTrackerActions(v0 context.Context, v1 ListActionsOpts) ([]*IssueTrackerAction, error) {
+	r0, r1 := m.ListIssueTrackerActionsFunc.nextHook()(v0, v1)
+	m.ListIssueTrackerActionsFunc.appendCall(CodeMonitorStoreListIssueTrackerActionsFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the ListEmailActions
-// method of the parent MockCodeMonitorStore instance is invoked and the
-// hook queue is empty.
-func (f *CodeMonitorStoreListEmailActionsFunc) SetDefaultHook(hook func(context.Context, ListActionsOpts) ([]*EmailAction, error)) {
+// SetDefaultHook sets function that is called when the
+// ListIssueTrackerActions method of the parent MockCodeMonitorStore instance
+// is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) SetDefaultHook(hook func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// ListEmailActions method of the parent MockCodeMonitorStore instance
+// ListIssueTrackerActions method of the parent MockCodeMonitorStore instance
 // invokes the hook at the front of the queue and discards it. After the
 // queue is empty, the default hook function is invoked for any future
 // action.
-func (f *CodeMonitorStoreListEmailActionsFunc) PushHook(hook func(context.Context, ListActionsOpts) ([]*EmailAction, error)) {
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) PushHook(hook func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -4830,20 +6077,20 @@ func (f *CodeMonitorStoreListEmailActionsFunc) PushHook(hook func(context.Contex
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeMonitorStoreListEmailActionsFunc) SetDefaultReturn(r0 []*EmailAction, r1 error) {
-	f.SetDefaultHook(func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) SetDefaultReturn(r0 []*IssueTrackerAction, r1 error) {
+	f.SetDefaultHook(func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeMonitorStoreListEmailActionsFunc) PushReturn(r0 []*EmailAction, r1 error) {
-	f.PushHook(func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) PushReturn(r0 []*IssueTrackerAction, r1 error) {
+	f.PushHook(func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error) {
 		return r0, r1
 	})
 }
 
-func (f *CodeMonitorStoreListEmailActionsFunc) nextHook() func(context.Context, ListActionsOpts) ([]*EmailAction, error) {
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) nextHook() func(context.Context, ListActionsOpts) ([]*IssueTrackerAction, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -4856,27 +6103,28 @@ func (f *CodeMonitorStoreListEmailActionsFunc) nextHook() func(context.Context,
 	return hook
 }
 
-func (f *CodeMonitorStoreListEmailActionsFunc) appendCall(r0 CodeMonitorStoreListEmailActionsFuncCall) {
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) appendCall(r0 CodeMonitorStoreListIssueTrackerActionsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeMonitorStoreListEmailActionsFuncCall
-// objects describing the invocations of this function.
-func (f *CodeMonitorStoreListEmailActionsFunc) History() []CodeMonitorStoreListEmailActionsFuncCall {
+// History returns a sequence of
+// CodeMonitorStoreListIssueTrackerActionsFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreListIssueTrackerActionsFunc) History() []CodeMonitorStoreListIssueTrackerActionsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreListEmailActionsFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreListIssueTrackerActionsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreListEmailActionsFuncCall is an object that describes an
-// invocation of method ListEmailActions on an instance of
-// MockCodeMonitorStore.
-type CodeMonitorStoreListEmailActionsFuncCall struct {
+// CodeMonitorStoreListIssueTrackerActionsFuncCall is an object that
+// describes an invocation of method ListIssueTrackerActions on an instance
+// of MockCodeMonitorStore.
+type CodeMonitorStoreListIssueTrackerActionsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -4885,7 +6133,7 @@ type CodeMonitorStoreListEmailActionsFuncCall struct {
 	Arg1 ListActionsOpts
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []*EmailAction
+	Result0 []*IssueTrackerAction
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -4893,13 +6141,13 @@ type CodeMonitorStoreListEmailActionsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeMonitorStoreListEmailActionsFuncCall) Args() []interface{} {
+func (c CodeMonitorStoreListIssueTrackerActionsFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeMonitorStoreListEmailActionsFuncCall) Results() []interface{} {
+func (c CodeMonitorStoreListIssueTrackerActionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -5885,6 +7133,120 @@ func (c CodeMonitorStoreTransactFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeMonitorStoreUpdateActionDeliveryFunc describes the behavior when the
+// UpdateActionDelivery method of the parent MockCodeMonitorStore instance is
+// invoked.
+type CodeMonitorStoreUpdateActionDeliveryFunc struct {
+	defaultHook func(context.Context, ActionTable, int64, ActionDelivery) error
+	hooks       []func(context.Context, ActionTable, int64, ActionDelivery) error
+	history     []CodeMonitorStoreUpdateActionDeliveryFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateActionDelivery delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) UpdateActionDelivery(v0 context.Context, v1 ActionTable, v2 int64, v3 ActionDelivery) error {
+	r0 := m.UpdateActionDeliveryFunc.nextHook()(v0, v1, v2, v3)
+	m.UpdateActionDeliveryFunc.appendCall(CodeMonitorStoreUpdateActionDeliveryFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the UpdateActionDelivery
+// method of the parent MockCodeMonitorStore instance is invoked and the hook
+// queue is empty.
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) SetDefaultHook(hook func(context.Context, ActionTable, int64, ActionDelivery) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateActionDelivery method of the parent MockCodeMonitorStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) PushHook(hook func(context.Context, ActionTable, int64, ActionDelivery) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, ActionTable, int64, ActionDelivery) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, ActionTable, int64, ActionDelivery) error {
+		return r0
+	})
+}
+
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) nextHook() func(context.Context, ActionTable, int64, ActionDelivery) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) appendCall(r0 CodeMonitorStoreUpdateActionDeliveryFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreUpdateActionDeliveryFuncCall
+// objects describing the invocations of this function.
+func (f *CodeMonitorStoreUpdateActionDeliveryFunc) History() []CodeMonitorStoreUpdateActionDeliveryFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreUpdateActionDeliveryFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreUpdateActionDeliveryFuncCall is an object that describes
+// an invocation of method UpdateActionDelivery on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreUpdateActionDeliveryFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 ActionTable
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int64
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 ActionDelivery
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreUpdateActionDeliveryFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreUpdateActionDeliveryFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // CodeMonitorStoreUpdateEmailActionFunc describes the behavior when the
 // UpdateEmailAction method of the parent MockCodeMonitorStore instance is
 // invoked.
@@ -5959,17 +7321,132 @@ func (f *CodeMonitorStoreUpdateEmailActionFunc) appendCall(r0 CodeMonitorStoreUp
 // objects describing the invocations of this function.
 func (f *CodeMonitorStoreUpdateEmailActionFunc) History() []CodeMonitorStoreUpdateEmailActionFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeMonitorStoreUpdateEmailActionFuncCall, len(f.history))
+	history := make([]CodeMonitorStoreUpdateEmailActionFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreUpdateEmailActionFuncCall is an object that describes an
+// invocation of method UpdateEmailAction on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreUpdateEmailActionFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 *EmailActionArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *EmailAction
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreUpdateEmailActionFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreUpdateEmailActionFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeMonitorStoreUpdateIssueTrackerActionFunc describes the behavior when
+// the UpdateIssueTrackerAction method of the parent MockCodeMonitorStore
+// instance is invoked.
+type CodeMonitorStoreUpdateIssueTrackerActionFunc struct {
+	defaultHook func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)
+	hooks       []func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)
+	history     []CodeMonitorStoreUpdateIssueTrackerActionFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateIssueTrackerAction delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) UpdateIssueTrackerAction(v0 context.Context, v1 int64, v2 *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+	r0, r1 := m.UpdateIssueTrackerActionFunc.nextHook()(v0, v1, v2)
+	m.UpdateIssueTrackerActionFunc.appendCall(CodeMonitorStoreUpdateIssueTrackerActionFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// UpdateIssueTrackerAction method of the parent MockCodeMonitorStore
+// instance is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) SetDefaultHook(hook func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateIssueTrackerAction method of the parent MockCodeMonitorStore
+// instance invokes the hook at the front of the queue and discards it. After
+// the queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) PushHook(hook func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) SetDefaultReturn(r0 *IssueTrackerAction, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) PushReturn(r0 *IssueTrackerAction, r1 error) {
+	f.PushHook(func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) nextHook() func(context.Context, int64, *IssueTrackerActionArgs) (*IssueTrackerAction, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) appendCall(r0 CodeMonitorStoreUpdateIssueTrackerActionFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreUpdateIssueTrackerActionFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreUpdateIssueTrackerActionFunc) History() []CodeMonitorStoreUpdateIssueTrackerActionFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreUpdateIssueTrackerActionFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeMonitorStoreUpdateEmailActionFuncCall is an object that describes an
-// invocation of method UpdateEmailAction on an instance of
-// MockCodeMonitorStore.
-type CodeMonitorStoreUpdateEmailActionFuncCall struct {
+// CodeMonitorStoreUpdateIssueTrackerActionFuncCall is an object that
+// describes an invocation of method UpdateIssueTrackerAction on an instance
+// of MockCodeMonitorStore.
+type CodeMonitorStoreUpdateIssueTrackerActionFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -5978,10 +7455,10 @@ type CodeMonitorStoreUpdateEmailActionFuncCall struct {
 	Arg1 int64
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 *EmailActionArgs
+	Arg2 *IssueTrackerActionArgs
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *EmailAction
+	Result0 *IssueTrackerAction
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -5989,13 +7466,13 @@ type CodeMonitorStoreUpdateEmailActionFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeMonitorStoreUpdateEmailActionFuncCall) Args() []interface{} {
+func (c CodeMonitorStoreUpdateIssueTrackerActionFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeMonitorStoreUpdateEmailActionFuncCall) Results() []interface{} {
+func (c CodeMonitorStoreUpdateIssueTrackerActionFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -6459,6 +7936,121 @@ func (c CodeMonitorStoreUpdateSlackWebhookActionFuncCall) Results() []interface{
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc describes the
+// behavior when the UpdateTriggerJobWithContentChanges method of the parent
+// MockCodeMonitorStore instance is invoked.
+type CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc struct {
+	defaultHook func(context.Context, int32, string, []*ContentChange) error
+	hooks       []func(context.Context, int32, string, []*ContentChange) error
+	history     []CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateTriggerJobWithContentChanges delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) UpdateTriggerJobWithContentChanges(v0 context.Context, v1 int32, v2 string, v3 []*ContentChange) error {
+	r0 := m.UpdateTriggerJobWithContentChangesFunc.nextHook()(v0, v1, v2, v3)
+	m.UpdateTriggerJobWithContentChangesFunc.appendCall(CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// UpdateTriggerJobWithContentChanges method of the parent
+// MockCodeMonitorStore instance is invoked and the hook queue is empty.
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) SetDefaultHook(hook func(context.Context, int32, string, []*ContentChange) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateTriggerJobWithContentChanges method of the parent
+// MockCodeMonitorStore instance invokes the hook at the front of the queue
+// and discards it. After the queue is empty, the default hook function is
+// invoked for any future action.
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) PushHook(hook func(context.Context, int32, string, []*ContentChange) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int32, string, []*ContentChange) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int32, string, []*ContentChange) error {
+		return r0
+	})
+}
+
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) nextHook() func(context.Context, int32, string, []*ContentChange) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) appendCall(r0 CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeMonitorStoreUpdateTriggerJobWithContentChangesFunc) History() []CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall is an object
+// that describes an invocation of method UpdateTriggerJobWithContentChanges
+// on an instance of MockCodeMonitorStore.
+type CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []*ContentChange
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreUpdateTriggerJobWithContentChangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // CodeMonitorStoreUpdateTriggerJobWithResultsFunc describes the behavior
 // when the UpdateTriggerJobWithResults method of the parent
 // MockCodeMonitorStore instance is invoked.
@@ -6694,6 +8286,226 @@ func (c CodeMonitorStoreUpdateWebhookActionFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeMonitorStoreUpsertContentSnapshotFunc describes the behavior when the
+// UpsertContentSnapshot method of the parent MockCodeMonitorStore instance
+// is invoked.
+type CodeMonitorStoreUpsertContentSnapshotFunc struct {
+	defaultHook func(context.Context, int64, api.RepoID, *ContentSnapshot) error
+	hooks       []func(context.Context, int64, api.RepoID, *ContentSnapshot) error
+	history     []CodeMonitorStoreUpsertContentSnapshotFuncCall
+	mutex       sync.Mutex
+}
+
+// UpsertContentSnapshot delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) UpsertContentSnapshot(v0 context.Context, v1 int64, v2 api.RepoID, v3 *ContentSnapshot) error {
+	r0 := m.UpsertContentSnapshotFunc.nextHook()(v0, v1, v2, v3)
+	m.UpsertContentSnapshotFunc.appendCall(CodeMonitorStoreUpsertContentSnapshotFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the UpsertContentSnapshot
+// method of the parent MockCodeMonitorStore instance is invoked and the hook
+// queue is empty.
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) SetDefaultHook(hook func(context.Context, int64, api.RepoID, *ContentSnapshot) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpsertContentSnapshot method of the parent MockCodeMonitorStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) PushHook(hook func(context.Context, int64, api.RepoID, *ContentSnapshot) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, api.RepoID, *ContentSnapshot) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, api.RepoID, *ContentSnapshot) error {
+		return r0
+	})
+}
+
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) nextHook() func(context.Context, int64, api.RepoID, *ContentSnapshot) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) appendCall(r0 CodeMonitorStoreUpsertContentSnapshotFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeMonitorStoreUpsertContentSnapshotFuncCall objects describing the
+// invocations of this function.
+func (f *CodeMonitorStoreUpsertContentSnapshotFunc) History() []CodeMonitorStoreUpsertContentSnapshotFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreUpsertContentSnapshotFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreUpsertContentSnapshotFuncCall is an object that describes
+// an invocation of method UpsertContentSnapshot on an instance of
+// MockCodeMonitorStore.
+type CodeMonitorStoreUpsertContentSnapshotFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.RepoID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 *ContentSnapshot
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreUpsertContentSnapshotFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreUpsertContentSnapshotFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// CodeMonitorStoreUpsertIssueFunc describes the behavior when the
+// UpsertIssue method of the parent MockCodeMonitorStore instance is invoked.
+type CodeMonitorStoreUpsertIssueFunc struct {
+	defaultHook func(context.Context, *Issue) error
+	hooks       []func(context.Context, *Issue) error
+	history     []CodeMonitorStoreUpsertIssueFuncCall
+	mutex       sync.Mutex
+}
+
+// UpsertIssue delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeMonitorStore) UpsertIssue(v0 context.Context, v1 *Issue) error {
+	r0 := m.UpsertIssueFunc.nextHook()(v0, v1)
+	m.UpsertIssueFunc.appendCall(CodeMonitorStoreUpsertIssueFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the UpsertIssue method of
+// the parent MockCodeMonitorStore instance is invoked and the hook queue is
+// empty.
+func (f *CodeMonitorStoreUpsertIssueFunc) SetDefaultHook(hook func(context.Context, *Issue) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpsertIssue method of the parent MockCodeMonitorStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeMonitorStoreUpsertIssueFunc) PushHook(hook func(context.Context, *Issue) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeMonitorStoreUpsertIssueFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, *Issue) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeMonitorStoreUpsertIssueFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, *Issue) error {
+		return r0
+	})
+}
+
+func (f *CodeMonitorStoreUpsertIssueFunc) nextHook() func(context.Context, *Issue) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeMonitorStoreUpsertIssueFunc) appendCall(r0 CodeMonitorStoreUpsertIssueFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeMonitorStoreUpsertIssueFuncCall objects
+// describing the invocations of this function.
+func (f *CodeMonitorStoreUpsertIssueFunc) History() []CodeMonitorStoreUpsertIssueFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeMonitorStoreUpsertIssueFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeMonitorStoreUpsertIssueFuncCall is an object that describes an
+// invocation of method UpsertIssue on an instance of MockCodeMonitorStore.
+type CodeMonitorStoreUpsertIssueFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *Issue
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeMonitorStoreUpsertIssueFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeMonitorStoreUpsertIssueFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // CodeMonitorStoreUpsertLastSearchedFunc describes the behavior when the
 // UpsertLastSearched method of the parent MockCodeMonitorStore instance is
 // invoked.
@@ -26,6 +26,7 @@ var EncryptionConfigs = []EncryptionConfig{
 	userCredentialsEncryptionConfig,
 	batchChangesSiteCredentialsEncryptionConfig,
 	webhooklogsEncryptionConfig,
+	codeMonitorIssueTrackersEncryptionConfig,
 }
 
 var externalServicesEncryptionConfig = EncryptionConfig{
@@ -80,6 +81,16 @@ var webhooklogsEncryptionConfig = EncryptionConfig{
 	Limit:               5,
 }
 
+var codeMonitorIssueTrackersEncryptionConfig = EncryptionConfig{
+	TableName:           "cm_issue_trackers",
+	IDFieldName:         "id",
+	KeyIDFieldName:      "encryption_key_id",
+	EncryptedFieldNames: []string{"token"},
+	Scan:                basestore.NewMapScanner(scanEncryptedString),
+	Key:                 func() encryption.Key { return keyring.Default().ExternalServiceKey },
+	Limit:               100,
+}
+
 func scanEncryptedString(scanner dbutil.Scanner) (id int, e Encrypted, err error) {
 	e.Values = make([]string, 1)
 	err = scanner.Scan(&id, &e.KeyID, &e.Values[0])
@@ -207,6 +207,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
 	extsvc.KindGoPackages:      {CodeHost: true, JSONSchema: schema.GoModulesSchemaJSON},
 	extsvc.KindJVMPackages:     {CodeHost: true, JSONSchema: schema.JVMPackagesSchemaJSON},
+	extsvc.KindMercurial:       {CodeHost: true, JSONSchema: schema.MercurialSchemaJSON},
 	extsvc.KindNpmPackages:     {CodeHost: true, JSONSchema: schema.NpmPackagesSchemaJSON},
 	extsvc.KindOther:           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 	extsvc.KindPagure:          {CodeHost: true, JSONSchema: schema.PagureSchemaJSON},
@@ -214,6 +215,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindPhabricator:     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
 	extsvc.KindPythonPackages:  {CodeHost: true, JSONSchema: schema.PythonPackagesSchemaJSON},
 	extsvc.KindRustPackages:    {CodeHost: true, JSONSchema: schema.RustPackagesSchemaJSON},
+	extsvc.KindSubversion:      {CodeHost: true, JSONSchema: schema.SubversionSchemaJSON},
 }
 
 // ExternalServiceKind describes a kind of external service.
@@ -447,6 +449,20 @@ func MakeValidateExternalServiceConfigFunc(gitHubValidators []func(*types.GitHub
 				return nil, err
 			}
 			err = validateOtherExternalServiceConnection(&c)
+
+		case extsvc.KindMercurial:
+			var c schema.MercurialConnection
+			if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+				return nil, err
+			}
+			err = validateMercurialConnection(&c)
+
+		case extsvc.KindSubversion:
+			var c schema.SubversionConnection
+			if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+				return nil, err
+			}
+			err = validateSubversionConnection(&c)
 		}
 
 		return normalized, errors.Append(errs, err)
@@ -482,6 +498,54 @@ func validateOtherExternalServiceConnection(c *schema.OtherExternalServiceConnec
 	return nil
 }
 
+// validateMercurialConnection validates the repo items of c like
+// validateOtherExternalServiceConnection. Mercurial has no git:// protocol.
+func validateMercurialConnection(c *schema.MercurialConnection) error {
+	parseRepo := url.Parse
+	if c.Url != "" {
+		// We ignore the error because this already validated by JSON Schema.
+		baseURL, _ := url.Parse(c.Url)
+		parseRepo = baseURL.Parse
+	}
+
+	for i, repo := range c.Repos {
+		cloneURL, err := parseRepo(repo)
+		if err != nil {
+			return errors.Errorf(`repos.%d: %s`, i, err)
+		}
+
+		switch cloneURL.Scheme {
+		case "http", "https", "ssh":
+			continue
+		default:
+			return errors.Errorf("repos.%d: scheme %q not one of http, https or ssh", i, cloneURL.Scheme)
+		}
+	}
+
+	return nil
+}
+
+// validateSubversionConnection validates that the repo items of c are paths
+// relative to the root URL.
+func validateSubversionConnection(c *schema.SubversionConnection) error {
+	for i, repo := range c.Repos {
+		u, err := url.Parse(repo)
+		if err != nil {
+			return errors.Errorf(`repos.%d: %s`, i, err)
+		}
+		if u.IsAbs() || strings.HasPrefix(repo, "/") {
+			return errors.Errorf("repos.%d: %q is not a path relative to the url", i, repo)
+		}
+		for _, elem := range strings.Split(repo, "/") {
+			if elem == ".." {
+				return errors.Errorf("repos.%d: %q must not contain ..", i, repo)
+			}
+		}
+	}
+
+	return nil
+}
+
 func validateGitHubConnection(githubValidators []func(*types.GitHubConnection) error, id int64, c *schema.GitHubConnection) error {
 	var err error
 	for _, validate := range githubValidators {
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -60,6 +61,16 @@ type GitserverRepoStore interface {
 	ListReposWithoutSize(ctx context.Context) (map[api.RepoName]api.RepoID, error)
 	// UpdateRepoSizes sets repo sizes according to input map. Key is repoID, value is repo_size_bytes.
 	UpdateRepoSizes(ctx context.Context, shardID string, repos map[api.RepoID]int64) (int, error)
+	// LogCorruption appends a step of repairing a corrupt clone to the
+	// corruption logs of a GitServerRepo, which keep the latest
+	// MaxCorruptionLogs steps.
+	LogCorruption(ctx context.Context, name api.RepoName, log types.RepoCorruptionLog, shardID string) error
+	// SetQuarantine records that the corrupt clone of a GitServerRepo was moved
+	// into quarantine at path. An empty path clears the quarantine.
+	SetQuarantine(ctx context.Context, name api.RepoName, path, shardID string) error
+	// ListQuarantined returns the repos whose corrupt clones are in quarantine,
+	// most recently quarantined first.
+	ListQuarantined(ctx context.Context) ([]*types.QuarantinedRepo, error)
 }
 
 var _ GitserverRepoStore = (*gitserverRepoStore)(nil)
@@ -622,6 +633,97 @@ AND
 	tmp.repo_size_bytes IS DISTINCT FROM gr.repo_size_bytes
 `
 
+// MaxCorruptionLogs is the number of repair steps kept in the corruption logs
+// of a repo.
+const MaxCorruptionLogs = 10
+
+func (s *gitserverRepoStore) LogCorruption(ctx context.Context, name api.RepoName, log types.RepoCorruptionLog, shardID string) error {
+	log.Reason = sanitizeToUTF8(log.Reason)
+	entry, err := json.Marshal([]types.RepoCorruptionLog{log})
+	if err != nil {
+		return errors.Wrap(err, "marshalling corruption log")
+	}
+
+	err = s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:gitserverRepoStore.LogCorruption
+UPDATE gitserver_repos
+SET
+	corruption_logs = (
+		SELECT jsonb_agg(entry ORDER BY i)
+		FROM jsonb_array_elements(corruption_logs || %s::jsonb) WITH ORDINALITY AS t(entry, i)
+		WHERE i > jsonb_array_length(corruption_logs) + 1 - %s
+	),
+	shard_id = %s,
+	updated_at = NOW()
+WHERE
+	repo_id = (SELECT id FROM repo WHERE name = %s)
+`, string(entry), MaxCorruptionLogs, shardID, name))
+	if err != nil {
+		return errors.Wrap(err, "logging corruption")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) SetQuarantine(ctx context.Context, name api.RepoName, path, shardID string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:gitserverRepoStore.SetQuarantine
+UPDATE gitserver_repos
+SET
+	quarantine_path = %s,
+	quarantined_at = CASE WHEN %s::text = '' THEN NULL ELSE NOW() END,
+	shard_id = %s,
+	updated_at = NOW()
+WHERE
+	repo_id = (SELECT id FROM repo WHERE name = %s)
+`, dbutil.NewNullString(path), path, shardID, name))
+	if err != nil {
+		return errors.Wrap(err, "setting quarantine")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) ListQuarantined(ctx context.Context) (_ []*types.QuarantinedRepo, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listQuarantinedQuery))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing quarantined repos")
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var repos []*types.QuarantinedRepo
+	for rows.Next() {
+		var repo types.QuarantinedRepo
+		var logs []byte
+		if err := rows.Scan(&repo.RepoID, &repo.Name, &repo.ShardID, &repo.QuarantinePath, &repo.QuarantinedAt, &logs); err != nil {
+			return nil, errors.Wrap(err, "scanning quarantined repo")
+		}
+		if err := json.Unmarshal(logs, &repo.CorruptionLogs); err != nil {
+			return nil, errors.Wrap(err, "unmarshalling corruption logs")
+		}
+		repos = append(repos, &repo)
+	}
+
+	return repos, nil
+}
+
+const listQuarantinedQuery = `
+-- source: internal/database/gitserver_repos.go:gitserverRepoStore.ListQuarantined
+SELECT
+	gr.repo_id,
+	repo.name,
+	gr.shard_id,
+	gr.quarantine_path,
+	gr.quarantined_at,
+	gr.corruption_logs
+FROM gitserver_repos gr
+JOIN repo ON repo.id = gr.repo_id
+WHERE
+	gr.quarantined_at IS NOT NULL
+	AND repo.deleted_at IS NULL
+ORDER BY gr.quarantined_at DESC
+`
+
 // sanitizeToUTF8 will remove any null character terminated string. The null character can be
 // represented in one of the following ways in Go:
 //
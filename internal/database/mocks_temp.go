@@ -18407,9 +18407,15 @@ type MockGitserverRepoStore struct {
 	// object controlling the behavior of the method
 	// IterateWithNonemptyLastError.
 	IterateWithNonemptyLastErrorFunc *GitserverRepoStoreIterateWithNonemptyLastErrorFunc
+	// ListQuarantinedFunc is an instance of a mock function object
+	// controlling the behavior of the method ListQuarantined.
+	ListQuarantinedFunc *GitserverRepoStoreListQuarantinedFunc
 	// ListReposWithoutSizeFunc is an instance of a mock function object
 	// controlling the behavior of the method ListReposWithoutSize.
 	ListReposWithoutSizeFunc *GitserverRepoStoreListReposWithoutSizeFunc
+	// LogCorruptionFunc is an instance of a mock function object
+	// controlling the behavior of the method LogCorruption.
+	LogCorruptionFunc *GitserverRepoStoreLogCorruptionFunc
 	// SetCloneStatusFunc is an instance of a mock function object
 	// controlling the behavior of the method SetCloneStatus.
 	SetCloneStatusFunc *GitserverRepoStoreSetCloneStatusFunc
@@ -18419,6 +18425,9 @@ type MockGitserverRepoStore struct {
 	// SetLastFetchedFunc is an instance of a mock function object
 	// controlling the behavior of the method SetLastFetched.
 	SetLastFetchedFunc *GitserverRepoStoreSetLastFetchedFunc
+	// SetQuarantineFunc is an instance of a mock function object
+	// controlling the behavior of the method SetQuarantine.
+	SetQuarantineFunc *GitserverRepoStoreSetQuarantineFunc
 	// SetRepoSizeFunc is an instance of a mock function object controlling
 	// the behavior of the method SetRepoSize.
 	SetRepoSizeFunc *GitserverRepoStoreSetRepoSizeFunc
@@ -18477,11 +18486,21 @@ func NewMockGitserverRepoStore() *MockGitserverRepoStore {
 				return
 			},
 		},
+		ListQuarantinedFunc: &GitserverRepoStoreListQuarantinedFunc{
+			defaultHook: func(context.Context) (r0 []*types.QuarantinedRepo, r1 error) {
+				return
+			},
+		},
 		ListReposWithoutSizeFunc: &GitserverRepoStoreListReposWithoutSizeFunc{
 			defaultHook: func(context.Context) (r0 map[api.RepoName]api.RepoID, r1 error) {
 				return
 			},
 		},
+		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
+			defaultHook: func(context.Context, api.RepoName, types.RepoCorruptionLog, string) (r0 error) {
+				return
+			},
+		},
 		SetCloneStatusFunc: &GitserverRepoStoreSetCloneStatusFunc{
 			defaultHook: func(context.Context, api.RepoName, types.CloneStatus, string) (r0 error) {
 				return
@@ -18497,6 +18516,11 @@ func NewMockGitserverRepoStore() *MockGitserverRepoStore {
 				return
 			},
 		},
+		SetQuarantineFunc: &GitserverRepoStoreSetQuarantineFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) (r0 error) {
+				return
+			},
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: func(context.Context, api.RepoName, int64, string) (r0 error) {
 				return
@@ -18565,11 +18589,21 @@ func NewStrictMockGitserverRepoStore() *MockGitserverRepoStore {
 				panic("unexpected invocation of MockGitserverRepoStore.IterateWithNonemptyLastError")
 			},
 		},
+		ListQuarantinedFunc: &GitserverRepoStoreListQuarantinedFunc{
+			defaultHook: func(context.Context) ([]*types.QuarantinedRepo, error) {
+				panic("unexpected invocation of MockGitserverRepoStore.ListQuarantined")
+			},
+		},
 		ListReposWithoutSizeFunc: &GitserverRepoStoreListReposWithoutSizeFunc{
 			defaultHook: func(context.Context) (map[api.RepoName]api.RepoID, error) {
 				panic("unexpected invocation of MockGitserverRepoStore.ListReposWithoutSize")
 			},
 		},
+		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
+			defaultHook: func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.LogCorruption")
+			},
+		},
 		SetCloneStatusFunc: &GitserverRepoStoreSetCloneStatusFunc{
 			defaultHook: func(context.Context, api.RepoName, types.CloneStatus, string) error {
 				panic("unexpected invocation of MockGitserverRepoStore.SetCloneStatus")
@@ -18585,6 +18619,11 @@ func NewStrictMockGitserverRepoStore() *MockGitserverRepoStore {
 				panic("unexpected invocation of MockGitserverRepoStore.SetLastFetched")
 			},
 		},
+		SetQuarantineFunc: &GitserverRepoStoreSetQuarantineFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetQuarantine")
+			},
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: func(context.Context, api.RepoName, int64, string) error {
 				panic("unexpected invocation of MockGitserverRepoStore.SetRepoSize")
@@ -18639,9 +18678,15 @@ func NewMockGitserverRepoStoreFrom(i GitserverRepoStore) *MockGitserverRepoStore
 		IterateWithNonemptyLastErrorFunc: &GitserverRepoStoreIterateWithNonemptyLastErrorFunc{
 			defaultHook: i.IterateWithNonemptyLastError,
 		},
+		ListQuarantinedFunc: &GitserverRepoStoreListQuarantinedFunc{
+			defaultHook: i.ListQuarantined,
+		},
 		ListReposWithoutSizeFunc: &GitserverRepoStoreListReposWithoutSizeFunc{
 			defaultHook: i.ListReposWithoutSize,
 		},
+		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
+			defaultHook: i.LogCorruption,
+		},
 		SetCloneStatusFunc: &GitserverRepoStoreSetCloneStatusFunc{
 			defaultHook: i.SetCloneStatus,
 		},
@@ -18651,6 +18696,9 @@ func NewMockGitserverRepoStoreFrom(i GitserverRepoStore) *MockGitserverRepoStore
 		SetLastFetchedFunc: &GitserverRepoStoreSetLastFetchedFunc{
 			defaultHook: i.SetLastFetched,
 		},
+		SetQuarantineFunc: &GitserverRepoStoreSetQuarantineFunc{
+			defaultHook: i.SetQuarantine,
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: i.SetRepoSize,
 		},
@@ -19433,6 +19481,114 @@ func (c GitserverRepoStoreIterateWithNonemptyLastErrorFuncCall) Results() []inte
 	return []interface{}{c.Result0}
 }
 
+// GitserverRepoStoreListQuarantinedFunc describes the behavior when the
+// ListQuarantined method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreListQuarantinedFunc struct {
+	defaultHook func(context.Context) ([]*types.QuarantinedRepo, error)
+	hooks       []func(context.Context) ([]*types.QuarantinedRepo, error)
+	history     []GitserverRepoStoreListQuarantinedFuncCall
+	mutex       sync.Mutex
+}
+
+// ListQuarantined delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) ListQuarantined(v0 context.Context) ([]*types.QuarantinedRepo, error) {
+	r0, r1 := m.ListQuarantinedFunc.nextHook()(v0)
+	m.ListQuarantinedFunc.appendCall(GitserverRepoStoreListQuarantinedFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListQuarantined
+// method of the parent MockGitserverRepoStore instance is invoked and the
+// hook queue is empty.
+func (f *GitserverRepoStoreListQuarantinedFunc) SetDefaultHook(hook func(context.Context) ([]*types.QuarantinedRepo, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListQuarantined method of the parent MockGitserverRepoStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverRepoStoreListQuarantinedFunc) PushHook(hook func(context.Context) ([]*types.QuarantinedRepo, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreListQuarantinedFunc) SetDefaultReturn(r0 []*types.QuarantinedRepo, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]*types.QuarantinedRepo, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreListQuarantinedFunc) PushReturn(r0 []*types.QuarantinedRepo, r1 error) {
+	f.PushHook(func(context.Context) ([]*types.QuarantinedRepo, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverRepoStoreListQuarantinedFunc) nextHook() func(context.Context) ([]*types.QuarantinedRepo, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreListQuarantinedFunc) appendCall(r0 GitserverRepoStoreListQuarantinedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreListQuarantinedFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreListQuarantinedFunc) History() []GitserverRepoStoreListQuarantinedFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreListQuarantinedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreListQuarantinedFuncCall is an object that describes an
+// invocation of method ListQuarantined on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreListQuarantinedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*types.QuarantinedRepo
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreListQuarantinedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreListQuarantinedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverRepoStoreListReposWithoutSizeFunc describes the behavior when
 // the ListReposWithoutSize method of the parent MockGitserverRepoStore
 // instance is invoked.
@@ -19542,6 +19698,119 @@ func (c GitserverRepoStoreListReposWithoutSizeFuncCall) Results() []interface{}
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverRepoStoreLogCorruptionFunc describes the behavior when the
+// LogCorruption method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreLogCorruptionFunc struct {
+	defaultHook func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error
+	hooks       []func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error
+	history     []GitserverRepoStoreLogCorruptionFuncCall
+	mutex       sync.Mutex
+}
+
+// LogCorruption delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) LogCorruption(v0 context.Context, v1 api.RepoName, v2 types.RepoCorruptionLog, v3 string) error {
+	r0 := m.LogCorruptionFunc.nextHook()(v0, v1, v2, v3)
+	m.LogCorruptionFunc.appendCall(GitserverRepoStoreLogCorruptionFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the LogCorruption method
+// of the parent MockGitserverRepoStore instance is invoked and the hook
+// queue is empty.
+func (f *GitserverRepoStoreLogCorruptionFunc) SetDefaultHook(hook func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// LogCorruption method of the parent MockGitserverRepoStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverRepoStoreLogCorruptionFunc) PushHook(hook func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreLogCorruptionFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreLogCorruptionFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreLogCorruptionFunc) nextHook() func(context.Context, api.RepoName, types.RepoCorruptionLog, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreLogCorruptionFunc) appendCall(r0 GitserverRepoStoreLogCorruptionFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreLogCorruptionFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreLogCorruptionFunc) History() []GitserverRepoStoreLogCorruptionFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreLogCorruptionFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreLogCorruptionFuncCall is an object that describes an
+// invocation of method LogCorruption on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreLogCorruptionFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 types.RepoCorruptionLog
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreLogCorruptionFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreLogCorruptionFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GitserverRepoStoreSetCloneStatusFunc describes the behavior when the
 // SetCloneStatus method of the parent MockGitserverRepoStore instance is
 // invoked.
@@ -19880,6 +20149,119 @@ func (c GitserverRepoStoreSetLastFetchedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GitserverRepoStoreSetQuarantineFunc describes the behavior when the
+// SetQuarantine method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreSetQuarantineFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, string) error
+	hooks       []func(context.Context, api.RepoName, string, string) error
+	history     []GitserverRepoStoreSetQuarantineFuncCall
+	mutex       sync.Mutex
+}
+
+// SetQuarantine delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetQuarantine(v0 context.Context, v1 api.RepoName, v2 string, v3 string) error {
+	r0 := m.SetQuarantineFunc.nextHook()(v0, v1, v2, v3)
+	m.SetQuarantineFunc.appendCall(GitserverRepoStoreSetQuarantineFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetQuarantine method
+// of the parent MockGitserverRepoStore instance is invoked and the hook
+// queue is empty.
+func (f *GitserverRepoStoreSetQuarantineFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetQuarantine method of the parent MockGitserverRepoStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverRepoStoreSetQuarantineFunc) PushHook(hook func(context.Context, api.RepoName, string, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetQuarantineFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetQuarantineFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetQuarantineFunc) nextHook() func(context.Context, api.RepoName, string, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetQuarantineFunc) appendCall(r0 GitserverRepoStoreSetQuarantineFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreSetQuarantineFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreSetQuarantineFunc) History() []GitserverRepoStoreSetQuarantineFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetQuarantineFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetQuarantineFuncCall is an object that describes an
+// invocation of method SetQuarantine on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetQuarantineFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetQuarantineFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetQuarantineFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GitserverRepoStoreSetRepoSizeFunc describes the behavior when the
 // SetRepoSize method of the parent MockGitserverRepoStore instance is
 // invoked.
@@ -732,6 +732,57 @@ func (c *V3Client) Fork(ctx context.Context, owner, repo string, org *string) (*
 	return convertRestRepo(restRepo), nil
 }
 
+// Issue is a GitHub issue, as returned by the REST API.
+type Issue struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreateIssueInput is the input of CreateIssue.
+type CreateIssueInput struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// CreateIssue creates an issue in the given repository.
+//
+// API docs: https://docs.github.com/en/rest/issues/issues#create-an-issue
+func (c *V3Client) CreateIssue(ctx context.Context, owner, repo string, input *CreateIssueInput) (*Issue, error) {
+	var issue Issue
+	resp, err := c.post(ctx, "repos/"+owner+"/"+repo+"/issues", input, &issue)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.statusCode != http.StatusCreated {
+		return nil, errors.Newf("expected status code 201, got %d", resp.statusCode)
+	}
+
+	return &issue, nil
+}
+
+// CreateIssueComment comments on the issue with the given number.
+//
+// API docs: https://docs.github.com/en/rest/issues/comments#create-an-issue-comment
+func (c *V3Client) CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	payload := struct {
+		Body string `json:"body"`
+	}{Body: body}
+
+	resp, err := c.post(ctx, fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, number), payload, &struct{}{})
+	if err != nil {
+		return err
+	}
+
+	if resp.statusCode != http.StatusCreated {
+		return errors.Newf("expected status code 201, got %d", resp.statusCode)
+	}
+
+	return nil
+}
+
 // GetAppInstallation gets information of a GitHub App installation.
 //
 // API docs: https://docs.github.com/en/rest/reference/apps#get-an-installation-for-the-authenticated-app
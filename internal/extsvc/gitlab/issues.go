@@ -0,0 +1,94 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type Issue struct {
+	ID     ID     `json:"id"`
+	IID    ID     `json:"iid"`
+	Title  string `json:"title"`
+	WebURL string `json:"web_url"`
+}
+
+// CreateIssueOpts are the options of CreateIssue.
+type CreateIssueOpts struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Labels      string  `json:"labels,omitempty"` // comma-separated
+	AssigneeIDs []int32 `json:"assignee_ids,omitempty"`
+}
+
+// CreateIssue creates an issue in the given project.
+//
+// API docs: https://docs.gitlab.com/ee/api/issues.html#new-issue
+func (c *Client) CreateIssue(ctx context.Context, project *Project, opts CreateIssueOpts) (*Issue, error) {
+	if MockCreateIssue != nil {
+		return MockCreateIssue(c, ctx, project, opts)
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling options")
+	}
+
+	time.Sleep(c.rateLimitMonitor.RecommendedWaitForBackgroundOp(1))
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("projects/%d/issues", project.ID), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request to create an issue")
+	}
+
+	resp := &Issue{}
+	if _, _, err := c.do(ctx, req, resp); err != nil {
+		if aerr := c.convertToArchivedError(ctx, err, project); aerr != nil {
+			return nil, aerr
+		}
+
+		return nil, errors.Wrap(err, "sending request to create an issue")
+	}
+
+	return resp, nil
+}
+
+// CreateIssueNote comments on the issue with the given IID.
+//
+// API docs: https://docs.gitlab.com/ee/api/notes.html#create-new-issue-note
+func (c *Client) CreateIssueNote(ctx context.Context, project *Project, iid ID, body string) error {
+	if MockCreateIssueNote != nil {
+		return MockCreateIssueNote(c, ctx, project, iid, body)
+	}
+
+	var payload = struct {
+		Body string `json:"body"`
+	}{
+		Body: body,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshalling payload")
+	}
+
+	time.Sleep(c.rateLimitMonitor.RecommendedWaitForBackgroundOp(1))
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("projects/%d/issues/%d/notes", project.ID, iid), bytes.NewBuffer(data))
+	if err != nil {
+		return errors.Wrap(err, "creating request to comment on an issue")
+	}
+
+	var resp struct {
+		ID int32 `json:"id"`
+	}
+	if _, _, err := c.do(ctx, req, &resp); err != nil {
+		return errors.Wrap(err, "sending request to comment on an issue")
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCreateIssue(t *testing.T) {
+	ctx := context.Background()
+	project := &Project{}
+	opts := CreateIssueOpts{Title: "test-title", Labels: "a,b"}
+
+	t.Run("error status code", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPEmptyResponse{http.StatusNotFound}
+
+		issue, err := client.CreateIssue(ctx, project, opts)
+		if issue != nil {
+			t.Errorf("unexpected non-nil issue: %+v", issue)
+		}
+		if err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("malformed response", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{
+			responseBody: `this is not valid JSON`,
+		}
+
+		issue, err := client.CreateIssue(ctx, project, opts)
+		if issue != nil {
+			t.Errorf("unexpected non-nil issue: %+v", issue)
+		}
+		if err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{
+			responseBody: `{"id":1,"iid":42,"title":"test-title","web_url":"https://example.com/a/b/-/issues/42"}`,
+		}
+
+		issue, err := client.CreateIssue(ctx, project, opts)
+		if err != nil {
+			t.Errorf("unexpected non-nil error: %+v", err)
+		}
+		want := &Issue{ID: 1, IID: 42, Title: "test-title", WebURL: "https://example.com/a/b/-/issues/42"}
+		if diff := cmp.Diff(issue, want); diff != "" {
+			t.Errorf("unexpected issue: %s", diff)
+		}
+	})
+}
+
+func TestCreateIssueNote(t *testing.T) {
+	ctx := context.Background()
+	project := &Project{}
+
+	t.Run("error status code", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPEmptyResponse{http.StatusNotFound}
+
+		err := client.CreateIssueNote(ctx, project, 42, "test-comment")
+		if err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{
+			responseBody: `{"id":1,"body":"test-comment"}`,
+		}
+
+		err := client.CreateIssueNote(ctx, project, 42, "test-comment")
+		if err != nil {
+			t.Errorf("unexpected non-nil error: %+v", err)
+		}
+	})
+}
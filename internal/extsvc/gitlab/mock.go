@@ -21,6 +21,13 @@ var MockListTree func(c *Client, ctx context.Context, op ListTreeOp) ([]*Tree, e
 // Client.CreateMergeRequest
 var MockCreateMergeRequest func(c *Client, ctx context.Context, project *Project, opts CreateMergeRequestOpts) (*MergeRequest, error)
 
+// MockCreateIssue, if non-nil, will be called instead of Client.CreateIssue
+var MockCreateIssue func(c *Client, ctx context.Context, project *Project, opts CreateIssueOpts) (*Issue, error)
+
+// MockCreateIssueNote, if non-nil, will be called instead of
+// Client.CreateIssueNote
+var MockCreateIssueNote func(c *Client, ctx context.Context, project *Project, iid ID, body string) error
+
 // MockGetMergeRequest, if non-nil, will be called instead of
 // Client.GetMergeRequest
 var MockGetMergeRequest func(c *Client, ctx context.Context, project *Project, iid ID) (*MergeRequest, error)
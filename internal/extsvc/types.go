@@ -123,6 +123,8 @@ const (
 	KindRustPackages    = "RUSTPACKAGES"
 	KindNpmPackages     = "NPMPACKAGES"
 	KindPagure          = "PAGURE"
+	KindMercurial       = "MERCURIAL"
+	KindSubversion      = "SUBVERSION"
 	KindOther           = "OTHER"
 )
 
@@ -183,6 +185,12 @@ const (
 	// TypeRustPackages is the (api.ExternalRepoSpec).ServiceType value for Python packages.
 	TypeRustPackages = "rustPackages"
 
+	// TypeMercurial is the (api.ExternalRepoSpec).ServiceType value for Mercurial repositories.
+	TypeMercurial = "mercurial"
+
+	// TypeSubversion is the (api.ExternalRepoSpec).ServiceType value for Subversion repositories.
+	TypeSubversion = "subversion"
+
 	// TypeOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	TypeOther = "other"
 )
@@ -221,6 +229,10 @@ func KindToType(kind string) string {
 		return TypeGoModules
 	case KindPagure:
 		return TypePagure
+	case KindMercurial:
+		return TypeMercurial
+	case KindSubversion:
+		return TypeSubversion
 	case KindOther:
 		return TypeOther
 	default:
@@ -262,6 +274,10 @@ func TypeToKind(t string) string {
 		return KindGoPackages
 	case TypePagure:
 		return KindPagure
+	case TypeMercurial:
+		return KindMercurial
+	case TypeSubversion:
+		return KindSubversion
 	case TypeOther:
 		return KindOther
 	default:
@@ -314,6 +330,10 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeRustPackages, true
 	case TypePagure:
 		return TypePagure, true
+	case TypeMercurial:
+		return TypeMercurial, true
+	case TypeSubversion:
+		return TypeSubversion, true
 	case TypeOther:
 		return TypeOther, true
 	default:
@@ -353,6 +373,10 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindRustPackages, true
 	case KindPagure:
 		return KindPagure, true
+	case KindMercurial:
+		return KindMercurial, true
+	case KindSubversion:
+		return KindSubversion, true
 	case KindOther:
 		return KindOther, true
 	default:
@@ -432,6 +456,10 @@ func getConfigPrototype(kind string) (any, error) {
 		return &schema.PythonPackagesConnection{}, nil
 	case KindRustPackages:
 		return &schema.RustPackagesConnection{}, nil
+	case KindMercurial:
+		return &schema.MercurialConnection{}, nil
+	case KindSubversion:
+		return &schema.SubversionConnection{}, nil
 	case KindOther:
 		return &schema.OtherExternalServiceConnection{}, nil
 	default:
@@ -713,6 +741,10 @@ func uniqueCodeHostIdentifier(kind string, cfg any) (string, error) {
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
 		rawURL = c.Url
+	case *schema.MercurialConnection:
+		rawURL = c.Url
+	case *schema.SubversionConnection:
+		rawURL = c.Url
 	case *schema.GitoliteConnection:
 		rawURL = c.Host
 	case *schema.AWSCodeCommitConnection:
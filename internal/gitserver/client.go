@@ -77,10 +77,11 @@ func NewClient(db database.DB) Client {
 		addrs: func() []string {
 			return conf.Get().ServiceConnections().GitServers
 		},
-		pinned:      pinnedReposFromConfig,
-		db:          db,
-		httpClient:  defaultDoer,
-		HTTPLimiter: defaultLimiter,
+		pinned:            pinnedReposFromConfig,
+		replicationFactor: replicationFactorFromConfig,
+		db:                db,
+		httpClient:        defaultDoer,
+		HTTPLimiter:       defaultLimiter,
 		// Use the binary name for userAgent. This should effectively identify
 		// which service is making the request (excluding requests proxied via the
 		// frontend internal API)
@@ -97,9 +98,10 @@ func NewTestClient(cli httpcli.Doer, db database.DB, addrs []string) Client {
 		addrs: func() []string {
 			return addrs
 		},
-		pinned:      pinnedReposFromConfig,
-		httpClient:  cli,
-		HTTPLimiter: parallel.NewRun(500),
+		pinned:            pinnedReposFromConfig,
+		replicationFactor: replicationFactorFromConfig,
+		httpClient:        cli,
+		HTTPLimiter:       parallel.NewRun(500),
 		// Use the binary name for userAgent. This should effectively identify
 		// which service is making the request (excluding requests proxied via the
 		// frontend internal API)
@@ -134,6 +136,11 @@ type clientImplementor struct {
 	// and sync the pinned map.
 	pinned func() map[string]string
 
+	// replicationFactor returns the number of gitserver instances that have a
+	// copy of each repository. Read-only requests for specific commits are
+	// spread across them.
+	replicationFactor func() int
+
 	// db is a connection to the database
 	db database.DB
 
@@ -166,6 +173,12 @@ type Client interface {
 	// If possible, the error returned will be of type protocol.CreateCommitFromPatchError
 	CreateCommitFromPatch(context.Context, protocol.CreateCommitFromPatchRequest) (string, error)
 
+	// CreateCommitFromEdits will attempt to create a commit from a list of file
+	// edits. If possible, the error returned will be of type
+	// protocol.CreateCommitFromEditsError, which lists the conflicting paths if
+	// the edits conflict with the target ref.
+	CreateCommitFromEdits(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error)
+
 	// GetDefaultBranch returns the name of the default branch and the commit it's
 	// currently at from the given repository. If short is true, then `main` instead
 	// of `refs/heads/main` would be returned.
@@ -208,6 +221,10 @@ type Client interface {
 
 	RepoCloneProgress(context.Context, ...api.RepoName) (*protocol.RepoCloneProgressResponse, error)
 
+	// RepoMaintenanceStatus returns the state of the commit-graph, bitmap and
+	// packfiles of the repository, and whether it needs maintenance.
+	RepoMaintenanceStatus(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error)
+
 	// ResolveRevision will return the absolute commit for a commit-ish spec. If spec is empty, HEAD is
 	// used.
 	//
@@ -272,6 +289,10 @@ type Client interface {
 	// Commits returns all commits matching the options.
 	Commits(ctx context.Context, repo api.RepoName, opt CommitsOptions, checker authz.SubRepoPermissionChecker) ([]*gitdomain.Commit, error)
 
+	// LastCommitsForPaths returns the last commit reachable from commit that
+	// modified each of the given paths, keyed by path.
+	LastCommitsForPaths(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string, checker authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error)
+
 	// FirstEverCommit returns the first commit ever made to the repository.
 	FirstEverCommit(ctx context.Context, repo api.RepoName, checker authz.SubRepoPermissionChecker) (*gitdomain.Commit, error)
 
@@ -425,6 +446,10 @@ func AddrForRepo(ctx context.Context, userAgent string, db database.DB, repo api
 type GitServerAddresses struct {
 	Addresses     []string
 	PinnedServers map[string]string
+
+	// ReplicationFactor is the number of instances that have a copy of each
+	// repository, see ReplicaAddrsForRepo.
+	ReplicationFactor int
 }
 
 // RendezvousAddrForRepo returns the gitserver address to use for the given repo name using the
@@ -486,8 +511,8 @@ func (a *archiveReader) Close() error {
 }
 
 // archiveURL returns a URL from which an archive of the given Git repository can
-// be downloaded from.
-func (c *clientImplementor) archiveURL(ctx context.Context, repo api.RepoName, opt ArchiveOptions) (*url.URL, error) {
+// be downloaded from the gitserver instance at addr.
+func archiveURL(addr string, repo api.RepoName, opt ArchiveOptions) *url.URL {
 	q := url.Values{
 		"repo":    {string(repo)},
 		"treeish": {opt.Treeish},
@@ -498,16 +523,12 @@ func (c *clientImplementor) archiveURL(ctx context.Context, repo api.RepoName, o
 		q.Add("path", string(pathspec))
 	}
 
-	addrForRepo, err := c.AddrForRepo(ctx, repo)
-	if err != nil {
-		return nil, err
-	}
 	return &url.URL{
 		Scheme:   "http",
-		Host:     addrForRepo,
+		Host:     addr,
 		Path:     "/archive",
 		RawQuery: q.Encode(),
-	}, nil
+	}
 }
 
 type badRequestError struct{ error }
@@ -535,13 +556,31 @@ func (c *RemoteGitCommand) sendExec(ctx context.Context) (_ io.ReadCloser, _ htt
 		return nil, nil, err
 	}
 
-	req := &protocol.ExecRequest{
-		Repo:           repoName,
-		EnsureRevision: c.EnsureRevision(),
-		Args:           c.args[1:],
-		NoTimeout:      c.noTimeout,
+	var resp *http.Response
+	var err error
+	if c.command != nil {
+		// Commands of the typed command API are limited by gitserver, which
+		// ignores noTimeout.
+		opts := c.command.Options()
+		opts.Repo, opts.EnsureRevision = repoName, c.EnsureRevision()
+		resp, err = c.execFn(ctx, repoName, "commands/"+c.command.Command(), c.command)
+		if err == nil && isUnknownRoute(resp) {
+			// gitserver instances that don't serve the typed command API
+			// yet, for example during a rolling upgrade, run the command
+			// with /exec instead.
+			resp.Body.Close()
+			resp = nil
+		}
+	}
+	if resp == nil && err == nil {
+		req := &protocol.ExecRequest{
+			Repo:           repoName,
+			EnsureRevision: c.EnsureRevision(),
+			Args:           c.args[1:],
+			NoTimeout:      c.noTimeout,
+		}
+		resp, err = c.execFn(ctx, repoName, "exec", req)
 	}
-	resp, err := c.execFn(ctx, repoName, "exec", req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -559,12 +598,34 @@ func (c *RemoteGitCommand) sendExec(ctx context.Context) (_ io.ReadCloser, _ htt
 		resp.Body.Close()
 		return nil, nil, &gitdomain.RepoNotExistError{Repo: repoName, CloneInProgress: payload.CloneInProgress, CloneProgress: payload.CloneProgress}
 
+	case http.StatusBadRequest:
+		defer resp.Body.Close()
+		return nil, nil, errors.Errorf("bad request: %s", readResponseBody(io.LimitReader(resp.Body, 200)))
+
 	default:
 		resp.Body.Close()
 		return nil, nil, errors.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 }
 
+// isUnknownRoute reports whether resp is the response of gitserver to a
+// request for a route it doesn't serve, as opposed to a request for a
+// repository it doesn't have, which is answered with a NotFoundPayload. The
+// body of resp is replaced so that it can be read again.
+func isUnknownRoute(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusNotFound {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var payload protocol.NotFoundPayload
+	return json.Unmarshal(body, &payload) != nil
+}
+
 func (c *clientImplementor) Search(ctx context.Context, args *protocol.SearchRequest, onMatches func([]protocol.CommitMatch)) (limitHit bool, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "GitserverClient.Search")
 	span.SetTag("repo", string(args.Repo))
@@ -593,8 +654,36 @@ func (c *clientImplementor) Search(ctx context.Context, args *protocol.SearchReq
 		return false, err
 	}
 
-	uri := "http://" + addrForRepo + "/search"
-	resp, err := c.do(ctx, repoName, "POST", uri, buf.Bytes())
+	// Searches of specific commits may be served by a secondary instance. If
+	// it fails before returning any matches, we search on the primary instance.
+	// A secondary instance which didn't clone the repo yet isn't unhealthy.
+	if isCommitSearch(args) {
+		addr, primary, err := c.readAddrForRepo(ctx, repoName)
+		if err != nil {
+			return false, err
+		}
+		if addr != primary {
+			var matched bool
+			limitHit, err := c.search(ctx, addr, repoName, buf.Bytes(), func(matches []protocol.CommitMatch) {
+				matched = true
+				onMatches(matches)
+			})
+			if err == nil || matched {
+				return limitHit, err
+			}
+			if !gitdomain.IsRepoNotExist(err) {
+				replicasHealth.markUnhealthy(addr)
+			}
+		}
+	}
+
+	return c.search(ctx, addrForRepo, repoName, buf.Bytes(), onMatches)
+}
+
+// search sends the encoded search request to the gitserver instance at addr.
+func (c *clientImplementor) search(ctx context.Context, addr string, repoName api.RepoName, body []byte, onMatches func([]protocol.CommitMatch)) (limitHit bool, err error) {
+	uri := "http://" + addr + "/search"
+	resp, err := c.do(ctx, repoName, "POST", uri, body)
 	if err != nil {
 		return false, err
 	}
@@ -839,6 +928,29 @@ func (c *clientImplementor) gitCommand(repo api.RepoName, arg ...string) GitComm
 	}
 }
 
+// command returns a command of the typed command API of gitserver, which
+// builds the arguments of the command from req.
+func (c *clientImplementor) command(req protocol.CommandRequest) (GitCommand, error) {
+	args, err := req.Args()
+	if err != nil {
+		return nil, err
+	}
+	repo := req.Options().Repo
+	if ClientMocks.LocalGitserver {
+		cmd := NewLocalGitCommand(repo, args...)
+		if ClientMocks.LocalGitCommandReposDir != "" {
+			cmd.ReposDir = ClientMocks.LocalGitCommandReposDir
+		}
+		return cmd, nil
+	}
+	return &RemoteGitCommand{
+		repo:    repo,
+		execFn:  c.httpPost,
+		args:    append([]string{git}, args...),
+		command: req,
+	}, nil
+}
+
 func (c *clientImplementor) RequestRepoUpdate(ctx context.Context, repo api.RepoName, since time.Duration) (*protocol.RepoUpdateResponse, error) {
 	req := &protocol.RepoUpdateRequest{
 		Repo:  repo,
@@ -1024,6 +1136,28 @@ func (c *clientImplementor) RepoCloneProgress(ctx context.Context, repos ...api.
 	return &res, err
 }
 
+func (c *clientImplementor) RepoMaintenanceStatus(ctx context.Context, repo api.RepoName) (*protocol.RepoMaintenanceStatus, error) {
+	resp, err := c.httpPost(ctx, repo, "repo-maintenance-status", &protocol.RepoMaintenanceStatusRequest{Repo: repo})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "RepoMaintenanceStatus",
+			Err: errors.Errorf("RepoMaintenanceStatus: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200))),
+		}
+	}
+
+	var status protocol.RepoMaintenanceStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
 func (c *clientImplementor) ReposStats(ctx context.Context) (map[string]*protocol.ReposStats, error) {
 	stats := map[string]*protocol.ReposStats{}
 	var allErr error
@@ -1196,6 +1330,37 @@ func (c *clientImplementor) CreateCommitFromPatch(ctx context.Context, req proto
 	return res.Rev, nil
 }
 
+func (c *clientImplementor) CreateCommitFromEdits(ctx context.Context, req protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error) {
+	resp, err := c.httpPost(ctx, req.Repo, "create-commit-from-edits", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Errors, including conflicts, are reported in the response body.
+	var res protocol.CreateCommitFromEditsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		c.logger.Warn("decoding gitserver create-commit-from-edits response", sglog.Error(err))
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "CreateCommitFromEdits",
+			Err: errors.Errorf("CreateCommitFromEdits: http status %d, %v", resp.StatusCode, err),
+		}
+	}
+
+	if res.Error != nil {
+		return &res, res.Error
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "CreateCommitFromEdits",
+			Err: errors.Errorf("CreateCommitFromEdits: http status %d", resp.StatusCode),
+		}
+	}
+	return &res, nil
+}
+
 func (c *clientImplementor) GetObject(ctx context.Context, repo api.RepoName, objectName string) (*gitdomain.GitObject, error) {
 	if ClientMocks.GetObject != nil {
 		return ClientMocks.GetObject(repo, objectName)
@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -552,6 +553,49 @@ func TestClient_ResolveRevisions(t *testing.T) {
 
 }
 
+func TestClient_CommandFallsBackToExec(t *testing.T) {
+	root := t.TempDir()
+	remote := createSimpleGitRepo(t, root)
+
+	db := newMockDB()
+	handler := (&server.Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: filepath.Join(root, "repos"),
+		GetRemoteURLFunc: func(_ context.Context, name api.RepoName) (string, error) {
+			return remote, nil
+		},
+		GetVCSSyncer: func(ctx context.Context, name api.RepoName) (server.VCSSyncer, error) {
+			return &server.GitRepoSyncer{}, nil
+		},
+		DB: db,
+	}).Handler()
+	// Simulate a gitserver instance that doesn't serve the typed command API.
+	var execCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/commands/"):
+			http.NotFound(w, r)
+			return
+		case r.URL.Path == "/exec":
+			atomic.AddInt32(&execCalls, 1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	cli := gitserver.NewTestClient(&http.Client{}, db, []string{u.Host})
+
+	ctx := context.Background()
+	_, err := cli.RequestRepoUpdate(ctx, api.RepoName(remote), 0)
+	require.NoError(t, err)
+
+	got, err := cli.ResolveRevision(ctx, api.RepoName(remote), "HEAD", gitserver.ResolveRevisionOptions{})
+	require.NoError(t, err)
+	require.Equal(t, api.CommitID("c5151eceb40d5e625716589b745248e1a6c6228d"), got)
+	require.Equal(t, int32(1), atomic.LoadInt32(&execCalls))
+}
+
 func TestClient_AddrForRepo_UsesConfToRead_PinnedRepos(t *testing.T) {
 	ctx := context.Background()
 	client := gitserver.NewTestClient(&http.Client{}, newMockDB(), []string{"gitserver1", "gitserver2"})
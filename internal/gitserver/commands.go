@@ -1,6 +1,7 @@
 package gitserver
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/hex"
@@ -10,6 +11,7 @@ import (
 	"io/fs"
 	"net/http"
 	"net/mail"
+	"net/url"
 	"os"
 	stdlibpath "path"
 	"path/filepath"
@@ -301,22 +303,22 @@ func (opts *CommitGraphOptions) LogFields() []log.Field {
 // be rooted at the given commit. If a non-zero limit is supplied, at most that
 // many commits will be returned.
 func (c *clientImplementor) CommitGraph(ctx context.Context, repo api.RepoName, opts CommitGraphOptions) (_ *gitdomain.CommitGraph, err error) {
-	args := []string{"log", "--pretty=%H %P", "--topo-order"}
-	if opts.AllRefs {
-		args = append(args, "--all")
+	req := &protocol.LogRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Format:         "%H %P",
+		TopoOrder:      true,
+		All:            opts.AllRefs,
+		Since:          opts.Since,
+		MaxCount:       opts.Limit,
 	}
 	if opts.Commit != "" {
-		args = append(args, opts.Commit)
+		req.Revisions = []string{opts.Commit}
 	}
-	if opts.Since != nil {
-		args = append(args, fmt.Sprintf("--since=%s", opts.Since.Format(time.RFC3339)))
-	}
-	if opts.Limit > 0 {
-		args = append(args, fmt.Sprintf("-%d", opts.Limit))
+	cmd, err := c.command(req)
+	if err != nil {
+		return nil, err
 	}
 
-	cmd := c.gitCommand(repo, args...)
-
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
 		return nil, err
@@ -360,7 +362,17 @@ func (c *clientImplementor) DiffPath(ctx context.Context, checker authz.SubRepoP
 
 // DiffSymbols performs a diff command which is expected to be parsed by our symbols package
 func (c *clientImplementor) DiffSymbols(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]byte, error) {
-	command := c.gitCommand(repo, "diff", "-z", "--name-status", "--no-renames", string(commitA), string(commitB))
+	command, err := c.command(&protocol.DiffRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Base:           string(commitA),
+		Head:           string(commitB),
+		NameStatus:     true,
+		NoRenames:      true,
+		NullTerminated: true,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return command.Output(ctx)
 }
 
@@ -517,20 +529,22 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 		return nil, err
 	}
 
-	args := []string{
-		"ls-tree",
-		"--long", // show size
-		"--full-name",
-		"-z",
-		string(commit),
-	}
-	if recurse {
-		args = append(args, "-r", "-t")
+	req := &protocol.LsTreeRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Tree:           string(commit),
+		Long:           true, // show size
+		FullName:       true,
+		NullTerminated: true,
+		Recursive:      recurse,
+		ShowTrees:      recurse,
 	}
 	if path != "" {
-		args = append(args, "--", filepath.ToSlash(path))
+		req.Paths = []string{filepath.ToSlash(path)}
+	}
+	cmd, err := c.command(req)
+	if err != nil {
+		return nil, err
 	}
-	cmd := c.gitCommand(repo, args...)
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
 		if bytes.Contains(out, []byte("exists on disk, but not in")) {
@@ -608,7 +622,13 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 			}
 		case "commit":
 			mode = mode | gitdomain.ModeSubmodule
-			cmd := c.gitCommand(repo, "show", fmt.Sprintf("%s:.gitmodules", commit))
+			cmd, err := c.command(&protocol.ShowRequest{
+				CommandOptions: protocol.CommandOptions{Repo: repo},
+				Object:         fmt.Sprintf("%s:.gitmodules", commit),
+			})
+			if err != nil {
+				return nil, err
+			}
 			var submodule gitdomain.Submodule
 			if out, err := cmd.Output(ctx); err == nil {
 
@@ -699,9 +719,77 @@ func (c *clientImplementor) BlameFile(ctx context.Context, checker authz.SubRepo
 	span.SetTag("path", path)
 	span.SetTag("opt", opt)
 	defer span.Finish()
+	if opt != nil && IsAbsoluteRevision(string(opt.NewestCommit)) && !ClientMocks.LocalGitserver {
+		// Blames of commits are cached by gitserver.
+		return c.blameFileCached(ctx, checker, repo, path, opt)
+	}
 	return blameFileCmd(ctx, c.gitserverGitCommandFunc(repo), path, opt, repo, checker)
 }
 
+func (c *clientImplementor) blameFileCached(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, path string, opt *BlameOptions) ([]*Hunk, error) {
+	a := actor.FromContext(ctx)
+	if hasAccess, err := authz.FilterActorPath(ctx, checker, a, repo, path); err != nil || !hasAccess {
+		return nil, err
+	}
+
+	req := &protocol.BlameRequest{
+		Repo:      repo,
+		Commit:    opt.NewestCommit,
+		Path:      filepath.ToSlash(path),
+		StartLine: opt.StartLine,
+		EndLine:   opt.EndLine,
+	}
+	resp, err := c.httpPostRead(ctx, repo, opt.NewestCommit, "commands/blame", req)
+	if err != nil {
+		return nil, err
+	}
+	if isUnknownRoute(resp) {
+		// gitserver instances that don't cache blames yet, for example during
+		// a rolling upgrade, run git blame with /exec instead.
+		resp.Body.Close()
+		return blameFileCmd(ctx, c.gitserverGitCommandFunc(repo), path, opt, repo, checker)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		var payload protocol.NotFoundPayload
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+		return nil, &gitdomain.RepoNotExistError{Repo: repo, CloneInProgress: payload.CloneInProgress, CloneProgress: payload.CloneProgress}
+	default:
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "BlameFile",
+			Err: errors.Errorf("BlameFile: http status %d, %s", resp.StatusCode, readResponseBody(resp.Body)),
+		}
+	}
+
+	var res protocol.BlameResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, errors.Wrap(err, "decoding blame response")
+	}
+	if len(res.Hunks) == 0 {
+		return nil, nil
+	}
+	hunks := make([]*Hunk, 0, len(res.Hunks))
+	for _, h := range res.Hunks {
+		hunks = append(hunks, &Hunk{
+			StartLine: h.StartLine,
+			EndLine:   h.EndLine,
+			StartByte: h.StartByte,
+			EndByte:   h.EndByte,
+			CommitID:  h.CommitID,
+			Author:    h.Author,
+			Message:   h.Message,
+			Filename:  h.Filename,
+		})
+	}
+	return hunks, nil
+}
+
 func blameFileCmd(ctx context.Context, command gitCommandFunc, path string, opt *BlameOptions, repo api.RepoName, checker authz.SubRepoPermissionChecker) ([]*Hunk, error) {
 	a := actor.FromContext(ctx)
 	if hasAccess, err := authz.FilterActorPath(ctx, checker, a, repo, path); err != nil || !hasAccess {
@@ -903,7 +991,13 @@ func (c *clientImplementor) ResolveRevision(ctx context.Context, repo api.RepoNa
 		spec = spec + "^0"
 	}
 
-	cmd := c.gitCommand(repo, "rev-parse", spec)
+	cmd, err := c.command(&protocol.RevParseRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Revisions:      []string{spec},
+	})
+	if err != nil {
+		return "", err
+	}
 	cmd.SetEnsureRevision(spec)
 
 	// We don't ever need to ensure that HEAD is in git-server.
@@ -979,7 +1073,15 @@ func (c *clientImplementor) LsFiles(ctx context.Context, checker authz.SubRepoPe
 // ListFiles returns a list of root-relative file paths matching the given
 // pattern in a particular commit of a repository.
 func (c *clientImplementor) ListFiles(ctx context.Context, repo api.RepoName, commit api.CommitID, pattern *regexp.Regexp, checker authz.SubRepoPermissionChecker) (_ []string, err error) {
-	cmd := c.gitCommand(repo, "ls-tree", "--name-only", "-r", string(commit), "--")
+	cmd, err := c.command(&protocol.LsTreeRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Tree:           string(commit),
+		NameOnly:       true,
+		Recursive:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
@@ -1020,9 +1122,15 @@ func (c *clientImplementor) ListDirectoryChildren(
 	commit api.CommitID,
 	dirnames []string,
 ) (map[string][]string, error) {
-	args := []string{"ls-tree", "--name-only", string(commit), "--"}
-	args = append(args, cleanDirectoriesForLsTree(dirnames)...)
-	cmd := c.gitCommand(repo, args...)
+	cmd, err := c.command(&protocol.LsTreeRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Tree:           string(commit),
+		NameOnly:       true,
+		Paths:          cleanDirectoriesForLsTree(dirnames),
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
@@ -1325,7 +1433,7 @@ func (c *clientImplementor) newBlobReader(ctx context.Context, repo api.RepoName
 		return nil, err
 	}
 
-	cmd := c.gitCommand(repo, "show", string(commit)+":"+name)
+	cmd := c.readGitCommand(repo, commit, "show", string(commit)+":"+name)
 	stdout, err := cmd.StdoutReader(ctx)
 	if err != nil {
 		return nil, err
@@ -1560,17 +1668,21 @@ func hasAccessToCommit(ctx context.Context, commit *wrappedCommit, repoName api.
 // supplied branch name is the default branch, then this method instead returns
 // all commits reachable from HEAD.
 func (c *clientImplementor) CommitsUniqueToBranch(ctx context.Context, repo api.RepoName, branchName string, isDefaultBranch bool, maxAge *time.Time, checker authz.SubRepoPermissionChecker) (_ map[string]time.Time, err error) {
-	args := []string{"log", "--pretty=format:%H:%cI"}
-	if maxAge != nil {
-		args = append(args, fmt.Sprintf("--after=%s", *maxAge))
+	req := &protocol.LogRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Format:         "%H:%cI",
+		Since:          maxAge,
 	}
 	if isDefaultBranch {
-		args = append(args, "HEAD")
+		req.Revisions = []string{"HEAD"}
 	} else {
-		args = append(args, branchName, "^HEAD")
+		req.Revisions = []string{branchName, "^HEAD"}
 	}
 
-	cmd := c.gitCommand(repo, args...)
+	cmd, err := c.command(req)
+	if err != nil {
+		return nil, err
+	}
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
 		return nil, err
@@ -1684,7 +1796,12 @@ func (c *clientImplementor) getWrappedCommits(ctx context.Context, repo api.Repo
 		return nil, err
 	}
 
-	cmd := c.gitCommand(repo, args...)
+	var cmd GitCommand
+	if gitdomain.IsAbsoluteRevision(opt.Range) {
+		cmd = c.readGitCommand(repo, api.CommitID(opt.Range), args...)
+	} else {
+		cmd = c.gitCommand(repo, args...)
+	}
 	if !opt.NoEnsureRevision {
 		cmd.SetEnsureRevision(opt.Range)
 	}
@@ -1890,6 +2007,148 @@ func (c *clientImplementor) FirstEverCommit(ctx context.Context, repo api.RepoNa
 	return c.GetCommit(ctx, repo, id, ResolveRevisionOptions{NoEnsureRevision: true}, checker)
 }
 
+// lastCommitLogFormat prints one \x1e-prefixed record per commit, followed by
+// the names of the files it modified when combined with --name-only.
+const lastCommitLogFormat = "%x1e%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct"
+
+// LastCommitsForPaths returns the last commit reachable from commit that
+// modified each of the given paths. Paths that were never modified (or
+// cannot be read by the current actor) are omitted from the result.
+func (c *clientImplementor) LastCommitsForPaths(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string, checker authz.SubRepoPermissionChecker) (_ map[string]*gitdomain.Commit, err error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: LastCommitsForPaths")
+	span.SetTag("Commit", commit)
+	span.SetTag("Paths", len(paths))
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
+
+	if err := checkSpecArgSafety(string(commit)); err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		if authz.SubRepoEnabled(checker) {
+			if ok, err := authz.CanReadAllPaths(ctx, checker, repo, []string{path}); err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+		}
+		pending[path] = struct{}{}
+	}
+	if len(pending) == 0 {
+		return map[string]*gitdomain.Commit{}, nil
+	}
+
+	// -z disables quoting of unusual paths in the --name-only output.
+	req := &protocol.LogRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Format:         lastCommitLogFormat,
+		NullTerminated: true,
+		NameOnly:       true,
+		NoRenames:      true,
+		Revisions:      []string{string(commit)},
+	}
+	for path := range pending {
+		req.Paths = append(req.Paths, ":(literal)"+path)
+	}
+
+	cmd, err := c.command(req)
+	if err != nil {
+		return nil, err
+	}
+	cmd.SetEnsureRevision(string(commit))
+	rc, err := cmd.StdoutReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Closing the reader early stops git once all paths have been found.
+	defer rc.Close()
+
+	commits := make(map[string]*gitdomain.Commit, len(pending))
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	scanner.Split(splitLogRecords)
+	for len(pending) > 0 && scanner.Scan() {
+		lastCommit, files, err := parseLastCommitRecord(scanner.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if _, ok := pending[file]; ok {
+				commits[file] = lastCommit
+				delete(pending, file)
+			}
+		}
+	}
+	if len(pending) > 0 {
+		// We only get here after consuming the whole log, so errors are
+		// meaningful.
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return commits, nil
+}
+
+// splitLogRecords is a bufio.SplitFunc for records formatted with
+// lastCommitLogFormat.
+func splitLogRecords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) > 0 && data[0] == '\x1e' {
+		if i := bytes.IndexByte(data[1:], '\x1e'); i >= 0 {
+			return i + 1, data[1 : i+1], nil
+		}
+		if atEOF {
+			return len(data), data[1:], nil
+		}
+		return 0, nil, nil
+	}
+	if atEOF {
+		return len(data), nil, nil
+	}
+	if i := bytes.IndexByte(data, '\x1e'); i >= 0 {
+		return i, nil, nil
+	}
+	return len(data), nil, nil
+}
+
+// parseLastCommitRecord parses a single record formatted with
+// lastCommitLogFormat.
+func parseLastCommitRecord(record []byte) (*gitdomain.Commit, []string, error) {
+	header, names, _ := bytes.Cut(record, []byte{'\n'})
+	parts := bytes.Split(header, []byte{'\x00'})
+	if len(parts) != 7 {
+		return nil, nil, errors.Errorf("invalid commit log entry: %q", header)
+	}
+
+	authorTime, err := strconv.ParseInt(string(parts[3]), 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("parsing git commit author time: %s", err)
+	}
+	committerTime, err := strconv.ParseInt(string(parts[6]), 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("parsing git commit committer time: %s", err)
+	}
+
+	var files []string
+	for _, name := range bytes.Split(names, []byte{'\x00'}) {
+		if len(name) > 0 {
+			files = append(files, string(name))
+		}
+	}
+
+	return &gitdomain.Commit{
+		ID:        api.CommitID(parts[0]),
+		Author:    gitdomain.Signature{Name: string(parts[1]), Email: string(parts[2]), Date: time.Unix(authorTime, 0).UTC()},
+		Committer: &gitdomain.Signature{Name: string(parts[4]), Email: string(parts[5]), Date: time.Unix(committerTime, 0).UTC()},
+	}, files, nil
+}
+
 // CommitExists determines if the given commit exists in the given repository.
 func (c *clientImplementor) CommitExists(ctx context.Context, repo api.RepoName, id api.CommitID, checker authz.SubRepoPermissionChecker) (bool, error) {
 	commit, err := c.getCommit(ctx, repo, id, ResolveRevisionOptions{NoEnsureRevision: true}, checker)
@@ -2018,7 +2277,13 @@ func (c *clientImplementor) GetCommits(ctx context.Context, repoCommits []api.Re
 // repositories), a false-valued flag is returned along with a nil error and
 // empty revision.
 func (c *clientImplementor) Head(ctx context.Context, repo api.RepoName, checker authz.SubRepoPermissionChecker) (_ string, revisionExists bool, err error) {
-	cmd := c.gitCommand(repo, "rev-parse", "HEAD")
+	cmd, err := c.command(&protocol.RevParseRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Revisions:      []string{"HEAD"},
+	})
+	if err != nil {
+		return "", false, err
+	}
 
 	out, err := cmd.Output(ctx)
 	if err != nil {
@@ -2313,7 +2578,15 @@ func (c *clientImplementor) CommitDate(ctx context.Context, repo api.RepoName, c
 		}
 	}
 
-	cmd := c.gitCommand(repo, "show", "-s", "--format=%H:%cI", string(commit))
+	cmd, err := c.command(&protocol.ShowRequest{
+		CommandOptions: protocol.CommandOptions{Repo: repo},
+		Object:         string(commit),
+		NoPatch:        true,
+		Format:         "%H:%cI",
+	})
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
 
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
@@ -2387,14 +2660,35 @@ func (c *clientImplementor) ArchiveReader(
 		return nil, err
 	}
 
-	u, err := c.archiveURL(ctx, repo, options)
+	addr, err := c.AddrForRepo(ctx, repo)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.do(ctx, repo, "POST", u.String(), nil)
-	if err != nil {
-		return nil, err
+	var resp *http.Response
+	// Archives of specific commits may be served by a secondary instance. If it
+	// fails, we request the archive from the primary instance.
+	if gitdomain.IsAbsoluteRevision(options.Treeish) {
+		replica, primary, err := c.readAddrForRepo(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		if replica != primary {
+			resp, err = c.do(ctx, repo, "POST", archiveURL(replica, repo, options).String(), nil)
+			if replicaFailed(replica, resp, err) {
+				if resp != nil {
+					resp.Body.Close()
+				}
+				resp = nil
+			}
+		}
+	}
+
+	if resp == nil {
+		resp, err = c.do(ctx, repo, "POST", archiveURL(addr, repo, options).String(), nil)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	switch resp.StatusCode {
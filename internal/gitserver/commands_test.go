@@ -3,9 +3,11 @@ package gitserver
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -25,6 +27,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -516,6 +520,49 @@ func runBlameFileTest(ctx context.Context, t *testing.T, repo api.RepoName, path
 	}
 }
 
+func TestClient_BlameFileCached(t *testing.T) {
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	commit := api.CommitID(strings.Repeat("a", 40))
+	author := gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")}
+
+	var status int
+	cli := NewTestClient(httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		var req protocol.BlameRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if r.URL.Path != "/commands/blame" || req.Commit != commit || req.Path != "f" || req.StartLine != 2 || req.EndLine != 3 {
+			t.Fatalf("unexpected request to %s: %+v", r.URL.Path, req)
+		}
+
+		var body any = protocol.BlameResponse{Hunks: []*protocol.BlameHunk{
+			{StartLine: 2, EndLine: 4, StartByte: 0, EndByte: 6, CommitID: commit, Author: author, Message: "foo", Filename: "f"},
+		}}
+		if status == http.StatusNotFound {
+			body = protocol.NotFoundPayload{CloneInProgress: true}
+		}
+		b, _ := json.Marshal(body)
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(b)), Request: r}, nil
+	}), database.NewMockDB(), []string{"gitserver-0"})
+
+	status = http.StatusOK
+	hunks, err := cli.BlameFile(context.Background(), nil, repo, "f", &BlameOptions{NewestCommit: commit, StartLine: 2, EndLine: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Hunk{{StartLine: 2, EndLine: 4, StartByte: 0, EndByte: 6, CommitID: commit, Author: author, Message: "foo", Filename: "f"}}
+	if diff := cmp.Diff(want, hunks); diff != "" {
+		t.Errorf("unexpected hunks (-want +got):\n%s", diff)
+	}
+
+	status = http.StatusNotFound
+	_, err = cli.BlameFile(context.Background(), nil, repo, "f", &BlameOptions{NewestCommit: commit, StartLine: 2, EndLine: 3})
+	var notExist *gitdomain.RepoNotExistError
+	if !errors.As(err, &notExist) || !notExist.CloneInProgress {
+		t.Errorf("expected clone in progress error, got %v", err)
+	}
+}
+
 func TestIsAbsoluteRevision(t *testing.T) {
 	yes := []string{"8cb03d28ad1c6a875f357c5d862237577b06e57c", "20697a062454c29d84e3f006b22eb029d730cd00"}
 	no := []string{"ref: refs/heads/appsinfra/SHEP-20-review", "master", "HEAD", "refs/heads/master", "20697a062454c29d84e3f006b22eb029d730cd0", "20697a062454c29d84e3f006b22eb029d730cd000", "  20697a062454c29d84e3f006b22eb029d730cd00  ", "20697a062454c29d84e3f006b22eb029d730cd0 "}
@@ -2817,3 +2864,43 @@ func testBranches(t *testing.T, gitCommands []string, wantBranches []*gitdomain.
 		t.Fatalf("Branch mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestRepository_LastCommitsForPaths(t *testing.T) {
+	ClientMocks.LocalGitserver = true
+	defer ResetClientMocks()
+
+	ctx := context.Background()
+
+	repo := MakeGitRepository(t,
+		"echo a > a",
+		"echo b > 'b c'",
+		"git add a 'b c'",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"echo a >> a",
+		"git add a",
+		"GIT_COMMITTER_NAME=b GIT_COMMITTER_EMAIL=b@b.com GIT_COMMITTER_DATE=2007-01-02T15:04:05Z git commit -m bar --author='b <b@b.com>' --date 2007-01-02T15:04:05Z",
+	)
+
+	client := NewClient(database.NewMockDB())
+	head, err := client.ResolveRevision(ctx, repo, "HEAD", ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := client.LastCommitsForPaths(ctx, repo, head, []string{"a", "b c", "missing"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string, len(commits))
+	for path, c := range commits {
+		got[path] = c.Author.Name + " " + c.Committer.Date.Format(time.RFC3339)
+	}
+	want := map[string]string{
+		"a":   "b 2007-01-02T15:04:05Z",
+		"b c": "a 2006-01-02T15:04:05Z",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected last commits (-want +got):\n%s", diff)
+	}
+}
@@ -163,6 +163,9 @@ type RemoteGitCommand struct {
 	noTimeout      bool
 	exitStatus     int
 	execFn         func(ctx context.Context, repo api.RepoName, op string, payload any) (resp *http.Response, err error)
+	// command is the request of the typed command API to send instead of
+	// args, if set.
+	command protocol.CommandRequest
 }
 
 // DividedOutput runs the command and returns its standard output and standard error.
@@ -65,6 +65,9 @@ type MockClient struct {
 	// ContributorCountFunc is an instance of a mock function object
 	// controlling the behavior of the method ContributorCount.
 	ContributorCountFunc *ClientContributorCountFunc
+	// CreateCommitFromEditsFunc is an instance of a mock function object
+	// controlling the behavior of the method CreateCommitFromEdits.
+	CreateCommitFromEditsFunc *ClientCreateCommitFromEditsFunc
 	// CreateCommitFromPatchFunc is an instance of a mock function object
 	// controlling the behavior of the method CreateCommitFromPatch.
 	CreateCommitFromPatchFunc *ClientCreateCommitFromPatchFunc
@@ -104,6 +107,9 @@ type MockClient struct {
 	// IsRepoCloneableFunc is an instance of a mock function object
 	// controlling the behavior of the method IsRepoCloneable.
 	IsRepoCloneableFunc *ClientIsRepoCloneableFunc
+	// LastCommitsForPathsFunc is an instance of a mock function object
+	// controlling the behavior of the method LastCommitsForPaths.
+	LastCommitsForPathsFunc *ClientLastCommitsForPathsFunc
 	// ListBranchesFunc is an instance of a mock function object controlling
 	// the behavior of the method ListBranches.
 	ListBranchesFunc *ClientListBranchesFunc
@@ -155,6 +161,9 @@ type MockClient struct {
 	// RepoCloneProgressFunc is an instance of a mock function object
 	// controlling the behavior of the method RepoCloneProgress.
 	RepoCloneProgressFunc *ClientRepoCloneProgressFunc
+	// RepoMaintenanceStatusFunc is an instance of a mock function object
+	// controlling the behavior of the method RepoMaintenanceStatus.
+	RepoMaintenanceStatusFunc *ClientRepoMaintenanceStatusFunc
 	// ReposStatsFunc is an instance of a mock function object controlling
 	// the behavior of the method ReposStats.
 	ReposStatsFunc *ClientReposStatsFunc
@@ -250,6 +259,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		CreateCommitFromEditsFunc: &ClientCreateCommitFromEditsFunc{
+			defaultHook: func(context.Context, protocol.CreateCommitFromEditsRequest) (r0 *protocol.CreateCommitFromEditsResponse, r1 error) {
+				return
+			},
+		},
 		CreateCommitFromPatchFunc: &ClientCreateCommitFromPatchFunc{
 			defaultHook: func(context.Context, protocol.CreateCommitFromPatchRequest) (r0 string, r1 error) {
 				return
@@ -315,6 +329,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		LastCommitsForPathsFunc: &ClientLastCommitsForPathsFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (r0 map[string]*gitdomain.Commit, r1 error) {
+				return
+			},
+		},
 		ListBranchesFunc: &ClientListBranchesFunc{
 			defaultHook: func(context.Context, api.RepoName, BranchesOptions) (r0 []*gitdomain.Branch, r1 error) {
 				return
@@ -400,6 +419,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		RepoMaintenanceStatusFunc: &ClientRepoMaintenanceStatusFunc{
+			defaultHook: func(context.Context, api.RepoName) (r0 *protocol.RepoMaintenanceStatus, r1 error) {
+				return
+			},
+		},
 		ReposStatsFunc: &ClientReposStatsFunc{
 			defaultHook: func(context.Context) (r0 map[string]*protocol.ReposStats, r1 error) {
 				return
@@ -512,6 +536,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.ContributorCount")
 			},
 		},
+		CreateCommitFromEditsFunc: &ClientCreateCommitFromEditsFunc{
+			defaultHook: func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error) {
+				panic("unexpected invocation of MockClient.CreateCommitFromEdits")
+			},
+		},
 		CreateCommitFromPatchFunc: &ClientCreateCommitFromPatchFunc{
 			defaultHook: func(context.Context, protocol.CreateCommitFromPatchRequest) (string, error) {
 				panic("unexpected invocation of MockClient.CreateCommitFromPatch")
@@ -577,6 +606,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.IsRepoCloneable")
 			},
 		},
+		LastCommitsForPathsFunc: &ClientLastCommitsForPathsFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error) {
+				panic("unexpected invocation of MockClient.LastCommitsForPaths")
+			},
+		},
 		ListBranchesFunc: &ClientListBranchesFunc{
 			defaultHook: func(context.Context, api.RepoName, BranchesOptions) ([]*gitdomain.Branch, error) {
 				panic("unexpected invocation of MockClient.ListBranches")
@@ -662,6 +696,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.RepoCloneProgress")
 			},
 		},
+		RepoMaintenanceStatusFunc: &ClientRepoMaintenanceStatusFunc{
+			defaultHook: func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error) {
+				panic("unexpected invocation of MockClient.RepoMaintenanceStatus")
+			},
+		},
 		ReposStatsFunc: &ClientReposStatsFunc{
 			defaultHook: func(context.Context) (map[string]*protocol.ReposStats, error) {
 				panic("unexpected invocation of MockClient.ReposStats")
@@ -748,6 +787,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		ContributorCountFunc: &ClientContributorCountFunc{
 			defaultHook: i.ContributorCount,
 		},
+		CreateCommitFromEditsFunc: &ClientCreateCommitFromEditsFunc{
+			defaultHook: i.CreateCommitFromEdits,
+		},
 		CreateCommitFromPatchFunc: &ClientCreateCommitFromPatchFunc{
 			defaultHook: i.CreateCommitFromPatch,
 		},
@@ -787,6 +829,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		IsRepoCloneableFunc: &ClientIsRepoCloneableFunc{
 			defaultHook: i.IsRepoCloneable,
 		},
+		LastCommitsForPathsFunc: &ClientLastCommitsForPathsFunc{
+			defaultHook: i.LastCommitsForPaths,
+		},
 		ListBranchesFunc: &ClientListBranchesFunc{
 			defaultHook: i.ListBranches,
 		},
@@ -838,6 +883,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		RepoCloneProgressFunc: &ClientRepoCloneProgressFunc{
 			defaultHook: i.RepoCloneProgress,
 		},
+		RepoMaintenanceStatusFunc: &ClientRepoMaintenanceStatusFunc{
+			defaultHook: i.RepoMaintenanceStatus,
+		},
 		ReposStatsFunc: &ClientReposStatsFunc{
 			defaultHook: i.ReposStats,
 		},
@@ -2317,6 +2365,115 @@ func (c ClientContributorCountFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientCreateCommitFromEditsFunc describes the behavior when the
+// CreateCommitFromEdits method of the parent MockClient instance is
+// invoked.
+type ClientCreateCommitFromEditsFunc struct {
+	defaultHook func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error)
+	hooks       []func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error)
+	history     []ClientCreateCommitFromEditsFuncCall
+	mutex       sync.Mutex
+}
+
+// CreateCommitFromEdits delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockClient) CreateCommitFromEdits(v0 context.Context, v1 protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error) {
+	r0, r1 := m.CreateCommitFromEditsFunc.nextHook()(v0, v1)
+	m.CreateCommitFromEditsFunc.appendCall(ClientCreateCommitFromEditsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// CreateCommitFromEdits method of the parent MockClient instance is invoked
+// and the hook queue is empty.
+func (f *ClientCreateCommitFromEditsFunc) SetDefaultHook(hook func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CreateCommitFromEdits method of the parent MockClient instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *ClientCreateCommitFromEditsFunc) PushHook(hook func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientCreateCommitFromEditsFunc) SetDefaultReturn(r0 *protocol.CreateCommitFromEditsResponse, r1 error) {
+	f.SetDefaultHook(func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientCreateCommitFromEditsFunc) PushReturn(r0 *protocol.CreateCommitFromEditsResponse, r1 error) {
+	f.PushHook(func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientCreateCommitFromEditsFunc) nextHook() func(context.Context, protocol.CreateCommitFromEditsRequest) (*protocol.CreateCommitFromEditsResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientCreateCommitFromEditsFunc) appendCall(r0 ClientCreateCommitFromEditsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientCreateCommitFromEditsFuncCall objects
+// describing the invocations of this function.
+func (f *ClientCreateCommitFromEditsFunc) History() []ClientCreateCommitFromEditsFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientCreateCommitFromEditsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientCreateCommitFromEditsFuncCall is an object that describes an
+// invocation of method CreateCommitFromEdits on an instance of MockClient.
+type ClientCreateCommitFromEditsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 protocol.CreateCommitFromEditsRequest
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *protocol.CreateCommitFromEditsResponse
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientCreateCommitFromEditsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientCreateCommitFromEditsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientCreateCommitFromPatchFunc describes the behavior when the
 // CreateCommitFromPatch method of the parent MockClient instance is
 // invoked.
@@ -3781,6 +3938,123 @@ func (c ClientIsRepoCloneableFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// ClientLastCommitsForPathsFunc describes the behavior when the
+// LastCommitsForPaths method of the parent MockClient instance is invoked.
+type ClientLastCommitsForPathsFunc struct {
+	defaultHook func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error)
+	history     []ClientLastCommitsForPathsFuncCall
+	mutex       sync.Mutex
+}
+
+// LastCommitsForPaths delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) LastCommitsForPaths(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 []string, v4 authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error) {
+	r0, r1 := m.LastCommitsForPathsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.LastCommitsForPathsFunc.appendCall(ClientLastCommitsForPathsFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the LastCommitsForPaths
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientLastCommitsForPathsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// LastCommitsForPaths method of the parent MockClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ClientLastCommitsForPathsFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientLastCommitsForPathsFunc) SetDefaultReturn(r0 map[string]*gitdomain.Commit, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientLastCommitsForPathsFunc) PushReturn(r0 map[string]*gitdomain.Commit, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientLastCommitsForPathsFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, []string, authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientLastCommitsForPathsFunc) appendCall(r0 ClientLastCommitsForPathsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientLastCommitsForPathsFuncCall objects
+// describing the invocations of this function.
+func (f *ClientLastCommitsForPathsFunc) History() []ClientLastCommitsForPathsFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientLastCommitsForPathsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientLastCommitsForPathsFuncCall is an object that describes an
+// invocation of method LastCommitsForPaths on an instance of MockClient.
+type ClientLastCommitsForPathsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 authz.SubRepoPermissionChecker
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string]*gitdomain.Commit
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientLastCommitsForPathsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientLastCommitsForPathsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientListBranchesFunc describes the behavior when the ListBranches
 // method of the parent MockClient instance is invoked.
 type ClientListBranchesFunc struct {
@@ -5726,6 +6000,112 @@ func (c ClientRepoCloneProgressFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientRepoMaintenanceStatusFunc describes the behavior when the
+// RepoMaintenanceStatus method of the parent MockClient instance is invoked.
+type ClientRepoMaintenanceStatusFunc struct {
+	defaultHook func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error)
+	hooks       []func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error)
+	history     []ClientRepoMaintenanceStatusFuncCall
+	mutex       sync.Mutex
+}
+
+// RepoMaintenanceStatus delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) RepoMaintenanceStatus(v0 context.Context, v1 api.RepoName) (*protocol.RepoMaintenanceStatus, error) {
+	r0, r1 := m.RepoMaintenanceStatusFunc.nextHook()(v0, v1)
+	m.RepoMaintenanceStatusFunc.appendCall(ClientRepoMaintenanceStatusFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RepoMaintenanceStatus
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientRepoMaintenanceStatusFunc) SetDefaultHook(hook func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RepoMaintenanceStatus method of the parent MockClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ClientRepoMaintenanceStatusFunc) PushHook(hook func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientRepoMaintenanceStatusFunc) SetDefaultReturn(r0 *protocol.RepoMaintenanceStatus, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientRepoMaintenanceStatusFunc) PushReturn(r0 *protocol.RepoMaintenanceStatus, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientRepoMaintenanceStatusFunc) nextHook() func(context.Context, api.RepoName) (*protocol.RepoMaintenanceStatus, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientRepoMaintenanceStatusFunc) appendCall(r0 ClientRepoMaintenanceStatusFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientRepoMaintenanceStatusFuncCall objects
+// describing the invocations of this function.
+func (f *ClientRepoMaintenanceStatusFunc) History() []ClientRepoMaintenanceStatusFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientRepoMaintenanceStatusFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientRepoMaintenanceStatusFuncCall is an object that describes an
+// invocation of method RepoMaintenanceStatus on an instance of MockClient.
+type ClientRepoMaintenanceStatusFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method invocation.
+	Arg1 api.RepoName
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *protocol.RepoMaintenanceStatus
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientRepoMaintenanceStatusFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientRepoMaintenanceStatusFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientReposStatsFunc describes the behavior when the ReposStats method of
 // the parent MockClient instance is invoked.
 type ClientReposStatsFunc struct {
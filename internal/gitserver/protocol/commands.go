@@ -0,0 +1,221 @@
+package protocol
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The typed command API of gitserver runs read-only git commands described by
+// request structs instead of arbitrary arguments. Each command has its own
+// endpoint /commands/<name>, and gitserver builds the arguments of the command
+// from the request with Args, so that only the options below can be passed to
+// git. The response streams the standard output of the command, followed by
+// the same trailers as /exec.
+
+// CommandRequest is a request of the typed command API.
+type CommandRequest interface {
+	// Options returns the options shared by all commands.
+	Options() *CommandOptions
+	// Command returns the name of the git command, which is also the last
+	// path element of its endpoint.
+	Command() string
+	// Args returns the arguments to git, starting with the command. It
+	// returns an error if the request is invalid.
+	Args() ([]string, error)
+}
+
+// CommandOptions are the options shared by all requests of the typed command
+// API.
+type CommandOptions struct {
+	Repo api.RepoName `json:"repo"`
+	// EnsureRevision is fetched from the code host before running the command
+	// if the repository doesn't have it.
+	EnsureRevision string `json:"ensureRevision"`
+}
+
+func (o *CommandOptions) Options() *CommandOptions { return o }
+
+// LogRequest is a request to list commits with git log.
+type LogRequest struct {
+	CommandOptions
+	// Format is the format of each commit, for example "%H %P". See the
+	// documentation for "--format" in git-log.
+	Format string `json:"format"`
+	// Revisions are the revisions to list the commits of. If empty, HEAD is
+	// used.
+	Revisions []string `json:"revisions"`
+	// All lists the commits of all refs.
+	All bool `json:"all"`
+	// TopoOrder lists parents after all of their children.
+	TopoOrder bool `json:"topoOrder"`
+	// Since only lists the commits after this time, if set.
+	Since *time.Time `json:"since"`
+	// MaxCount limits the number of commits, if positive.
+	MaxCount int `json:"maxCount"`
+	// NameOnly lists the names of the files each commit changed.
+	NameOnly bool `json:"nameOnly"`
+	// NoRenames doesn't detect renames in the changed files.
+	NoRenames bool `json:"noRenames"`
+	// NullTerminated separates commits and paths with NUL bytes.
+	NullTerminated bool `json:"nullTerminated"`
+	// Paths limits the commits to those that changed these pathspecs.
+	Paths []string `json:"paths"`
+}
+
+func (r *LogRequest) Command() string { return "log" }
+
+func (r *LogRequest) Args() ([]string, error) {
+	args := []string{"log"}
+	if r.Format != "" {
+		args = append(args, "--format=format:"+r.Format)
+	}
+	args = appendFlag(args, r.All, "--all")
+	args = appendFlag(args, r.TopoOrder, "--topo-order")
+	if r.Since != nil {
+		args = append(args, "--since="+r.Since.Format(time.RFC3339))
+	}
+	if r.MaxCount > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(r.MaxCount))
+	}
+	args = appendFlag(args, r.NameOnly, "--name-only")
+	args = appendFlag(args, r.NoRenames, "--no-renames")
+	args = appendFlag(args, r.NullTerminated, "-z")
+	return appendRevisionsAndPaths(args, r.Revisions, r.Paths)
+}
+
+// DiffRequest is a request to compare two trees with git diff.
+type DiffRequest struct {
+	CommandOptions
+	// Base and Head are the revisions to compare.
+	Base string `json:"base"`
+	Head string `json:"head"`
+	// NameStatus only lists the names and statuses of the changed files.
+	NameStatus bool `json:"nameStatus"`
+	// NoRenames doesn't detect renames.
+	NoRenames bool `json:"noRenames"`
+	// NullTerminated separates paths with NUL bytes.
+	NullTerminated bool `json:"nullTerminated"`
+	// Paths limits the diff to these pathspecs.
+	Paths []string `json:"paths"`
+}
+
+func (r *DiffRequest) Command() string { return "diff" }
+
+func (r *DiffRequest) Args() ([]string, error) {
+	if r.Base == "" || r.Head == "" {
+		return nil, errors.New("diff requires a base and a head revision")
+	}
+	args := appendFlag([]string{"diff"}, r.NameStatus, "--name-status")
+	args = appendFlag(args, r.NoRenames, "--no-renames")
+	args = appendFlag(args, r.NullTerminated, "-z")
+	return appendRevisionsAndPaths(args, []string{r.Base, r.Head}, r.Paths)
+}
+
+// ShowRequest is a request to show an object with git show.
+type ShowRequest struct {
+	CommandOptions
+	// Object is the object to show, for example a commit or <commit>:<path>
+	// for a file.
+	Object string `json:"object"`
+	// Format is the format of a commit, for example "%H:%cI". See the
+	// documentation for "--format" in git-show.
+	Format string `json:"format"`
+	// NoPatch doesn't show the diff of a commit.
+	NoPatch bool `json:"noPatch"`
+}
+
+func (r *ShowRequest) Command() string { return "show" }
+
+func (r *ShowRequest) Args() ([]string, error) {
+	if r.Object == "" {
+		return nil, errors.New("show requires an object")
+	}
+	args := appendFlag([]string{"show"}, r.NoPatch, "--no-patch")
+	if r.Format != "" {
+		args = append(args, "--format=format:"+r.Format)
+	}
+	return appendRevisionsAndPaths(args, []string{r.Object}, nil)
+}
+
+// LsTreeRequest is a request to list the contents of a tree with git ls-tree.
+type LsTreeRequest struct {
+	CommandOptions
+	// Tree is the tree or commit to list.
+	Tree string `json:"tree"`
+	// Long shows the size of blobs.
+	Long bool `json:"long"`
+	// FullName shows paths relative to the root of the repository.
+	FullName bool `json:"fullName"`
+	// NameOnly only lists the paths.
+	NameOnly bool `json:"nameOnly"`
+	// Recursive lists the contents of subtrees.
+	Recursive bool `json:"recursive"`
+	// ShowTrees lists the subtrees themselves when listing recursively.
+	ShowTrees bool `json:"showTrees"`
+	// NullTerminated separates entries with NUL bytes.
+	NullTerminated bool `json:"nullTerminated"`
+	// Paths limits the listing to these paths.
+	Paths []string `json:"paths"`
+}
+
+func (r *LsTreeRequest) Command() string { return "ls-tree" }
+
+func (r *LsTreeRequest) Args() ([]string, error) {
+	if r.Tree == "" {
+		return nil, errors.New("ls-tree requires a tree")
+	}
+	args := appendFlag([]string{"ls-tree"}, r.Long, "--long")
+	args = appendFlag(args, r.FullName, "--full-name")
+	args = appendFlag(args, r.NameOnly, "--name-only")
+	args = appendFlag(args, r.Recursive, "-r")
+	args = appendFlag(args, r.ShowTrees, "-t")
+	args = appendFlag(args, r.NullTerminated, "-z")
+	return appendRevisionsAndPaths(args, []string{r.Tree}, r.Paths)
+}
+
+// RevParseRequest is a request to resolve revisions to object IDs with git
+// rev-parse.
+type RevParseRequest struct {
+	CommandOptions
+	// Revisions are the revisions to resolve, one object ID per line.
+	Revisions []string `json:"revisions"`
+}
+
+func (r *RevParseRequest) Command() string { return "rev-parse" }
+
+func (r *RevParseRequest) Args() ([]string, error) {
+	if len(r.Revisions) == 0 {
+		return nil, errors.New("rev-parse requires a revision")
+	}
+	args, err := appendRevisionsAndPaths([]string{"rev-parse"}, r.Revisions, nil)
+	if err != nil {
+		return nil, err
+	}
+	// rev-parse doesn't accept paths, but prints the "--" separator.
+	return args[:len(args)-1], nil
+}
+
+func appendFlag(args []string, set bool, flag string) []string {
+	if set {
+		return append(args, flag)
+	}
+	return args
+}
+
+// appendRevisionsAndPaths appends the revisions and pathspecs to args,
+// separated by "--". Revisions can't start with "-", so that they aren't
+// interpreted as options.
+func appendRevisionsAndPaths(args, revisions, paths []string) ([]string, error) {
+	for _, rev := range revisions {
+		if rev == "" || strings.HasPrefix(rev, "-") {
+			return nil, errors.Errorf("invalid git revision spec %q", rev)
+		}
+		args = append(args, rev)
+	}
+	args = append(args, "--")
+	return append(args, paths...), nil
+}
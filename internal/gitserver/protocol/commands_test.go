@@ -0,0 +1,65 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCommandRequestArgs(t *testing.T) {
+	since := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		req  CommandRequest
+		want []string
+	}{
+		{
+			req:  &LogRequest{Format: "%H %P", TopoOrder: true, Since: &since, MaxCount: 10, Revisions: []string{"main", "^HEAD"}},
+			want: []string{"log", "--format=format:%H %P", "--topo-order", "--since=2022-09-01T00:00:00Z", "--max-count=10", "main", "^HEAD", "--"},
+		},
+		{
+			req:  &LogRequest{NameOnly: true, NoRenames: true, NullTerminated: true, Revisions: []string{"c"}, Paths: []string{":(literal)-a"}},
+			want: []string{"log", "--name-only", "--no-renames", "-z", "c", "--", ":(literal)-a"},
+		},
+		{
+			req:  &DiffRequest{Base: "a", Head: "b", NameStatus: true, Paths: []string{"f"}},
+			want: []string{"diff", "--name-status", "a", "b", "--", "f"},
+		},
+		{
+			req:  &ShowRequest{Object: "c", NoPatch: true, Format: "%H:%cI"},
+			want: []string{"show", "--no-patch", "--format=format:%H:%cI", "c", "--"},
+		},
+		{
+			req:  &LsTreeRequest{Tree: "c", Long: true, Recursive: true, ShowTrees: true, Paths: []string{"dir/"}},
+			want: []string{"ls-tree", "--long", "-r", "-t", "c", "--", "dir/"},
+		},
+		{
+			req:  &RevParseRequest{Revisions: []string{"HEAD"}},
+			want: []string{"rev-parse", "HEAD"},
+		},
+	}
+	for _, test := range tests {
+		got, err := test.req.Args()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("unexpected args of %s (-want +got):\n%s", test.req.Command(), diff)
+		}
+	}
+
+	// Revisions can't be options.
+	for _, req := range []CommandRequest{
+		&LogRequest{Revisions: []string{"--output=/tmp/x"}},
+		&DiffRequest{Base: "a", Head: "-R"},
+		&DiffRequest{Base: "a"},
+		&ShowRequest{},
+		&LsTreeRequest{Tree: "-d"},
+		&RevParseRequest{Revisions: []string{"--git-dir"}},
+		&RevParseRequest{},
+	} {
+		if args, err := req.Args(); err == nil {
+			t.Errorf("expected error for %s request, got args %v", req.Command(), args)
+		}
+	}
+}
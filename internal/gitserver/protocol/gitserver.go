@@ -45,7 +45,7 @@ type SearchEventDone struct {
 func (s SearchEventDone) Err() error {
 	if s.Error != "" {
 		var e gitdomain.RepoNotExistError
-		if err := json.Unmarshal([]byte(s.Error), &e); err != nil {
+		if err := json.Unmarshal([]byte(s.Error), &e); err == nil {
 			return &e
 		}
 		return errors.New(s.Error)
@@ -214,6 +214,14 @@ type ReposStats struct {
 
 	// GitDirBytes is the amount of bytes stored in .git directories.
 	GitDirBytes int64
+
+	// PartialClones is the number of repositories that are partial clones,
+	// which omit objects that are fetched from the code host when needed.
+	PartialClones int64
+
+	// PartialCloneGitDirBytes is the amount of bytes stored in .git
+	// directories of partial clones. It is included in GitDirBytes.
+	PartialCloneGitDirBytes int64
 }
 
 // RepoCloneProgressRequest is a request for information about the clone progress of multiple
@@ -235,6 +243,35 @@ type RepoCloneProgressResponse struct {
 	Results map[api.RepoName]*RepoCloneProgress
 }
 
+// RepoMaintenanceStatusRequest is a request for the maintenance status of a
+// repository.
+type RepoMaintenanceStatusRequest struct {
+	Repo api.RepoName
+}
+
+// RepoMaintenanceStatus is the state of the data structures gitserver maintains
+// to speed up git commands in a repository.
+type RepoMaintenanceStatus struct {
+	Cloned bool // whether the repository is cloned
+
+	CommitGraph       bool // whether the repository has a commit-graph
+	CommitGraphLayers int  // the number of layers of the split commit-graph
+	ChangedPaths      bool // whether every commit-graph layer has changed-path Bloom filters
+
+	Bitmap        bool      // whether the repository has a reachability bitmap
+	BitmapModTime time.Time // when the newest bitmap was written
+
+	Packfiles    int // the number of packfiles
+	LooseObjects int // the estimated number of loose objects
+
+	// NeedsMaintenance is the reason why the next janitor run maintains the
+	// repository, or empty if it doesn't need maintenance.
+	NeedsMaintenance string
+	// MaintenanceFailures is the number of failed maintenance runs since the
+	// last successful one.
+	MaintenanceFailures int
+}
+
 // CreateCommitFromPatchRequest is the request information needed for creating
 // the simulated staging area git object for a repo.
 type CreateCommitFromPatchRequest struct {
@@ -327,6 +364,94 @@ func (e *CreateCommitFromPatchError) Error() string {
 	return e.InternalError
 }
 
+// FileEditOperation is the kind of a FileEdit.
+type FileEditOperation string
+
+const (
+	// FileEditWrite writes Content to the file at Path, creating it if it
+	// doesn't exist.
+	FileEditWrite FileEditOperation = "write"
+	// FileEditDelete deletes the file at Path.
+	FileEditDelete FileEditOperation = "delete"
+	// FileEditRename renames the file at Path to NewPath.
+	FileEditRename FileEditOperation = "rename"
+	// FileEditChmod sets whether the file at Path is executable.
+	FileEditChmod FileEditOperation = "chmod"
+)
+
+// FileEdit is an operation on a single file.
+type FileEdit struct {
+	Operation FileEditOperation
+	// Path is the path of the file relative to the root of the repository.
+	Path string
+	// Content is the new content of the file for FileEditWrite.
+	Content []byte
+	// NewPath is the new path of the file for FileEditRename. It must not
+	// exist yet.
+	NewPath string
+	// Executable is whether the file is executable for FileEditChmod. Files
+	// written with FileEditWrite keep their mode, new files aren't executable.
+	Executable bool
+}
+
+// CreateCommitFromEditsRequest is the request information needed for creating
+// a commit from a list of file edits.
+type CreateCommitFromEditsRequest struct {
+	// Repo is the repository to create the commit in.
+	Repo api.RepoName
+	// BaseCommit is the commit the edits were made against.
+	BaseCommit api.CommitID
+	// Edits are applied in order to the tree of the parent commit.
+	Edits []FileEdit
+	// TargetRef is the ref that will be created or updated for the commit. If
+	// it exists and doesn't point to BaseCommit, the edits are applied on top
+	// of the commit it points to, unless they touch files that changed since
+	// BaseCommit, which is a conflict.
+	TargetRef string
+	// If set to true and the TargetRef already exists, an unique number will be appended to the end (ie TargetRef-{#}). The generated ref will be returned.
+	UniqueRef bool
+	// CommitInfo is the information that will be used when creating the commit
+	CommitInfo PatchCommitInfo
+	// Push specifies whether the target ref will be pushed to the code host: if
+	// nil, no push will be attempted, if non-nil, a push will be attempted.
+	// The push is rejected if the ref was updated on the code host in the
+	// meantime.
+	Push *PushConfig
+}
+
+// CreateCommitFromEditsResponse is the response type returned after creating
+// a commit from a list of file edits.
+type CreateCommitFromEditsResponse struct {
+	// Rev is the ref that the commit can be found at
+	Rev string
+	// Commit is the ID of the created commit
+	Commit api.CommitID
+
+	// Error is populated only on error
+	Error *CreateCommitFromEditsError
+}
+
+// SetError adds the supplied error related details to e.
+func (e *CreateCommitFromEditsResponse) SetError(repo, command, out string, err error) {
+	if e.Error == nil {
+		e.Error = &CreateCommitFromEditsError{}
+	}
+	e.Error.RepositoryName = repo
+	e.Error.Command = command
+	e.Error.CombinedOutput = out
+	e.Error.InternalError = err.Error()
+}
+
+// CreateCommitFromEditsError is populated on errors running
+// CreateCommitFromEdits
+type CreateCommitFromEditsError struct {
+	CreateCommitFromPatchError
+
+	// ConflictingPaths are the edited paths that changed since the base
+	// commit, if the edits conflict with the target ref.
+	ConflictingPaths []string
+}
+
 type GetObjectRequest struct {
 	Repo       api.RepoName
 	ObjectName string
@@ -335,3 +460,31 @@ type GetObjectRequest struct {
 type GetObjectResponse struct {
 	Object gitdomain.GitObject
 }
+
+// BlameRequest is a request to blame the file at Path in Commit.
+type BlameRequest struct {
+	Repo api.RepoName
+	// Commit must be a full commit ID, since blames are cached per commit.
+	Commit api.CommitID
+	Path   string
+
+	StartLine int `json:",omitempty"` // 1-indexed start line (or 0 for beginning of file)
+	EndLine   int `json:",omitempty"` // 1-indexed end line (or 0 for end of file)
+}
+
+// BlameResponse is the response to a BlameRequest.
+type BlameResponse struct {
+	Hunks []*BlameHunk
+}
+
+// A BlameHunk is a contiguous portion of a file associated with a commit.
+type BlameHunk struct {
+	StartLine int // 1-indexed start line number
+	EndLine   int // 1-indexed end line number
+	StartByte int // 0-indexed start byte position (inclusive)
+	EndByte   int // 0-indexed end byte position (exclusive)
+	CommitID  api.CommitID
+	Author    gitdomain.Signature
+	Message   string
+	Filename  string
+}
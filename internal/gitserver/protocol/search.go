@@ -74,6 +74,28 @@ func (d *DiffMatches) String() string {
 	return fmt.Sprintf("%T(%s)", d, d.Expr)
 }
 
+// DiffAddedMatches is a predicate that matches if any of the lines added by
+// the commit match the given regex pattern.
+type DiffAddedMatches struct {
+	Expr       string
+	IgnoreCase bool
+}
+
+func (d *DiffAddedMatches) String() string {
+	return fmt.Sprintf("%T(%s)", d, d.Expr)
+}
+
+// DiffRemovedMatches is a predicate that matches if any of the lines removed
+// by the commit match the given regex pattern.
+type DiffRemovedMatches struct {
+	Expr       string
+	IgnoreCase bool
+}
+
+func (d *DiffRemovedMatches) String() string {
+	return fmt.Sprintf("%T(%s)", d, d.Expr)
+}
+
 // DiffModifiesFile is a predicate that matches if the commit modifies any files
 // that match the given regex pattern.
 type DiffModifiesFile struct {
@@ -218,6 +240,8 @@ func RegisterGob() {
 		gob.Register(&CommitAfter{})
 		gob.Register(&MessageMatches{})
 		gob.Register(&DiffMatches{})
+		gob.Register(&DiffAddedMatches{})
+		gob.Register(&DiffRemovedMatches{})
 		gob.Register(&DiffModifiesFile{})
 		gob.Register(&Boolean{})
 		gob.Register(&Operator{})
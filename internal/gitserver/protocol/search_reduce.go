@@ -210,6 +210,26 @@ func mergeOrRegexp(n Node) Node {
 			} else {
 				mergeable[key] = v
 			}
+		case *DiffAddedMatches:
+			key := DiffAddedMatches{IgnoreCase: v.IgnoreCase}
+			if prev, ok := mergeable[key]; ok {
+				mergeable[key] = &DiffAddedMatches{
+					Expr:       union(prev.(*DiffAddedMatches).Expr, v.Expr),
+					IgnoreCase: v.IgnoreCase,
+				}
+			} else {
+				mergeable[key] = v
+			}
+		case *DiffRemovedMatches:
+			key := DiffRemovedMatches{IgnoreCase: v.IgnoreCase}
+			if prev, ok := mergeable[key]; ok {
+				mergeable[key] = &DiffRemovedMatches{
+					Expr:       union(prev.(*DiffRemovedMatches).Expr, v.Expr),
+					IgnoreCase: v.IgnoreCase,
+				}
+			} else {
+				mergeable[key] = v
+			}
 		case *DiffModifiesFile:
 			key := DiffModifiesFile{IgnoreCase: v.IgnoreCase}
 			if prev, ok := mergeable[key]; ok {
@@ -254,7 +274,7 @@ func estimatedNodeCost(n Node) float64 {
 		return 10
 	case *DiffModifiesFile:
 		return 1000
-	case *DiffMatches:
+	case *DiffMatches, *DiffAddedMatches, *DiffRemovedMatches:
 		return 10000
 	default:
 		return 1
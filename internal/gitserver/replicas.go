@@ -0,0 +1,194 @@
+package gitserver
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// ReplicaAddrsForRepo returns the addresses of the gitserver instances that have
+// a copy of the given repo. The first address is the primary instance returned
+// by AddrForRepo. It is followed by the secondary instances, which mirror the
+// repo from the primary instance, if the replication factor is greater than one.
+func ReplicaAddrsForRepo(ctx context.Context, userAgent string, db database.DB, repo api.RepoName, addresses GitServerAddresses) ([]string, error) {
+	primary, err := AddrForRepo(ctx, userAgent, db, repo, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	n := addresses.ReplicationFactor
+	if n > len(addresses.Addresses) {
+		n = len(addresses.Addresses)
+	}
+	replicas := []string{primary}
+	if n <= 1 {
+		return replicas, nil
+	}
+
+	// Each secondary instance is picked among the remaining instances, so that
+	// adding or removing an instance only moves the copies it had.
+	remaining := make([]string, 0, len(addresses.Addresses))
+	for _, addr := range addresses.Addresses {
+		if addr != primary {
+			remaining = append(remaining, addr)
+		}
+	}
+	for len(replicas) < n && len(remaining) > 0 {
+		addr := RendezvousAddrForRepo(repo, remaining)
+		replicas = append(replicas, addr)
+		for i := range remaining {
+			if remaining[i] == addr {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+	return replicas, nil
+}
+
+func replicationFactorFromConfig() int {
+	cfg := conf.Get()
+	if cfg.ExperimentalFeatures != nil && cfg.ExperimentalFeatures.GitServerReplicationFactor > 1 {
+		return cfg.ExperimentalFeatures.GitServerReplicationFactor
+	}
+	return 1
+}
+
+// unhealthyReplicaPeriod is how long read-only requests are not sent to a
+// secondary instance after a request to it failed.
+const unhealthyReplicaPeriod = 30 * time.Second
+
+// replicaHealth tracks the secondary instances to which requests recently
+// failed.
+type replicaHealth struct {
+	mu        sync.Mutex
+	unhealthy map[string]time.Time
+}
+
+var replicasHealth = &replicaHealth{unhealthy: map[string]time.Time{}}
+
+func (h *replicaHealth) markUnhealthy(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unhealthy[addr] = time.Now().Add(unhealthyReplicaPeriod)
+}
+
+func (h *replicaHealth) healthy(addr string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	until, ok := h.unhealthy[addr]
+	if !ok {
+		return true
+	}
+	if time.Now().After(until) {
+		delete(h.unhealthy, addr)
+		return true
+	}
+	return false
+}
+
+// readAddrForRepo returns the address of a healthy instance with a copy of the
+// repo to send a read-only request for a specific commit to, along with the
+// address of the primary instance.
+func (c *clientImplementor) readAddrForRepo(ctx context.Context, repo api.RepoName) (addr, primary string, err error) {
+	addrs := c.Addrs()
+	if len(addrs) == 0 {
+		panic("unexpected state: no gitserver addresses")
+	}
+	replicas, err := ReplicaAddrsForRepo(ctx, c.userAgent, c.db, repo, GitServerAddresses{
+		Addresses:         addrs,
+		PinnedServers:     c.pinned(),
+		ReplicationFactor: c.replicationFactor(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	primary = replicas[0]
+	healthy := []string{primary}
+	for _, replica := range replicas[1:] {
+		if replicasHealth.healthy(replica) {
+			healthy = append(healthy, replica)
+		}
+	}
+	return healthy[rand.Intn(len(healthy))], primary, nil
+}
+
+// replicaFailed reports whether a request to a secondary instance failed in a
+// way that the request should be sent to the primary instance instead. A
+// secondary instance responds with 404 until it has cloned the repo.
+func replicaFailed(addr string, resp *http.Response, err error) bool {
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		replicasHealth.markUnhealthy(addr)
+		return true
+	}
+	return resp.StatusCode == http.StatusNotFound
+}
+
+// httpPostRead is like httpPost for read-only requests for the given commit,
+// which may be served by a secondary instance. If the secondary instance
+// fails, the request is sent to the primary instance.
+func (c *clientImplementor) httpPostRead(ctx context.Context, repo api.RepoName, commit api.CommitID, op string, payload any) (*http.Response, error) {
+	addr, primary, err := c.readAddrForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	if addr == primary {
+		return c.httpPost(ctx, repo, op, payload)
+	}
+
+	replicaPayload := payload
+	if req, ok := payload.(*protocol.ExecRequest); ok && req.EnsureRevision == "" {
+		// Secondary instances fetch the commit from the primary instance if they
+		// don't have it yet, which bounds how stale they can be.
+		r := *req
+		r.EnsureRevision = string(commit)
+		replicaPayload = &r
+	}
+	b, err := json.Marshal(replicaPayload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, repo, "POST", "http://"+addr+"/"+op, b)
+	if !replicaFailed(addr, resp, err) {
+		return resp, nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return c.httpPost(ctx, repo, op, payload)
+}
+
+// readGitCommand is like gitCommand for read-only commands for the given
+// commit, which may be served by a secondary instance.
+func (c *clientImplementor) readGitCommand(repo api.RepoName, commit api.CommitID, arg ...string) GitCommand {
+	cmd := c.gitCommand(repo, arg...)
+	if remote, ok := cmd.(*RemoteGitCommand); ok {
+		remote.execFn = func(ctx context.Context, repo api.RepoName, op string, payload any) (*http.Response, error) {
+			return c.httpPostRead(ctx, repo, commit, op, payload)
+		}
+	}
+	return cmd
+}
+
+// isCommitSearch reports whether args only searches full commit IDs.
+func isCommitSearch(args *protocol.SearchRequest) bool {
+	if len(args.Revisions) == 0 {
+		return false
+	}
+	for _, rev := range args.Revisions {
+		if !gitdomain.IsAbsoluteRevision(rev.RevSpec) || rev.RefGlob != "" || rev.ExcludeRefGlob != "" {
+			return false
+		}
+	}
+	return true
+}
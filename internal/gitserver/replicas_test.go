@@ -0,0 +1,244 @@
+package gitserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestReplicaAddrsForRepo(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMockDB()
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+
+	primary, err := AddrForRepo(ctx, "test", db, repo, GitServerAddresses{Addresses: addrs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for factor, want := range map[int]int{0: 1, 1: 1, 2: 2, 3: 3, 4: 3} {
+		replicas, err := ReplicaAddrsForRepo(ctx, "test", db, repo, GitServerAddresses{Addresses: addrs, ReplicationFactor: factor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(replicas) != want {
+			t.Errorf("factor %d: got %d replicas %q, want %d", factor, len(replicas), replicas, want)
+		}
+		if replicas[0] != primary {
+			t.Errorf("factor %d: got primary %q, want %q", factor, replicas[0], primary)
+		}
+		seen := map[string]bool{}
+		for _, addr := range replicas {
+			if seen[addr] {
+				t.Errorf("factor %d: duplicate replica %q", factor, addr)
+			}
+			seen[addr] = true
+		}
+	}
+
+	// A pinned repo stays on its pinned instance, which is its primary.
+	replicas, err := ReplicaAddrsForRepo(ctx, "test", db, repo, GitServerAddresses{
+		Addresses:         addrs,
+		PinnedServers:     map[string]string{string(repo): "gitserver-2"},
+		ReplicationFactor: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replicas) != 2 || replicas[0] != "gitserver-2" || replicas[1] == "gitserver-2" {
+		t.Errorf("unexpected replicas of pinned repo %q", replicas)
+	}
+}
+
+func TestClient_httpPostRead(t *testing.T) {
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	addrs := []string{"gitserver-0", "gitserver-1"}
+	primary, err := AddrForRepo(context.Background(), "test", database.NewMockDB(), repo, GitServerAddresses{Addresses: addrs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondary := addrs[0]
+	if secondary == primary {
+		secondary = addrs[1]
+	}
+
+	type request struct {
+		host           string
+		ensureRevision string
+	}
+
+	for _, tc := range []struct {
+		name            string
+		secondaryStatus int
+		secondaryErr    error
+		wantHost        string
+		wantUnhealthy   bool
+	}{
+		{name: "secondary", secondaryStatus: http.StatusOK, wantHost: secondary},
+		{name: "not cloned on secondary", secondaryStatus: http.StatusNotFound, wantHost: primary},
+		{name: "secondary failed", secondaryStatus: http.StatusInternalServerError, wantHost: primary, wantUnhealthy: true},
+		{name: "secondary unreachable", secondaryErr: errors.New("connection refused"), wantHost: primary, wantUnhealthy: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replicasHealth = &replicaHealth{unhealthy: map[string]time.Time{}}
+
+			var requests []request
+			cli := NewTestClient(httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+				var req protocol.ExecRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				requests = append(requests, request{host: r.URL.Host, ensureRevision: req.EnsureRevision})
+
+				status := http.StatusOK
+				if r.URL.Host == secondary {
+					if tc.secondaryErr != nil {
+						return nil, tc.secondaryErr
+					}
+					status = tc.secondaryStatus
+				}
+				return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(r.URL.Host))}, nil
+			}), database.NewMockDB(), addrs).(*clientImplementor)
+			cli.replicationFactor = func() int { return 2 }
+
+			// The instance is picked at random, so we retry until the secondary
+			// instance is picked.
+			commit := api.CommitID(strings.Repeat("a", 40))
+			for i := 0; ; i++ {
+				if i == 100 {
+					t.Fatal("secondary instance was never picked")
+				}
+				requests = nil
+				resp, err := cli.httpPostRead(context.Background(), repo, commit, "exec", &protocol.ExecRequest{Repo: repo})
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if requests[0].host != secondary {
+					continue
+				}
+				if string(body) != tc.wantHost {
+					t.Fatalf("got response from %q, want %q", body, tc.wantHost)
+				}
+				break
+			}
+
+			if requests[0].ensureRevision != string(commit) {
+				t.Errorf("expected secondary to ensure revision %q, got %q", commit, requests[0].ensureRevision)
+			}
+			if len(requests) > 1 && requests[1].ensureRevision != "" {
+				t.Errorf("expected primary not to ensure revision, got %q", requests[1].ensureRevision)
+			}
+			if healthy := replicasHealth.healthy(secondary); healthy == tc.wantUnhealthy {
+				t.Errorf("got healthy %v, want %v", healthy, !tc.wantUnhealthy)
+			}
+		})
+	}
+}
+
+func TestClient_SearchReplicas(t *testing.T) {
+	repo := api.RepoName("github.com/sourcegraph/sourcegraph")
+	addrs := []string{"gitserver-0", "gitserver-1"}
+	primary, err := AddrForRepo(context.Background(), "test", database.NewMockDB(), repo, GitServerAddresses{Addresses: addrs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondary := addrs[0]
+	if secondary == primary {
+		secondary = addrs[1]
+	}
+
+	for _, tc := range []struct {
+		name          string
+		secondaryErr  error
+		wantUnhealthy bool
+	}{
+		{name: "secondary"},
+		{name: "not cloned on secondary", secondaryErr: &gitdomain.RepoNotExistError{Repo: repo, CloneInProgress: true}},
+		{name: "secondary failed", secondaryErr: errors.New("boom"), wantUnhealthy: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replicasHealth = &replicaHealth{unhealthy: map[string]time.Time{}}
+
+			var hosts []string
+			cli := NewTestClient(httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+				hosts = append(hosts, r.URL.Host)
+				var searchErr error
+				if r.URL.Host == secondary {
+					searchErr = tc.secondaryErr
+				}
+				done, err := json.Marshal(protocol.NewSearchEventDone(false, searchErr))
+				if err != nil {
+					t.Fatal(err)
+				}
+				body := "event: done\ndata: " + string(done) + "\n\n"
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+			}), database.NewMockDB(), addrs).(*clientImplementor)
+			cli.replicationFactor = func() int { return 2 }
+
+			// The instance is picked at random, so we retry until the secondary
+			// instance is picked.
+			for i := 0; ; i++ {
+				if i == 100 {
+					t.Fatal("secondary instance was never picked")
+				}
+				hosts = nil
+				_, err := cli.Search(context.Background(), &protocol.SearchRequest{
+					Repo:      repo,
+					Revisions: []protocol.RevisionSpecifier{{RevSpec: strings.Repeat("a", 40)}},
+					Query:     &protocol.MessageMatches{Expr: "fix"},
+				}, func([]protocol.CommitMatch) {})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hosts[0] == secondary {
+					break
+				}
+			}
+
+			wantHosts := []string{secondary}
+			if tc.secondaryErr != nil {
+				wantHosts = append(wantHosts, primary)
+			}
+			if diff := cmp.Diff(wantHosts, hosts); diff != "" {
+				t.Errorf("unexpected hosts searched (-want +got):\n%s", diff)
+			}
+			if healthy := replicasHealth.healthy(secondary); healthy == tc.wantUnhealthy {
+				t.Errorf("got healthy %v, want %v", healthy, !tc.wantUnhealthy)
+			}
+		})
+	}
+}
+
+func TestIsCommitSearch(t *testing.T) {
+	commit := strings.Repeat("a", 40)
+	for _, tc := range []struct {
+		revs []protocol.RevisionSpecifier
+		want bool
+	}{
+		{nil, false},
+		{[]protocol.RevisionSpecifier{{RevSpec: commit}}, true},
+		{[]protocol.RevisionSpecifier{{RevSpec: commit}, {RevSpec: strings.Repeat("b", 40)}}, true},
+		{[]protocol.RevisionSpecifier{{RevSpec: "HEAD"}}, false},
+		{[]protocol.RevisionSpecifier{{RevSpec: commit}, {RefGlob: "refs/heads/*"}}, false},
+	} {
+		if got := isCommitSearch(&protocol.SearchRequest{Revisions: tc.revs}); got != tc.want {
+			t.Errorf("isCommitSearch(%v) = %v, want %v", tc.revs, got, tc.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
 	"os/exec"
 	"sync"
 
@@ -16,6 +17,9 @@ import (
 type DiffFetcher struct {
 	dir string
 
+	// Env is added to the environment of the subprocess.
+	Env []string
+
 	startOnce sync.Once
 	stdin     io.Writer
 	stderr    io.Reader
@@ -51,6 +55,9 @@ func (d *DiffFetcher) start() (err error) {
 			"--root",           // Treat the root commit as a big creation event (otherwise the diff would be empty)
 		)
 		d.cmd.Dir = d.dir
+		if len(d.Env) > 0 {
+			d.cmd.Env = append(os.Environ(), d.Env...)
+		}
 
 		var stdoutReader io.ReadCloser
 		stdoutReader, err = d.cmd.StdoutPipe()
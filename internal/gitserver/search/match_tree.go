@@ -31,6 +31,12 @@ func ToMatchTree(q protocol.Node) (MatchTree, error) {
 	case *protocol.DiffMatches:
 		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
 		return &DiffMatches{re}, err
+	case *protocol.DiffAddedMatches:
+		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
+		return &DiffAddedMatches{re}, err
+	case *protocol.DiffRemovedMatches:
+		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
+		return &DiffRemovedMatches{re}, err
 	case *protocol.DiffModifiesFile:
 		re, err := casetransform.CompileRegexp(v.Expr, v.IgnoreCase)
 		return &DiffModifiesFile{re}, err
@@ -134,6 +140,32 @@ type DiffMatches struct {
 }
 
 func (dm *DiffMatches) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	return matchDiffLines(lc, dm.Regexp, true, true)
+}
+
+// DiffAddedMatches is a predicate that matches if any of the lines added by
+// the commit match the given regex pattern.
+type DiffAddedMatches struct {
+	*casetransform.Regexp
+}
+
+func (dm *DiffAddedMatches) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	return matchDiffLines(lc, dm.Regexp, true, false)
+}
+
+// DiffRemovedMatches is a predicate that matches if any of the lines removed
+// by the commit match the given regex pattern.
+type DiffRemovedMatches struct {
+	*casetransform.Regexp
+}
+
+func (dm *DiffRemovedMatches) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit, error) {
+	return matchDiffLines(lc, dm.Regexp, false, true)
+}
+
+// matchDiffLines matches re against the added and/or removed lines of the
+// commit's diff. Context lines are never matched.
+func matchDiffLines(lc *LazyCommit, re *casetransform.Regexp, added, removed bool) (CommitFilterResult, MatchedCommit, error) {
 	diff, err := lc.Diff()
 	if err != nil {
 		return filterResult(false), MatchedCommit{}, err
@@ -151,13 +183,14 @@ func (dm *DiffMatches) Match(lc *LazyCommit) (CommitFilterResult, MatchedCommit,
 				}
 
 				origin, lineWithoutPrefix := line[0], line[1:]
-				switch origin {
-				case '+', '-':
+				switch {
+				case origin == '+' && added:
+				case origin == '-' && removed:
 				default:
 					continue
 				}
 
-				matches := dm.FindAllIndex(lineWithoutPrefix, -1, &lc.LowerBuf)
+				matches := re.FindAllIndex(lineWithoutPrefix, -1, &lc.LowerBuf)
 				if matches != nil {
 					if lineHighlights == nil {
 						lineHighlights = make(map[int]result.Ranges, 1)
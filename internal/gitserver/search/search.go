@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -85,6 +86,7 @@ const (
 type CommitSearcher struct {
 	Logger               log.Logger
 	RepoDir              string
+	Env                  []string
 	Query                MatchTree
 	Revisions            []protocol.RevisionSpecifier
 	IncludeDiff          bool
@@ -143,6 +145,9 @@ func (cs *CommitSearcher) feedBatches(ctx context.Context, jobs chan job, result
 	}
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = cs.RepoDir
+	if len(cs.Env) > 0 {
+		cmd.Env = append(os.Environ(), cs.Env...)
+	}
 	stdoutReader, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -220,6 +225,7 @@ func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 	if err != nil {
 		return err
 	}
+	diffFetcher.Env = cs.Env
 	defer diffFetcher.Stop()
 
 	startBuf := make([]byte, 1024)
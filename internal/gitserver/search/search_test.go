@@ -215,6 +215,77 @@ func TestSearch(t *testing.T) {
 	})
 }
 
+func TestSearchAddedRemoved(t *testing.T) {
+	cmds := []string{
+		"echo needle > file1",
+		"git add -A",
+		"GIT_COMMITTER_NAME=adder " +
+			"GIT_COMMITTER_EMAIL=adder@ccheek.com " +
+			"GIT_COMMITTER_DATE=2006-01-02T15:04:05Z " +
+			"GIT_AUTHOR_NAME=adder " +
+			"GIT_AUTHOR_EMAIL=adder@ccheek.com " +
+			"GIT_AUTHOR_DATE=2006-01-02T15:04:05Z " +
+			"git commit -m add",
+		"echo haystack > file1",
+		"git add -A",
+		"GIT_COMMITTER_NAME=remover " +
+			"GIT_COMMITTER_EMAIL=remover@ccheek.com " +
+			"GIT_COMMITTER_DATE=2006-01-02T15:04:05Z " +
+			"GIT_AUTHOR_NAME=remover " +
+			"GIT_AUTHOR_EMAIL=remover@ccheek.com " +
+			"GIT_AUTHOR_DATE=2006-01-02T15:04:05Z " +
+			"git commit -m remove",
+	}
+	dir := initGitRepository(t, cmds...)
+
+	search := func(t *testing.T, query protocol.Node) []*protocol.CommitMatch {
+		tree, err := ToMatchTree(query)
+		require.NoError(t, err)
+		searcher := &CommitSearcher{
+			RepoDir:     dir,
+			Query:       tree,
+			IncludeDiff: true,
+		}
+		var matches []*protocol.CommitMatch
+		err = searcher.Search(context.Background(), func(match *protocol.CommitMatch) {
+			matches = append(matches, match)
+		})
+		require.NoError(t, err)
+		return matches
+	}
+
+	t.Run("diff matches both", func(t *testing.T) {
+		matches := search(t, &protocol.DiffMatches{Expr: "needle"})
+		require.Len(t, matches, 2)
+	})
+
+	t.Run("added matches", func(t *testing.T) {
+		matches := search(t, &protocol.DiffAddedMatches{Expr: "needle"})
+		require.Len(t, matches, 1)
+		require.Equal(t, "adder", matches[0].Author.Name)
+		require.Len(t, matches[0].Diff.MatchedRanges, 1)
+	})
+
+	t.Run("removed matches", func(t *testing.T) {
+		matches := search(t, &protocol.DiffRemovedMatches{Expr: "needle"})
+		require.Len(t, matches, 1)
+		require.Equal(t, "remover", matches[0].Author.Name)
+		// Only the removed line is highlighted, not the added one.
+		require.Len(t, matches[0].Diff.MatchedRanges, 1)
+		require.Contains(t, matches[0].Diff.Content, "-needle")
+	})
+
+	t.Run("removed and added in the same commit", func(t *testing.T) {
+		matches := search(t, protocol.NewAnd(
+			&protocol.DiffRemovedMatches{Expr: "needle"},
+			&protocol.DiffAddedMatches{Expr: "haystack"},
+		))
+		require.Len(t, matches, 1)
+		require.Equal(t, "remover", matches[0].Author.Name)
+		require.Len(t, matches[0].Diff.MatchedRanges, 2)
+	})
+}
+
 func TestCommitScanner(t *testing.T) {
 	cases := []struct {
 		input    []byte
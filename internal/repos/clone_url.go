@@ -92,6 +92,14 @@ func cloneURL(parsed any, logger log.Logger, kind string, repo *types.Repo) (str
 		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
 			return otherCloneURL(repo, r), nil
 		}
+	case *schema.MercurialConnection:
+		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
+			return otherCloneURL(repo, r), nil
+		}
+	case *schema.SubversionConnection:
+		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
+			return subversionCloneURL(repo, r, t)
+		}
 	case *schema.GoModulesConnection:
 		return string(repo.Name), nil
 	case *schema.PythonPackagesConnection:
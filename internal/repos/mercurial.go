@@ -0,0 +1,109 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A MercurialSource yields repositories from a single Mercurial connection
+// configured in Sourcegraph via the external services configuration. Like an
+// OtherSource, it lists the repositories of its configuration. gitserver
+// mirrors them as Git repositories.
+type MercurialSource struct {
+	svc  *types.ExternalService
+	conn *schema.MercurialConnection
+}
+
+// NewMercurialSource returns a new MercurialSource from the given external
+// service.
+func NewMercurialSource(ctx context.Context, svc *types.ExternalService) (*MercurialSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.MercurialConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return &MercurialSource{svc: svc, conn: &c}, nil
+}
+
+// ListRepos returns all Mercurial repositories of the connection.
+func (s MercurialSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	var base *url.URL
+	if s.conn.Url != "" {
+		var err error
+		if base, err = url.Parse(s.conn.Url); err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+	}
+
+	urn := s.svc.URN()
+	for _, repo := range s.conn.Repos {
+		u, err := otherRepoCloneURL(base, repo)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		r, err := s.makeRepo(urn, u)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s MercurialSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s MercurialSource) makeRepo(urn string, u *url.URL) (*types.Repo, error) {
+	repoURL := u.String()
+	// Repository names are derived like for other external services.
+	repoSource := reposource.Other{OtherExternalServiceConnection: &schema.OtherExternalServiceConnection{
+		Url:                   s.conn.Url,
+		Repos:                 s.conn.Repos,
+		RepositoryPathPattern: s.conn.RepositoryPathPattern,
+	}}
+	repoName, err := repoSource.CloneURLToRepoName(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	repoURI, err := repoSource.CloneURLToRepoURI(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path, u.RawQuery = "", ""
+	serviceID := u.String()
+
+	return &types.Repo{
+		Name: repoName,
+		URI:  repoURI,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(repoName),
+			ServiceType: extsvc.TypeMercurial,
+			ServiceID:   serviceID,
+		},
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: repoURL,
+			},
+		},
+		Metadata: &extsvc.OtherRepoMetadata{
+			RelativePath: strings.TrimPrefix(repoURL, serviceID),
+		},
+	}, nil
+}
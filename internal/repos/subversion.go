@@ -0,0 +1,172 @@
+package repos
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A SubversionSource yields repositories from a single Subversion connection
+// configured in Sourcegraph via the external services configuration. The
+// repositories are either listed in the configuration or discovered under
+// the root URL. gitserver mirrors them as Git repositories.
+type SubversionSource struct {
+	svc  *types.ExternalService
+	conn *schema.SubversionConnection
+
+	// list returns the entries of the Subversion directory at u. Directories
+	// have a trailing slash.
+	list func(ctx context.Context, u *url.URL, username, password string) ([]string, error)
+}
+
+// NewSubversionSource returns a new SubversionSource from the given external
+// service.
+func NewSubversionSource(ctx context.Context, svc *types.ExternalService) (*SubversionSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.SubversionConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return &SubversionSource{svc: svc, conn: &c, list: svnList}, nil
+}
+
+// ListRepos returns the Subversion repositories of the connection.
+func (s SubversionSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	base, err := url.Parse(strings.TrimSuffix(s.conn.Url, "/") + "/")
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	paths := s.conn.Repos
+	if len(paths) == 0 {
+		if paths, err = s.discoverRepos(ctx, base); err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+	}
+
+	urn := s.svc.URN()
+	for _, p := range paths {
+		results <- SourceResult{Source: s, Repo: s.makeRepo(urn, base, strings.Trim(p, "/"))}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s SubversionSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+// discoverRepos returns the paths of the repositories under the root URL:
+// the root itself if it contains the trunk directory, and otherwise every
+// directory at the root.
+func (s SubversionSource) discoverRepos(ctx context.Context, base *url.URL) ([]string, error) {
+	entries, err := s.list(ctx, base, s.conn.Username, s.conn.Password)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing repositories")
+	}
+
+	trunk := "trunk"
+	if s.conn.Layout != nil {
+		trunk = s.conn.Layout.Trunk
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry, "/") {
+			continue
+		}
+		dir := strings.TrimSuffix(entry, "/")
+		if trunk != "" && dir == trunk {
+			return []string{""}, nil
+		}
+		paths = append(paths, dir)
+	}
+	return paths, nil
+}
+
+func (s SubversionSource) makeRepo(urn string, base *url.URL, repoPath string) *types.Repo {
+	u := *base
+	u.Path = path.Join(base.Path, repoPath)
+	repoURL := u.String()
+
+	// Repository names are derived like for other external services. The
+	// path of a repository at the root URL is empty.
+	nameBase := (&url.URL{Host: base.Host, Path: base.Path}).String()
+	repoName := api.RepoName(strings.TrimSuffix(reposource.OtherRepoName(s.conn.RepositoryPathPattern, nameBase, repoPath), "/"))
+	repoURI := strings.TrimSuffix(reposource.OtherRepoName("", nameBase, repoPath), "/")
+
+	u.Path, u.RawQuery = "", ""
+	serviceID := u.String()
+
+	return &types.Repo{
+		Name: repoName,
+		URI:  repoURI,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(repoName),
+			ServiceType: extsvc.TypeSubversion,
+			ServiceID:   serviceID,
+		},
+		Private: s.conn.Username != "",
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: repoURL,
+			},
+		},
+		Metadata: &extsvc.OtherRepoMetadata{
+			RelativePath: strings.TrimPrefix(repoURL, serviceID),
+		},
+	}
+}
+
+// svnList returns the entries of the Subversion directory at u with svn list.
+func svnList(ctx context.Context, u *url.URL, username, password string) ([]string, error) {
+	args := []string{"list", "--non-interactive", "--no-auth-cache"}
+	if username != "" {
+		args = append(args, "--username", username, "--password-from-stdin")
+	}
+	cmd := exec.CommandContext(ctx, "svn", append(args, u.String())...)
+	cmd.Stdin = strings.NewReader(password)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "svn list failed with output %q", stderr.String())
+	}
+	var entries []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return entries, nil
+}
+
+// subversionCloneURL returns the clone URL of a Subversion repository, with
+// the credentials of the connection. gitserver passes them to git svn.
+func subversionCloneURL(repo *types.Repo, m *extsvc.OtherRepoMetadata, cfg *schema.SubversionConnection) (string, error) {
+	u, err := url.Parse(otherCloneURL(repo, m))
+	if err != nil {
+		return "", err
+	}
+	if cfg.Username != "" {
+		u.User = url.UserPassword(cfg.Username, cfg.Password)
+	}
+	return u.String(), nil
+}
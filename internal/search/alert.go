@@ -74,6 +74,8 @@ func (q *QueryDescription) QueryString() string {
 			return q.Query + " patternType:literal"
 		case query.SearchTypeStructural:
 			return q.Query + " patternType:structural"
+		case query.SearchTypeFuzzy:
+			return q.Query + " patternType:fuzzy"
 		case query.SearchTypeLucky:
 			return q.Query
 		default:
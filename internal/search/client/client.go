@@ -99,12 +99,15 @@ func (s *searchClient) Plan(
 
 	var plan query.Plan
 	plan, err = query.Pipeline(
-		query.Init(searchQuery, searchType),
+		query.InitWithMacros(searchQuery, searchType, query.Macros(settings.SearchMacros)),
 		query.With(searchContextsQueryEnabled, substituteContextsStep),
 	)
 	if err != nil {
 		return nil, &QueryError{Query: searchQuery, Err: err}
 	}
+	if err := validateCursor(plan); err != nil {
+		return nil, &QueryError{Query: searchQuery, Err: err}
+	}
 	tr.LazyPrintf("parsing done")
 
 	inputs := &search.Inputs{
@@ -123,6 +126,19 @@ func (s *searchClient) Plan(
 	return inputs, nil
 }
 
+// validateCursor checks that the value of the cursor: field, if any, is a
+// cursor we can resume from.
+func validateCursor(plan query.Plan) error {
+	for _, b := range plan {
+		if v := b.FindValue(query.FieldCursor); v != "" {
+			if _, err := search.DecodeCursor(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (s *searchClient) Execute(
 	ctx context.Context,
 	stream streaming.Sender,
@@ -175,6 +191,8 @@ func SearchTypeFromString(patternType string) (query.SearchType, error) {
 		return query.SearchTypeLucky, nil
 	case "keyword":
 		return query.SearchTypeKeyword, nil
+	case "fuzzy":
+		return query.SearchTypeFuzzy, nil
 	default:
 		return -1, errors.Errorf("unrecognized patternType %q", patternType)
 	}
@@ -225,6 +243,8 @@ func overrideSearchType(input string, searchType query.SearchType) query.SearchT
 			searchType = query.SearchTypeLucky
 		case "keyword":
 			searchType = query.SearchTypeKeyword
+		case "fuzzy":
+			searchType = query.SearchTypeFuzzy
 		}
 	})
 	return searchType
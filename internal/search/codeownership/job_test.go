@@ -98,6 +98,65 @@ func Test_applyCodeOwnershipFiltering(t *testing.T) {
 				},
 			}),
 		},
+		{
+			name: "falls back to lower precedence code owners files",
+			args: args{
+				includeOwners: []string{"@sqs"},
+				excludeOwners: []string{},
+				matches: []result.Match{
+					&result.FileMatch{
+						File: result.File{
+							Path: "README.md",
+						},
+					},
+					&result.FileMatch{
+						File: result.File{
+							Path: "package.json",
+						},
+					},
+				},
+				repoContent: map[string]string{
+					".github/CODEOWNERS": "README.md @docs\n",
+					"CODEOWNERS":         "* @sqs\n",
+				},
+			},
+			want: autogold.Want("results matching fallback ownership", []result.Match{
+				&result.FileMatch{
+					File: result.File{
+						Path: "package.json",
+					},
+				},
+			}),
+		},
+		{
+			name: "filters results based on gitlab code owners sections",
+			args: args{
+				includeOwners: []string{"@sqs"},
+				excludeOwners: []string{},
+				matches: []result.Match{
+					&result.FileMatch{
+						File: result.File{
+							Path: "README.md",
+						},
+					},
+					&result.FileMatch{
+						File: result.File{
+							Path: "package.json",
+						},
+					},
+				},
+				repoContent: map[string]string{
+					".gitlab/CODEOWNERS": "* @everyone\n\n[Docs] @sqs\n*.md\n",
+				},
+			},
+			want: autogold.Want("results matching section ownership", []result.Match{
+				&result.FileMatch{
+					File: result.File{
+						Path: "README.md",
+					},
+				},
+			}),
+		},
 	}
 
 	for _, tt := range tests {
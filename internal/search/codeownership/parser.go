@@ -0,0 +1,429 @@
+package codeownership
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Dialect is a flavour of the CODEOWNERS file format.
+type Dialect int
+
+const (
+	// DialectGitHub is the GitHub format: a flat list of rules where the
+	// last matching rule wins.
+	DialectGitHub Dialect = iota
+
+	// DialectGitLab extends the GitHub format with `[Section]` headers.
+	// Sections are evaluated independently and a path is owned by the last
+	// matching rule of every section.
+	DialectGitLab
+
+	// DialectBitbucket extends the GitHub format with group definitions
+	// (`@@@Group @alice @bob`) that rules can reference as `@@Group`.
+	DialectBitbucket
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectGitLab:
+		return "gitlab"
+	case DialectBitbucket:
+		return "bitbucket"
+	default:
+		return "github"
+	}
+}
+
+const (
+	// EmailOwner is the owner type for email addresses.
+	EmailOwner = "email"
+	// TeamOwner is the owner type for teams and groups, e.g. @org/team.
+	TeamOwner = "team"
+	// UsernameOwner is the owner type for user handles.
+	UsernameOwner = "username"
+)
+
+// Owner is an owner of a path.
+type Owner struct {
+	// Value is the owner without its leading "@", e.g. "alice",
+	// "org/team" or "alice@example.com".
+	Value string
+
+	// Type is one of EmailOwner, TeamOwner or UsernameOwner.
+	Type string
+
+	// Section is the name of the GitLab section of the rule that assigned
+	// this owner. It is empty for rules outside of any section.
+	Section string
+}
+
+func (o Owner) String() string {
+	if o.Type == EmailOwner {
+		return o.Value
+	}
+	return "@" + o.Value
+}
+
+type Owners = []Owner
+
+// Section is a GitLab CODEOWNERS section, declared with a header such as
+// `[Documentation]`, `^[Optional docs]` or `[Frontend][2] @frontend`.
+type Section struct {
+	Name string
+
+	// Optional is true for sections declared with a leading "^", whose
+	// approval is not required.
+	Optional bool
+
+	// Approvals is the number of approvals required from the section's
+	// owners. Defaults to 1.
+	Approvals int
+
+	// DefaultOwners apply to rules in this section that do not list any
+	// owners themselves.
+	DefaultOwners []Owner
+}
+
+// Rule is a single line of an ownership file.
+type Rule struct {
+	Pattern    string
+	Owners     []Owner
+	Section    string
+	LineNumber int
+
+	re *regexp.Regexp
+}
+
+// Matches returns true if the rule's pattern matches path.
+func (r *Rule) Matches(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// File is a parsed ownership file.
+type File struct {
+	Dialect  Dialect
+	Rules    []*Rule
+	Sections []*Section
+}
+
+// Match returns the owners of path and whether any rule matched it. A path
+// matched only by rules without owners is explicitly unowned.
+func (f *File) Match(path string) (Owners, bool) {
+	// Within a section, the last matching rule wins. Rules outside of any
+	// section belong to the unnamed section.
+	lastMatch := make(map[string]*Rule)
+	for _, rule := range f.Rules {
+		if rule.Matches(path) {
+			lastMatch[rule.Section] = rule
+		}
+	}
+	if len(lastMatch) == 0 {
+		return nil, false
+	}
+
+	// Owners are returned in the order sections are declared.
+	var owners Owners
+	if rule, ok := lastMatch[""]; ok {
+		owners = append(owners, rule.Owners...)
+	}
+	for _, section := range f.Sections {
+		if rule, ok := lastMatch[section.Name]; ok {
+			owners = append(owners, rule.Owners...)
+		}
+	}
+	return owners, true
+}
+
+var sectionHeaderPattern = regexp.MustCompile(`^(\^)?\[([^\]]+)\](?:\[(\d+)\])?(\s.*)?$`)
+
+// matchSectionHeader returns the submatches of sectionHeaderPattern if line
+// is a GitLab section header, or nil otherwise. A header is only followed by
+// owners, so that patterns with character classes like `[Dd]ocs/ @team` are
+// not mistaken for headers.
+func matchSectionHeader(line string) []string {
+	m := sectionHeaderPattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	for _, field := range splitFields(m[4]) {
+		if !strings.Contains(field, "@") {
+			return nil
+		}
+	}
+	return m
+}
+
+// DetectDialect guesses the dialect of an ownership file from its contents.
+func DetectDialect(content []byte) Dialect {
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "@@@"):
+			return DialectBitbucket
+		case matchSectionHeader(line) != nil:
+			return DialectGitLab
+		}
+	}
+	return DialectGitHub
+}
+
+// ParseFile parses an ownership file written in the given dialect.
+func ParseFile(r io.Reader, dialect Dialect) (*File, error) {
+	f := &File{Dialect: dialect}
+
+	var (
+		section *Section
+		groups  = make(map[string][]Owner)
+	)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := stripComment(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if dialect == DialectGitLab {
+			if m := matchSectionHeader(line); m != nil {
+				s, err := parseSection(m)
+				if err != nil {
+					return nil, errors.Wrapf(err, "line %d", lineNumber)
+				}
+				// Sections with the same name are merged, GitLab compares
+				// section names case-insensitively.
+				section = nil
+				for _, existing := range f.Sections {
+					if strings.EqualFold(existing.Name, s.Name) {
+						section = existing
+						section.DefaultOwners = append(section.DefaultOwners, s.DefaultOwners...)
+						break
+					}
+				}
+				if section == nil {
+					section = s
+					f.Sections = append(f.Sections, section)
+				}
+				continue
+			}
+		}
+
+		fields := splitFields(line)
+
+		if dialect == DialectBitbucket && strings.HasPrefix(fields[0], "@@@") {
+			name := strings.TrimPrefix(fields[0], "@@@")
+			members, err := parseOwners(fields[1:], "", groups)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+			groups[name] = members
+			continue
+		}
+
+		sectionName := ""
+		if section != nil {
+			sectionName = section.Name
+		}
+
+		owners, err := parseOwners(fields[1:], sectionName, groups)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		if len(owners) == 0 && section != nil {
+			for _, o := range section.DefaultOwners {
+				o.Section = sectionName
+				owners = append(owners, o)
+			}
+		}
+
+		re, err := patternToRegexp(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+
+		f.Rules = append(f.Rules, &Rule{
+			Pattern:    fields[0],
+			Owners:     owners,
+			Section:    sectionName,
+			LineNumber: lineNumber,
+			re:         re,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func parseSection(m []string) (*Section, error) {
+	s := &Section{
+		Name:      strings.TrimSpace(m[2]),
+		Optional:  m[1] == "^",
+		Approvals: 1,
+	}
+	if m[3] != "" {
+		approvals, err := strconv.Atoi(m[3])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid approval count for section %q", s.Name)
+		}
+		s.Approvals = approvals
+	}
+	owners, err := parseOwners(splitFields(m[4]), s.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.DefaultOwners = owners
+	return s, nil
+}
+
+func parseOwners(fields []string, section string, groups map[string][]Owner) ([]Owner, error) {
+	var owners []Owner
+	for _, field := range fields {
+		switch {
+		case strings.HasPrefix(field, "@@") && groups != nil:
+			members, ok := groups[strings.TrimPrefix(field, "@@")]
+			if !ok {
+				return nil, errors.Errorf("undefined group %q", field)
+			}
+			for _, o := range members {
+				o.Section = section
+				owners = append(owners, o)
+			}
+		case strings.HasPrefix(field, "@"):
+			value := strings.TrimPrefix(field, "@")
+			typ := UsernameOwner
+			if strings.Contains(value, "/") {
+				typ = TeamOwner
+			}
+			owners = append(owners, Owner{Value: value, Type: typ, Section: section})
+		case strings.Contains(field, "@"):
+			owners = append(owners, Owner{Value: field, Type: EmailOwner, Section: section})
+		default:
+			return nil, errors.Errorf("invalid owner %q", field)
+		}
+	}
+	return owners, nil
+}
+
+// stripComment removes a trailing comment from line, honouring escaped
+// "\#", and trims surrounding whitespace.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '#' {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// splitFields splits line on unescaped whitespace.
+func splitFields(line string) []string {
+	var (
+		fields []string
+		cur    strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && line[i+1] == ' ' {
+			cur.WriteByte(' ')
+			i++
+			continue
+		}
+		if c == ' ' || c == '\t' {
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
+
+// patternToRegexp converts a gitignore-style ownership pattern to a regular
+// expression matching repository-relative paths.
+//
+// Patterns containing a slash anywhere but at their end are anchored to the
+// repository root, other patterns match at any depth. A pattern matching a
+// directory matches everything below it, except for patterns ending in "/*"
+// which only match direct children. Patterns ending in "/" only match
+// directories.
+func patternToRegexp(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, errors.New("empty pattern")
+	}
+
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	directChildren := strings.HasSuffix(pattern, "/*")
+	directoryOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("(?:^|/)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, errors.Errorf("unterminated character class in pattern %q", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case directChildren:
+		b.WriteString("$")
+	case directoryOnly:
+		b.WriteString("/.*$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
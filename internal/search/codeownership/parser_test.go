@@ -0,0 +1,218 @@
+package codeownership
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPatternToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "*",
+			matches: []string{"README.md", "a/b/c.go"},
+		},
+		{
+			pattern: "*.go",
+			matches: []string{"main.go", "cmd/server/main.go"},
+			misses:  []string{"main.golang", "README.md"},
+		},
+		{
+			pattern: "README.md",
+			matches: []string{"README.md", "docs/README.md"},
+			misses:  []string{"README.markdown"},
+		},
+		{
+			pattern: "/README.md",
+			matches: []string{"README.md"},
+			misses:  []string{"docs/README.md"},
+		},
+		{
+			pattern: "apps/",
+			matches: []string{"apps/web/index.ts", "src/apps/main.go"},
+			misses:  []string{"apps"},
+		},
+		{
+			pattern: "docs/*",
+			matches: []string{"docs/index.md"},
+			misses:  []string{"docs/api/index.md", "src/docs/index.md"},
+		},
+		{
+			pattern: "/build/logs/",
+			matches: []string{"build/logs/out.log", "build/logs/2022/out.log"},
+			misses:  []string{"src/build/logs/out.log"},
+		},
+		{
+			pattern: "**/logs",
+			matches: []string{"logs/out.log", "build/logs/out.log", "a/b/logs/c/out.log"},
+			misses:  []string{"build/logsx/out.log"},
+		},
+		{
+			pattern: "src/**/test?.go",
+			matches: []string{"src/test1.go", "src/a/b/testX.go"},
+			misses:  []string{"src/a/test10.go"},
+		},
+		{
+			pattern: "file[0-9].txt",
+			matches: []string{"file1.txt"},
+			misses:  []string{"filea.txt"},
+		},
+		{
+			pattern: `\#hash`,
+			matches: []string{"#hash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := patternToRegexp(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range tt.matches {
+				if !re.MatchString(path) {
+					t.Errorf("expected %q to match %q", tt.pattern, path)
+				}
+			}
+			for _, path := range tt.misses {
+				if re.MatchString(path) {
+					t.Errorf("expected %q not to match %q", tt.pattern, path)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	t.Run("github", func(t *testing.T) {
+		f, err := ParseFile(strings.NewReader(`
+# Default owners
+*       @global-owner alice@example.com
+*.go    @acme/gophers # trailing comment
+/vendor/
+`), DialectGitHub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertOwners(t, f, "README.md", []Owner{
+			{Value: "global-owner", Type: UsernameOwner},
+			{Value: "alice@example.com", Type: EmailOwner},
+		})
+		assertOwners(t, f, "cmd/main.go", []Owner{
+			{Value: "acme/gophers", Type: TeamOwner},
+		})
+		// Explicitly unowned by the last matching rule.
+		assertOwners(t, f, "vendor/lib/lib.go", nil)
+	})
+
+	t.Run("gitlab sections", func(t *testing.T) {
+		f, err := ParseFile(strings.NewReader(`
+*.md @docs-default
+
+[Documentation] @tech-writers
+docs/
+README.md @alice
+
+^[Frontend][2] @frontend
+*.ts
+*.md @frontend-docs
+
+[documentation]
+/CHANGELOG.md @release-managers
+`), DialectGitLab)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff([]*Section{
+			{
+				Name:          "Documentation",
+				Approvals:     1,
+				DefaultOwners: []Owner{{Value: "tech-writers", Type: UsernameOwner, Section: "Documentation"}},
+			},
+			{
+				Name:          "Frontend",
+				Optional:      true,
+				Approvals:     2,
+				DefaultOwners: []Owner{{Value: "frontend", Type: UsernameOwner, Section: "Frontend"}},
+			},
+		}, f.Sections); diff != "" {
+			t.Errorf("unexpected sections (-want +got):\n%s", diff)
+		}
+
+		// Every section contributes the owners of its last matching rule.
+		assertOwners(t, f, "README.md", []Owner{
+			{Value: "docs-default", Type: UsernameOwner},
+			{Value: "alice", Type: UsernameOwner, Section: "Documentation"},
+			{Value: "frontend-docs", Type: UsernameOwner, Section: "Frontend"},
+		})
+		// Rules without owners use the section's default owners.
+		assertOwners(t, f, "docs/setup.txt", []Owner{
+			{Value: "tech-writers", Type: UsernameOwner, Section: "Documentation"},
+		})
+		assertOwners(t, f, "web/app.ts", []Owner{
+			{Value: "frontend", Type: UsernameOwner, Section: "Frontend"},
+		})
+		// Sections with the same name are merged case-insensitively.
+		assertOwners(t, f, "CHANGELOG.md", []Owner{
+			{Value: "docs-default", Type: UsernameOwner},
+			{Value: "release-managers", Type: UsernameOwner, Section: "Documentation"},
+			{Value: "frontend-docs", Type: UsernameOwner, Section: "Frontend"},
+		})
+	})
+
+	t.Run("bitbucket groups", func(t *testing.T) {
+		f, err := ParseFile(strings.NewReader(`
+@@@Backend @alice bob@example.com
+*.go @@Backend @carol
+`), DialectBitbucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertOwners(t, f, "main.go", []Owner{
+			{Value: "alice", Type: UsernameOwner},
+			{Value: "bob@example.com", Type: EmailOwner},
+			{Value: "carol", Type: UsernameOwner},
+		})
+	})
+
+	t.Run("invalid owner", func(t *testing.T) {
+		_, err := ParseFile(strings.NewReader("*.go gophers\n"), DialectGitHub)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDetectDialect(t *testing.T) {
+	for content, want := range map[string]Dialect{
+		"*.go @gophers\n":                       DialectGitHub,
+		"[Backend]\n*.go @gophers\n":            DialectGitLab,
+		"^[Docs][2] @writers\n":                 DialectGitLab,
+		"@@@Backend @gophers\n*.go @@Backend\n": DialectBitbucket,
+		// Character classes are patterns, not section headers.
+		"[Dd]ocs/ @writers\n":          DialectGitHub,
+		"[Mm]akefile @builders\n":      DialectGitHub,
+		"[abc] docs/ @writers\n":       DialectGitHub,
+		"[Docs] @writers @acme/docs\n": DialectGitLab,
+	} {
+		if got := DetectDialect([]byte(content)); got != want {
+			t.Errorf("DetectDialect(%q) = %s, want %s", content, got, want)
+		}
+	}
+}
+
+func assertOwners(t *testing.T, f *File, path string, want Owners) {
+	t.Helper()
+	got, _ := f.Match(path)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected owners for %q (-want +got):\n%s", path, diff)
+	}
+}
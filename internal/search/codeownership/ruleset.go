@@ -3,63 +3,56 @@ package codeownership
 import (
 	"bytes"
 	"context"
-
-	"github.com/hmarr/codeowners"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 )
 
+// ownershipFilePaths are the locations an ownership file is loaded from, in
+// order of precedence. All existing files are loaded: a path is owned
+// according to the first file with a rule matching it, so files lower in the
+// list act as fallbacks for paths not covered by the ones above.
+//
+// The order follows GitHub, which prefers .github/ over the repository root
+// over docs/. The .gitlab/ and .bitbucket/ locations are only used by their
+// respective code hosts, so their relative order does not matter in practice.
+var ownershipFilePaths = []string{
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	".bitbucket/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
 type Ruleset struct {
-	codeownersRuleset codeowners.Ruleset
+	files []*File
 }
-type Owners = []codeowners.Owner
 
 func (r *Ruleset) Match(path string) (Owners, error) {
-	if r.codeownersRuleset == nil {
-		return Owners{}, nil
-	}
-
-	rule, err := r.codeownersRuleset.Match(path)
-	if err != nil {
-		return Owners{}, err
+	for _, f := range r.files {
+		if owners, ok := f.Match(path); ok {
+			return owners, nil
+		}
 	}
+	return Owners{}, nil
+}
 
-	if rule == nil {
-		return Owners{}, nil
+// Sections returns the GitLab sections declared across all loaded
+// ownership files.
+func (r *Ruleset) Sections() []*Section {
+	var sections []*Section
+	for _, f := range r.files {
+		sections = append(sections, f.Sections...)
 	}
-
-	// We directly return the codeowners.Owner struct to avoid creating
-	// unnecessary copies. We also found that the longest list of owners
-	// is less than 50.
-	// c.f. https://github.com/sourcegraph/sourcegraph/pull/39250#discussion_r927942090
-	return rule.Owners, nil
+	return sections
 }
 
 func NewRuleset(ctx context.Context, gitserver gitserver.Client, repoName api.RepoName, commitID api.CommitID) (Ruleset, error) {
 	ruleset := Ruleset{}
 
-	content, err := loadOwnershipFile(ctx, gitserver, repoName, commitID)
-	if err != nil {
-		return ruleset, err
-	}
-	if content == nil {
-		return ruleset, nil
-	}
-
-	codeownersRuleset, err := codeowners.ParseFile(bytes.NewReader(content))
-	if err != nil {
-		return ruleset, err
-	}
-
-	ruleset.codeownersRuleset = codeownersRuleset
-
-	return ruleset, nil
-}
-
-func loadOwnershipFile(ctx context.Context, gitserver gitserver.Client, repoName api.RepoName, commitID api.CommitID) ([]byte, error) {
-	for _, path := range []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"} {
+	for _, path := range ownershipFilePaths {
 		content, err := gitserver.ReadFile(
 			ctx,
 			repoName,
@@ -67,11 +60,32 @@ func loadOwnershipFile(ctx context.Context, gitserver gitserver.Client, repoName
 			path,
 			authz.DefaultSubRepoPermsChecker,
 		)
+		if err != nil || content == nil {
+			continue
+		}
 
-		if err == nil && content != nil {
-			return content, nil
+		f, err := ParseFile(bytes.NewReader(content), dialectForFile(path, content))
+		if err != nil {
+			return ruleset, err
 		}
+		ruleset.files = append(ruleset.files, f)
 	}
 
-	return nil, nil
+	return ruleset, nil
+}
+
+// dialectForFile returns the dialect of the ownership file at path. Files in
+// host-specific directories use that host's dialect, files in shared
+// locations are detected from their content.
+func dialectForFile(path string, content []byte) Dialect {
+	switch {
+	case strings.HasPrefix(path, ".github/"):
+		return DialectGitHub
+	case strings.HasPrefix(path, ".gitlab/"):
+		return DialectGitLab
+	case strings.HasPrefix(path, ".bitbucket/"):
+		return DialectBitbucket
+	default:
+		return DetectDialect(content)
+	}
 }
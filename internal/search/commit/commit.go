@@ -323,6 +323,14 @@ func queryParameterToPredicate(parameter query.Parameter, caseSensitive, diff bo
 		} else {
 			newPred = &gitprotocol.MessageMatches{Expr: parameter.Value, IgnoreCase: !caseSensitive}
 		}
+	case query.FieldAdded:
+		if diff {
+			newPred = &gitprotocol.DiffAddedMatches{Expr: parameter.Value, IgnoreCase: !caseSensitive}
+		}
+	case query.FieldRemoved:
+		if diff {
+			newPred = &gitprotocol.DiffRemovedMatches{Expr: parameter.Value, IgnoreCase: !caseSensitive}
+		}
 	case query.FieldFile:
 		newPred = &gitprotocol.DiffModifiesFile{Expr: parameter.Value, IgnoreCase: !caseSensitive}
 	case query.FieldLang:
@@ -63,6 +63,19 @@ func TestQueryToGitQuery(t *testing.T) {
 			&protocol.MessageMatches{Expr: "message2", IgnoreCase: true},
 			&protocol.DiffModifiesFile{Expr: "file", IgnoreCase: true},
 		),
+	}, {
+		name: "added and removed are converted for diffs",
+		input: query.Basic{
+			Parameters: []query.Parameter{
+				{Field: query.FieldAdded, Value: "added"},
+				{Field: query.FieldRemoved, Value: "removed", Negated: true},
+			},
+		},
+		diff: true,
+		output: protocol.NewAnd(
+			&protocol.DiffAddedMatches{Expr: "added", IgnoreCase: true},
+			protocol.NewNot(&protocol.DiffRemovedMatches{Expr: "removed", IgnoreCase: true}),
+		),
 	}}
 
 	for _, tc := range cases {
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// cursorVersion is the version of the encoding of a Cursor. Bump it when
+// the encoding changes in a backwards incompatible way.
+const cursorVersion = 1
+
+// Cursor records how far a search has paged through the repositories it
+// searches, so that it can be resumed with the `cursor:` parameter. A search
+// can page through repositories more than once, e.g. once for indexed and
+// once for unindexed search, so a cursor holds the position of each pager,
+// keyed by PagerKey.
+//
+// A Cursor is immutable once it is sent in a stream.
+type Cursor struct {
+	Pagers map[string]*PagerPosition
+}
+
+// PagerPosition is the position of a single repository pager.
+type PagerPosition struct {
+	// Page is the position of the current page of repositories. It is empty
+	// for the first page.
+	Page types.MultiCursor `json:"page,omitempty"`
+
+	// Searched are the repositories of the current page which have been
+	// searched completely.
+	Searched []api.RepoID `json:"searched,omitempty"`
+
+	// Done is true if the pager searched all of its repositories.
+	Done bool `json:"done,omitempty"`
+}
+
+// Update updates c with the positions of other. The positions of other are
+// more recent. It modifies c but does not modify other.
+func (c *Cursor) Update(other *Cursor) {
+	if len(other.Pagers) == 0 {
+		return
+	}
+	if c.Pagers == nil {
+		c.Pagers = make(map[string]*PagerPosition, len(other.Pagers))
+	}
+	for key, pos := range other.Pagers {
+		c.Pagers[key] = pos
+	}
+}
+
+// Done returns true if all pagers in c searched all of their repositories,
+// in which case there is nothing left to resume.
+func (c *Cursor) Done() bool {
+	for _, pos := range c.Pagers {
+		if !pos.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// Position returns the position of the pager identified by key, or nil if
+// the pager has not started yet.
+func (c *Cursor) Position(key string) *PagerPosition {
+	if c == nil {
+		return nil
+	}
+	return c.Pagers[key]
+}
+
+type encodedCursor struct {
+	Version int                       `json:"v"`
+	Pagers  map[string]*PagerPosition `json:"pagers"`
+}
+
+// Encode returns the opaque representation of c, which is the value of the
+// `cursor:` parameter.
+func (c *Cursor) Encode() string {
+	b, _ := json.Marshal(encodedCursor{Version: cursorVersion, Pagers: c.Pagers})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses the value of a `cursor:` parameter.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("invalid cursor %q", s)
+	}
+	var enc encodedCursor
+	if err := json.Unmarshal(b, &enc); err != nil {
+		return nil, errors.Errorf("invalid cursor %q", s)
+	}
+	if enc.Version != cursorVersion {
+		return nil, errors.Errorf("cursor %q is from an incompatible version of Sourcegraph. Run the search again without it", s)
+	}
+	for key, pos := range enc.Pagers {
+		if pos == nil {
+			delete(enc.Pagers, key)
+		}
+	}
+	return &Cursor{Pagers: enc.Pagers}, nil
+}
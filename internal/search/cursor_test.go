@@ -0,0 +1,58 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCursor(t *testing.T) {
+	c := &Cursor{Pagers: map[string]*PagerPosition{
+		"a": {
+			Page:     types.MultiCursor{{Column: "stars", Value: "10", Direction: "prev"}},
+			Searched: []api.RepoID{3, 1},
+		},
+		"b": {Done: true},
+	}}
+
+	got, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(c, got); diff != "" {
+		t.Fatalf("cursor did not round trip (-want +got):\n%s", diff)
+	}
+
+	if c.Done() {
+		t.Fatal("expected cursor with pending pager to not be done")
+	}
+	c.Update(&Cursor{Pagers: map[string]*PagerPosition{"a": {Done: true}}})
+	if !c.Done() {
+		t.Fatal("expected cursor to be done after all pagers are done")
+	}
+	if !(&Cursor{}).Done() {
+		t.Fatal("expected empty cursor to be done")
+	}
+
+	if got := c.Position("c"); got != nil {
+		t.Fatalf("expected no position for unknown pager, got %+v", got)
+	}
+	if got := (*Cursor)(nil).Position("a"); got != nil {
+		t.Fatalf("expected no position for nil cursor, got %+v", got)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"not base64!",
+		"bm90IGpzb24",                // "not json"
+		"eyJ2IjoyLCJwYWdlcnMiOnt9fQ", // {"v":2,"pagers":{}}
+	} {
+		if _, err := DecodeCursor(s); err == nil {
+			t.Errorf("expected error decoding %q", s)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package filter
 import (
 	"strings"
 
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -21,6 +22,19 @@ func (sp SelectPath) String() string {
 	return strings.Join(sp, ".")
 }
 
+// CaptureGroup returns the argument of `select:content.group(...)`, which is
+// the index or name of a capture group of the search pattern.
+func (sp SelectPath) CaptureGroup() (string, bool) {
+	if len(sp) != 2 || sp[0] != Content {
+		return "", false
+	}
+	name, arg, ok := splitSelectArgument(sp[1])
+	if !ok || name != "group" {
+		return "", false
+	}
+	return arg, true
+}
+
 // Root is the top-level result type that is being selected.
 // Returns an empty string if SelectPath is empty
 func (sp SelectPath) Root() string {
@@ -39,9 +53,12 @@ var validSelectors = object{
 			"removed": nil,
 		},
 	},
-	Content: nil,
+	Content: object{
+		"group()": nil, // capture group index or name, e.g. group(1)
+	},
 	File: {
 		"directory": nil,
+		"owners":    nil,
 		"path":      nil,
 	},
 	Repository: nil,
@@ -80,7 +97,14 @@ func SelectPathFromString(s string) (SelectPath, error) {
 	fields := strings.Split(s, ".")
 	cur := validSelectors
 	for _, field := range fields {
-		child, ok := cur[field]
+		key := field
+		if name, arg, ok := splitSelectArgument(field); ok {
+			if !isCaptureGroupRef(arg) {
+				return SelectPath{}, errors.Errorf("invalid argument %q on select path %q. Use the index or name of a capture group", arg, s)
+			}
+			key = name + "()"
+		}
+		child, ok := cur[key]
 		if !ok {
 			return SelectPath{}, errors.Errorf("invalid field %q on select path %q", field, s)
 		}
@@ -88,3 +112,20 @@ func SelectPathFromString(s string) (SelectPath, error) {
 	}
 	return SelectPath(fields), nil
 }
+
+// splitSelectArgument splits a field like "group(1)" into its name and
+// argument.
+func splitSelectArgument(field string) (name, arg string, ok bool) {
+	open := strings.IndexByte(field, '(')
+	if open < 0 || !strings.HasSuffix(field, ")") {
+		return "", "", false
+	}
+	return field[:open], field[open+1 : len(field)-1], true
+}
+
+var captureGroupRef = lazyregexp.New(`^(?:[1-9][0-9]*|[A-Za-z_][A-Za-z0-9_]*)$`)
+
+// isCaptureGroupRef returns true if s is a valid capture group index or name.
+func isCaptureGroupRef(s string) bool {
+	return captureGroupRef.MatchString(s)
+}
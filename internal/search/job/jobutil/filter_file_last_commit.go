@@ -0,0 +1,200 @@
+package jobutil
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/regexp"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// lastCommitBatchSize is the maximum number of paths we ask gitserver about
+// in a single request.
+const lastCommitBatchSize = 100
+
+// NewFileLastCommitFilterJob creates a filter job to post-filter file results
+// for the file:has.commit.after(), file:older.than() and file:modified.by()
+// predicates. The last commit modifying each file is looked up in gitserver,
+// batched per repository and commit. All filters must hold for a file to be
+// kept. Results other than file matches are filtered out.
+func NewFileLastCommitFilterJob(filters []query.FileLastCommitFilter, child job.Job) job.Job {
+	authorMatchers := make([]*regexp.Regexp, len(filters))
+	for i, f := range filters {
+		if f.Author != "" {
+			// Invariant: the author pattern is validated when parsing the predicate.
+			authorMatchers[i] = regexp.MustCompile("(?i:" + f.Author + ")")
+		}
+	}
+
+	return &fileLastCommitFilterJob{
+		filters:        filters,
+		authorMatchers: authorMatchers,
+		child:          child,
+	}
+}
+
+type fileLastCommitFilterJob struct {
+	filters []query.FileLastCommitFilter
+
+	// authorMatchers[i] is the compiled author pattern of filters[i], if any.
+	authorMatchers []*regexp.Regexp
+
+	child job.Job
+}
+
+func (j *fileLastCommitFilterJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu   sync.Mutex
+		errs error
+	)
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		var err error
+		event.Results, err = j.filterMatches(ctx, clients.Gitserver, event.Results)
+		if err != nil {
+			mu.Lock()
+			errs = errors.Append(errs, err)
+			mu.Unlock()
+		}
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, filteredStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	return alert, errs
+}
+
+type repoCommit struct {
+	repo   api.RepoName
+	commit api.CommitID
+}
+
+func (j *fileLastCommitFilterJob) filterMatches(ctx context.Context, gs gitserver.Client, matches []result.Match) ([]result.Match, error) {
+	// Group paths by repository and commit so we can ask gitserver in batches.
+	var order []repoCommit
+	paths := make(map[repoCommit][]string)
+	for _, m := range matches {
+		fm, ok := m.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+		key := repoCommit{repo: fm.Repo.Name, commit: fm.CommitID}
+		if _, ok := paths[key]; !ok {
+			order = append(order, key)
+		}
+		paths[key] = append(paths[key], fm.Path)
+	}
+
+	var errs error
+	lastCommits := make(map[repoCommit]map[string]*gitdomain.Commit, len(order))
+	for _, key := range order {
+		commits := make(map[string]*gitdomain.Commit, len(paths[key]))
+		for batch := paths[key]; len(batch) > 0; {
+			n := len(batch)
+			if n > lastCommitBatchSize {
+				n = lastCommitBatchSize
+			}
+			res, err := gs.LastCommitsForPaths(ctx, key.repo, key.commit, batch[:n], authz.DefaultSubRepoPermsChecker)
+			if err != nil {
+				errs = errors.Append(errs, err)
+			}
+			for path, c := range res {
+				commits[path] = c
+			}
+			batch = batch[n:]
+		}
+		lastCommits[key] = commits
+	}
+
+	filtered := matches[:0]
+	for _, m := range matches {
+		fm, ok := m.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+		c, ok := lastCommits[repoCommit{repo: fm.Repo.Name, commit: fm.CommitID}][fm.Path]
+		if !ok {
+			// Without history we cannot tell whether the filters hold.
+			continue
+		}
+		if j.matches(c) {
+			filtered = append(filtered, fm)
+		}
+	}
+	return filtered, errs
+}
+
+// matches returns true if c satisfies all filters.
+func (j *fileLastCommitFilterJob) matches(c *gitdomain.Commit) bool {
+	date := c.Author.Date
+	if c.Committer != nil {
+		// git log --after and --before use the committer date too.
+		date = c.Committer.Date
+	}
+
+	for i, f := range j.filters {
+		var ok bool
+		switch {
+		case f.After != nil:
+			ok = date.After(*f.After)
+		case f.Before != nil:
+			ok = date.Before(*f.Before)
+		case j.authorMatchers[i] != nil:
+			ok = j.authorMatchers[i].MatchString(c.Author.Name) || j.authorMatchers[i].MatchString(c.Author.Email)
+		}
+		if ok == f.Negated {
+			return false
+		}
+	}
+	return true
+}
+
+func (j *fileLastCommitFilterJob) Name() string {
+	return "FileLastCommitFilterJob"
+}
+
+func (j *fileLastCommitFilterJob) Fields(v job.Verbosity) (res []otlog.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		for _, f := range j.filters {
+			switch {
+			case f.After != nil:
+				res = append(res, trace.Printf("after", "%s (negated: %t)", f.After, f.Negated))
+			case f.Before != nil:
+				res = append(res, trace.Printf("before", "%s (negated: %t)", f.Before, f.Negated))
+			default:
+				res = append(res, trace.Printf("author", "%q (negated: %t)", f.Author, f.Negated))
+			}
+		}
+	}
+	return res
+}
+
+func (j *fileLastCommitFilterJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *fileLastCommitFilterJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
@@ -0,0 +1,127 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestFileLastCommitFilterJob(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2022, 1, d, 0, 0, 0, 0, time.UTC) }
+	timePtr := func(t time.Time) *time.Time { return &t }
+
+	commit := func(author string, date time.Time) *gitdomain.Commit {
+		return &gitdomain.Commit{
+			Author:    gitdomain.Signature{Name: author, Email: author + "@example.com", Date: date},
+			Committer: &gitdomain.Signature{Name: author, Email: author + "@example.com", Date: date},
+		}
+	}
+	lastCommits := map[string]*gitdomain.Commit{
+		"old.go":   commit("alice", day(1)),
+		"new.go":   commit("bob", day(20)),
+		"newer.go": commit("Alice", day(25)),
+	}
+
+	fm := func(path string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{Name: "repo"},
+				CommitID: "deadbeef",
+				Path:     path,
+			},
+		}
+	}
+	input := func() result.Matches {
+		return result.Matches{
+			fm("old.go"),
+			fm("new.go"),
+			fm("newer.go"),
+			fm("unknown.go"),
+			&result.RepoMatch{Name: "repo"},
+		}
+	}
+
+	cases := []struct {
+		name    string
+		filters []query.FileLastCommitFilter
+		want    []string
+	}{{
+		name:    "has.commit.after",
+		filters: []query.FileLastCommitFilter{{After: timePtr(day(10))}},
+		want:    []string{"new.go", "newer.go"},
+	}, {
+		name:    "older.than",
+		filters: []query.FileLastCommitFilter{{Before: timePtr(day(10))}},
+		want:    []string{"old.go"},
+	}, {
+		name:    "modified.by is case insensitive",
+		filters: []query.FileLastCommitFilter{{Author: "^alice$"}},
+		want:    []string{"old.go", "newer.go"},
+	}, {
+		name:    "modified.by matches email",
+		filters: []query.FileLastCommitFilter{{Author: "bob@example"}},
+		want:    []string{"new.go"},
+	}, {
+		name: "filters are combined",
+		filters: []query.FileLastCommitFilter{
+			{After: timePtr(day(10))},
+			{Author: "alice"},
+		},
+		want: []string{"newer.go"},
+	}, {
+		name:    "negated",
+		filters: []query.FileLastCommitFilter{{Author: "alice", Negated: true}},
+		want:    []string{"new.go"},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gs := gitserver.NewMockClient()
+			gs.LastCommitsForPathsFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, commit api.CommitID, paths []string, _ authz.SubRepoPermissionChecker) (map[string]*gitdomain.Commit, error) {
+				require.Equal(t, api.RepoName("repo"), repo)
+				require.Equal(t, api.CommitID("deadbeef"), commit)
+				res := make(map[string]*gitdomain.Commit)
+				for _, p := range paths {
+					if c, ok := lastCommits[p]; ok {
+						res[p] = c
+					}
+				}
+				return res, nil
+			})
+
+			childJob := mockjob.NewMockJob()
+			childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+				s.Send(streaming.SearchEvent{Results: input()})
+				return nil, nil
+			})
+
+			var got []string
+			stream := streaming.StreamFunc(func(ev streaming.SearchEvent) {
+				for _, m := range ev.Results {
+					got = append(got, m.(*result.FileMatch).Path)
+				}
+			})
+
+			j := NewFileLastCommitFilterJob(tc.filters, childJob)
+			alert, err := j.Run(context.Background(), job.RuntimeClients{Gitserver: gs}, stream)
+			require.Nil(t, alert)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Len(t, gs.LastCommitsForPathsFunc.History(), 1)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/scope"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
 	"github.com/sourcegraph/sourcegraph/internal/search/structural"
@@ -87,6 +88,10 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		fileMatchLimit := int32(computeFileMatchLimit(b, inputs.Protocol))
 		selector, _ := filter.SelectPathFromString(b.FindValue(query.FieldSelect)) // Invariant: select is validated
 		repoOptions := toRepoOptions(b, inputs.UserSettings)
+		cursor, err := toCursor(b)
+		if err != nil {
+			return nil, err
+		}
 		repoUniverseSearch, skipRepoSubsetSearch, runZoektOverRepos := jobMode(b, repoOptions, resultTypes, inputs.PatternType, inputs.OnSourcegraphDotCom)
 
 		builder := &jobBuilder{
@@ -96,6 +101,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 			features:       inputs.Features,
 			fileMatchLimit: fileMatchLimit,
 			selector:       selector,
+			scope:          scope.Kind(b.FindValue(query.FieldScope)), // Invariant: scope is validated
 		}
 
 		if resultTypes.Has(result.TypeFile | result.TypePath) {
@@ -117,6 +123,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 					child:            &reposPartialJob{job},
 					repoOpts:         repoOptions,
 					containsRefGlobs: query.ContainsRefGlobs(b.ToParseTree()),
+					cursor:           cursor,
 				})
 			}
 		}
@@ -140,6 +147,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 					child:            &reposPartialJob{job},
 					repoOpts:         repoOptions,
 					containsRefGlobs: query.ContainsRefGlobs(b.ToParseTree()),
+					cursor:           cursor,
 				})
 			}
 		}
@@ -182,16 +190,34 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
+	{ // Apply file:has.commit.after(), file:older.than() and file:modified.by() post-filter
+		if filters := b.FileLastCommitFilters(); len(filters) > 0 {
+			basicJob = NewFileLastCommitFilterJob(filters, basicJob)
+		}
+	}
+
 	{ // Apply code ownership post-search filter
 		if includeOwners, excludeOwners := b.FileHasOwner(); inputs.Features.CodeOwnershipFilters == true && (len(includeOwners) > 0 || len(excludeOwners) > 0) {
 			basicJob = codeownershipjob.New(basicJob, includeOwners, excludeOwners)
 		}
 	}
 
+	selectOwners := false
+	selectGroup, selectsGroup := "", false
 	{ // Apply selectors
 		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
 			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
-			basicJob = NewSelectJob(sp, basicJob)
+			if isSelectOwners(sp) {
+				// Owners are resolved after subrepo permissions
+				// are applied, see below.
+				selectOwners = true
+			} else if group, ok := sp.CaptureGroup(); ok {
+				// Like owners, capture group values are extracted
+				// after subrepo permissions are applied.
+				selectGroup, selectsGroup = group, true
+			} else {
+				basicJob = NewSelectJob(sp, basicJob)
+			}
 		}
 	}
 
@@ -202,6 +228,22 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
+	{ // Apply select:file.owners
+		if selectOwners {
+			basicJob = NewSelectOwnersJob(basicJob)
+		}
+	}
+
+	{ // Apply select:content.group()
+		if selectsGroup {
+			pattern, group, err := captureGroupPattern(originalQuery, selectGroup)
+			if err != nil {
+				return nil, err
+			}
+			basicJob = NewSelectCaptureGroupJob(pattern, group, basicJob)
+		}
+	}
+
 	{ // Apply limit
 		maxResults := b.ToParseTree().MaxResults(inputs.DefaultLimit())
 		basicJob = NewLimitJob(maxResults, basicJob)
@@ -292,6 +334,10 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 	useFullDeadline := f.GetTimeout() != nil || f.Count() != nil || searchInputs.Protocol == search.Streaming
 
 	repoOptions := toRepoOptions(f.ToBasic(), searchInputs.UserSettings)
+	cursor, err := toCursor(f.ToBasic())
+	if err != nil {
+		return nil, err
+	}
 
 	_, skipRepoSubsetSearch, _ := jobMode(f.ToBasic(), repoOptions, resultTypes, searchInputs.PatternType, searchInputs.OnSourcegraphDotCom)
 
@@ -322,6 +368,7 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 					child:            &reposPartialJob{searcherJob},
 					repoOpts:         repoOptions,
 					containsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
+					cursor:           cursor,
 				})
 			}
 		}
@@ -339,6 +386,7 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 					child:            &reposPartialJob{symbolSearchJob},
 					repoOpts:         repoOptions,
 					containsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
+					cursor:           cursor,
 				})
 			}
 		}
@@ -538,6 +586,7 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 		// Values dependent on pattern atom.
 		IsRegExp:        isRegexp,
 		IsStructuralPat: b.IsStructural(),
+		IsFuzzy:         b.IsFuzzy(),
 		IsCaseSensitive: b.IsCaseSensitive(),
 		FileMatchLimit:  int32(count),
 		Pattern:         b.PatternString(),
@@ -551,6 +600,7 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 		Languages:                    langInclude,
 		PathPatternsAreCaseSensitive: b.IsCaseSensitive(),
 		CombyRule:                    b.FindValue(query.FieldCombyRule),
+		Scope:                        b.FindValue(query.FieldScope),
 		Index:                        b.Index(),
 		Select:                       selector,
 	}
@@ -560,7 +610,9 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 // the `pattern`, and top-level `searchType` (coming from a GQL value).
 func computeResultTypes(types []string, b query.Basic, searchType query.SearchType) result.Types {
 	var rts result.Types
-	if searchType == query.SearchTypeStructural && !b.IsEmptyPattern() {
+	if (searchType == query.SearchTypeStructural || searchType == query.SearchTypeFuzzy) && !b.IsEmptyPattern() {
+		// Like structural search, fuzzy search verifies candidate files
+		// from Zoekt in searcher.
 		rts = result.TypeStructural
 	} else {
 		if len(types) == 0 {
@@ -629,6 +681,16 @@ func toRepoOptions(b query.Basic, userSettings *schema.Settings) search.RepoOpti
 	}
 }
 
+// toCursor returns the position to resume the search from, if the query
+// specifies a cursor.
+func toCursor(b query.Basic) (*search.Cursor, error) {
+	v := b.FindValue(query.FieldCursor)
+	if v == "" {
+		return nil, nil
+	}
+	return search.DecodeCursor(v)
+}
+
 // jobBuilder represents computed static values that are backend agnostic: we
 // generally need to compute these values before we're able to create (or build)
 // multiple specific jobs. If you want to add new fields or state to run a
@@ -648,6 +710,7 @@ type jobBuilder struct {
 	features       *search.Features
 	fileMatchLimit int32
 	selector       filter.SelectPath
+	scope          scope.Kind
 }
 
 func (b *jobBuilder) newZoektGlobalSearch(typ search.IndexedRequestType) (job.Job, error) {
@@ -712,13 +775,19 @@ func (b *jobBuilder) newZoektSearch(typ search.IndexedRequestType) (job.Job, err
 			Typ:            typ,
 			FileMatchLimit: b.fileMatchLimit,
 			Select:         b.selector,
+			Scope:          b.scope,
 		}, nil
 	}
 	return nil, errors.Errorf("attempt to create unrecognized zoekt search with value %v", typ)
 }
 
 func jobMode(b query.Basic, repoOptions search.RepoOptions, resultTypes result.Types, st query.SearchType, onSourcegraphDotCom bool) (repoUniverseSearch, skipRepoSubsetSearch, runZoektOverRepos bool) {
-	isGlobalSearch := isGlobal(repoOptions) && st != query.SearchTypeStructural
+	isGlobalSearch := isGlobal(repoOptions) && st != query.SearchTypeStructural && st != query.SearchTypeFuzzy
+	// A cursor holds the positions of repository pagers, so searches which
+	// are resumable page through repositories instead of searching globally.
+	// Matches are restricted to a scope by reading whole files, which we
+	// only do for the repositories of a page.
+	isGlobalSearch = isGlobalSearch && !b.Exists(query.FieldCursor) && !b.Exists(query.FieldScope)
 
 	hasGlobalSearchResultType := resultTypes.Has(result.TypeFile | result.TypePath | result.TypeSymbol)
 	isIndexedSearch := b.Index() != query.No
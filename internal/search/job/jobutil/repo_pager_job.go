@@ -2,22 +2,32 @@ package jobutil
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
 
 	otlog "github.com/opentracing/opentracing-go/log"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/printer"
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 type repoPagerJob struct {
 	repoOpts         search.RepoOptions
 	containsRefGlobs bool                          // whether to include repositories with refs
 	child            job.PartialJob[resolvedRepos] // child job tree that need populating a repos field to run
+
+	// cursor is the position to resume from, parsed from the cursor: field.
+	// It is nil if the search starts from the beginning.
+	cursor *search.Cursor
 }
 
 // resolvedRepos is the set of information to complete the partial
@@ -81,14 +91,30 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 	_, ctx, stream, finish := job.StartSpan(ctx, stream, p)
 	defer func() { finish(alert, err) }()
 
+	repoOpts := p.repoOpts
+	position := newPagerPosition(p.cursorKey())
+	if start := p.cursor.Position(position.key); start != nil {
+		if start.Done {
+			return nil, nil
+		}
+		repoOpts.Cursors = start.Page
+		position.resume(start)
+	}
+	stream = position.stream(stream)
+
 	var maxAlerter search.MaxAlerter
 
 	repoResolver := repos.NewResolver(clients.Logger, clients.DB, clients.SearcherURLs, clients.Zoekt)
+	pageCursors := repoOpts.Cursors
 	pager := func(page *repos.Resolved) error {
+		repoRevs, cursor := position.startPage(pageCursors, page.RepoRevs)
+		pageCursors = page.Next
+		stream.Send(streaming.SearchEvent{Stats: streaming.Stats{Cursor: cursor}})
+
 		indexed, unindexed, err := zoekt.PartitionRepos(
 			ctx,
 			clients.Logger,
-			page.RepoRevs,
+			repoRevs,
 			clients.Zoekt,
 			search.TextRequest,
 			p.repoOpts.UseIndex,
@@ -104,7 +130,125 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 		return err
 	}
 
-	return maxAlerter.Alert, repoResolver.Paginate(ctx, p.repoOpts, pager)
+	err = repoResolver.Paginate(ctx, repoOpts, pager)
+	if err == nil && ctx.Err() == nil {
+		stream.Send(streaming.SearchEvent{Stats: streaming.Stats{Cursor: position.done()}})
+	}
+	return maxAlerter.Alert, err
+}
+
+// cursorKey identifies p in a search.Cursor. It is derived from the repo
+// options and the child jobs of p, so it is the same every time a query is
+// run.
+func (p *repoPagerJob) cursorKey() string {
+	h := sha256.Sum256([]byte(printer.SexpVerbose(p, job.VerbosityBasic, false)))
+	return hex.EncodeToString(h[:8])
+}
+
+// pagerPosition tracks the position of a repoPagerJob as it pages through
+// repositories.
+type pagerPosition struct {
+	key string
+
+	mu sync.Mutex
+	// page is the position of the current page.
+	page search.PagerPosition
+	// pending is the number of revisions of each repository in the current
+	// page which have not been searched completely yet.
+	pending map[api.RepoID]int
+	// skip are the repositories of the first page which were searched
+	// completely before the search was resumed.
+	skip map[api.RepoID]struct{}
+}
+
+func newPagerPosition(key string) *pagerPosition {
+	return &pagerPosition{key: key}
+}
+
+// resume continues from start, which must be the position of the same pager.
+func (p *pagerPosition) resume(start *search.PagerPosition) {
+	p.skip = make(map[api.RepoID]struct{}, len(start.Searched))
+	for _, id := range start.Searched {
+		p.skip[id] = struct{}{}
+	}
+}
+
+// startPage moves to the page at cursors with repoRevs. It returns the
+// repository revisions of the page which still need to be searched and the
+// cursor of the new position.
+func (p *pagerPosition) startPage(cursors []*types.Cursor, repoRevs []*search.RepositoryRevisions) ([]*search.RepositoryRevisions, search.Cursor) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.page = search.PagerPosition{Page: cursors}
+	p.pending = make(map[api.RepoID]int, len(repoRevs))
+
+	remaining := make([]*search.RepositoryRevisions, 0, len(repoRevs))
+	for _, rr := range repoRevs {
+		if _, ok := p.skip[rr.Repo.ID]; ok {
+			p.page.Searched = append(p.page.Searched, rr.Repo.ID)
+			continue
+		}
+		p.pending[rr.Repo.ID] = len(rr.Revs)
+		remaining = append(remaining, rr)
+	}
+	// Only the first page can contain repositories we searched before.
+	p.skip = nil
+	return remaining, p.cursorLocked()
+}
+
+// done marks all pages as searched and returns the cursor of the final
+// position.
+func (p *pagerPosition) done() search.Cursor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.page = search.PagerPosition{Done: true}
+	p.pending = nil
+	return p.cursorLocked()
+}
+
+// cursorLocked returns a cursor holding the current position. p.mu must be
+// held.
+func (p *pagerPosition) cursorLocked() search.Cursor {
+	page := p.page
+	page.Searched = append([]api.RepoID(nil), p.page.Searched...)
+	return search.Cursor{Pagers: map[string]*search.PagerPosition{p.key: &page}}
+}
+
+// stream returns a sender which records the repositories reported as
+// searched in events sent to it, and attaches the updated position to those
+// events instead. The repositories searched completely are only passed on as
+// part of the position, so they don't accumulate in the stats of the search.
+func (p *pagerPosition) stream(parent streaming.Sender) streaming.Sender {
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		if len(event.Stats.Searched) > 0 {
+			p.mu.Lock()
+			updated := false
+			for _, id := range event.Stats.Searched {
+				n, ok := p.pending[id]
+				if !ok {
+					continue
+				}
+				if n > 1 {
+					p.pending[id] = n - 1
+					continue
+				}
+				delete(p.pending, id)
+				p.page.Searched = append(p.page.Searched, id)
+				updated = true
+			}
+			if updated {
+				event.Stats.Cursor = p.cursorLocked()
+			}
+			p.mu.Unlock()
+
+			event.Stats.Searched = nil
+			if len(event.Results) == 0 && event.Stats.Zero() {
+				return
+			}
+		}
+		parent.Send(event)
+	})
 }
 
 func (p *repoPagerJob) Name() string {
@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -32,3 +33,91 @@ func Test_setRepos(t *testing.T) {
 	require.Len(t, j.(*ParallelJob).children[0].(*zoekt.RepoSubsetTextSearchJob).Repos.RepoRevs, 1)
 	require.Len(t, j.(*ParallelJob).children[1].(*searcher.TextSearchJob).Repos, 2)
 }
+
+func TestPagerPosition(t *testing.T) {
+	repoRevs := func(ids ...api.RepoID) []*search.RepositoryRevisions {
+		rrs := make([]*search.RepositoryRevisions, 0, len(ids))
+		for _, id := range ids {
+			rrs = append(rrs, &search.RepositoryRevisions{Repo: types.MinimalRepo{ID: id}, Revs: []string{"a", "b"}})
+		}
+		return rrs
+	}
+	searched := func(ids ...api.RepoID) streaming.SearchEvent {
+		return streaming.SearchEvent{Stats: streaming.Stats{Searched: ids}}
+	}
+	page := types.MultiCursor{{Column: "id", Value: "3", Direction: "next"}}
+
+	p := newPagerPosition("key")
+	p.resume(&search.PagerPosition{Page: page, Searched: []api.RepoID{1}})
+
+	var cursor search.Cursor
+	stream := p.stream(streaming.StreamFunc(func(event streaming.SearchEvent) {
+		// Searched repos are only passed on as part of the position.
+		require.Empty(t, event.Stats.Searched)
+		cursor.Update(&event.Stats.Cursor)
+	}))
+
+	// Repos searched before resuming are skipped.
+	remaining, c := p.startPage(page, repoRevs(1, 2, 3))
+	require.Len(t, remaining, 2)
+	require.Equal(t, []api.RepoID{1}, c.Position("key").Searched)
+
+	// A repo is only searched once all its revisions are.
+	stream.Send(searched(2))
+	require.Nil(t, cursor.Position("key"))
+	stream.Send(searched(2, 4))
+	require.Equal(t, &search.PagerPosition{Page: page, Searched: []api.RepoID{1, 2}}, cursor.Position("key"))
+	require.False(t, cursor.Done())
+
+	// Skipped repos only apply to the first page.
+	remaining, c = p.startPage(nil, repoRevs(1, 5))
+	require.Len(t, remaining, 2)
+	require.Empty(t, c.Position("key").Searched)
+
+	done := p.done()
+	cursor.Update(&done)
+	require.True(t, cursor.Done())
+}
+
+func TestPagerPositionResumeReportsSearched(t *testing.T) {
+	repoRevs := []*search.RepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: 1}, Revs: []string{"HEAD"}},
+		{Repo: types.MinimalRepo{ID: 2}, Revs: []string{"HEAD"}},
+		{Repo: types.MinimalRepo{ID: 3}, Revs: []string{"HEAD"}},
+	}
+	searched := func(ids ...api.RepoID) streaming.SearchEvent {
+		return streaming.SearchEvent{Stats: streaming.Stats{Searched: ids}}
+	}
+	page := types.MultiCursor{{Column: "id", Value: "1", Direction: "next"}}
+
+	// run pages through repoRevs from start, reports the repos in searchedIDs
+	// as searched completely, and returns the aggregated stats it passed on.
+	run := func(start *search.Cursor, searchedIDs ...api.RepoID) (streaming.Stats, []*search.RepositoryRevisions) {
+		p := newPagerPosition("key")
+		if pos := start.Position("key"); pos != nil {
+			p.resume(pos)
+		}
+		agg := streaming.NewAggregatingStream()
+		stream := p.stream(agg)
+
+		remaining, c := p.startPage(page, repoRevs)
+		stream.Send(streaming.SearchEvent{Stats: streaming.Stats{Cursor: c}})
+		for _, id := range searchedIDs {
+			stream.Send(searched(id))
+		}
+		return agg.Stats, remaining
+	}
+
+	// The first run is stopped after searching repo 1.
+	stats, remaining := run(nil, 1)
+	require.Len(t, remaining, 3)
+	require.Empty(t, stats.Searched)
+	require.Equal(t, []api.RepoID{1}, stats.Cursor.Position("key").Searched)
+
+	// Resuming skips repo 1, but still reports it as searched, so that
+	// consumers of the position see every repo of the page.
+	resumed, remaining := run(&stats.Cursor, 2, 3)
+	require.Len(t, remaining, 2)
+	require.Empty(t, resumed.Searched)
+	require.Equal(t, &search.PagerPosition{Page: page, Searched: []api.RepoID{1, 2, 3}}, resumed.Cursor.Position("key"))
+}
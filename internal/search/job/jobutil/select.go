@@ -2,16 +2,22 @@ package jobutil
 
 import (
 	"context"
+	"sort"
+	"strconv"
 	"sync"
 
+	"github.com/grafana/regexp"
 	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // NewSelectJob creates a job that transforms streamed results with
@@ -96,3 +102,269 @@ func newSelectingStream(parent streaming.Sender, s filter.SelectPath) streaming.
 		parent.Send(e)
 	})
 }
+
+// isSelectOwners returns true if path selects the code owners of file
+// matches, i.e. `select:file.owners`.
+func isSelectOwners(path filter.SelectPath) bool {
+	return len(path) == 2 && path.Root() == filter.File && path[1] == "owners"
+}
+
+// NewSelectOwnersJob creates a job that resolves the code owners of streamed
+// file matches (`select:file.owners`). Owners are deduplicated across the
+// whole search, so results are only sent once the child job completes.
+func NewSelectOwnersJob(child job.Job) job.Job {
+	return &selectOwnersJob{child: child}
+}
+
+type selectOwnersJob struct {
+	child job.Job
+}
+
+func (j *selectOwnersJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu     sync.Mutex
+		errs   error
+		owners = make(map[string]*result.OwnerMatch)
+	)
+
+	rules := codeownership.NewRulesCache()
+
+	ownersStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		for _, m := range event.Results {
+			fm, ok := m.(*result.FileMatch)
+			if !ok {
+				continue
+			}
+
+			ruleset, err := rules.GetFromCacheOrFetch(ctx, clients.Gitserver, fm.Repo.Name, fm.CommitID)
+			if err == nil {
+				var fileOwners codeownership.Owners
+				fileOwners, err = ruleset.Match(fm.Path)
+				if err == nil {
+					mu.Lock()
+					for _, o := range fileOwners {
+						handle := o.String()
+						om, ok := owners[handle]
+						if !ok {
+							om = &result.OwnerMatch{Handle: handle, Type: o.Type}
+							owners[handle] = om
+						}
+						om.FileCount++
+						om.MatchCount += fm.ResultCount()
+					}
+					mu.Unlock()
+				}
+			}
+			if err != nil {
+				mu.Lock()
+				errs = errors.Append(errs, err)
+				mu.Unlock()
+			}
+		}
+
+		// Forward stats but hold back results until all owners are known.
+		event.Results = nil
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, ownersStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(owners) > 0 {
+		stream.Send(streaming.SearchEvent{Results: sortedOwnerMatches(owners)})
+	}
+	return alert, errs
+}
+
+func (j *selectOwnersJob) Name() string {
+	return "SelectOwnersJob"
+}
+
+func (j *selectOwnersJob) Fields(job.Verbosity) []log.Field { return nil }
+
+func (j *selectOwnersJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *selectOwnersJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+// sortedOwnerMatches returns owners ordered by descending match count, then
+// by handle.
+func sortedOwnerMatches(owners map[string]*result.OwnerMatch) result.Matches {
+	matches := make(result.Matches, 0, len(owners))
+	for _, om := range owners {
+		matches = append(matches, om)
+	}
+	sort.Slice(matches, func(i, k int) bool {
+		a, b := matches[i].(*result.OwnerMatch), matches[k].(*result.OwnerMatch)
+		if a.MatchCount != b.MatchCount {
+			return a.MatchCount > b.MatchCount
+		}
+		return a.Handle < b.Handle
+	})
+	return matches
+}
+
+// captureGroupPattern returns the regular expression of the search pattern
+// of b, and the index of its capture group selected by
+// `select:content.group(group)`.
+func captureGroupPattern(b query.Basic, group string) (*regexp.Regexp, int, error) {
+	p, ok := b.Pattern.(query.Pattern)
+	if !ok || p.Negated || !p.Annotation.Labels.IsSet(query.Regexp) {
+		return nil, 0, errors.New("select:content.group() requires a single regular expression search pattern. Add patterntype:regexp to the query")
+	}
+
+	expr := p.Value
+	if !b.IsCaseSensitive() {
+		expr = "(?i:" + expr + ")"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index := re.SubexpIndex(group)
+	if n, err := strconv.Atoi(group); err == nil && n <= re.NumSubexp() {
+		index = n
+	}
+	if index < 1 {
+		return nil, 0, errors.Errorf("the search pattern %q does not have a capture group %q", p.Value, group)
+	}
+	return re, index, nil
+}
+
+// NewSelectCaptureGroupJob creates a job that extracts the values of a
+// capture group of pattern from the line matches of streamed file matches
+// (`select:content.group(...)`). Values are deduplicated across the whole
+// search, so results are only sent once the child job completes.
+func NewSelectCaptureGroupJob(pattern *regexp.Regexp, group int, child job.Job) job.Job {
+	return &selectCaptureGroupJob{pattern: pattern, group: group, child: child}
+}
+
+type selectCaptureGroupJob struct {
+	pattern *regexp.Regexp
+	group   int
+	child   job.Job
+}
+
+func (j *selectCaptureGroupJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu       sync.Mutex
+		captures = make(map[string]*result.CaptureGroupMatch)
+	)
+
+	capturesStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		mu.Lock()
+		for _, m := range event.Results {
+			fm, ok := m.(*result.FileMatch)
+			if !ok {
+				continue
+			}
+
+			seen := map[string]struct{}{}
+			for _, value := range j.captures(fm.ChunkMatches) {
+				cm, ok := captures[value]
+				if !ok {
+					cm = &result.CaptureGroupMatch{Value: value}
+					captures[value] = cm
+				}
+				if _, ok := seen[value]; !ok {
+					seen[value] = struct{}{}
+					cm.FileCount++
+				}
+				cm.MatchCount++
+			}
+		}
+		mu.Unlock()
+
+		// Forward stats but hold back results until all values are known.
+		event.Results = nil
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, capturesStream)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(captures) > 0 {
+		stream.Send(streaming.SearchEvent{Results: sortedCaptureGroupMatches(captures)})
+	}
+	return alert, err
+}
+
+// captures returns the value of the capture group for every match in
+// chunks in which it participated.
+func (j *selectCaptureGroupJob) captures(chunks result.ChunkMatches) []string {
+	var values []string
+	for _, chunk := range chunks {
+		for _, r := range chunk.Ranges {
+			rr := r.Sub(chunk.ContentStart)
+			if rr.Start.Offset < 0 || rr.End.Offset > len(chunk.Content) || rr.Start.Offset > rr.End.Offset {
+				continue
+			}
+			text := chunk.Content[rr.Start.Offset:rr.End.Offset]
+			submatches := j.pattern.FindStringSubmatchIndex(text)
+			if len(submatches) == 0 || submatches[2*j.group] < 0 {
+				continue
+			}
+			values = append(values, text[submatches[2*j.group]:submatches[2*j.group+1]])
+		}
+	}
+	return values
+}
+
+func (j *selectCaptureGroupJob) Name() string {
+	return "SelectCaptureGroupJob"
+}
+
+func (j *selectCaptureGroupJob) Fields(v job.Verbosity) (res []log.Field) {
+	switch v {
+	case job.VerbosityMax, job.VerbosityBasic:
+		res = append(res,
+			log.String("pattern", j.pattern.String()),
+			log.Int("group", j.group),
+		)
+	}
+	return res
+}
+
+func (j *selectCaptureGroupJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *selectCaptureGroupJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+// sortedCaptureGroupMatches returns captures ordered by descending match
+// count, then by value.
+func sortedCaptureGroupMatches(captures map[string]*result.CaptureGroupMatch) result.Matches {
+	matches := make(result.Matches, 0, len(captures))
+	for _, cm := range captures {
+		matches = append(matches, cm)
+	}
+	sort.Slice(matches, func(i, k int) bool {
+		a, b := matches[i].(*result.CaptureGroupMatch), matches[k].(*result.CaptureGroupMatch)
+		if a.MatchCount != b.MatchCount {
+			return a.MatchCount > b.MatchCount
+		}
+		return a.Value < b.Value
+	})
+	return matches
+}
@@ -1,14 +1,24 @@
 package jobutil
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/hexops/autogold"
+	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func TestWithSelect(t *testing.T) {
@@ -221,3 +231,113 @@ func TestWithSelect(t *testing.T) {
   }
 ]`).Equal(t, test("content"))
 }
+
+func TestSelectOwnersJob(t *testing.T) {
+	gitserver.Mocks.ReadFile = func(_ api.CommitID, file string) ([]byte, error) {
+		if file != "CODEOWNERS" {
+			return nil, errors.New("file does not exist")
+		}
+		return []byte("*.go @gopher\nREADME.md @docs alice@example.com\n"), nil
+	}
+	t.Cleanup(func() { gitserver.Mocks.ReadFile = nil })
+
+	fm := func(path string, matches int) *result.FileMatch {
+		return &result.FileMatch{
+			File:         result.File{Path: path},
+			ChunkMatches: result.ChunkMatches{{Ranges: make(result.Ranges, matches)}},
+		}
+	}
+
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("main.go", 2), fm("README.md", 1)}})
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("cmd/util.go", 3), fm("LICENSE", 1)}})
+		return nil, nil
+	})
+
+	agg := streaming.NewAggregatingStream()
+	clients := job.RuntimeClients{Gitserver: gitserver.NewClient(database.NewMockDB())}
+	_, err := NewSelectOwnersJob(child).Run(context.Background(), clients, agg)
+	require.NoError(t, err)
+
+	require.Equal(t, result.Matches{
+		&result.OwnerMatch{Handle: "@gopher", Type: "username", FileCount: 2, MatchCount: 5},
+		&result.OwnerMatch{Handle: "@docs", Type: "username", FileCount: 1, MatchCount: 1},
+		&result.OwnerMatch{Handle: "alice@example.com", Type: "email", FileCount: 1, MatchCount: 1},
+	}, agg.Results)
+}
+
+func TestSelectCaptureGroupJob(t *testing.T) {
+	fm := func(path string, lines ...string) *result.FileMatch {
+		m := &result.FileMatch{File: result.File{Path: path}}
+		for i, line := range lines {
+			// Each line matches from its first character to its end.
+			m.ChunkMatches = append(m.ChunkMatches, result.ChunkMatch{
+				Content:      line,
+				ContentStart: result.Location{Offset: i * 100, Line: i},
+				Ranges: result.Ranges{{
+					Start: result.Location{Offset: i * 100, Line: i},
+					End:   result.Location{Offset: i*100 + len(line), Line: i, Column: len(line)},
+				}},
+			})
+		}
+		return m
+	}
+
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			fm("a/go.mod", "golang.org/x/net v0.1.0", "golang.org/x/net v0.2.0"),
+			fm("b/go.mod", "golang.org/x/net v0.1.0", "golang.org/x/net v0.1.0"),
+		}})
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			fm("c/go.mod", "golang.org/x/net v0.2.0"),
+			&result.RepoMatch{Name: "ignored"},
+		}})
+		return nil, nil
+	})
+
+	plan, err := query.Pipeline(query.Init(`golang\.org/x/net (?P<version>v[0-9.]+)`, query.SearchTypeRegex))
+	require.NoError(t, err)
+	pattern, group, err := captureGroupPattern(plan[0], "version")
+	require.NoError(t, err)
+	require.Equal(t, 1, group)
+
+	agg := streaming.NewAggregatingStream()
+	_, err = NewSelectCaptureGroupJob(pattern, group, child).Run(context.Background(), job.RuntimeClients{}, agg)
+	require.NoError(t, err)
+
+	require.Equal(t, result.Matches{
+		&result.CaptureGroupMatch{Value: "v0.1.0", FileCount: 2, MatchCount: 3},
+		&result.CaptureGroupMatch{Value: "v0.2.0", FileCount: 2, MatchCount: 2},
+	}, agg.Results)
+}
+
+func TestCaptureGroupPattern(t *testing.T) {
+	cases := []struct {
+		query   string
+		group   string
+		want    int
+		wantErr string
+	}{
+		{query: `/a(b)(c)/`, group: "2", want: 2},
+		{query: `/a(?P<name>b)/`, group: "name", want: 1},
+		{query: `/a(b)/`, group: "2", wantErr: `the search pattern "a(b)" does not have a capture group "2"`},
+		{query: `/a(b)/`, group: "name", wantErr: `the search pattern "a(b)" does not have a capture group "name"`},
+		{query: `a(b)`, group: "1", wantErr: "select:content.group() requires a single regular expression search pattern. Add patterntype:regexp to the query"},
+		{query: `/a(b)/ or /c(d)/`, group: "1", wantErr: "select:content.group() requires a single regular expression search pattern. Add patterntype:regexp to the query"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			plan, err := query.Pipeline(query.Init(tc.query, query.SearchTypeStandard))
+			require.NoError(t, err)
+			_, got, err := captureGroupPattern(plan[0], tc.group)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -27,12 +27,18 @@ const (
 	FieldCommitter = "committer"
 	FieldMessage   = "message"
 
+	// For diff search only:
+	FieldAdded   = "added"
+	FieldRemoved = "removed"
+
 	// Temporary experimental fields:
 	FieldIndex     = "index"
 	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
 	FieldSelect    = "select"
+	FieldCursor    = "cursor" // Resumes a search from a cursor reported in its progress
+	FieldScope     = "scope"  // Restricts content matches to code, comments or strings
 )
 
 var allFields = map[string]struct{}{
@@ -64,6 +70,8 @@ var allFields = map[string]struct{}{
 	FieldMessage:            empty,
 	"m":                     empty,
 	"msg":                   empty,
+	FieldAdded:              empty,
+	FieldRemoved:            empty,
 	FieldIndex:              empty,
 	FieldCount:              empty,
 	FieldTimeout:            empty,
@@ -71,6 +79,8 @@ var allFields = map[string]struct{}{
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
+	FieldCursor:             empty,
+	FieldScope:              empty,
 }
 
 var aliases = map[string]string{
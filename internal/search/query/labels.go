@@ -19,6 +19,7 @@ const (
 	// than canonical form (r: instead of repo:)
 	IsAlias
 	Standard
+	Fuzzy
 )
 
 var allLabels = map[labels]string{
@@ -32,6 +33,7 @@ var allLabels = map[labels]string{
 	Structural:                "Structural",
 	IsPredicate:               "IsPredicate",
 	IsAlias:                   "IsAlias",
+	Fuzzy:                     "Fuzzy",
 }
 
 func (l *labels) IsSet(label labels) bool {
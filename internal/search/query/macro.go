@@ -0,0 +1,151 @@
+package query
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/grafana/regexp"
+)
+
+// Macros maps macro names to the query fragments they expand to. A macro
+// named "prod-repos" is referenced in a query as @prod-repos.
+type Macros map[string]string
+
+var macroReferencePattern = regexp.MustCompile(`^@([a-zA-Z0-9][a-zA-Z0-9_.-]*)$`)
+
+// macroName returns the name of the macro referenced by a pattern, if the
+// pattern is a macro reference.
+func macroName(pattern Pattern, searchType SearchType) (string, bool) {
+	if pattern.Annotation.Labels.IsSet(Quoted) {
+		return "", false
+	}
+	if pattern.Annotation.Labels.IsSet(Regexp) && searchType != SearchTypeRegex {
+		// An explicit /@regexp/ pattern in standard search.
+		return "", false
+	}
+	m := macroReferencePattern.FindStringSubmatch(pattern.Value)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// ExpandMacros substitutes @name references in nodes for the query fragment
+// of the macro with that name. Macros may reference other macros. References
+// to undefined macros are left as patterns, so that queries like @Override
+// still search for the literal string.
+//
+// Each expansion is an And operator annotated with the macro's name, which
+// StringHumanCollapsed uses to print the query as the user wrote it.
+// Expansion happens on the parse tree, before the query is processed for its
+// search type, so that macros combine with the rest of the query like
+// parenthesized expressions.
+func ExpandMacros(nodes []Node, macros Macros, searchType SearchType) ([]Node, error) {
+	if len(macros) == 0 {
+		return nodes, nil
+	}
+	return expandMacros(nodes, macros, searchType, nil)
+}
+
+func expandMacros(nodes []Node, macros Macros, searchType SearchType, stack []string) ([]Node, error) {
+	expanded := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		switch v := node.(type) {
+		case Pattern:
+			name, ok := macroName(v, searchType)
+			if !ok {
+				expanded = append(expanded, v)
+				continue
+			}
+			if _, ok := macros[name]; !ok {
+				expanded = append(expanded, v)
+				continue
+			}
+			if v.Negated {
+				return nil, &UnsupportedError{Msg: "macro @" + name + " cannot be negated"}
+			}
+			operands, err := expandMacro(name, macros, searchType, stack)
+			if err != nil {
+				return nil, err
+			}
+			expanded = append(expanded, Operator{
+				Kind:     And,
+				Operands: operands,
+				Annotation: Annotation{
+					Range: v.Annotation.Range,
+					Macro: name,
+				},
+			})
+		case Operator:
+			operands, err := expandMacros(v.Operands, macros, searchType, stack)
+			if err != nil {
+				return nil, err
+			}
+			v.Operands = operands
+			expanded = append(expanded, v)
+		default:
+			expanded = append(expanded, node)
+		}
+	}
+	return expanded, nil
+}
+
+// expandMacro parses and expands the macro name. stack holds the names of
+// the macros currently being expanded, to detect cycles.
+func expandMacro(name string, macros Macros, searchType SearchType, stack []string) ([]Node, error) {
+	for i, seen := range stack {
+		if seen == name {
+			return nil, macroCycleError(append(stack[i:], name))
+		}
+	}
+
+	nodes, err := Parse(macros[name], searchType)
+	if err != nil {
+		return nil, macroError(name, err)
+	}
+	if len(nodes) == 0 {
+		return nil, macroError(name, &UnsupportedError{Msg: "macro is empty"})
+	}
+	return expandMacros(nodes, macros, searchType, append(stack[:len(stack):len(stack)], name))
+}
+
+// collapseMacros replaces macro expansions in nodes with the @name reference
+// they were expanded from.
+func collapseMacros(nodes []Node) []Node {
+	collapsed := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		v, ok := node.(Operator)
+		if !ok {
+			collapsed = append(collapsed, node)
+			continue
+		}
+		if v.Annotation.Macro != "" {
+			collapsed = append(collapsed, Pattern{
+				Value:      "@" + v.Annotation.Macro,
+				Annotation: Annotation{Labels: Literal, Range: v.Annotation.Range},
+			})
+			continue
+		}
+		v.Operands = collapseMacros(v.Operands)
+		collapsed = append(collapsed, v)
+	}
+	return collapsed
+}
+
+// sortedMacroNames returns the names of macros in a stable order.
+func sortedMacroNames(macros Macros) []string {
+	names := make([]string, 0, len(macros))
+	for name := range macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatMacroPath(path []string) string {
+	refs := make([]string, 0, len(path))
+	for _, name := range path {
+		refs = append(refs, "@"+name)
+	}
+	return strings.Join(refs, " -> ")
+}
@@ -0,0 +1,124 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestExpandMacros(t *testing.T) {
+	macros := Macros{
+		"prod-repos": `repo:^github\.com/acme/(api|web)$ -file:test`,
+		"go":         `lang:go -file:vendor/`,
+		"prod-go":    `@prod-repos @go`,
+		"either":     `repo:a or repo:b`,
+	}
+
+	test := func(input string) (collapsed, expanded string) {
+		nodes, err := Parse(input, SearchTypeStandard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, err = ExpandMacros(nodes, macros, SearchTypeStandard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := Pipeline(InitWithMacros(input, SearchTypeStandard, macros))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return StringHumanCollapsed(nodes), plan.ToQ().String()
+	}
+
+	cases := []struct {
+		input     string
+		collapsed string
+		expanded  string
+	}{{
+		input:     "@prod-repos TODO",
+		collapsed: "@prod-repos TODO",
+		expanded:  `(and "repo:^github\\.com/acme/(api|web)$" "-file:test" "TODO")`,
+	}, {
+		input:     "@prod-go fmt.Println",
+		collapsed: "@prod-go fmt.Println",
+		expanded:  `(and "repo:^github\\.com/acme/(api|web)$" "-file:test" "lang:go" "-file:vendor/" "fmt.Println")`,
+	}, {
+		input:     "@either foo",
+		collapsed: "@either foo",
+		expanded:  `(or (and "repo:a" "foo") (and "repo:b" "foo"))`,
+	}, {
+		input:     "@undefined foo",
+		collapsed: "@undefined foo",
+		expanded:  `"@undefined foo"`,
+	}, {
+		input:     `"@prod-repos"`,
+		collapsed: `"@prod-repos"`,
+		expanded:  `"\"@prod-repos\""`,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			collapsed, expanded := test(tc.input)
+			if collapsed != tc.collapsed {
+				t.Errorf("collapsed: got %s, want %s", collapsed, tc.collapsed)
+			}
+			if expanded != tc.expanded {
+				t.Errorf("expanded: got %s, want %s", expanded, tc.expanded)
+			}
+		})
+	}
+}
+
+func TestExpandMacrosErrors(t *testing.T) {
+	macros := Macros{
+		"a":     "@b repo:a",
+		"b":     "@a repo:b",
+		"self":  "@self",
+		"empty": " ",
+	}
+
+	cases := []struct {
+		input string
+		want  string
+	}{{
+		input: "@a",
+		want:  "macro @a references itself: @a -> @b -> @a. Remove one of the references and try again",
+	}, {
+		input: "@self",
+		want:  "macro @self references itself: @self -> @self. Remove one of the references and try again",
+	}, {
+		input: "@empty",
+		want:  "invalid macro @empty: macro is empty",
+	}, {
+		input: "not @a",
+		want:  "macro @a cannot be negated",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Pipeline(InitWithMacros(tc.input, SearchTypeStandard, macros))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Error() != tc.want {
+				t.Errorf("got %q, want %q", err.Error(), tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateMacros(t *testing.T) {
+	if err := ValidateMacros(Macros{"prod-repos": "repo:acme", "all": "@prod-repos lang:go"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := ValidateMacros(Macros{"has space": "repo:acme"}); err == nil {
+		t.Fatal("expected error for invalid macro name")
+	}
+	if err := ValidateMacros(Macros{"loop": "@loop"}); err == nil {
+		t.Fatal("expected error for cyclic macro")
+	}
+	var unsupported *UnsupportedError
+	if err := ValidateMacros(Macros{"empty": " "}); !errors.As(err, &unsupported) {
+		t.Fatalf("expected wrapped UnsupportedError, got %v", err)
+	}
+}
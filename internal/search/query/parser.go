@@ -34,6 +34,9 @@ func (Operator) node()  {}
 type Annotation struct {
 	Labels labels `json:"labels"`
 	Range  Range  `json:"range"`
+
+	// Macro is the name of the macro an operator was expanded from, if any.
+	Macro string `json:"macro,omitempty"`
 }
 
 // Pattern is a leaf node of expressions representing a search pattern fragment.
@@ -1073,7 +1076,7 @@ func (p *parser) parseAnd() ([]Node, error) {
 	switch p.leafParser {
 	case SearchTypeRegex:
 		left, err = p.parseLeaves(Regexp)
-	case SearchTypeLiteral, SearchTypeStructural:
+	case SearchTypeLiteral, SearchTypeStructural, SearchTypeFuzzy:
 		left, err = p.parseLeaves(Literal)
 	case SearchTypeStandard, SearchTypeLucky:
 		left, err = p.parseLeaves(Literal | Standard)
@@ -2,6 +2,7 @@ package query
 
 import (
 	"strings"
+	"time"
 
 	"github.com/grafana/regexp"
 
@@ -40,6 +41,9 @@ var DefaultPredicateRegistry = PredicateRegistry{
 		"contains.content": func() Predicate { return &FileContainsContentPredicate{} },
 		"has.content":      func() Predicate { return &FileContainsContentPredicate{} },
 		"has.owner":        func() Predicate { return &FileHasOwnerPredicate{} },
+		"has.commit.after": func() Predicate { return &FileHasCommitAfterPredicate{} },
+		"older.than":       func() Predicate { return &FileOlderThanPredicate{} },
+		"modified.by":      func() Predicate { return &FileModifiedByPredicate{} },
 	},
 }
 
@@ -306,3 +310,61 @@ func (f *FileHasOwnerPredicate) ParseParams(params string) error {
 
 func (f FileHasOwnerPredicate) Field() string { return FieldFile }
 func (f FileHasOwnerPredicate) Name() string  { return "has.owner" }
+
+/* file:has.commit.after(time) */
+
+type FileHasCommitAfterPredicate struct {
+	TimeRef string
+	Time    time.Time
+}
+
+func (f *FileHasCommitAfterPredicate) ParseParams(params string) (err error) {
+	f.TimeRef = params
+	f.Time, err = ParseGitDate(params, time.Now)
+	if err != nil {
+		return errors.Errorf("file:has.commit.after argument: %w", err)
+	}
+	return nil
+}
+
+func (f FileHasCommitAfterPredicate) Field() string { return FieldFile }
+func (f FileHasCommitAfterPredicate) Name() string  { return "has.commit.after" }
+
+/* file:older.than(time) */
+
+type FileOlderThanPredicate struct {
+	TimeRef string
+	Time    time.Time
+}
+
+func (f *FileOlderThanPredicate) ParseParams(params string) (err error) {
+	f.TimeRef = params
+	f.Time, err = ParseGitDate(params, time.Now)
+	if err != nil {
+		return errors.Errorf("file:older.than argument: %w", err)
+	}
+	return nil
+}
+
+func (f FileOlderThanPredicate) Field() string { return FieldFile }
+func (f FileOlderThanPredicate) Name() string  { return "older.than" }
+
+/* file:modified.by(pattern) */
+
+type FileModifiedByPredicate struct {
+	Author string
+}
+
+func (f *FileModifiedByPredicate) ParseParams(params string) error {
+	if _, err := regexp.Compile(params); err != nil {
+		return errors.Errorf("file:modified.by argument: %w", err)
+	}
+	if params == "" {
+		return errors.Errorf("file:modified.by argument should not be empty")
+	}
+	f.Author = params
+	return nil
+}
+
+func (f FileModifiedByPredicate) Field() string { return FieldFile }
+func (f FileModifiedByPredicate) Name() string  { return "modified.by" }
@@ -3,6 +3,7 @@ package query
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestRepoContainsFilePredicate(t *testing.T) {
@@ -168,3 +169,46 @@ func TestFileHasOwnerPredicate(t *testing.T) {
 		}
 	})
 }
+
+func TestFileLastCommitPredicates(t *testing.T) {
+	t.Run("has.commit.after", func(t *testing.T) {
+		p := &FileHasCommitAfterPredicate{}
+		if err := p.ParseParams("2022-01-02"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC); !p.Time.Equal(want) {
+			t.Fatalf("expected %s, got %s", want, p.Time)
+		}
+		if err := p.ParseParams("xyzzy"); err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("older.than", func(t *testing.T) {
+		p := &FileOlderThanPredicate{}
+		if err := p.ParseParams("1 year ago"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !p.Time.Before(time.Now()) {
+			t.Fatalf("expected a time in the past, got %s", p.Time)
+		}
+		if err := p.ParseParams(""); err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("modified.by", func(t *testing.T) {
+		p := &FileModifiedByPredicate{}
+		if err := p.ParseParams("alice|bob@example.com"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if p.Author != "alice|bob@example.com" {
+			t.Fatalf("unexpected author %q", p.Author)
+		}
+		for _, params := range []string{"", "("} {
+			if err := (&FileModifiedByPredicate{}).ParseParams(params); err == nil {
+				t.Fatalf("expected error for %q but got none", params)
+			}
+		}
+	})
+}
@@ -39,6 +39,11 @@ func stringHumanPattern(nodes []Node) string {
 				separator = " OR "
 			case And:
 				separator = " AND "
+			case Concat:
+				// Concatenated patterns only remain in unprocessed
+				// queries, for example when collapsing macros.
+				result = append(result, strings.Join(nested, " "))
+				continue
 			}
 			result = append(result, "("+strings.Join(nested, separator)+")")
 		}
@@ -122,6 +127,14 @@ func StringHuman(nodes []Node) string {
 	return stringHumanParameters(parameters) + " " + stringHumanPattern([]Node{pattern})
 }
 
+// StringHumanCollapsed is StringHuman, but prints macro expansions as the
+// @name reference they were expanded from. Use it on the output of
+// ExpandMacros to show the query as the user wrote it, and StringHuman to
+// show the expanded query.
+func StringHumanCollapsed(nodes []Node) string {
+	return StringHuman(collapseMacros(nodes))
+}
+
 // toString returns a string representation of a query's structure.
 func toString(nodes []Node) string {
 	var result []string
@@ -101,6 +101,8 @@ func For(searchType SearchType) step {
 		processType = succeeds(escapeParensHeuristic, substituteConcat(fuzzyRegexp))
 	case SearchTypeStructural:
 		processType = succeeds(labelStructural, ellipsesForHoles, substituteConcat(space))
+	case SearchTypeFuzzy:
+		processType = succeeds(labelFuzzy, substituteConcat(space))
 	}
 	normalize := succeeds(LowercaseFieldNames, SubstituteAliases(searchType), SubstituteCountAll)
 	return Sequence(normalize, processType)
@@ -115,6 +117,19 @@ func Init(in string, searchType SearchType) step {
 	return Sequence(parser, For(searchType))
 }
 
+// InitWithMacros is Init, but expands @name references to macros after
+// parsing, before the query is processed for its search type.
+func InitWithMacros(in string, searchType SearchType, macros Macros) step {
+	parser := func([]Node) ([]Node, error) {
+		nodes, err := Parse(in, searchType)
+		if err != nil {
+			return nil, err
+		}
+		return ExpandMacros(nodes, macros, searchType)
+	}
+	return Sequence(parser, For(searchType))
+}
+
 // InitLiteral is Init where SearchType is Literal.
 func InitLiteral(in string) step {
 	return Init(in, SearchTypeLiteral)
@@ -678,6 +678,10 @@ func substituteConcat(callback func([]Pattern) []Node) func(nodes []Node) []Node
 					previous := v.Operands[0]
 					if p, ok := previous.(Pattern); ok {
 						ps = append(ps, p)
+					} else {
+						// Operators may be concatenated with patterns
+						// when expanded from a macro.
+						newNode = append(newNode, substituteNodes([]Node{previous})...)
 					}
 					for _, node := range v.Operands[1:] {
 						if isPattern(node) && isPattern(previous) {
@@ -809,6 +813,20 @@ func ConcatRevFilters(b Basic) Basic {
 	return Basic{Parameters: toParameters(modified), Pattern: b.Pattern}
 }
 
+// labelFuzzy converts Literal labels to Fuzzy labels. Like structural queries,
+// fuzzy queries are parsed the same as literal queries.
+func labelFuzzy(nodes []Node) []Node {
+	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
+		annotation.Labels.Unset(Literal)
+		annotation.Labels.Set(Fuzzy)
+		return Pattern{
+			Value:      value,
+			Negated:    negated,
+			Annotation: annotation,
+		}
+	})
+}
+
 // labelStructural converts Literal labels to Structural labels. Structural
 // queries are parsed the same as literal queries, we just convert the labels as
 // a postprocessing step to keep the parser lean.
@@ -36,6 +36,7 @@ const (
 	SearchTypeLucky
 	SearchTypeStandard
 	SearchTypeKeyword
+	SearchTypeFuzzy
 )
 
 func (s SearchType) String() string {
@@ -52,6 +53,8 @@ func (s SearchType) String() string {
 		return "lucky"
 	case SearchTypeKeyword:
 		return "keyword"
+	case SearchTypeFuzzy:
+		return "fuzzy"
 	default:
 		return fmt.Sprintf("unknown{%d}", s)
 	}
@@ -292,6 +295,10 @@ func (b Basic) IsStructural() bool {
 	return b.HasPatternLabel(Structural)
 }
 
+func (b Basic) IsFuzzy() bool {
+	return b.HasPatternLabel(Fuzzy)
+}
+
 // PatternString returns the simple string pattern of a basic query. It assumes
 // there is only on pattern atom.
 func (b Basic) PatternString() string {
@@ -443,6 +450,37 @@ func (p Parameters) FileHasOwner() (include, exclude []string) {
 	return include, exclude
 }
 
+// FileLastCommitFilter is a condition on the last commit that modified a
+// file, from one of the file:has.commit.after(), file:older.than() or
+// file:modified.by() predicates. Exactly one of After, Before and Author is
+// set.
+type FileLastCommitFilter struct {
+	After   *time.Time
+	Before  *time.Time
+	Author  string
+	Negated bool
+}
+
+func (p Parameters) FileLastCommitFilters() (res []FileLastCommitFilter) {
+	nodes := toNodes(p)
+
+	VisitTypedPredicate(nodes, func(pred *FileHasCommitAfterPredicate, negated bool) {
+		t := pred.Time
+		res = append(res, FileLastCommitFilter{After: &t, Negated: negated})
+	})
+
+	VisitTypedPredicate(nodes, func(pred *FileOlderThanPredicate, negated bool) {
+		t := pred.Time
+		res = append(res, FileLastCommitFilter{Before: &t, Negated: negated})
+	})
+
+	VisitTypedPredicate(nodes, func(pred *FileModifiedByPredicate, negated bool) {
+		res = append(res, FileLastCommitFilter{Author: pred.Author, Negated: negated})
+	})
+
+	return res
+}
+
 // Exists returns whether a parameter exists in the query (whether negated or not).
 func (p Parameters) Exists(field string) bool {
 	found := false
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/regexp"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/scope"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -216,6 +217,13 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return err
 	}
 
+	isValidScope := func() error {
+		if _, ok := scope.Parse(value); !ok {
+			return errors.Errorf("invalid value %q for field %q. Valid values are: code, comment, string", value, field)
+		}
+		return nil
+	}
+
 	isValidGitDate := func() error {
 		_, err := ParseGitDate(value, time.Now)
 		return err
@@ -270,7 +278,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldAuthor,
 		FieldCommitter,
-		FieldMessage:
+		FieldMessage,
+		FieldAdded,
+		FieldRemoved:
 		return satisfies(isValidRegexp)
 	case
 		FieldIndex,
@@ -287,11 +297,15 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldTimeout:
 		return satisfies(isSingular, isNotNegated, isDuration)
 	case
-		FieldRev:
+		FieldRev,
+		FieldCursor:
 		return satisfies(isSingular, isNotNegated)
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
+	case
+		FieldScope:
+		return satisfies(isSingular, isNotNegated, isValidScope)
 	default:
 		return isUnrecognizedField()
 	}
@@ -352,6 +366,25 @@ func validateCommitParameters(nodes []Node) error {
 	return nil
 }
 
+// Queries containing added: or removed: without type:diff are not valid,
+// since these fields match lines of a diff.
+func validateDiffParameters(nodes []Node) error {
+	var seenDiffParam string
+	var typeDiffExists bool
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if field == FieldAdded || field == FieldRemoved {
+			seenDiffParam = field
+		}
+		if field == FieldType && value == "diff" {
+			typeDiffExists = true
+		}
+	})
+	if seenDiffParam != "" && !typeDiffExists {
+		return errors.Errorf(`your query contains the field '%s', which requires type:diff in the query`, seenDiffParam)
+	}
+	return nil
+}
+
 func validateTypeStructural(nodes []Node) error {
 	seenStructural := false
 	seenType := false
@@ -376,6 +409,36 @@ func validateTypeStructural(nodes []Node) error {
 	return nil
 }
 
+// Queries containing scope: must search file contents with a literal or
+// regexp pattern, since only text search restricts matches to a scope.
+func validateScope(nodes []Node) error {
+	if !Exists(nodes, func(node Node) bool {
+		p, ok := node.(Parameter)
+		return ok && p.Field == FieldScope
+	}) {
+		return nil
+	}
+
+	var err error
+	VisitPattern(nodes, func(_ string, _ bool, annotation Annotation) {
+		if annotation.Labels.IsSet(Structural) {
+			err = errors.New("the query contains `scope:`, which is not supported for structural search")
+		}
+		if annotation.Labels.IsSet(Fuzzy) {
+			err = errors.New("the query contains `scope:`, which is not supported for fuzzy search")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	VisitField(nodes, FieldType, func(value string, _ bool, _ Annotation) {
+		if value != "file" {
+			err = errors.Errorf("the query contains `scope:`, which only applies to file contents and is not supported for type:%s", value)
+		}
+	})
+	return err
+}
+
 func validateRefGlobs(nodes []Node) error {
 	if !ContainsRefGlobs(nodes) {
 		return nil
@@ -475,6 +538,9 @@ func validatePattern(nodes []Node) error {
 		if annotation.Labels.IsSet(Structural) && negated {
 			err = errors.New("the query contains a negated search pattern. Structural search does not support negated search patterns at the moment")
 		}
+		if annotation.Labels.IsSet(Fuzzy) && negated {
+			err = errors.New("the query contains a negated search pattern. Fuzzy search does not support negated search patterns")
+		}
 	})
 	return err
 }
@@ -495,7 +561,9 @@ func validate(nodes []Node) error {
 		validateRepoRevPair,
 		validateRepoHasFile,
 		validateCommitParameters,
+		validateDiffParameters,
 		validateTypeStructural,
+		validateScope,
 		validateRefGlobs,
 	)
 }
@@ -545,3 +613,25 @@ func ContainsRefGlobs(q Q) bool {
 	}
 	return containsRefGlobs
 }
+
+// ValidateMacros returns an error if any macro has an invalid name, does not
+// parse, or references itself directly or through other macros.
+func ValidateMacros(macros Macros) error {
+	for _, name := range sortedMacroNames(macros) {
+		if !macroReferencePattern.MatchString("@" + name) {
+			return errors.Errorf("invalid macro name %q. Macro names may only contain letters, digits, '_', '.' and '-', and must start with a letter or digit", name)
+		}
+		if _, err := expandMacro(name, macros, SearchTypeStandard, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func macroError(name string, err error) error {
+	return errors.Wrapf(err, "invalid macro @%s", name)
+}
+
+func macroCycleError(path []string) error {
+	return errors.Errorf("macro @%s references itself: %s. Remove one of the references and try again", path[0], formatMacroPath(path))
+}
@@ -63,6 +63,11 @@ func TestValidation(t *testing.T) {
 			want:       "the query contains a negated search pattern. Structural search does not support negated search patterns at the moment",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input:      `getUserName -content:getUserID`,
+			want:       "the query contains a negated search pattern. Fuzzy search does not support negated search patterns",
+			searchType: SearchTypeFuzzy,
+		},
 		{
 			input: "repo:foo rev:a rev:b",
 			want:  `field "rev" may not be used more than once`,
@@ -99,11 +104,44 @@ func TestValidation(t *testing.T) {
 			input: "type:symbol select:symbol.timelime",
 			want:  `invalid field "timelime" on select path "symbol.timelime"`,
 		},
+		{
+			input: "select:content.group(0)",
+			want:  `invalid argument "0" on select path "content.group(0)". Use the index or name of a capture group`,
+		},
+		{
+			input: "select:content.grop(1)",
+			want:  `invalid field "grop(1)" on select path "content.grop(1)"`,
+		},
+		{
+			input: "TODO scope:comments",
+			want:  `invalid value "comments" for field "scope". Valid values are: code, comment, string`,
+		},
+		{
+			input: "TODO -scope:comment",
+			want:  `field "scope" does not support negation`,
+		},
+		{
+			input: "TODO scope:comment type:commit",
+			want:  "the query contains `scope:`, which only applies to file contents and is not supported for type:commit",
+		},
+		{
+			input:      "TODO(:[args]) scope:code",
+			want:       "the query contains `scope:`, which is not supported for structural search",
+			searchType: SearchTypeStructural,
+		},
 		{
 			input:      "nice try type:repo",
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input: "added:foo",
+			want:  "your query contains the field 'added', which requires type:diff in the query",
+		},
+		{
+			input: "type:commit removed:foo",
+			want:  "your query contains the field 'removed', which requires type:diff in the query",
+		},
 		{
 			input:      "type:diff nice try",
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
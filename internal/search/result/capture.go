@@ -0,0 +1,52 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CaptureGroupMatch is a distinct value of a capture group of the search
+// pattern, as selected by `select:content.group(...)`. Like owner matches,
+// capture group matches are not associated with a single repository: each
+// value is reported once across the whole result set.
+type CaptureGroupMatch struct {
+	// Value is the text matched by the capture group.
+	Value string
+
+	// FileCount is the number of matched files containing Value.
+	FileCount int
+
+	// MatchCount is the number of matches in which the capture group
+	// matched Value.
+	MatchCount int
+}
+
+func (c *CaptureGroupMatch) RepoName() types.MinimalRepo {
+	// Capture group values span repositories.
+	return types.MinimalRepo{}
+}
+
+func (c *CaptureGroupMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (c *CaptureGroupMatch) ResultCount() int {
+	return 1
+}
+
+func (c *CaptureGroupMatch) Select(path filter.SelectPath) Match {
+	if _, ok := path.CaptureGroup(); ok {
+		return c
+	}
+	return nil
+}
+
+func (c *CaptureGroupMatch) Key() Key {
+	return Key{
+		TypeRank: rankCaptureMatch,
+		Capture:  c.Value,
+	}
+}
+
+func (c *CaptureGroupMatch) searchResultMarker() {}
@@ -8,8 +8,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
-// Match is *FileMatch | *RepoMatch | *CommitMatch. We have a private method
-// to ensure only those types implement Match.
+// Match is *FileMatch | *RepoMatch | *CommitMatch | *OwnerMatch |
+// *CaptureGroupMatch. We have a private method to ensure only those types
+// implement Match.
 type Match interface {
 	ResultCount() int
 
@@ -34,16 +35,20 @@ var (
 	_ Match = (*RepoMatch)(nil)
 	_ Match = (*CommitMatch)(nil)
 	_ Match = (*CommitDiffMatch)(nil)
+	_ Match = (*OwnerMatch)(nil)
+	_ Match = (*CaptureGroupMatch)(nil)
 )
 
 // Match ranks are used for sorting the different match types.
 // Match types with lower ranks will be sorted before match types
 // with higher ranks.
 const (
-	rankFileMatch   = 0
-	rankCommitMatch = 1
-	rankDiffMatch   = 2
-	rankRepoMatch   = 3
+	rankFileMatch    = 0
+	rankCommitMatch  = 1
+	rankDiffMatch    = 2
+	rankRepoMatch    = 3
+	rankOwnerMatch   = 4
+	rankCaptureMatch = 5
 )
 
 // Key is a sorting or deduplicating key for a Match. It contains all the
@@ -74,6 +79,14 @@ type Key struct {
 	// Empty if there is no file associated with the match (e.g. RepoMatch or CommitMatch)
 	Path string
 
+	// Owner is the handle of the code owner the match belongs to.
+	// Empty if the match is not an owner match (e.g. FileMatch)
+	Owner string
+
+	// Capture is the value of the capture group the match belongs to.
+	// Empty if the match is not a capture group match (e.g. FileMatch)
+	Capture string
+
 	// TypeRank is the sorting rank of the type this key belongs to.
 	TypeRank int
 }
@@ -100,6 +113,14 @@ func (k Key) Less(other Key) bool {
 		return k.Path < other.Path
 	}
 
+	if k.Owner != other.Owner {
+		return k.Owner < other.Owner
+	}
+
+	if k.Capture != other.Capture {
+		return k.Capture < other.Capture
+	}
+
 	return k.TypeRank < other.TypeRank
 }
 
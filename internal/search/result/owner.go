@@ -0,0 +1,56 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// OwnerMatch is a code owner of one or more matched files, as resolved from
+// the CODEOWNERS rules of the repositories the files belong to. Owner
+// matches are not associated with a single repository: the same owner is
+// reported once across the whole result set.
+type OwnerMatch struct {
+	// Handle is the owner as written in the ownership file, e.g. "@alice",
+	// "@acme/security" or "alice@example.com".
+	Handle string
+
+	// Type is one of "username", "team" or "email".
+	Type string
+
+	// FileCount is the number of matched files owned by this owner.
+	FileCount int
+
+	// MatchCount is the number of matches in the files owned by this
+	// owner.
+	MatchCount int
+}
+
+func (o *OwnerMatch) RepoName() types.MinimalRepo {
+	// Owners span repositories.
+	return types.MinimalRepo{}
+}
+
+func (o *OwnerMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (o *OwnerMatch) ResultCount() int {
+	return 1
+}
+
+func (o *OwnerMatch) Select(path filter.SelectPath) Match {
+	if path.Root() == filter.File && len(path) > 1 && path[1] == "owners" {
+		return o
+	}
+	return nil
+}
+
+func (o *OwnerMatch) Key() Key {
+	return Key{
+		TypeRank: rankOwnerMatch,
+		Owner:    o.Handle,
+	}
+}
+
+func (o *OwnerMatch) searchResultMarker() {}
@@ -0,0 +1,308 @@
+// Package scope classifies the bytes of a file as code, comments or string
+// literals. It backs the `scope:` query parameter, which restricts the
+// matches of a search pattern to one of these kinds of text.
+//
+// The lexers are deliberately lightweight: they only know the comment and
+// string syntax of a language, which is enough to classify most text
+// correctly without parsing. The language of a file is inferred from its
+// path, in the same way as for the `lang:` parameter.
+package scope
+
+import (
+	"bytes"
+	"path"
+	"unicode/utf8"
+
+	"github.com/go-enry/go-enry/v2"
+)
+
+// Kind is a kind of text in a file.
+type Kind string
+
+const (
+	Code    Kind = "code"
+	Comment Kind = "comment"
+	String  Kind = "string"
+)
+
+// Parse returns the Kind named by s, the value of a `scope:` parameter.
+func Parse(s string) (Kind, bool) {
+	switch k := Kind(s); k {
+	case Code, Comment, String:
+		return k, true
+	}
+	return "", false
+}
+
+// Region is a comment or string literal in a file. Start and End are byte
+// offsets, and a Region includes its delimiters.
+type Region struct {
+	Start, End int
+	Kind       Kind
+}
+
+// Regions returns the comments and string literals in content, sorted by
+// offset. Everything outside of the returned regions is code. It returns nil
+// if there is no lexer for the language of the file at path, in which case
+// the whole file is considered code.
+func Regions(path string, content []byte) []Region {
+	l := lexerFor(path)
+	if l == nil {
+		return nil
+	}
+	return l.regions(content)
+}
+
+// Contains reports whether the match [start, end) is text of kind k. A code
+// match may not overlap any region. A comment or string match must be
+// contained in a single region of that kind.
+func Contains(regions []Region, k Kind, start, end int) bool {
+	// Find the first region which ends after start.
+	lo, hi := 0, len(regions)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if regions[mid].End <= start {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	if k == Code {
+		return lo == len(regions) || regions[lo].Start >= end
+	}
+	if lo == len(regions) {
+		return false
+	}
+	r := regions[lo]
+	return r.Kind == k && r.Start <= start && end <= r.End
+}
+
+// stringSyntax describes a kind of string literal.
+type stringSyntax struct {
+	open, close string
+
+	// escape is the escape character, or 0 if the literal has no escapes.
+	escape byte
+
+	// multiline is true if the literal may span lines. Otherwise an
+	// unterminated literal ends at the end of the line.
+	multiline bool
+
+	// boundary is true if the literal only starts at the beginning of a
+	// word, so that apostrophes in words like "don't" are not quotes.
+	boundary bool
+}
+
+// lexer describes the comment and string syntax of a language.
+type lexer struct {
+	lineComments  []string
+	blockComments [][2]string
+	strings       []stringSyntax
+}
+
+var (
+	dq = stringSyntax{open: `"`, close: `"`, escape: '\\'}
+	sq = stringSyntax{open: `'`, close: `'`, escape: '\\'}
+
+	cLike = &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{dq, sq},
+	}
+	goLexer = &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{dq, sq, {open: "`", close: "`", multiline: true}},
+	}
+	jsLike = &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{dq, sq, {open: "`", close: "`", escape: '\\', multiline: true}},
+	}
+	// Rust and Swift use ' for lifetimes and char literals respectively. We
+	// only treat " as a string delimiter to not mistake lifetimes for
+	// strings.
+	rustLike = &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{{open: `"""`, close: `"""`, escape: '\\', multiline: true}, dq},
+	}
+	jvmLike = &lexer{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{{open: `"""`, close: `"""`, escape: '\\', multiline: true}, dq, sq},
+	}
+	phpLexer = &lexer{
+		lineComments:  []string{"//", "#"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{dq, sq},
+	}
+	pythonLexer = &lexer{
+		lineComments: []string{"#"},
+		strings: []stringSyntax{
+			{open: `"""`, close: `"""`, escape: '\\', multiline: true},
+			{open: `'''`, close: `'''`, escape: '\\', multiline: true},
+			dq, sq,
+		},
+	}
+	// Shell scripts and configuration files often contain unquoted prose,
+	// so single quotes within words are apostrophes.
+	hashComments = &lexer{
+		lineComments: []string{"#"},
+		strings:      []stringSyntax{dq, {open: `'`, close: `'`, escape: '\\', boundary: true}},
+	}
+	sqlLexer = &lexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{{open: `'`, close: `'`}, {open: `"`, close: `"`}},
+	}
+	luaLexer = &lexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"--[[", "]]"}},
+		strings:       []stringSyntax{{open: "[[", close: "]]", multiline: true}, dq, sq},
+	}
+	haskellLexer = &lexer{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"{-", "-}"}},
+		strings:       []stringSyntax{dq},
+	}
+	markupLexer = &lexer{
+		blockComments: [][2]string{{"<!--", "-->"}},
+	}
+	cssLexer = &lexer{
+		blockComments: [][2]string{{"/*", "*/"}},
+		strings:       []stringSyntax{dq, sq},
+	}
+)
+
+// lexers maps the names of languages, as returned by enry, to their lexers.
+var lexers = map[string]*lexer{
+	"C":               cLike,
+	"C++":             cLike,
+	"C#":              cLike,
+	"Objective-C":     cLike,
+	"Java":            cLike,
+	"Dart":            cLike,
+	"Go":              goLexer,
+	"JavaScript":      jsLike,
+	"TypeScript":      jsLike,
+	"TSX":             jsLike,
+	"Rust":            rustLike,
+	"Swift":           rustLike,
+	"Kotlin":          jvmLike,
+	"Scala":           jvmLike,
+	"PHP":             phpLexer,
+	"Python":          pythonLexer,
+	"Starlark":        pythonLexer,
+	"Ruby":            hashComments,
+	"Perl":            hashComments,
+	"Shell":           hashComments,
+	"YAML":            hashComments,
+	"TOML":            hashComments,
+	"Dockerfile":      hashComments,
+	"Makefile":        hashComments,
+	"SQL":             sqlLexer,
+	"PLpgSQL":         sqlLexer,
+	"Lua":             luaLexer,
+	"Haskell":         haskellLexer,
+	"HTML":            markupLexer,
+	"XML":             markupLexer,
+	"Markdown":        markupLexer,
+	"CSS":             cssLexer,
+	"SCSS":            cLike,
+	"Less":            cLike,
+	"Protocol Buffer": cLike,
+}
+
+// lexerFor returns the lexer for the file at p. Extensions can be ambiguous,
+// e.g. .rs is used by Rust and RenderScript, so we use the first candidate
+// language we have a lexer for.
+func lexerFor(p string) *lexer {
+	langs := enry.GetLanguagesByExtension(p, nil, nil)
+	if len(langs) == 0 {
+		langs = enry.GetLanguagesByFilename(path.Base(p), nil, nil)
+	}
+	for _, lang := range langs {
+		if l, ok := lexers[lang]; ok {
+			return l
+		}
+	}
+	return nil
+}
+
+// regions scans content for comments and string literals.
+func (l *lexer) regions(content []byte) []Region {
+	var regions []Region
+	for i := 0; i < len(content); {
+		start := i
+		if end, ok := l.comment(content, i); ok {
+			regions = append(regions, Region{Start: start, End: end, Kind: Comment})
+			i = end
+		} else if end, ok := l.string(content, i); ok {
+			regions = append(regions, Region{Start: start, End: end, Kind: String})
+			i = end
+		} else {
+			i++
+		}
+	}
+	return regions
+}
+
+// comment returns the end of the comment starting at content[i], if any.
+func (l *lexer) comment(content []byte, i int) (int, bool) {
+	rest := content[i:]
+	// Block comments are checked first since some start with a line
+	// comment, e.g. --[[ in Lua.
+	for _, bc := range l.blockComments {
+		if bytes.HasPrefix(rest, []byte(bc[0])) {
+			end := bytes.Index(rest[len(bc[0]):], []byte(bc[1]))
+			if end < 0 {
+				return len(content), true
+			}
+			return i + len(bc[0]) + end + len(bc[1]), true
+		}
+	}
+	for _, lc := range l.lineComments {
+		if bytes.HasPrefix(rest, []byte(lc)) {
+			end := bytes.IndexByte(rest, '\n')
+			if end < 0 {
+				return len(content), true
+			}
+			return i + end, true
+		}
+	}
+	return 0, false
+}
+
+// string returns the end of the string literal starting at content[i], if
+// any.
+func (l *lexer) string(content []byte, i int) (int, bool) {
+	rest := content[i:]
+	for _, s := range l.strings {
+		if !bytes.HasPrefix(rest, []byte(s.open)) {
+			continue
+		}
+		if s.boundary && i > 0 && isWordByte(content[i-1]) {
+			continue
+		}
+		for j := len(s.open); j < len(rest); j++ {
+			switch {
+			case s.escape != 0 && rest[j] == s.escape:
+				j++
+			case !s.multiline && rest[j] == '\n':
+				return i + j, true
+			case bytes.HasPrefix(rest[j:], []byte(s.close)):
+				return i + j + len(s.close), true
+			}
+		}
+		return len(content), true
+	}
+	return 0, false
+}
+
+// isWordByte reports whether b can be part of a word. Bytes of multibyte
+// UTF-8 sequences are treated as letters.
+func isWordByte(b byte) bool {
+	return b == '_' || b >= utf8.RuneSelf || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
@@ -0,0 +1,103 @@
+package scope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []string
+	}{{
+		name:    "go",
+		path:    "main.go",
+		content: "x := \"a // b\" // TODO \"c\"\n/* d */ y := `e\nf` + 'g'",
+		want:    []string{`"a // b"`, `// TODO "c"`, "/* d */", "`e\nf`", "'g'"},
+	}, {
+		name:    "escaped quote",
+		path:    "a.js",
+		content: `s = "a\"b" // c`,
+		want:    []string{`"a\"b"`, "// c"},
+	}, {
+		name:    "unterminated string ends at newline",
+		path:    "a.c",
+		content: "s = \"a\nb // c",
+		want:    []string{`"a`, "// c"},
+	}, {
+		name:    "python",
+		path:    "a.py",
+		content: "def f():\n    \"\"\"doc\n    string\"\"\"\n    return 'x' # note",
+		want:    []string{"\"\"\"doc\n    string\"\"\"", "'x'", "# note"},
+	}, {
+		name:    "rust lifetimes are code",
+		path:    "lib.rs",
+		content: `fn f<'a>(s: &'a str) -> &'a str { "s" }`,
+		want:    []string{`"s"`},
+	}, {
+		name:    "filename",
+		path:    "build/Dockerfile",
+		content: "# comment\nRUN echo 'hi'",
+		want:    []string{"# comment", "'hi'"},
+	}, {
+		name:    "apostrophes in words are not quotes",
+		path:    "config.yaml",
+		content: "help: don't panic # it's fine\nname: 'x'",
+		want:    []string{"# it's fine", "'x'"},
+	}, {
+		name:    "unknown language",
+		path:    "notes.unknown",
+		content: "// not a comment",
+		want:    nil,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range Regions(tt.path, []byte(tt.content)) {
+				got = append(got, tt.content[r.Start:r.End])
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected regions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	content := `x := "secret" // TODO fix`
+	regions := Regions("a.go", []byte(content))
+
+	tests := []struct {
+		match string
+		kind  Kind
+		want  bool
+	}{
+		{"x", Code, true},
+		{"x", Comment, false},
+		{"secret", String, true},
+		{"secret", Code, false},
+		{"TODO", Comment, true},
+		{"TODO", String, false},
+		{`" // TODO`, Comment, false},
+		{`" // TODO`, String, false},
+		{`" // TODO`, Code, false},
+	}
+	for _, tt := range tests {
+		start := strings.Index(content, tt.match)
+		if got := Contains(regions, tt.kind, start, start+len(tt.match)); got != tt.want {
+			t.Errorf("Contains(%q, %s) = %v, want %v", tt.match, tt.kind, got, tt.want)
+		}
+	}
+
+	if !Contains(nil, Code, 0, 1) {
+		t.Error("expected everything to be code without regions")
+	}
+	if Contains(nil, Comment, 0, 1) {
+		t.Error("expected nothing to be a comment without regions")
+	}
+}
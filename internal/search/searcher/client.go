@@ -67,9 +67,11 @@ func Search(
 			CombyRule:                    p.CombyRule,
 			PathPatternsAreRegExps:       true,
 			Select:                       p.Select.Root(),
+			Scope:                        p.Scope,
 			Limit:                        int(p.FileMatchLimit),
 			IsRegExp:                     p.IsRegExp,
 			IsStructuralPat:              p.IsStructuralPat,
+			IsFuzzy:                      p.IsFuzzy,
 			IsWordMatch:                  p.IsWordMatch,
 			IsCaseSensitive:              p.IsCaseSensitive,
 			PathPatternsAreCaseSensitive: p.PathPatternsAreCaseSensitive,
@@ -112,10 +112,17 @@ func (s *TextSearchJob) Run(ctx context.Context, clients job.RuntimeClients, str
 					}
 					// non-diff search reports timeout through err, so pass false for timedOut
 					status, limitHit, err := search.HandleRepoSearchResult(repo.ID, []string{rev}, repoLimitHit, false, err)
+					var searched []api.RepoID
+					if err == nil && status.Len() == 0 {
+						// Tell the repo pager that this revision was searched
+						// completely, so that a resumed search can skip it.
+						searched = []api.RepoID{repo.ID}
+					}
 					stream.Send(streaming.SearchEvent{
 						Stats: streaming.Stats{
 							Status:     status,
 							IsLimitHit: limitHit,
+							Searched:   searched,
 						},
 					})
 					return err
@@ -188,9 +195,9 @@ func (s *TextSearchJob) searchFilesInRepo(
 		return false, err
 	}
 
-	// Structural and hybrid search both speak to zoekt so need the endpoints.
+	// Structural, fuzzy and hybrid search speak to zoekt so need the endpoints.
 	var indexerEndpoints []string
-	if info.IsStructuralPat || s.Features.HybridSearch {
+	if info.IsStructuralPat || info.IsFuzzy || s.Features.HybridSearch {
 		indexerEndpoints, err = search.Indexers().Map.Endpoints()
 		if err != nil {
 			return false, err
@@ -31,6 +31,7 @@ func BuildProgressEvent(stats ProgressStats, namer RepoNamer) Progress {
 		DurationMs:        stats.ElapsedMilliseconds,
 		Skipped:           skipped,
 		Trace:             stats.Trace,
+		Cursor:            stats.Cursor,
 	}
 }
 
@@ -52,6 +53,9 @@ type ProgressStats struct {
 
 	Trace string // only filled if requested
 
+	// Cursor is the encoded position to resume the search from, if any.
+	Cursor string
+
 	DisplayLimit int
 
 	// we smuggle in the namer via this field. Note: we don't calculate the
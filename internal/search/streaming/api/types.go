@@ -25,6 +25,11 @@ type Progress struct {
 
 	// Trace is the URL of an associated trace if the query is logging one.
 	Trace string `json:"trace,omitempty"`
+
+	// Cursor is an opaque position from which the search can be resumed by
+	// adding cursor:<value> to the query. It is empty once all repositories
+	// have been searched.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // Skipped is a description of shards or documents that were skipped.
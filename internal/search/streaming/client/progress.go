@@ -55,6 +55,11 @@ func (p *ProgressAggregator) currentStats() api.ProgressStats {
 	// Suggest the next 1000 after rounding off.
 	suggestedLimit := (p.Limit + 1500) / 1000 * 1000
 
+	var cursor string
+	if !p.Stats.Cursor.Done() {
+		cursor = p.Stats.Cursor.Encode()
+	}
+
 	return api.ProgressStats{
 		MatchCount:          p.MatchCount,
 		ElapsedMilliseconds: int(time.Since(p.Start).Milliseconds()),
@@ -67,6 +72,7 @@ func (p *ProgressAggregator) currentStats() api.ProgressStats {
 		SuggestedLimit:      suggestedLimit,
 		Trace:               p.Trace,
 		DisplayLimit:        p.DisplayLimit,
+		Cursor:              cursor,
 	}
 }
 
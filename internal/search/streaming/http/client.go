@@ -127,6 +127,10 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case OwnerMatchType:
+		r.EventMatch = &EventOwnerMatch{}
+	case CaptureGroupMatchType:
+		r.EventMatch = &EventCaptureGroupMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
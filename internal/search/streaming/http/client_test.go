@@ -48,6 +48,14 @@ func TestFrontendClient(t *testing.T) {
 				Type:   CommitMatchType,
 				Detail: "test",
 			},
+			&EventOwnerMatch{
+				Type:   OwnerMatchType,
+				Handle: "@test",
+			},
+			&EventCaptureGroupMatch{
+				Type:  CaptureGroupMatchType,
+				Value: "v1.2.3",
+			},
 		},
 	}, {
 		Name: "filters",
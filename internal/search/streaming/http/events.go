@@ -161,6 +161,33 @@ type EventCommitMatch struct {
 
 func (e *EventCommitMatch) eventMatch() {}
 
+// EventOwnerMatch is a code owner of matched files, as returned by
+// `select:file.owners` queries.
+type EventOwnerMatch struct {
+	// Type is always OwnerMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Handle     string `json:"handle"`
+	OwnerType  string `json:"ownerType"`
+	FileCount  int    `json:"fileCount"`
+	MatchCount int    `json:"matchCount"`
+}
+
+func (e *EventOwnerMatch) eventMatch() {}
+
+// EventCaptureGroupMatch is a distinct value of a capture group of the search
+// pattern, as returned by `select:content.group(...)` queries.
+type EventCaptureGroupMatch struct {
+	// Type is always CaptureGroupMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Value      string `json:"value"`
+	FileCount  int    `json:"fileCount"`
+	MatchCount int    `json:"matchCount"`
+}
+
+func (e *EventCaptureGroupMatch) eventMatch() {}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -206,6 +233,8 @@ const (
 	SymbolMatchType
 	CommitMatchType
 	PathMatchType
+	OwnerMatchType
+	CaptureGroupMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -220,6 +249,10 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"commit"`), nil
 	case PathMatchType:
 		return []byte(`"path"`), nil
+	case OwnerMatchType:
+		return []byte(`"owner"`), nil
+	case CaptureGroupMatchType:
+		return []byte(`"capture"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -236,6 +269,10 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = CommitMatchType
 	} else if bytes.Equal(b, []byte(`"path"`)) {
 		*t = PathMatchType
+	} else if bytes.Equal(b, []byte(`"owner"`)) {
+		*t = OwnerMatchType
+	} else if bytes.Equal(b, []byte(`"capture"`)) {
+		*t = CaptureGroupMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
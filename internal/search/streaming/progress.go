@@ -28,6 +28,16 @@ type Stats struct {
 	// ExcludedArchived is the count of excluded archived repos because the
 	// search query doesn't apply to them, but that we want to know about.
 	ExcludedArchived int
+
+	// Cursor is the position from which the search can be resumed with the
+	// cursor: parameter.
+	Cursor search.Cursor
+
+	// Searched are the repositories which were searched completely, once for
+	// each searched revision. Backends report them for the repo pager, which
+	// does not pass them on: the repositories a pager searched completely are
+	// part of its position in Cursor instead.
+	Searched []api.RepoID
 }
 
 // Update updates c with the other data, deduping as necessary. It modifies c but
@@ -52,6 +62,10 @@ func (c *Stats) Update(other *Stats) {
 
 	c.ExcludedForks = c.ExcludedForks + other.ExcludedForks
 	c.ExcludedArchived = c.ExcludedArchived + other.ExcludedArchived
+
+	c.Cursor.Update(&other.Cursor)
+
+	c.Searched = append(c.Searched, other.Searched...)
 }
 
 // Zero returns true if stats is empty. IE calling Update will result in no
@@ -65,7 +79,9 @@ func (c *Stats) Zero() bool {
 		len(c.Repos) > 0 ||
 		c.Status.Len() > 0 ||
 		c.ExcludedForks > 0 ||
-		c.ExcludedArchived > 0)
+		c.ExcludedArchived > 0 ||
+		len(c.Cursor.Pagers) > 0 ||
+		len(c.Searched) > 0)
 }
 
 func (c *Stats) String() string {
@@ -83,6 +99,8 @@ func (c *Stats) String() string {
 		{"repos", len(c.Repos)},
 		{"excludedForks", c.ExcludedForks},
 		{"excludedArchived", c.ExcludedArchived},
+		{"cursorPagers", len(c.Cursor.Pagers)},
+		{"searched", len(c.Searched)},
 	}
 	for _, p := range nums {
 		if p.n != 0 {
@@ -185,12 +185,14 @@ type TextPatternInfo struct {
 	IsNegated       bool
 	IsRegExp        bool
 	IsStructuralPat bool
+	IsFuzzy         bool
 	CombyRule       string
 	IsWordMatch     bool
 	IsCaseSensitive bool
 	FileMatchLimit  int32
 	Index           query.YesNoOnly
 	Select          filter.SelectPath
+	Scope           string
 
 	// We do not support IsMultiline
 	// IsMultiline     bool
@@ -222,6 +224,9 @@ func (p *TextPatternInfo) Fields() []otlog.Field {
 	if p.IsStructuralPat {
 		add(otlog.Bool("isStructural", p.IsStructuralPat))
 	}
+	if p.IsFuzzy {
+		add(otlog.Bool("isFuzzy", p.IsFuzzy))
+	}
 	if p.CombyRule != "" {
 		add(otlog.String("combyRule", p.CombyRule))
 	}
@@ -238,6 +243,9 @@ func (p *TextPatternInfo) Fields() []otlog.Field {
 	if len(p.Select) > 0 {
 		add(trace.Strings("select", p.Select))
 	}
+	if p.Scope != "" {
+		add(otlog.String("scope", p.Scope))
+	}
 	if len(p.IncludePatterns) > 0 {
 		add(trace.Strings("includePatterns", p.IncludePatterns))
 	}
@@ -268,6 +276,9 @@ func (p *TextPatternInfo) String() string {
 			args = append(args, "comby")
 		}
 	}
+	if p.IsFuzzy {
+		args = append(args, "fuzzy")
+	}
 	if p.IsWordMatch {
 		args = append(args, "word")
 	}
@@ -286,6 +297,9 @@ func (p *TextPatternInfo) String() string {
 	for _, lang := range p.Languages {
 		args = append(args, fmt.Sprintf("lang:%s", lang))
 	}
+	if p.Scope != "" {
+		args = append(args, fmt.Sprintf("scope:%s", p.Scope))
+	}
 
 	path := "f"
 	if p.PathPatternsAreCaseSensitive {
@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/scope"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -272,7 +273,7 @@ func DoZoektSearchGlobal(ctx context.Context, client zoekt.Streamer, args *searc
 }
 
 // zoektSearch searches repositories using zoekt.
-func zoektSearch(ctx context.Context, repos *IndexedRepoRevs, q zoektquery.Q, typ search.IndexedRequestType, client zoekt.Streamer, fileMatchLimit int32, selector filter.SelectPath, since func(t time.Time) time.Duration, c streaming.Sender) error {
+func zoektSearch(ctx context.Context, repos *IndexedRepoRevs, q zoektquery.Q, typ search.IndexedRequestType, client zoekt.Streamer, fileMatchLimit int32, selector filter.SelectPath, kind scope.Kind, since func(t time.Time) time.Duration, c streaming.Sender) error {
 	if len(repos.RepoRevs) == 0 {
 		return nil
 	}
@@ -286,6 +287,9 @@ func zoektSearch(ctx context.Context, repos *IndexedRepoRevs, q zoektquery.Q, ty
 
 	k := ResultCountFactor(len(repos.RepoRevs), fileMatchLimit, false)
 	searchOpts := SearchOpts(ctx, k, fileMatchLimit, selector)
+	// We need the content of files to verify matches are in scope. Only
+	// text searches are restricted to a scope.
+	searchOpts.Whole = kind != "" && typ == search.TextRequest
 
 	// Start event stream.
 	t0 := time.Now()
@@ -308,8 +312,13 @@ func zoektSearch(ctx context.Context, repos *IndexedRepoRevs, q zoektquery.Q, ty
 	}
 
 	foundResults := atomic.Bool{}
+	incomplete := atomic.Bool{}
 	err := client.StreamSearch(ctx, finalQuery, &searchOpts, backend.ZoektStreamFunc(func(event *zoekt.SearchResult) {
 		foundResults.CAS(false, event.FileCount != 0 || event.MatchCount != 0)
+		incomplete.CAS(false, event.FilesSkipped+event.ShardsSkipped+event.Crashes > 0)
+		if searchOpts.Whole {
+			filterScope(event, kind)
+		}
 		sendMatches(event, repos.getRepoInputRev, typ, selector, c)
 	}))
 	if err != nil {
@@ -324,8 +333,23 @@ func zoektSearch(ctx context.Context, repos *IndexedRepoRevs, q zoektquery.Q, ty
 		return statusMap
 	}
 
-	if !foundResults.Load() && since(t0) >= searchOpts.MaxWallTime {
-		c.Send(streaming.SearchEvent{Stats: streaming.Stats{Status: mkStatusMap(search.RepoStatusTimedout)}})
+	if since(t0) >= searchOpts.MaxWallTime {
+		if !foundResults.Load() {
+			c.Send(streaming.SearchEvent{Stats: streaming.Stats{Status: mkStatusMap(search.RepoStatusTimedout)}})
+		}
+		return nil
+	}
+
+	if !incomplete.Load() && ctx.Err() == nil {
+		// Tell the repo pager that all revisions were searched completely,
+		// so that a resumed search can skip them.
+		var searched []api.RepoID
+		for _, r := range repos.RepoRevs {
+			for range r.Revs {
+				searched = append(searched, r.Repo.ID)
+			}
+		}
+		c.Send(streaming.SearchEvent{Stats: streaming.Stats{Searched: searched}})
 	}
 	return nil
 }
@@ -400,6 +424,61 @@ func sendMatches(event *zoekt.SearchResult, getRepoInputRev repoRevFunc, typ sea
 	})
 }
 
+// filterScope removes the content matches in event which are not of kind k.
+// Files without any matches left are removed. It also drops the content of
+// files, which is only requested to verify the matches.
+func filterScope(event *zoekt.SearchResult, k scope.Kind) {
+	files := event.Files[:0]
+	for _, file := range event.Files {
+		regions := scope.Regions(file.FileName, file.Content)
+		inScope := func(start, end int) bool {
+			return scope.Contains(regions, k, start, end)
+		}
+
+		lms := file.LineMatches[:0]
+		for _, l := range file.LineMatches {
+			if !l.FileName {
+				fragments := l.LineFragments[:0]
+				for _, m := range l.LineFragments {
+					if inScope(int(m.Offset), int(m.Offset)+m.MatchLength) {
+						fragments = append(fragments, m)
+					}
+				}
+				if len(fragments) == 0 {
+					continue
+				}
+				l.LineFragments = fragments
+			}
+			lms = append(lms, l)
+		}
+		file.LineMatches = lms
+
+		cms := file.ChunkMatches[:0]
+		for _, cm := range file.ChunkMatches {
+			if !cm.FileName {
+				ranges := cm.Ranges[:0]
+				for _, r := range cm.Ranges {
+					if inScope(int(r.Start.ByteOffset), int(r.End.ByteOffset)) {
+						ranges = append(ranges, r)
+					}
+				}
+				if len(ranges) == 0 {
+					continue
+				}
+				cm.Ranges = ranges
+			}
+			cms = append(cms, cm)
+		}
+		file.ChunkMatches = cms
+
+		file.Content = nil
+		if len(file.LineMatches) > 0 || len(file.ChunkMatches) > 0 {
+			files = append(files, file)
+		}
+	}
+	event.Files = files
+}
+
 func zoektFileMatchToMultilineMatches(file *zoekt.FileMatch) result.ChunkMatches {
 	cms := make(result.ChunkMatches, 0, len(file.ChunkMatches))
 	for _, l := range file.LineMatches {
@@ -597,6 +676,7 @@ type RepoSubsetTextSearchJob struct {
 	Typ            search.IndexedRequestType
 	FileMatchLimit int32
 	Select         filter.SelectPath
+	Scope          scope.Kind                    // if non-empty, only content matches of this kind are returned.
 	Since          func(time.Time) time.Duration `json:"-"` // since if non-nil will be used instead of time.Since. For tests
 }
 
@@ -617,7 +697,7 @@ func (z *RepoSubsetTextSearchJob) Run(ctx context.Context, clients job.RuntimeCl
 		since = z.Since
 	}
 
-	return nil, zoektSearch(ctx, z.Repos, z.Query, z.Typ, clients.Zoekt, z.FileMatchLimit, z.Select, since, stream)
+	return nil, zoektSearch(ctx, z.Repos, z.Query, z.Typ, clients.Zoekt, z.FileMatchLimit, z.Select, z.Scope, since, stream)
 }
 
 func (*RepoSubsetTextSearchJob) Name() string {
@@ -631,6 +711,9 @@ func (z *RepoSubsetTextSearchJob) Fields(v job.Verbosity) (res []otlog.Field) {
 			otlog.Int32("fileMatchLimit", z.FileMatchLimit),
 			trace.Stringer("select", z.Select),
 		)
+		if z.Scope != "" {
+			res = append(res, otlog.String("scope", string(z.Scope)))
+		}
 		// z.Repos is nil for un-indexed search
 		if z.Repos != nil {
 			res = append(res,
@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/scope"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -80,7 +82,8 @@ func TestIndexedSearch(t *testing.T) {
 				useFullDeadline: false,
 				since:           func(time.Time) time.Duration { return time.Second - time.Millisecond },
 			},
-			wantErr: false,
+			wantCommon: streaming.Stats{Searched: mkSearched(reposHEAD)},
+			wantErr:    false,
 		},
 		{
 			name: "no matches timeout",
@@ -167,7 +170,8 @@ func TestIndexedSearch(t *testing.T) {
 				"HEAD",
 				"HEAD",
 			},
-			wantErr: false,
+			wantCommon: streaming.Stats{Searched: mkSearched(makeRepositoryRevisions("foo/bar", "foo/foobar"))},
+			wantErr:    false,
 		},
 		{
 			name: "results multi-branch",
@@ -206,7 +210,8 @@ func TestIndexedSearch(t *testing.T) {
 				"dev",
 				"dev",
 			},
-			wantErr: false,
+			wantCommon: streaming.Stats{Searched: mkSearched(makeRepositoryRevisions("foo/bar@HEAD:dev:main"))},
+			wantErr:    false,
 		},
 		{
 			// if we search a branch that is indexed and unindexed, we should
@@ -234,6 +239,7 @@ func TestIndexedSearch(t *testing.T) {
 			},
 			wantMatchCount:     1,
 			wantMatchInputRevs: []string{"HEAD"},
+			wantCommon:         streaming.Stats{Searched: mkSearched(makeRepositoryRevisions("foo/bar@HEAD"))},
 		},
 		{
 			// Fallback to unindexed search if the query contains ref-globs.
@@ -324,6 +330,8 @@ func TestIndexedSearch(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			// Indexed repos are searched in map order.
+			sort.Slice(agg.Stats.Searched, func(i, j int) bool { return agg.Stats.Searched[i] < agg.Stats.Searched[j] })
 			if diff := cmp.Diff(&tt.wantCommon, &agg.Stats, cmpopts.EquateEmpty()); diff != "" {
 				t.Errorf("common mismatch (-want +got):\n%s", diff)
 			}
@@ -351,6 +359,19 @@ func TestIndexedSearch(t *testing.T) {
 	}
 }
 
+// mkSearched returns the sorted repo IDs reported as searched for repos, once
+// per revision.
+func mkSearched(repos []*search.RepositoryRevisions) []api.RepoID {
+	var ids []api.RepoID
+	for _, r := range repos {
+		for range r.Revs {
+			ids = append(ids, r.Repo.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
 func mkStatusMap(m map[string]search.RepoStatus) search.RepoStatusMap {
 	var rsm search.RepoStatusMap
 	for name, status := range m {
@@ -899,3 +920,43 @@ func TestZoektFileMatchToMultilineMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterScope(t *testing.T) {
+	content := []byte(`x := "TODO" // TODO`)
+	chunk := func(ranges ...zoekt.Range) zoekt.ChunkMatch {
+		return zoekt.ChunkMatch{
+			Content:      content,
+			ContentStart: zoekt.Location{ByteOffset: 0, LineNumber: 1, Column: 1},
+			Ranges:       ranges,
+		}
+	}
+	str := zoekt.Range{Start: zoekt.Location{6, 1, 7}, End: zoekt.Location{10, 1, 11}}
+	comment := zoekt.Range{Start: zoekt.Location{15, 1, 16}, End: zoekt.Location{19, 1, 20}}
+
+	event := &zoekt.SearchResult{
+		Files: []zoekt.FileMatch{{
+			FileName:     "a.go",
+			Content:      content,
+			ChunkMatches: []zoekt.ChunkMatch{chunk(str, comment)},
+		}, {
+			FileName:     "b.go",
+			Content:      []byte(`x := "TODO"`),
+			ChunkMatches: []zoekt.ChunkMatch{chunk(str)},
+		}, {
+			FileName:     "TODO.go",
+			Content:      []byte(`x := "TODO"`),
+			ChunkMatches: []zoekt.ChunkMatch{{FileName: true}, chunk(str)},
+		}},
+	}
+
+	filterScope(event, scope.Comment)
+
+	want := []zoekt.FileMatch{{
+		FileName:     "a.go",
+		ChunkMatches: []zoekt.ChunkMatch{chunk(comment)},
+	}, {
+		FileName:     "TODO.go",
+		ChunkMatches: []zoekt.ChunkMatch{{FileName: true}},
+	}}
+	require.Equal(t, want, event.Files)
+}
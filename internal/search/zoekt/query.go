@@ -2,6 +2,7 @@ package zoekt
 
 import (
 	"regexp/syntax"
+	"unicode/utf8"
 
 	"github.com/go-enry/go-enry/v2"
 	"github.com/grafana/regexp"
@@ -137,14 +138,20 @@ func toZoektPattern(
 			fileNameOnly := patternMatchesPath && !patternMatchesContent
 			contentOnly := !patternMatchesPath && patternMatchesContent
 
-			pattern := n.Value
-			if n.Annotation.Labels.IsSet(query.Literal) {
-				pattern = regexp.QuoteMeta(pattern)
-			}
+			if n.Annotation.Labels.IsSet(query.Fuzzy) {
+				// Zoekt only finds candidate files. Searcher verifies
+				// them with an edit-distance matcher.
+				q = FuzzyCandidatesQuery(n.Value, fileNameOnly, contentOnly, isCaseSensitive)
+			} else {
+				pattern := n.Value
+				if n.Annotation.Labels.IsSet(query.Literal) {
+					pattern = regexp.QuoteMeta(pattern)
+				}
 
-			q, err = parseRe(pattern, fileNameOnly, contentOnly, isCaseSensitive)
-			if err != nil {
-				return nil, err
+				q, err = parseRe(pattern, fileNameOnly, contentOnly, isCaseSensitive)
+				if err != nil {
+					return nil, err
+				}
 			}
 
 			if typ == search.SymbolRequest && q != nil {
@@ -171,6 +178,65 @@ func toZoektPattern(
 	return q, nil
 }
 
+const (
+	// fuzzyMaxDistance is the largest edit distance fuzzy search tolerates.
+	fuzzyMaxDistance = 2
+
+	// fuzzyMinPieceLen is the shortest piece of a fuzzy pattern we look up
+	// in the index. Shorter pieces do not contain a trigram.
+	fuzzyMinPieceLen = 3
+)
+
+// FuzzyMaxDistance returns the maximum edit distance at which pattern
+// matches in a fuzzy search. Longer patterns tolerate more edits, up to
+// fuzzyMaxDistance. Patterns too short to be split into trigram-sized pieces
+// must match exactly.
+func FuzzyMaxDistance(pattern string) int {
+	k := utf8.RuneCountInString(pattern)/fuzzyMinPieceLen - 1
+	if k > fuzzyMaxDistance {
+		k = fuzzyMaxDistance
+	}
+	if k < 0 {
+		k = 0
+	}
+	return k
+}
+
+// FuzzyCandidatesQuery returns a query matching files that may contain pattern
+// within FuzzyMaxDistance(pattern) edits. If pattern is split into k+1 pieces,
+// k edits leave at least one piece intact, so every match contains one of the
+// pieces exactly. The query over-approximates: candidates must be verified
+// with an edit-distance matcher.
+func FuzzyCandidatesQuery(pattern string, fileNameOnly, contentOnly, isCaseSensitive bool) zoekt.Q {
+	pieces := FuzzyPieces(pattern)
+	children := make([]zoekt.Q, 0, len(pieces))
+	for _, piece := range pieces {
+		children = append(children, &zoekt.Substring{
+			Pattern:       piece,
+			CaseSensitive: isCaseSensitive,
+			Content:       contentOnly,
+			FileName:      fileNameOnly,
+		})
+	}
+	if len(children) == 1 {
+		return children[0]
+	}
+	return &zoekt.Or{Children: children}
+}
+
+// FuzzyPieces splits pattern into FuzzyMaxDistance(pattern)+1 pieces of
+// (almost) equal length. Every fuzzy match of pattern contains at least one of
+// the pieces.
+func FuzzyPieces(pattern string) []string {
+	runes := []rune(pattern)
+	n := FuzzyMaxDistance(pattern) + 1
+	pieces := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		pieces = append(pieces, string(runes[i*len(runes)/n:(i+1)*len(runes)/n]))
+	}
+	return pieces
+}
+
 func mapSlice(values []string, f func(string) string) []string {
 	result := make([]string, len(values))
 	for i, v := range values {
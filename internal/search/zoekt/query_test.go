@@ -153,6 +153,14 @@ func Test_toZoektPattern(t *testing.T) {
 	autogold.Want("zoekt symbol nodes are atoms",
 		`(and sym:substr:"foo" (not sym:substr:"bar"))`).
 		Equal(t, test(`type:symbol (foo and not bar)`, query.SearchTypeLiteral, search.SymbolRequest))
+
+	autogold.Want("fuzzy pattern is split into candidate pieces",
+		`(or substr:"get" substr:"User" substr:"Name")`).
+		Equal(t, test(`getUserName`, query.SearchTypeFuzzy, search.TextRequest))
+
+	autogold.Want("short fuzzy pattern must match exactly",
+		`substr:"foo"`).
+		Equal(t, test(`foo`, query.SearchTypeFuzzy, search.TextRequest))
 }
 
 func queryEqual(a, b zoekt.Q) bool {
@@ -90,6 +90,13 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		if err != nil {
 			return "", err
 		}
+	case *schema.MercurialConnection:
+		err = es.redactURL(c.Url, "url")
+		if err != nil {
+			return "", err
+		}
+	case *schema.SubversionConnection:
+		es.redactString(c.Password, "password")
 	default:
 		// return an error; it's safer to fail than to incorrectly return unsafe data.
 		return "", errors.Errorf("Unrecognized ExternalServiceConfig for redaction: kind %+v not implemented", reflect.TypeOf(cfg))
@@ -191,6 +198,15 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 		if err != nil {
 			return err
 		}
+	case *schema.MercurialConnection:
+		o := oldCfg.(*schema.MercurialConnection)
+		err = es.unredactURL(c.Url, o.Url, "url")
+		if err != nil {
+			return err
+		}
+	case *schema.SubversionConnection:
+		o := oldCfg.(*schema.SubversionConnection)
+		es.unredactString(c.Password, o.Password, "password")
 	default:
 		// return an error; it's safer to fail than to incorrectly return unsafe data.
 		return errors.Errorf("Unrecognized ExternalServiceConfig for redaction: kind %+v not implemented", reflect.TypeOf(newCfg))
@@ -557,6 +557,31 @@ type GitserverRepo struct {
 	UpdatedAt     time.Time
 }
 
+// RepoCorruptionLog is a step of gitserver repairing a corrupt clone of a
+// repository.
+type RepoCorruptionLog struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Step is the step of the repair, for example "fsck" or "quarantined".
+	Step string `json:"step"`
+	// Reason describes the outcome of the step.
+	Reason string `json:"reason"`
+}
+
+// QuarantinedRepo is a repository whose corrupt clone gitserver couldn't
+// repair. The corrupt clone is kept in quarantine for inspection, and the
+// repository is recloned.
+type QuarantinedRepo struct {
+	RepoID api.RepoID
+	Name   api.RepoName
+	// The gitserver hostname the corrupt clone is kept on.
+	ShardID string
+	// The path of the corrupt clone on the gitserver.
+	QuarantinePath string
+	QuarantinedAt  time.Time
+	// The latest steps of the repair, oldest first.
+	CorruptionLogs []RepoCorruptionLog
+}
+
 // ExternalService is a connection to an external service.
 type ExternalService struct {
 	ID              int64
@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS gitserver_repos
+    DROP COLUMN IF EXISTS corruption_logs,
+    DROP COLUMN IF EXISTS quarantined_at,
+    DROP COLUMN IF EXISTS quarantine_path;
@@ -0,0 +1,8 @@
+ALTER TABLE IF EXISTS gitserver_repos
+    ADD COLUMN IF NOT EXISTS corruption_logs jsonb DEFAULT '[]'::jsonb NOT NULL,
+    ADD COLUMN IF NOT EXISTS quarantined_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS quarantine_path text;
+
+COMMENT ON COLUMN gitserver_repos.corruption_logs IS 'The latest steps of gitserver repairing a corrupt clone of the repository, each with a timestamp and a reason.';
+COMMENT ON COLUMN gitserver_repos.quarantined_at IS 'When gitserver moved a corrupt clone of the repository it could not repair into quarantine.';
+COMMENT ON COLUMN gitserver_repos.quarantine_path IS 'The path of the corrupt clone of the repository in quarantine on the gitserver shard.';
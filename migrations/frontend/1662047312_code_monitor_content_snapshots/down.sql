@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS cm_trigger_jobs
+    DROP COLUMN IF EXISTS content_changes;
+
+DROP TABLE IF EXISTS cm_content_snapshots;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS cm_content_snapshots (
+    monitor_id bigint NOT NULL REFERENCES cm_monitors(id) ON DELETE CASCADE,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_oid text NOT NULL,
+    matches jsonb NOT NULL,
+    PRIMARY KEY (monitor_id, repo_id)
+);
+
+COMMENT ON TABLE cm_content_snapshots IS 'The matches of a code monitor on file contents in a repository the last time it was searched';
+COMMENT ON COLUMN cm_content_snapshots.commit_oid IS 'The commit of the default branch that was searched';
+COMMENT ON COLUMN cm_content_snapshots.matches IS 'The matched lines, each with a path, a line number and a preview';
+
+ALTER TABLE IF EXISTS cm_trigger_jobs
+    ADD COLUMN IF NOT EXISTS content_changes jsonb DEFAULT '[]'::jsonb NOT NULL;
+
+COMMENT ON COLUMN cm_trigger_jobs.content_changes IS 'The matches of a code monitor on file contents that appeared or disappeared since the last run';
@@ -0,0 +1,14 @@
+ALTER TABLE IF EXISTS cm_action_jobs
+    DROP COLUMN IF EXISTS batched_into;
+
+ALTER TABLE IF EXISTS cm_slack_webhooks
+    DROP COLUMN IF EXISTS delivery_policy,
+    DROP COLUMN IF EXISTS max_notifications_per_hour;
+
+ALTER TABLE IF EXISTS cm_webhooks
+    DROP COLUMN IF EXISTS delivery_policy,
+    DROP COLUMN IF EXISTS max_notifications_per_hour;
+
+ALTER TABLE IF EXISTS cm_emails
+    DROP COLUMN IF EXISTS delivery_policy,
+    DROP COLUMN IF EXISTS max_notifications_per_hour;
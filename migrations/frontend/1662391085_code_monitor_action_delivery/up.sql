@@ -0,0 +1,23 @@
+ALTER TABLE IF EXISTS cm_emails
+    ADD COLUMN IF NOT EXISTS delivery_policy text DEFAULT 'IMMEDIATE' NOT NULL,
+    ADD COLUMN IF NOT EXISTS max_notifications_per_hour integer;
+
+ALTER TABLE IF EXISTS cm_webhooks
+    ADD COLUMN IF NOT EXISTS delivery_policy text DEFAULT 'IMMEDIATE' NOT NULL,
+    ADD COLUMN IF NOT EXISTS max_notifications_per_hour integer;
+
+ALTER TABLE IF EXISTS cm_slack_webhooks
+    ADD COLUMN IF NOT EXISTS delivery_policy text DEFAULT 'IMMEDIATE' NOT NULL,
+    ADD COLUMN IF NOT EXISTS max_notifications_per_hour integer;
+
+COMMENT ON COLUMN cm_emails.delivery_policy IS 'Whether notifications are sent after each run of the code monitor (IMMEDIATE), or as an hourly or daily digest (HOURLY_DIGEST, DAILY_DIGEST)';
+COMMENT ON COLUMN cm_emails.max_notifications_per_hour IS 'The maximum number of notifications sent per hour. Further results are sent with the next notification';
+COMMENT ON COLUMN cm_webhooks.delivery_policy IS 'Whether notifications are sent after each run of the code monitor (IMMEDIATE), or as an hourly or daily digest (HOURLY_DIGEST, DAILY_DIGEST)';
+COMMENT ON COLUMN cm_webhooks.max_notifications_per_hour IS 'The maximum number of notifications sent per hour. Further results are sent with the next notification';
+COMMENT ON COLUMN cm_slack_webhooks.delivery_policy IS 'Whether notifications are sent after each run of the code monitor (IMMEDIATE), or as an hourly or daily digest (HOURLY_DIGEST, DAILY_DIGEST)';
+COMMENT ON COLUMN cm_slack_webhooks.max_notifications_per_hour IS 'The maximum number of notifications sent per hour. Further results are sent with the next notification';
+
+ALTER TABLE IF EXISTS cm_action_jobs
+    ADD COLUMN IF NOT EXISTS batched_into integer REFERENCES cm_action_jobs(id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN cm_action_jobs.batched_into IS 'The action job whose notification included the results of this job, if they were sent as part of a digest';
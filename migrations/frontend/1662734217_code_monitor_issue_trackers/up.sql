@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS cm_issue_trackers (
+    id bigserial PRIMARY KEY,
+    monitor bigint NOT NULL REFERENCES cm_monitors(id) ON DELETE CASCADE,
+    enabled boolean NOT NULL,
+    include_results boolean NOT NULL DEFAULT false,
+    kind text NOT NULL,
+    url text NOT NULL,
+    repository text NOT NULL DEFAULT '',
+    token text NOT NULL DEFAULT '',
+    labels text[] NOT NULL DEFAULT '{}',
+    assignees text[] NOT NULL DEFAULT '{}',
+    assign_code_owners boolean NOT NULL DEFAULT false,
+    issue_per_commit boolean NOT NULL DEFAULT true,
+    rest_template jsonb,
+    delivery_policy text NOT NULL DEFAULT 'IMMEDIATE',
+    max_notifications_per_hour integer,
+    created_by integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    changed_by integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changed_at timestamp with time zone NOT NULL DEFAULT now(),
+    encryption_key_id text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS cm_issue_trackers_monitor ON cm_issue_trackers USING btree (monitor);
+
+COMMENT ON TABLE cm_issue_trackers IS 'Issue tracker actions configured on code monitors';
+COMMENT ON COLUMN cm_issue_trackers.monitor IS 'The code monitor that the action is defined on';
+COMMENT ON COLUMN cm_issue_trackers.kind IS 'The kind of issue tracker issues are created in (GITHUB, GITLAB or REST)';
+COMMENT ON COLUMN cm_issue_trackers.url IS 'The URL of the GitHub or GitLab instance, or the endpoint issues are created with for REST issue trackers';
+COMMENT ON COLUMN cm_issue_trackers.repository IS 'The GitHub repository or GitLab project issues are created in';
+COMMENT ON COLUMN cm_issue_trackers.token IS 'The access token used to create issues, or the Authorization header of requests to REST issue trackers';
+COMMENT ON COLUMN cm_issue_trackers.encryption_key_id IS 'The identifier of the key the token is encrypted with, or empty if it is not encrypted';
+COMMENT ON COLUMN cm_issue_trackers.assign_code_owners IS 'Whether the code owners of the matched files are assigned to the issues';
+COMMENT ON COLUMN cm_issue_trackers.issue_per_commit IS 'Whether an issue is created for each matching commit, or one for each notification of the code monitor';
+COMMENT ON COLUMN cm_issue_trackers.rest_template IS 'The templates of the requests that create and comment on issues of REST issue trackers';
+COMMENT ON COLUMN cm_issue_trackers.delivery_policy IS 'Whether notifications are sent after each run of the code monitor (IMMEDIATE), or as an hourly or daily digest (HOURLY_DIGEST, DAILY_DIGEST)';
+COMMENT ON COLUMN cm_issue_trackers.max_notifications_per_hour IS 'The maximum number of notifications sent per hour. Further results are sent with the next notification';
+
+CREATE TABLE IF NOT EXISTS cm_issues (
+    issue_tracker bigint NOT NULL REFERENCES cm_issue_trackers(id) ON DELETE CASCADE,
+    subject text NOT NULL,
+    issue_key text NOT NULL,
+    url text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    action_job integer,
+    PRIMARY KEY (issue_tracker, subject)
+);
+
+COMMENT ON TABLE cm_issues IS 'The issues created by issue tracker actions, which further matches about the same subject are commented on';
+COMMENT ON COLUMN cm_issues.subject IS 'The repository and commit the issue is about, or empty if the action creates one issue for all its notifications';
+COMMENT ON COLUMN cm_issues.issue_key IS 'The number or key of the issue in the issue tracker';
+COMMENT ON COLUMN cm_issues.action_job IS 'The action job that last created or commented on the issue, so that retries of that job skip it';
+
+ALTER TABLE IF EXISTS cm_action_jobs
+    ADD COLUMN IF NOT EXISTS issue_tracker bigint REFERENCES cm_issue_trackers(id) ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS cm_action_jobs
+    DROP CONSTRAINT IF EXISTS cm_action_jobs_only_one_action_type;
+
+ALTER TABLE IF EXISTS cm_action_jobs
+    ADD CONSTRAINT cm_action_jobs_only_one_action_type CHECK ((
+        CASE WHEN email IS NULL THEN 0 ELSE 1 END +
+        CASE WHEN webhook IS NULL THEN 0 ELSE 1 END +
+        CASE WHEN slack_webhook IS NULL THEN 0 ELSE 1 END +
+        CASE WHEN issue_tracker IS NULL THEN 0 ELSE 1 END
+    ) = 1);
+
+COMMENT ON CONSTRAINT cm_action_jobs_only_one_action_type ON cm_action_jobs IS 'Constrains that each queued code monitor action has exactly one action type';
+COMMENT ON COLUMN cm_action_jobs.issue_tracker IS 'The ID of the cm_issue_trackers action to execute if this is an issue tracker job. Mutually exclusive with email, webhook and slack_webhook';
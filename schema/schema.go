@@ -617,12 +617,18 @@ type ExperimentalFeatures struct {
 	EventLogging string `json:"eventLogging,omitempty"`
 	// Gerrit description: Allow adding Gerrit code host connections
 	Gerrit string `json:"gerrit,omitempty"`
+	// GitPartialClones description: JSON array of configuration that maps from Git clone URL domain/path to a partial clone filter. Partial clones omit the objects excluded by the filter, which are fetched from the code host when they are read and then kept in the repository. Changes only apply to repositories cloned afterwards.
+	GitPartialClones []*GitPartialCloneMapping `json:"gitPartialClones,omitempty"`
 	// GitServerPinnedRepos description: List of repositories pinned to specific gitserver instances. The specified repositories will remain at their pinned servers on scaling the cluster. If the specified pinned server differs from the current server that stores the repository, then it must be re-cloned to the specified server.
 	GitServerPinnedRepos map[string]string `json:"gitServerPinnedRepos,omitempty"`
+	// GitServerReplicationFactor description: The number of gitserver instances that have a copy of each repository. The primary instance, which the repository is assigned to, clones and fetches it from the code host. The other instances mirror it from the primary instance and serve read-only requests for specific commits, so that requests for frequently read repositories are spread across instances.
+	GitServerReplicationFactor int `json:"gitServerReplicationFactor,omitempty"`
 	// GoPackages description: Allow adding Go package host connections
 	GoPackages string `json:"goPackages,omitempty"`
 	// JvmPackages description: Allow adding JVM package host connections
 	JvmPackages string `json:"jvmPackages,omitempty"`
+	// Mercurial description: Allow adding Mercurial code host connections
+	Mercurial string `json:"mercurial,omitempty"`
 	// NpmPackages description: Allow adding npm package code host connections
 	NpmPackages string `json:"npmPackages,omitempty"`
 	// Pagure description: Allow adding Pagure code host connections
@@ -650,6 +656,8 @@ type ExperimentalFeatures struct {
 	// StructuralSearch description: Enables structural search.
 	StructuralSearch   string              `json:"structuralSearch,omitempty"`
 	SubRepoPermissions *SubRepoPermissions `json:"subRepoPermissions,omitempty"`
+	// Subversion description: Allow adding Subversion code host connections
+	Subversion string `json:"subversion,omitempty"`
 	// TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 	TlsExternal *TlsExternal `json:"tls.external,omitempty"`
 	// VersionContexts description: DEPRECATED: Use search contexts instead.
@@ -966,6 +974,14 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GitPartialCloneMapping description: Mapping from Git clone URL domain/path to a partial clone filter. The `domainPath` field contains the Git clone URL domain/path part, or a prefix of it such as the domain of a code host to apply the filter to all its repositories. The `filter` field contains the filter passed to `git fetch --filter`.
+type GitPartialCloneMapping struct {
+	// DomainPath description: Git clone URL domain/path, or a prefix of it
+	DomainPath string `json:"domainPath"`
+	// Filter description: Partial clone filter: `blob:none` omits all files, `blob:limit=<n>[kmg]` omits files larger than the limit and `tree:0` omits all trees and files (treeless clone).
+	Filter string `json:"filter"`
+}
+
 // Github description: GitHub configuration, both for queries and receiving release webhooks.
 type Github struct {
 	// Repository description: The repository to get the latest version of.
@@ -1134,6 +1150,18 @@ type MavenRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+
+// MercurialConnection description: Configuration for a Connection to Mercurial repositories. The repositories are mirrored as Git repositories.
+type MercurialConnection struct {
+	Repos []string `json:"repos"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Mercurial clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
+	//
+	// For example, if your Mercurial clone base URL is https://hg.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://hg.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/hg.example.com/repos/my/repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	Url                   string `json:"url,omitempty"`
+}
 type Mount struct {
 	// Mountpoint description: The path in the container to mount the path on the local machine to.
 	Mountpoint string `json:"mountpoint"`
@@ -1750,6 +1778,8 @@ type Settings struct {
 	SearchIncludeArchived *bool `json:"search.includeArchived,omitempty"`
 	// SearchIncludeForks description: Whether searches should include searching forked repositories.
 	SearchIncludeForks *bool `json:"search.includeForks,omitempty"`
+	// SearchMacros description: Named query fragments that can be referenced in search queries as `@name`. For example, a macro named `prod-repos` with the value `repo:^github\.com/acme/(api|web)$ -file:test` can be used in the query `@prod-repos TODO`. Macros may reference other macros.
+	SearchMacros map[string]string `json:"search.macros,omitempty"`
 	// SearchMigrateParser description: REMOVED. Previously, a flag to enable and/or-expressions in queries as an aid transition to new language features in versions <= 3.24.0.
 	SearchMigrateParser *bool `json:"search.migrateParser,omitempty"`
 	// SearchRepositoryGroups description: DEPRECATED: Use search contexts instead.
@@ -2122,6 +2152,33 @@ type SubRepoPermissions struct {
 	UserCacheTTLSeconds int `json:"userCacheTTLSeconds,omitempty"`
 }
 
+// SubversionConnection description: Configuration for a Connection to Subversion repositories. The repositories are mirrored as Git repositories.
+type SubversionConnection struct {
+	// Layout description: The layout of the repositories, as the paths of the trunk, branches and tags directories relative to each repository. Defaults to the standard layout with trunk, branches and tags directories. Omit branches or tags if the repositories don't have them, and trunk to mirror the whole repository as a single branch.
+	Layout *SubversionLayout `json:"layout,omitempty"`
+	// Password description: The password to authenticate with.
+	Password string `json:"password,omitempty"`
+	// Repos description: The paths of the repositories relative to the root URL. If omitted, every directory at the root URL is a repository, or the root URL itself if it contains the trunk directory.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Subversion root URL host and path, and "{repo}" is replaced with the repository path.
+	//
+	// For example, if your Subversion root URL is https://svn.example.com/repos and a repository is at the path "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that the repository is available on Sourcegraph at https://sourcegraph.example.com/svn.example.com/repos/my/repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: The root URL of the Subversion server. The repositories are located relative to it.
+	Url string `json:"url"`
+	// Username description: The username to authenticate with.
+	Username string `json:"username,omitempty"`
+}
+
+// SubversionLayout description: The layout of the repositories, as the paths of the trunk, branches and tags directories relative to each repository. Defaults to the standard layout with trunk, branches and tags directories. Omit branches or tags if the repositories don't have them, and trunk to mirror the whole repository as a single branch.
+type SubversionLayout struct {
+	Branches string `json:"branches,omitempty"`
+	Tags     string `json:"tags,omitempty"`
+	Trunk    string `json:"trunk,omitempty"`
+}
+
 // SyntaxHighlighting description: Syntax highlighting configuration
 type SyntaxHighlighting struct {
 	Engine    SyntaxHighlightingEngine   `json:"engine"`